@@ -1,3 +1,9 @@
 module github.com/VuNe/json-parser
 
 go 1.25.1
+
+require (
+	github.com/klauspost/compress v1.20.1
+	golang.org/x/text v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,72 @@
+package arena
+
+import "unsafe"
+
+// Default chunk sizes. Allocations larger than a chunk get a dedicated chunk.
+const (
+	byteChunkSize  = 64 * 1024 // bytes of string data per chunk
+	valueChunkSize = 4 * 1024  // array elements per chunk
+)
+
+// Arena is a slab allocator for parse-time allocations. Instead of one heap
+// object per string and per array, it carves them out of a few large chunks,
+// so a parsed document costs the garbage collector a handful of objects.
+//
+// Reset releases everything the arena handed out in one operation and reuses
+// the memory for the next document. After Reset, every value produced with
+// the arena (including object keys) must no longer be used, because the
+// underlying bytes are overwritten. This makes arenas a good fit for
+// request-scoped parsing, where a document is discarded once the request ends.
+//
+// An Arena is not safe for concurrent use.
+type Arena struct {
+	bytes  []byte
+	values []any
+}
+
+// New creates an empty arena. Chunks are allocated on first use.
+func New() *Arena {
+	return &Arena{}
+}
+
+// String copies b into the arena and returns a string backed by arena memory.
+func (a *Arena) String(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	if len(b) > cap(a.bytes)-len(a.bytes) {
+		a.bytes = make([]byte, 0, max(byteChunkSize, len(b)))
+	}
+
+	start := len(a.bytes)
+	a.bytes = append(a.bytes, b...)
+	return unsafe.String(&a.bytes[start], len(b))
+}
+
+// Values returns a slice of n elements backed by arena memory. The slice's
+// capacity is exactly n, so appending to it reallocates on the heap instead of
+// overwriting neighboring allocations.
+func (a *Arena) Values(n int) []any {
+	if n == 0 {
+		return nil
+	}
+
+	if n > cap(a.values)-len(a.values) {
+		a.values = make([]any, 0, max(valueChunkSize, n))
+	}
+
+	start := len(a.values)
+	a.values = a.values[:start+n]
+	return a.values[start : start+n : start+n]
+}
+
+// Reset frees all allocations at once so the current chunks can be reused.
+// Values previously returned by the arena must not be used afterwards.
+func (a *Arena) Reset() {
+	// Drop references held by array elements so the GC can reclaim maps and
+	// boxed numbers from the previous document.
+	clear(a.values)
+	a.values = a.values[:0]
+	a.bytes = a.bytes[:0]
+}
@@ -0,0 +1,89 @@
+package arena
+
+import "testing"
+
+func TestArena_String(t *testing.T) {
+	a := New()
+
+	first := a.String([]byte("hello"))
+	second := a.String([]byte("world"))
+
+	if first != "hello" || second != "world" {
+		t.Errorf("expected hello and world, got %q and %q", first, second)
+	}
+	if a.String(nil) != "" {
+		t.Error("expected empty string for empty input")
+	}
+}
+
+func TestArena_StringCopiesInput(t *testing.T) {
+	a := New()
+	input := []byte("abc")
+
+	s := a.String(input)
+	input[0] = 'x'
+
+	if s != "abc" {
+		t.Errorf("expected arena string to be independent of input, got %q", s)
+	}
+}
+
+func TestArena_StringLargerThanChunk(t *testing.T) {
+	a := New()
+	large := make([]byte, byteChunkSize+1)
+	for i := range large {
+		large[i] = 'a'
+	}
+
+	if s := a.String(large); len(s) != len(large) {
+		t.Errorf("expected length %d, got %d", len(large), len(s))
+	}
+}
+
+func TestArena_Values(t *testing.T) {
+	a := New()
+
+	first := a.Values(2)
+	second := a.Values(3)
+
+	if len(first) != 2 || cap(first) != 2 {
+		t.Errorf("expected len and cap 2, got %d and %d", len(first), cap(first))
+	}
+	if len(second) != 3 {
+		t.Errorf("expected len 3, got %d", len(second))
+	}
+
+	// Appending past the capacity must not overwrite the next allocation.
+	second[0] = "kept"
+	_ = append(first, "overflow")
+	if second[0] != "kept" {
+		t.Errorf("append overwrote neighboring allocation: %v", second[0])
+	}
+
+	if a.Values(0) != nil {
+		t.Error("expected nil slice for zero elements")
+	}
+}
+
+func TestArena_Reset(t *testing.T) {
+	a := New()
+
+	values := a.Values(1)
+	values[0] = "old"
+	a.String([]byte("old"))
+
+	a.Reset()
+
+	if len(a.values) != 0 || len(a.bytes) != 0 {
+		t.Errorf("expected empty chunks after reset, got %d values and %d bytes", len(a.values), len(a.bytes))
+	}
+	if values[0] != nil {
+		t.Errorf("expected reset to clear element references, got %v", values[0])
+	}
+
+	// The chunks are reused rather than reallocated.
+	reused := a.Values(1)
+	if &reused[0] != &values[0] {
+		t.Error("expected the value chunk to be reused after reset")
+	}
+}
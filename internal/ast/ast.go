@@ -0,0 +1,150 @@
+// Package ast builds a position-annotated parse tree of a JSON document, for
+// tools that need to know where each value, key and member appears in the
+// source, not just what it is.
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// Kind identifies the kind of a Node.
+type Kind string
+
+const (
+	Object  Kind = "object"  // children are members
+	Array   Kind = "array"   // children are the elements
+	Member  Kind = "member"  // children are the key and the value
+	Key     Kind = "key"     // Value is the decoded key
+	String  Kind = "string"  // Value is the decoded string
+	Number  Kind = "number"  // Value is an int64 or float64
+	Boolean Kind = "boolean" // Value is a bool
+	Null    Kind = "null"
+)
+
+// Span is the extent of a node in the source.
+type Span struct {
+	Start lexer.Position `json:"start"`
+	End   lexer.Position `json:"end"` // just past the node
+}
+
+// Node is a node of the parse tree. Its fields carry json tags, so encoding
+// a tree with encoder.Marshal exports it for tools in other languages, e.g.
+//
+//	{"kind": "member", "span": {...}, "children": [{"kind": "key", "value": "id", ...}, ...]}
+type Node struct {
+	Kind     Kind             `json:"kind"`
+	Span     Span             `json:"span"`
+	Value    parser.JSONValue `json:"value,omitzero"`     // scalar value of keys, strings, numbers and booleans
+	Children []*Node          `json:"children,omitempty"` // members, elements, or key and value
+}
+
+// Parse returns the parse tree of input. The lexer options, such as
+// lexer.WithNumberExtensions, select the accepted syntax.
+func Parse(input string, opts ...lexer.Option) (*Node, error) {
+	b := builder{events: stream.NewEventReader(lexer.New(input, opts...))}
+	root, err := b.nextNode()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.events.Next(); !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return root, nil
+}
+
+// builder assembles nodes from an event stream.
+type builder struct {
+	events stream.EventReader
+}
+
+// nextNode reads the events of the next value and returns its node.
+func (b *builder) nextNode() (*Node, error) {
+	event, err := b.events.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.node(event)
+}
+
+// node returns the node of the value starting with event, reading the rest
+// of its events.
+func (b *builder) node(event stream.Event) (*Node, error) {
+	node := &Node{Span: Span{Start: event.Position, End: event.End}}
+	var err error
+	switch event.Type {
+	case stream.StartObject:
+		node.Kind = Object
+		return node, b.readMembers(node)
+	case stream.StartArray:
+		node.Kind = Array
+		return node, b.readElements(node)
+	case stream.String:
+		node.Kind, node.Value = String, event.Value
+	case stream.Number:
+		node.Kind = Number
+		if node.Value, err = parser.ParseNumber(event.Value); err != nil {
+			return nil, err
+		}
+	case stream.Boolean:
+		node.Kind, node.Value = Boolean, event.Value == "true"
+	case stream.Null:
+		node.Kind = Null
+	default:
+		return nil, fmt.Errorf("unexpected %s event at %s", event.Type, event.Position)
+	}
+	return node, nil
+}
+
+// readMembers reads the members of obj up to its end.
+func (b *builder) readMembers(obj *Node) error {
+	for {
+		event, err := b.events.Next()
+		if err != nil {
+			return err
+		}
+		if event.Type == stream.EndObject {
+			obj.Span.End = event.End
+			return nil
+		}
+
+		key := &Node{Kind: Key, Span: Span{Start: event.Position, End: event.End}, Value: event.Value}
+		value, err := b.nextNode()
+		if err != nil {
+			return err
+		}
+		obj.Children = append(obj.Children, &Node{
+			Kind:     Member,
+			Span:     Span{Start: key.Span.Start, End: value.Span.End},
+			Children: []*Node{key, value},
+		})
+	}
+}
+
+// readElements reads the elements of arr up to its end.
+func (b *builder) readElements(arr *Node) error {
+	for {
+		event, err := b.events.Next()
+		if err != nil {
+			return err
+		}
+		if event.Type == stream.EndArray {
+			arr.Span.End = event.End
+			return nil
+		}
+
+		elem, err := b.node(event)
+		if err != nil {
+			return err
+		}
+		arr.Children = append(arr.Children, elem)
+	}
+}
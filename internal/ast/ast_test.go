@@ -0,0 +1,113 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// describe renders node as "kind[start-end]" offsets with its value and
+// children, e.g. `array[0-3](number[1-2]=1)`.
+func describe(node *Node) string {
+	var b strings.Builder
+	b.WriteString(string(node.Kind))
+	b.WriteString("[")
+	b.WriteString(strconv.Itoa(node.Span.Start.Offset) + "-" + strconv.Itoa(node.Span.End.Offset))
+	b.WriteString("]")
+	if node.Value != nil {
+		value, _ := encoder.Marshal(node.Value)
+		b.WriteString("=" + string(value))
+	}
+	if len(node.Children) > 0 {
+		parts := make([]string, len(node.Children))
+		for i, child := range node.Children {
+			parts[i] = describe(child)
+		}
+		b.WriteString("(" + strings.Join(parts, " ") + ")")
+	}
+	return b.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "scalar", input: ` "x" `, expected: `string[1-4]="x"`},
+		{name: "number", input: `-1.5`, expected: `number[0-4]=-1.5`},
+		{name: "false and null", input: `[false,null]`, expected: `array[0-12](boolean[1-6]=false null[7-11])`},
+		{
+			name:     "object",
+			input:    `{"a": [1], "b": {}}`,
+			expected: `object[0-19](member[1-9](key[1-4]="a" array[6-9](number[7-8]=1)) member[11-18](key[11-14]="b" object[16-18]))`,
+		},
+		{name: "empty array", input: `[]`, expected: `array[0-2]`},
+		{name: "escaped key", input: `{"a\nb": 0}`, expected: `object[0-11](member[1-10](key[1-7]="a\nb" number[9-10]=0))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describe(tree); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParse_Positions(t *testing.T) {
+	tree, err := Parse("{\n  \"id\": 7\n}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	member := tree.Children[0]
+	start, end := member.Span.Start, member.Span.End
+	if start.Line != 2 || start.Column != 3 || end.Line != 2 || end.Column != 10 {
+		t.Errorf("expected member at 2:3-2:10, got %d:%d-%d:%d", start.Line, start.Column, end.Line, end.Column)
+	}
+}
+
+func TestParse_LexerOptions(t *testing.T) {
+	tree, err := Parse(`0xFF`, lexer.WithNumberExtensions(lexer.HexNumbers))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tree.Value != int64(255) {
+		t.Errorf("expected 255, got %v", tree.Value)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{``, `{"a": }`, `[1, 2`, `{} []`, `{"a" 1}`} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
+
+func TestNode_Export(t *testing.T) {
+	tree, err := Parse(`[true]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := encoder.Marshal(tree)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"kind":"array","span":{"start":{"line":1,"column":1,"offset":0},"end":{"line":1,"column":7,"offset":6}},` +
+		`"children":[{"kind":"boolean","span":{"start":{"line":1,"column":2,"offset":1},"end":{"line":1,"column":6,"offset":5}},"value":true}]}`
+	if string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+}
@@ -0,0 +1,114 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/decoder"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Import reads a tree from its exported form, the parsed output of encoding
+// a Node (e.g. by `json-parser ast`), so tools in other languages can edit
+// the tree and hand it back. Spans are optional, so new nodes don't need
+// positions. Import fails unless the tree is well-formed; see ValueOf.
+func Import(doc parser.JSONValue) (*Node, error) {
+	var node Node
+	if err := decoder.Decode(doc, &node); err != nil {
+		return nil, err
+	}
+	if _, err := ValueOf(&node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// ValueOf returns the JSON value that the tree rooted at n represents. It
+// fails if the tree is malformed: an unknown kind, an object child that isn't
+// a member, a member without exactly a key and a value, or a scalar value of
+// the wrong type. Errors name the offending node by its JSON Pointer in the
+// exported tree.
+func ValueOf(n *Node) (parser.JSONValue, error) {
+	return valueOf(n, "")
+}
+
+// valueOf is ValueOf for the node at path in the exported tree.
+func valueOf(n *Node, path string) (parser.JSONValue, error) {
+	if n == nil {
+		return nil, nodeError(path, "missing node")
+	}
+
+	switch n.Kind {
+	case Object:
+		obj := parser.NewJSONObject()
+		for i, member := range n.Children {
+			memberPath := childPath(path, i)
+			if member == nil || member.Kind != Member {
+				return nil, nodeError(memberPath, "object children must be members")
+			}
+			if len(member.Children) != 2 {
+				return nil, nodeError(memberPath, "members must have a key and a value")
+			}
+			key := member.Children[0]
+			name, ok := key.Value.(string)
+			if key.Kind != Key || !ok {
+				return nil, nodeError(childPath(memberPath, 0), "the first child of a member must be a key with a string value")
+			}
+			value, err := valueOf(member.Children[1], childPath(memberPath, 1))
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = value
+		}
+		return obj, nil
+	case Array:
+		var arr []any
+		for i, elem := range n.Children {
+			value, err := valueOf(elem, childPath(path, i))
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		return arr, nil
+	case String:
+		if _, ok := n.Value.(string); !ok {
+			return nil, nodeError(path, "string nodes must have a string value")
+		}
+	case Number:
+		switch n.Value.(type) {
+		case int64, float64:
+		default:
+			return nil, nodeError(path, "number nodes must have a number value")
+		}
+	case Boolean:
+		if _, ok := n.Value.(bool); !ok {
+			return nil, nodeError(path, "boolean nodes must have a boolean value")
+		}
+	case Null:
+		if n.Value != nil {
+			return nil, nodeError(path, "null nodes must not have a value")
+		}
+	case Member, Key:
+		return nil, nodeError(path, fmt.Sprintf("%s nodes may only appear in objects", n.Kind))
+	default:
+		return nil, nodeError(path, fmt.Sprintf("unknown kind %q", n.Kind))
+	}
+	if len(n.Children) > 0 {
+		return nil, nodeError(path, fmt.Sprintf("%s nodes must not have children", n.Kind))
+	}
+	return n.Value, nil
+}
+
+// childPath returns the path of child i of the node at path.
+func childPath(path string, i int) string {
+	return path + "/children/" + strconv.Itoa(i)
+}
+
+// nodeError returns an error about the node at path.
+func nodeError(path, message string) error {
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Errorf("invalid tree at %s: %s", path, message)
+}
@@ -0,0 +1,111 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParseJSON(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestImport_RoundTrip(t *testing.T) {
+	input := `{"a": [1, -2.5, "", false, null], "": {"b": {}}, "c": []}`
+	tree, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exported, err := encoder.Marshal(tree)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	imported, err := Import(mustParseJSON(t, string(exported)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := describe(imported), describe(tree); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	value, err := ValueOf(imported)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := encoder.Marshal(value)
+	want, _ := encoder.Marshal(mustParseJSON(t, input))
+	if string(got) != string(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestImport_WithoutSpans(t *testing.T) {
+	doc := `{"kind": "array", "children": [{"kind": "string", "value": "x"}, {"kind": "number", "value": 2}]}`
+	tree, err := Import(mustParseJSON(t, doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, err := ValueOf(tree)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := encoder.Marshal(value); string(got) != `["x",2]` {
+		t.Errorf(`expected ["x",2], got %s`, got)
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{name: "not an object", doc: `[]`, wantErr: "cannot"},
+		{name: "unknown kind", doc: `{"kind": "tuple"}`, wantErr: `(root): unknown kind "tuple"`},
+		{name: "missing kind", doc: `{}`, wantErr: `unknown kind ""`},
+		{
+			name:    "object child not a member",
+			doc:     `{"kind": "object", "children": [{"kind": "null"}]}`,
+			wantErr: "/children/0: object children must be members",
+		},
+		{
+			name:    "member without value",
+			doc:     `{"kind": "object", "children": [{"kind": "member", "children": [{"kind": "key", "value": "a"}]}]}`,
+			wantErr: "/children/0: members must have a key and a value",
+		},
+		{
+			name:    "key not a string",
+			doc:     `{"kind": "object", "children": [{"kind": "member", "children": [{"kind": "key", "value": 1}, {"kind": "null"}]}]}`,
+			wantErr: "/children/0/children/0: the first child of a member",
+		},
+		{
+			name:    "nested value",
+			doc:     `{"kind": "array", "children": [{"kind": "array", "children": [{"kind": "number", "value": "1"}]}]}`,
+			wantErr: "/children/0/children/0: number nodes must have a number value",
+		},
+		{name: "null with value", doc: `{"kind": "null", "value": 0}`, wantErr: "null nodes must not have a value"},
+		{name: "scalar with children", doc: `{"kind": "boolean", "value": true, "children": [{"kind": "null"}]}`, wantErr: "boolean nodes must not have children"},
+		{name: "member outside object", doc: `{"kind": "member"}`, wantErr: "member nodes may only appear in objects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Import(mustParseJSON(t, tt.doc))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/ast"
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// runAST implements `json-parser ast [--compact] <file>`, which prints the
+// position-annotated parse tree of the document as JSON: every node has a
+// kind (object, array, member, key, string, number, boolean or null), a span
+// of start and end positions, and its children or scalar value. External
+// tools in any language can use it to map values back to the source.
+// With --import the file is such a tree instead, possibly edited, and the
+// document it represents is printed, so tools can compute edits on the tree
+// and leave serialization to this package.
+func (inv *invocation) runAST(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ast", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	compact := fs.Bool("compact", false, "print the output on a single line")
+	importTree := fs.Bool("import", false, "read an exported tree and print the document it represents")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: ast [--compact] [--import] <filename>")
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	content, err := inv.fileReader().ReadFile(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+
+	var result any
+	if *importTree {
+		result, err = inv.importAST(content)
+	} else {
+		result, err = ast.Parse(content)
+	}
+	if err != nil {
+		printError(stderr, "%s: %v", filename, err)
+		return ExitInvalid
+	}
+
+	var output []byte
+	if *compact {
+		output, err = encoder.Marshal(result)
+	} else {
+		output, err = encoder.MarshalIndent(result, "", "  ")
+	}
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout, string(output))
+	return ExitSuccess
+}
+
+// importAST returns the document represented by the exported tree in content.
+func (inv *invocation) importAST(content string) (parser.JSONValue, error) {
+	doc, err := inv.newHandler().ParseStringValue(content)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := ast.Import(doc)
+	if err != nil {
+		return nil, err
+	}
+	return ast.ValueOf(tree)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAST(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	valid := writeFile("valid.json", `{"a": 1}`)
+	invalid := writeFile("invalid.json", `{"a": }`)
+	tree := writeFile("tree.json", `{"kind": "object", "children": [{"kind": "member", "children": [{"kind": "key", "value": "a"}, {"kind": "boolean", "value": false}]}]}`)
+	malformedTree := writeFile("malformed.json", `{"kind": "member"}`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStdout string
+	}{
+		{
+			name:         "compact",
+			args:         []string{"ast", "--compact", valid},
+			expectedExit: ExitSuccess,
+			expectedStdout: `{"kind":"object","span":{"start":{"line":1,"column":1,"offset":0},"end":{"line":1,"column":9,"offset":8}},"children":[` +
+				`{"kind":"member","span":{"start":{"line":1,"column":2,"offset":1},"end":{"line":1,"column":8,"offset":7}},"children":[` +
+				`{"kind":"key","span":{"start":{"line":1,"column":2,"offset":1},"end":{"line":1,"column":5,"offset":4}},"value":"a"},` +
+				`{"kind":"number","span":{"start":{"line":1,"column":7,"offset":6},"end":{"line":1,"column":8,"offset":7}},"value":1}]}]}` + "\n",
+		},
+		{name: "indented", args: []string{"ast", valid}, expectedExit: ExitSuccess, expectedStdout: "{\n  \"kind\": \"object\",\n"},
+		{name: "import", args: []string{"ast", "--import", "--compact", tree}, expectedExit: ExitSuccess, expectedStdout: `{"a":false}` + "\n"},
+		{name: "import indented", args: []string{"ast", "--import", tree}, expectedExit: ExitSuccess, expectedStdout: "{\n  \"a\": false\n}\n"},
+		{name: "import malformed tree", args: []string{"ast", "--import", malformedTree}, expectedExit: ExitInvalid},
+		{name: "import invalid JSON", args: []string{"ast", "--import", invalid}, expectedExit: ExitInvalid},
+		{name: "invalid", args: []string{"ast", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"ast", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"ast"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.expectedStdout) {
+				t.Errorf("expected stdout to start with:\n%s\ngot:\n%s", tt.expectedStdout, stdout.String())
+			}
+		})
+	}
+}
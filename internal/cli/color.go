@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+)
+
+// ANSI SGR sequences used to decorate output.
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+	colorBold   = "1"
+)
+
+// colorMode is the --color setting.
+type colorMode int
+
+const (
+	colorAuto   colorMode = iota // color only when writing to a terminal
+	colorAlways                  // color even when piped or redirected
+	colorNever                   // never color
+)
+
+// colorModes maps --color values to color modes.
+var colorModes = map[string]colorMode{
+	"auto":   colorAuto,
+	"always": colorAlways,
+	"never":  colorNever,
+}
+
+// colorWriter is an output stream together with whether it may be decorated
+// with ANSI colors and whether errors are written to it as JSON. run wraps
+// stdout and stderr in it once the --color and --error-format settings and
+// the terminal are known, so subcommands keep taking io.Writer.
+type colorWriter struct {
+	io.Writer
+	color      bool
+	jsonErrors bool // --error-format json
+}
+
+// colorize wraps s in the given SGR codes if w accepts colors.
+func colorize(w io.Writer, s string, codes ...string) string {
+	if cw, ok := w.(*colorWriter); !ok || !cw.color {
+		return s
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + s + "\x1b[0m"
+}
+
+// printError writes an "Error: ..." line to w, highlighting the prefix when
+// w accepts colors. With --error-format json it writes a JSON object on one
+// line instead (see errorObject), detailing the first error among args.
+func printError(w io.Writer, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if cw, ok := w.(*colorWriter); ok && cw.jsonErrors {
+		var err error
+		for _, arg := range args {
+			if e, ok := arg.(error); ok {
+				err = e
+				break
+			}
+		}
+		data, _ := encoder.Marshal(errorObject(message, err))
+		fmt.Fprintf(w, "%s\n", data)
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", colorize(w, "Error:", colorBold, colorRed), message)
+}
+
+// extractColorFlags removes the global --color=auto|always|never and
+// --no-color flags from args, wherever they appear before a "--" terminator,
+// and returns the selected mode and the remaining arguments. The last flag
+// wins.
+func extractColorFlags(args []string) (colorMode, []string, error) {
+	mode := colorAuto
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || (name != "color" && name != "no-color") {
+			rest = append(rest, arg)
+			continue
+		}
+		if name == "no-color" {
+			if hasValue {
+				return colorAuto, nil, fmt.Errorf("--no-color does not take a value")
+			}
+			mode = colorNever
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return colorAuto, nil, fmt.Errorf("--color requires a value (auto, always or never)")
+			}
+			i++
+			value = args[i]
+		}
+		m, ok := colorModes[value]
+		if !ok {
+			return colorAuto, nil, fmt.Errorf("invalid --color value %q (expected auto, always or never)", value)
+		}
+		mode = m
+	}
+	return mode, rest, nil
+}
+
+// useColor decides whether w gets colored output. An explicit always or
+// never wins; in auto mode color is used only when w is a terminal and
+// neither NO_COLOR (https://no-color.org) nor TERM=dumb asks for plain
+// output.
+func useColor(mode colorMode, w io.Writer) bool {
+	switch mode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file connected to a terminal, as opposed
+// to a pipe, a regular file or an in-memory buffer.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
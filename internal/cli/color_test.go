@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractColorFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedMode colorMode
+		expectedArgs []string
+		expectError  bool
+	}{
+		{name: "none", args: []string{"lint", "a.json"}, expectedMode: colorAuto, expectedArgs: []string{"lint", "a.json"}},
+		{name: "before command", args: []string{"--color=always", "lint", "a.json"}, expectedMode: colorAlways, expectedArgs: []string{"lint", "a.json"}},
+		{name: "after command", args: []string{"lint", "a.json", "--color", "never"}, expectedMode: colorNever, expectedArgs: []string{"lint", "a.json"}},
+		{name: "no-color", args: []string{"-no-color", "a.json"}, expectedMode: colorNever, expectedArgs: []string{"a.json"}},
+		{name: "last wins", args: []string{"--no-color", "--color=auto", "a.json"}, expectedMode: colorAuto, expectedArgs: []string{"a.json"}},
+		{name: "after terminator", args: []string{"sort", "--", "--no-color"}, expectedMode: colorAuto, expectedArgs: []string{"sort", "--", "--no-color"}},
+		{name: "other flags kept", args: []string{"lint", "--on-warning=fail", "a.json"}, expectedMode: colorAuto, expectedArgs: []string{"lint", "--on-warning=fail", "a.json"}},
+		{name: "invalid value", args: []string{"--color=sometimes", "a.json"}, expectError: true},
+		{name: "missing value", args: []string{"a.json", "--color"}, expectError: true},
+		{name: "no-color with value", args: []string{"--no-color=true", "a.json"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, args, err := extractColorFlags(tt.args)
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got mode %d and args %q", mode, args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mode != tt.expectedMode {
+				t.Errorf("expected mode %d, got %d", tt.expectedMode, mode)
+			}
+			if !slices.Equal(args, tt.expectedArgs) {
+				t.Errorf("expected args %q, got %q", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+
+	t.Run("buffer is not a terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		if useColor(colorAuto, &buf) {
+			t.Error("expected no color for a non-terminal writer")
+		}
+	})
+
+	t.Run("regular file is not a terminal", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if useColor(colorAuto, f) {
+			t.Error("expected no color for a regular file")
+		}
+	})
+
+	t.Run("always overrides NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		if !useColor(colorAlways, &buf) {
+			t.Error("expected --color=always to force color")
+		}
+	})
+
+	t.Run("never", func(t *testing.T) {
+		if useColor(colorNever, &buf) {
+			t.Error("expected --color=never to disable color")
+		}
+	})
+}
+
+func TestRun_Color(t *testing.T) {
+	tempDir := t.TempDir()
+	warnings := filepath.Join(tempDir, "warnings.json")
+	if err := os.WriteFile(warnings, []byte(`{"tags": ["a", 1]}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	missing := filepath.Join(tempDir, "missing.json")
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectColor bool
+	}{
+		{name: "auto when piped", args: []string{"lint", warnings}},
+		{name: "always", args: []string{"--color=always", "lint", warnings}, expectColor: true},
+		{name: "always after file", args: []string{"lint", warnings, "--color", "always"}, expectColor: true},
+		{name: "no-color", args: []string{"--no-color", "lint", warnings}},
+		{name: "error prefix", args: []string{"--color=always", missing}, expectColor: true},
+		{name: "error prefix without color", args: []string{missing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			run("json-parser", tt.args, &stdout, &stderr)
+			output := stdout.String() + stderr.String()
+			if output == "" {
+				t.Fatal("expected output")
+			}
+			if got := strings.Contains(output, "\x1b["); got != tt.expectColor {
+				t.Errorf("expected color %v, got output %q", tt.expectColor, output)
+			}
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"--color=rainbow", warnings}, &stdout, &stderr); exitCode != ExitInvalid {
+			t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+		}
+		if !strings.Contains(stderr.String(), "invalid --color value") {
+			t.Errorf("unexpected stderr: %s", stderr.String())
+		}
+	})
+}
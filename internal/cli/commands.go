@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// command describes a CLI subcommand such as `json-parser sort file.json`.
+type command struct {
+	name        string
+	description string
+	run         func(inv *invocation, args []string, stdout, stderr io.Writer) int
+}
+
+// commands lists the available subcommands. Invocations that don't start with
+// one of these names fall back to validating the given file.
+var commands = []command{
+	{name: "validate", description: "Validate a document, optionally against an expected document", run: (*invocation).runValidate},
+	{name: "lint", description: "Report likely problems in a valid document", run: (*invocation).runLint},
+	{name: "format", description: "Pretty-print a document, keeping its member order", run: (*invocation).runFormat},
+	{name: "sort", description: "Print a document with recursively sorted keys", run: (*invocation).runSort},
+	{name: "hash", description: "Print the SHA-256 digest of the canonicalized document", run: (*invocation).runHash},
+	{name: "diff", description: "Print the structural differences between two documents", run: (*invocation).runDiff},
+	{name: "wrap", description: "Print the values of several files as one JSON array", run: (*invocation).runWrap},
+	{name: "split", description: "Split a top-level array into files of N elements", run: (*invocation).runSplit},
+	{name: "join", description: "Combine arrays or objects from several files into one", run: (*invocation).runJoin},
+	{name: "convert", description: "Convert between a JSON array, JSON Lines and JSON text sequences", run: (*invocation).runConvert},
+	{name: "edit", description: "Set, delete or rename values at JSON Pointers while streaming", run: (*invocation).runEdit},
+	{name: "patch", description: "Apply a JSON Patch (RFC 6902) to a document", run: (*invocation).runPatch},
+	{name: "index", description: "Print the byte ranges of the values at a depth", run: (*invocation).runIndex},
+	{name: "query", description: "Print the values selected by a path expression", run: (*invocation).runQuery},
+	{name: "find", description: "Print the paths of keys and values matching patterns", run: (*invocation).runFind},
+	{name: "stats", description: "Print structure statistics and estimated memory use", run: (*invocation).runStats},
+	{name: "ast", description: "Print the position-annotated parse tree as JSON", run: (*invocation).runAST},
+	{name: "gen-types", description: "Generate Go types with json tags from example documents", run: (*invocation).runGenTypes},
+}
+
+// findCommand returns the subcommand with the given name, if any.
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// run dispatches the command line arguments (without the program name) and
+// returns the process exit code. The global --color, --no-color,
+// --stdin-timeout, --fetch-timeout, --allow-comments, --error-format,
+// --max-depth, --max-bytes and --max-string-len flags may appear anywhere and
+// are handled here, before dispatching.
+func run(program string, args []string, stdout, stderr io.Writer) int {
+	// --error-format comes first so that it applies to the other flags' errors.
+	inv := newInvocation()
+	errorFormat, args, err := extractErrorFormat(args)
+	jsonErrors := errorFormat == "json"
+	mode := colorAuto
+	if err == nil {
+		mode, args, err = extractColorFlags(args)
+	}
+	if err == nil {
+		inv.stdinTimeout, args, err = extractStdinTimeout(args)
+	}
+	if err == nil {
+		inv.fetchTimeout, args, err = extractFetchTimeout(args)
+	}
+	if err == nil {
+		inv.allowComments, args, err = extractAllowComments(args)
+	}
+	if err == nil {
+		inv.limits, args, err = extractLimits(args)
+	}
+	profile := ""
+	if err == nil {
+		profile, args, err = extractProfile(args)
+	}
+	if err == nil {
+		inv.config, err = discoverConfig()
+	}
+	if err != nil {
+		printError(&colorWriter{Writer: stderr, jsonErrors: jsonErrors}, "%v", err)
+		return ExitInvalid
+	}
+	// Flags override the project config.
+	inv.applyProfile(cmp.Or(profile, inv.config.Profile))
+	stdout = &colorWriter{Writer: stdout, color: useColor(mode, stdout), jsonErrors: jsonErrors}
+	stderr = &colorWriter{Writer: stderr, color: useColor(mode, stderr), jsonErrors: jsonErrors}
+
+	if len(args) < 1 {
+		printUsage(program, stderr)
+		return ExitInvalid
+	}
+
+	if cmd, ok := findCommand(args[0]); ok {
+		return cmd.run(inv, args[1:], stdout, stderr)
+	}
+	return inv.runDefault(program, args, stdout, stderr)
+}
+
+// printUsage writes the top-level usage message.
+func printUsage(program string, w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [--stream] [--progress] [--schema schema.json] [--print] [--output pretty|compact|go] <filename>  (- reads standard input)\n", program)
+	fmt.Fprintf(w, "       %s <command> [flags] <args>\n", program)
+	fmt.Fprintln(w, "Filenames may also be http(s) URLs, which are downloaded.")
+	fmt.Fprintf(w, "Defaults are read from the first %s or %s found in the working directory or above.\n", configNames[0], configNames[1])
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(w, "\nGlobal flags:")
+	fmt.Fprintf(w, "  %-26s %s\n", "--color=auto|always|never", "colorize output (default auto: only on a terminal without NO_COLOR)")
+	fmt.Fprintf(w, "  %-26s %s\n", "--no-color", "same as --color=never")
+	fmt.Fprintf(w, "  %-26s %s\n", "--stdin-timeout=DURATION", "fail if standard input (-) is idle this long (default: wait)")
+	fmt.Fprintf(w, "  %-26s %s\n", "--fetch-timeout=DURATION", "fail downloads of http(s) URL arguments taking longer (default 30s, 0: no limit)")
+	fmt.Fprintf(w, "  %-26s %s\n", "--profile=NAME", "syntax of documents parsed whole: "+profileChoices()+" (default: RFC 8259, skipping a byte order mark)")
+	fmt.Fprintf(w, "  %-26s %s\n", "", "--profile=strict also rejects a byte order mark, duplicate keys and invalid Unicode")
+	fmt.Fprintf(w, "  %-26s %s\n", "--i-json", "same as --profile=i-json: no duplicate keys, imprecise numbers, invalid Unicode or top-level scalars")
+	fmt.Fprintf(w, "  %-26s %s\n", "--allow-comments", "accept // and /* */ comments (JSONC) in documents that are parsed whole")
+	fmt.Fprintf(w, "  %-26s %s\n", "--max-depth=N", "reject documents nesting objects and arrays deeper than N levels")
+	fmt.Fprintf(w, "  %-26s %s\n", "--max-bytes=N", "reject documents, also downloaded ones, longer than N bytes without reading further")
+	fmt.Fprintf(w, "  %-26s %s\n", "--max-string-len=N", "reject strings and keys longer than N bytes")
+	fmt.Fprintf(w, "  %-26s %s\n", "--error-format=text|json", "print errors as text (default) or as one JSON object per line")
+}
+
+// parseFlags parses flags that may appear anywhere among the positional
+// arguments (e.g. `sort file.json --arrays`), which the flag package alone
+// doesn't allow, and returns the positional arguments in order.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// flagErrorExitCode maps a flag parsing error to an exit code: asking for
+// help is not a failure.
+func flagErrorExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitSuccess
+	}
+	return ExitInvalid
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic numbers starting compressed streams.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompress returns a reader of the content of r, which was opened from
+// name, decompressing it on the fly if it is gzip- or zstd-compressed: if name
+// ends in .gz or .zst or, for other names such as "-", if the content starts
+// with the format's magic number. Closing the returned reader closes r.
+func decompress(r io.ReadCloser, name string) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+
+	var (
+		dr  io.ReadCloser
+		err error
+	)
+	switch {
+	case strings.HasSuffix(name, ".gz") || !strings.HasSuffix(name, ".zst") && bytes.HasPrefix(magic, gzipMagic):
+		dr, err = gzip.NewReader(br)
+	case strings.HasSuffix(name, ".zst") || bytes.HasPrefix(magic, zstdMagic):
+		var d *zstd.Decoder
+		if d, err = zstd.NewReader(br, zstd.WithDecoderConcurrency(1)); err == nil {
+			dr = d.IOReadCloser()
+		}
+	default:
+		return &readCloser{Reader: br, closers: []io.Closer{r}}, nil
+	}
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("cannot decompress '%s': %w", name, err)
+	}
+	return &readCloser{Reader: dr, closers: []io.Closer{dr, r}}, nil
+}
+
+// readCloser reads from a decompressing reader and closes it together with
+// the underlying file.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close implements io.Closer, closing every closer in order.
+func (rc *readCloser) Close() error {
+	var errs []error
+	for _, c := range rc.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestFileReader_Compressed(t *testing.T) {
+	const content = `{"level": "info", "msg": "started"}`
+	tempDir := t.TempDir()
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(content))
+	gw.Close()
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	zst := zw.EncodeAll([]byte(content), nil)
+	zw.Close()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{name: "plain", file: writeFile("plain.json", []byte(content))},
+		{name: "gzip by extension", file: writeFile("log.json.gz", gz.Bytes())},
+		{name: "zstd by extension", file: writeFile("log.json.zst", zst)},
+		{name: "gzip by magic number", file: writeFile("gzip.json", gz.Bytes())},
+		{name: "zstd by magic number", file: writeFile("zstd.json", zst)},
+		{name: "not gzip", file: writeFile("plain.json.gz", []byte(content)), wantErr: true},
+		{name: "truncated zstd", file: writeFile("truncated.json.zst", zst[:len(zst)/2]), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFileReader().ReadFile(tt.file)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != content {
+				t.Errorf("expected %q, got %q", content, got)
+			}
+		})
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"--stream", filepath.Join(tempDir, "log.json.zst")}, &stdout, &stderr); exitCode != ExitSuccess {
+			t.Errorf("expected exit code %d, got %d (stderr: %s)", ExitSuccess, exitCode, stderr.String())
+		}
+		if exitCode := run("json-parser", []string{filepath.Join(tempDir, "plain.json.gz")}, &stdout, &stderr); exitCode != ExitFileError {
+			t.Errorf("expected exit code %d, got %d (stderr: %s)", ExitFileError, exitCode, stderr.String())
+		}
+	})
+}
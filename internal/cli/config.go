@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/VuNe/json-parser/internal/decoder"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// configNames are the names of project config files, looked up in this order
+// in the working directory and then in each of its parents.
+var configNames = []string{".jsonparser.yml", "jsonparser.json"}
+
+// projectConfig holds the settings of a project config file, which provide
+// defaults for flags so that everyone working on a project gets the same
+// behavior:
+//
+//	profile: permissive  # default --profile
+//	indent: 4          # default format --indent
+//	ignore:            # files skipped when expanding patterns and directories
+//	  - node_modules
+//	  - "*.min.json"
+type projectConfig struct {
+	Profile string   `json:"profile" yaml:"profile"`
+	Indent  *int     `json:"indent" yaml:"indent"`
+	Ignore  []string `json:"ignore" yaml:"ignore"`
+
+	dir string // the directory of the config file, which Ignore is relative to
+}
+
+// configKeys are the settings a config file may contain.
+var configKeys = []string{"profile", "indent", "ignore"}
+
+// profileChoices lists the --profile names, those of parser.Profile, for
+// messages.
+func profileChoices() string {
+	names := parser.ProfileNames()
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// findConfig returns the path of the project config file for dir: the first
+// of configNames found in dir or its closest parent, or "" if there is none.
+func findConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range configNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// discoverConfig loads the project config file for the working directory, if
+// there is one (see findConfig).
+func discoverConfig() (projectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return projectConfig{}, err
+	}
+	path, err := findConfig(wd)
+	if err != nil || path == "" {
+		return projectConfig{}, err
+	}
+	return loadConfig(path)
+}
+
+// loadConfig reads and checks the config file at path, in JSON if its name
+// ends in .json and in YAML otherwise. Unknown settings are an error, so that
+// typos don't go unnoticed.
+func loadConfig(path string) (projectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return projectConfig{}, fmt.Errorf("failed to read config file '%s': %w", path, err)
+	}
+
+	var c projectConfig
+	if filepath.Ext(path) == ".json" {
+		err = decodeJSONConfig(data, &c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&c); errors.Is(err, io.EOF) {
+			err = nil // an empty file
+		}
+	}
+	if err == nil {
+		err = c.check()
+	}
+	if err != nil {
+		return projectConfig{}, fmt.Errorf("invalid config file '%s': %w", path, err)
+	}
+	c.dir = filepath.Dir(path)
+	return c, nil
+}
+
+// decodeJSONConfig decodes a JSON config file into c.
+func decodeJSONConfig(data []byte, c *projectConfig) error {
+	value, err := New().ParseStringValue(string(data))
+	if err != nil {
+		return err
+	}
+	var settings map[string]any
+	if err := decoder.Decode(value, &settings); err != nil {
+		return err
+	}
+	for key := range settings {
+		if !slices.Contains(configKeys, key) {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	return decoder.Decode(value, c)
+}
+
+// check reports invalid settings.
+func (c projectConfig) check() error {
+	if _, ok := parser.ParseProfile(c.Profile); c.Profile != "" && !ok {
+		return fmt.Errorf("invalid profile %q (expected %s)", c.Profile, profileChoices())
+	}
+	if c.Indent != nil && *c.Indent < 0 {
+		return fmt.Errorf("indent must not be negative")
+	}
+	for _, pattern := range c.Ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// indent returns the default format --indent.
+func (c projectConfig) indent() int {
+	if c.Indent == nil {
+		return 2
+	}
+	return *c.Indent
+}
+
+// ignored reports whether file is skipped by an ignore pattern. Patterns use
+// the syntax of path.Match with slashes as separators. A pattern containing a
+// slash is matched against the path of file relative to the config file's
+// directory and the paths of its parent directories, any other pattern against
+// the names of file and its parent directories, so a directory's name ignores
+// everything below it.
+func (c projectConfig) ignored(file string) bool {
+	if len(c.Ignore) == 0 || file == StdinName {
+		return false
+	}
+	rel := file
+	if abs, err := filepath.Abs(file); err == nil {
+		if r, err := filepath.Rel(c.dir, abs); err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			rel = r
+		}
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, pattern := range c.Ignore {
+		for i, part := range parts {
+			name := part
+			if strings.Contains(pattern, "/") {
+				name = strings.Join(parts[:i+1], "/")
+			}
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractProfile removes the global --profile=NAME flag, NAME being one of
+// parser.ProfileNames, and --i-json, short for --profile=i-json, from args, wherever they
+// appear before a "--" terminator, and returns the profile, "" if none is
+// given, and the remaining arguments. The last flag wins.
+func extractProfile(args []string) (string, []string, error) {
+	profile := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "i-json" && !hasValue {
+			profile = parser.ProfileIJSON.String()
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") || name != "profile" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("--profile requires a value: %s", profileChoices())
+			}
+			i++
+			value = args[i]
+		}
+		if _, ok := parser.ParseProfile(value); !ok {
+			return "", nil, fmt.Errorf("invalid --profile value %q (expected %s)", value, profileChoices())
+		}
+		profile = value
+	}
+	return profile, rest, nil
+}
+
+// applyProfile selects the named profile, ProfileDefault if name is "".
+func (inv *invocation) applyProfile(name string) {
+	if pr, ok := parser.ParseProfile(name); ok {
+		inv.profile = pr
+	}
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name           string
+		file           string
+		expectedIndent int
+		expected       projectConfig
+		expectedErr    string
+	}{
+		{
+			name:           "yaml",
+			file:           writeFile("full.yml", "profile: permissive\nindent: 4\nignore:\n  - node_modules\n  - \"*.min.json\"\n"),
+			expectedIndent: 4,
+			expected:       projectConfig{Profile: "permissive", Ignore: []string{"node_modules", "*.min.json"}},
+		},
+		{
+			name:           "json",
+			file:           writeFile("full.json", `{"profile": "json5", "indent": 0, "ignore": ["build/*"]}`),
+			expectedIndent: 0,
+			expected:       projectConfig{Profile: "json5", Ignore: []string{"build/*"}},
+		},
+		{name: "empty yaml", file: writeFile("empty.yml", ""), expectedIndent: 2},
+		{name: "unknown yaml setting", file: writeFile("typo.yml", "indnet: 4\n"), expectedErr: "indnet"},
+		{name: "unknown json setting", file: writeFile("typo.json", `{"indnet": 4}`), expectedErr: `unknown setting "indnet"`},
+		{name: "invalid profile", file: writeFile("profile.yml", "profile: lenient\n"), expectedErr: `invalid profile "lenient"`},
+		{name: "negative indent", file: writeFile("indent.json", `{"indent": -1}`), expectedErr: "indent must not be negative"},
+		{name: "invalid pattern", file: writeFile("pattern.yml", "ignore: ['[']\n"), expectedErr: `invalid ignore pattern "["`},
+		{name: "invalid json", file: writeFile("invalid.json", `{"indent": }`), expectedErr: "invalid config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := loadConfig(tt.file)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.Profile != tt.expected.Profile || !slices.Equal(c.Ignore, tt.expected.Ignore) || c.indent() != tt.expectedIndent {
+				t.Errorf("expected %+v with indent %d, got %+v with indent %d", tt.expected, tt.expectedIndent, c, c.indent())
+			}
+			if c.dir != tempDir {
+				t.Errorf("expected dir %q, got %q", tempDir, c.dir)
+			}
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, err := findConfig(nested); err != nil || path != "" {
+		t.Errorf("expected no config, got %q, %v", path, err)
+	}
+	for _, name := range []string{"jsonparser.json", ".jsonparser.yml"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if path, err := findConfig(nested); err != nil || path != filepath.Join(root, ".jsonparser.yml") {
+		t.Errorf("expected the YAML config in %s, got %q, %v", root, path, err)
+	}
+}
+
+func TestConfigIgnored(t *testing.T) {
+	c := projectConfig{Ignore: []string{"node_modules", "*.min.json", "build/out"}, dir: filepath.FromSlash("/project")}
+
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{file: "/project/config.json", expected: false},
+		{file: "/project/node_modules/pkg/package.json", expected: true},
+		{file: "/project/web/node_modules/x.json", expected: true},
+		{file: "/project/web/app.min.json", expected: true},
+		{file: "/project/build/out/result.json", expected: true},
+		{file: "/project/web/build/out/result.json", expected: false},
+		{file: "/elsewhere/app.min.json", expected: true},
+		{file: StdinName, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := c.ignored(filepath.FromSlash(tt.file)); got != tt.expected {
+				t.Errorf("expected ignored(%q) to be %v, got %v", tt.file, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProjectConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	writeFile(".jsonparser.yml", "profile: permissive\nindent: 4\nignore: [vendor, '*.min.json']\n")
+	writeFile("plain.json", `{"a": [1]}`)
+	writeFile("bom.json", "\uFEFF{\"a\": [1]}")
+	writeFile("unsafe.json", `{"id": 9007199254740993}`)
+	writeFile("surrogate.json", `{"s": "\uD83D"}`)
+	writeFile("duplicate.json", `{"a": 1, "a": 2}`)
+	writeFile("src/app.json", `{"a": 1,}`)
+	writeFile("src/app.min.json", `{`)
+	writeFile("src/vendor/lib.json", `{`)
+	t.Chdir(filepath.Join(tempDir, "src"))
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{name: "profile", args: []string{"app.json"}, expectedExit: ExitSuccess},
+		{name: "profile flag overrides", args: []string{"--profile=strict", "app.json"}, expectedExit: ExitInvalid, expectedStderr: "trailing comma not allowed"},
+		{name: "default profile flag overrides", args: []string{"--profile=default", "app.json"}, expectedExit: ExitInvalid, expectedStderr: "trailing comma not allowed"},
+		{name: "byte order mark skipped", args: []string{"../bom.json"}, expectedExit: ExitSuccess},
+		{name: "byte order mark skipped by default", args: []string{"--profile=default", "../bom.json"}, expectedExit: ExitSuccess},
+		{name: "duplicate keys rejected by strict", args: []string{"--profile=strict", "../duplicate.json"}, expectedExit: ExitInvalid, expectedStderr: "duplicate key"},
+		{name: "trailing commas accepted by json5", args: []string{"--profile=json5", "app.json"}, expectedExit: ExitSuccess},
+		{name: "byte order mark rejected by strict", args: []string{"--profile=strict", "../bom.json"}, expectedExit: ExitInvalid, expectedStderr: "byte order mark"},
+		{name: "byte order mark rejected by strict stream", args: []string{"--profile=strict", "--stream", "../bom.json"}, expectedExit: ExitInvalid, expectedStderr: "byte order mark"},
+		{name: "i-json", args: []string{"--profile=i-json", "../plain.json"}, expectedExit: ExitSuccess},
+		{name: "i-json rejects unsafe integers", args: []string{"--i-json", "../unsafe.json"}, expectedExit: ExitInvalid, expectedStderr: "exceeds the precision"},
+		{name: "i-json rejects lone surrogates", args: []string{"--i-json", "../surrogate.json"}, expectedExit: ExitInvalid},
+		{name: "i-json rejects byte order marks", args: []string{"--i-json", "../bom.json"}, expectedExit: ExitInvalid, expectedStderr: "byte order mark"},
+		{name: "unsafe integers without i-json", args: []string{"../unsafe.json"}, expectedExit: ExitSuccess},
+		{name: "indent", args: []string{"format", "../plain.json"}, expectedExit: ExitSuccess, expectedStdout: "{\n    \"a\": ["},
+		{name: "indent flag overrides", args: []string{"format", "--indent=1", "../plain.json"}, expectedExit: ExitSuccess, expectedStdout: "{\n \"a\": ["},
+		{name: "ignored by glob", args: []string{"validate", "*.json"}, expectedExit: ExitSuccess},
+		{name: "ignored when walking", args: []string{"validate", "--recursive", "."}, expectedExit: ExitSuccess},
+		{name: "named files are not ignored", args: []string{"validate", "app.min.json"}, expectedExit: ExitInvalid},
+		{name: "invalid profile flag", args: []string{"--profile=loose", "app.json"}, expectedExit: ExitInvalid, expectedStderr: "invalid --profile value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got: %s", tt.expectedStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestProjectConfig_InvalidProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, ".jsonparser.yml"), []byte("profile: lenient\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "app.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(tempDir)
+
+	// The profile is checked when the config is loaded, before any flag
+	// could override it.
+	var stdout, stderr bytes.Buffer
+	if exitCode := run("json-parser", []string{"--profile=strict", "app.json"}, &stdout, &stderr); exitCode != ExitInvalid {
+		t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+	}
+	if !strings.Contains(stderr.String(), `invalid config file`) || !strings.Contains(stderr.String(), `invalid profile "lenient"`) {
+		t.Errorf("expected a config error, got: %s", stderr.String())
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/jsonseq"
+	"github.com/VuNe/json-parser/internal/ndjson"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// valueWriter writes the values of a converted document.
+type valueWriter interface {
+	Write(value parser.JSONValue) error
+	Close() error
+}
+
+// formats maps the --from and --to values to how values are read from and
+// written in that format. All of them stream, so large files are fine.
+var formats = map[string]struct {
+	read  func(r io.Reader) iter.Seq2[parser.JSONValue, error]
+	write func(w io.Writer) valueWriter
+}{
+	"json":  {readArray, func(w io.Writer) valueWriter { return stream.NewArrayWriter(w) }},            // the elements of a top-level array
+	"jsonl": {readLines, func(w io.Writer) valueWriter { return &lineWriter{w: bufio.NewWriter(w)} }},  // one value per line
+	"seq":   {readRecords, func(w io.Writer) valueWriter { return &seqWriter{jsonseq.NewEncoder(w)} }}, // RFC 7464 records
+}
+
+// runConvert implements `json-parser convert [--from json|jsonl|seq] --to json|jsonl|seq <file>`,
+// which converts between a top-level JSON array, newline-delimited JSON
+// (JSONL/NDJSON) and JSON text sequences (RFC 7464, application/json-seq).
+// Without --from, the input is JSONL when converting to json and an array
+// otherwise.
+func (inv *invocation) runConvert(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "", "source format: json (a top-level array), jsonl or seq")
+	to := fs.String("to", "", "target format: `json` (a top-level array), jsonl or seq")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 || *to == "" {
+		fmt.Fprintln(stderr, "Usage: convert [--from json|jsonl|seq] --to json|jsonl|seq <filename>")
+		return ExitInvalid
+	}
+	if *from == "" {
+		*from = "json"
+		if *to == "json" {
+			*from = "jsonl"
+		}
+	}
+	source, ok := formats[*from]
+	if !ok {
+		printError(stderr, "invalid --from value %q (expected json, jsonl or seq)", *from)
+		return ExitInvalid
+	}
+	target, ok := formats[*to]
+	if !ok {
+		printError(stderr, "invalid --to value %q (expected json, jsonl or seq)", *to)
+		return ExitInvalid
+	}
+	if *from == *to {
+		printError(stderr, "--from and --to are both %s", *to)
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+	defer file.Close()
+
+	if err := convert(source.read(file), target.write(stdout)); err != nil {
+		printError(stderr, "%v", &ParseError{Err: fmt.Errorf("%s: %w", filename, err)})
+		return ExitInvalid
+	}
+	return ExitSuccess
+}
+
+// convert writes the values to w, stopping at the first error.
+func convert(values iter.Seq2[parser.JSONValue, error], w valueWriter) error {
+	for value, err := range values {
+		if err != nil {
+			return err
+		}
+		if err := w.Write(value); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// readArray iterates over the elements of the top-level array read from r.
+func readArray(r io.Reader) iter.Seq2[parser.JSONValue, error] {
+	return stream.NewElementReader(r).Elements()
+}
+
+// readLines iterates over the values of the JSON Lines read from r.
+func readLines(r io.Reader) iter.Seq2[parser.JSONValue, error] {
+	return func(yield func(parser.JSONValue, error) bool) {
+		for line, err := range ndjson.NewDecoder(r).Lines() {
+			if !yield(line.Value, err) {
+				return
+			}
+		}
+	}
+}
+
+// readRecords iterates over the values of the JSON text sequence read from r.
+func readRecords(r io.Reader) iter.Seq2[parser.JSONValue, error] {
+	return func(yield func(parser.JSONValue, error) bool) {
+		for record, err := range jsonseq.NewDecoder(r).Records() {
+			if !yield(record.Value, err) {
+				return
+			}
+		}
+	}
+}
+
+// lineWriter writes values as JSON Lines.
+type lineWriter struct {
+	w *bufio.Writer
+}
+
+// Write writes value compactly on its own line.
+func (lw *lineWriter) Write(value parser.JSONValue) error {
+	data, err := encoder.Marshal(value)
+	if err != nil {
+		return err
+	}
+	lw.w.Write(data)
+	return lw.w.WriteByte('\n')
+}
+
+// Close flushes the lines written.
+func (lw *lineWriter) Close() error {
+	return lw.w.Flush()
+}
+
+// seqWriter writes values as a JSON text sequence.
+type seqWriter struct {
+	enc jsonseq.Encoder
+}
+
+// Write writes value as a record.
+func (sw *seqWriter) Write(value parser.JSONValue) error {
+	return sw.enc.Encode(value)
+}
+
+// Close does nothing: records are written as they come.
+func (sw *seqWriter) Close() error {
+	return nil
+}
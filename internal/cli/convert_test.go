@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunConvert(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	array := writeFile("items.json", `[{"id": 1}, {"id": 2}]`)
+	lines := writeFile("items.jsonl", "{\"id\": 1}\n{\"id\": 2}\n")
+	invalidLines := writeFile("invalid.jsonl", "{\"id\": 1}\n{\"id\"\n")
+	seq := writeFile("items.seq", "\x1e{\"id\": 1}\n\x1e{\"id\": 2}\n")
+	truncatedSeq := writeFile("truncated.seq", "\x1e{\"id\": 1}\n\x1e12")
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "array to jsonl", args: []string{"convert", "--to", "jsonl", array}, expectedExit: ExitSuccess, expectedOut: "{\"id\":1}\n{\"id\":2}\n"},
+		{name: "jsonl to array", args: []string{"convert", lines, "--to=json"}, expectedExit: ExitSuccess, expectedOut: "[\n  {\"id\":1},\n  {\"id\":2}\n]\n"},
+		{name: "jsonl is not an array", args: []string{"convert", "--to", "jsonl", lines}, expectedExit: ExitInvalid},
+		{name: "invalid line", args: []string{"convert", "--to", "json", invalidLines}, expectedExit: ExitInvalid},
+		{name: "array to seq", args: []string{"convert", "--to", "seq", array}, expectedExit: ExitSuccess, expectedOut: "\x1e{\"id\":1}\n\x1e{\"id\":2}\n"},
+		{name: "jsonl to seq", args: []string{"convert", "--from", "jsonl", "--to", "seq", lines}, expectedExit: ExitSuccess, expectedOut: "\x1e{\"id\":1}\n\x1e{\"id\":2}\n"},
+		{name: "seq to jsonl", args: []string{"convert", "--from", "seq", "--to", "jsonl", seq}, expectedExit: ExitSuccess, expectedOut: "{\"id\":1}\n{\"id\":2}\n"},
+		{name: "seq to array", args: []string{"convert", "--from=seq", "--to=json", seq}, expectedExit: ExitSuccess, expectedOut: "[\n  {\"id\":1},\n  {\"id\":2}\n]\n"},
+		{name: "truncated record", args: []string{"convert", "--from", "seq", "--to", "jsonl", truncatedSeq}, expectedExit: ExitInvalid},
+		{name: "same format", args: []string{"convert", "--from", "seq", "--to", "seq", seq}, expectedExit: ExitInvalid},
+		{name: "unknown source format", args: []string{"convert", "--from", "yaml", "--to", "json", array}, expectedExit: ExitInvalid},
+		{name: "unknown format", args: []string{"convert", "--to", "yaml", array}, expectedExit: ExitInvalid},
+		{name: "missing --to", args: []string{"convert", array}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"convert", "--to", "json", filepath.Join(tempDir, "missing.jsonl")}, expectedExit: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
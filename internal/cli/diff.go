@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/diff"
+	"github.com/VuNe/json-parser/internal/encoder"
+)
+
+// runDiff implements `json-parser diff [--format text|json] <a> <b>`, which
+// prints the structural differences between two documents: the paths added,
+// removed and changed in b. Key order, whitespace and 1 vs 1.0 don't matter.
+// As with diff(1), the exit code is 0 if the documents are equal and 1 if
+// they differ. The json format prints an array of change objects (see
+// diff.Change.Object), empty if the documents are equal.
+func (inv *invocation) runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "output format: `text` or json")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(stderr, "Usage: diff [--format text|json] <filename> <filename>")
+		return ExitInvalid
+	}
+	if *format != "text" && *format != "json" {
+		printError(stderr, "invalid --format value %q (expected text or json)", *format)
+		return ExitInvalid
+	}
+
+	handler := inv.newHandler()
+	a, err := handler.ParseFileValue(positional[0])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+	b, err := handler.ParseFileValue(positional[1])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	changes := diff.Compare(a, b)
+	if *format == "json" {
+		objects := make([]any, len(changes))
+		for i, change := range changes {
+			objects[i] = change.Object()
+		}
+		output, err := encoder.MarshalIndent(objects, "", "  ")
+		if err != nil {
+			printError(stderr, "%v", err)
+			return ExitInvalid
+		}
+		fmt.Fprintln(stdout, string(output))
+	} else {
+		for _, change := range changes {
+			fmt.Fprintln(stdout, colorize(stdout, change.String(), changeColors[change.Type]))
+		}
+	}
+
+	if len(changes) > 0 {
+		return ExitInvalid
+	}
+	return ExitSuccess
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	a := writeFile("a.json", `{"name": "a", "tags": ["x"], "old": null}`)
+	b := writeFile("b.json", `{"tags": ["x", "y"], "name": "b"}`)
+	same := writeFile("same.json", "{\n  \"old\": null,\n  \"tags\": [\"x\"],\n  \"name\": \"a\"\n}")
+	invalid := writeFile("invalid.json", `{"name": }`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{
+			name:         "text",
+			args:         []string{"diff", a, b},
+			expectedExit: ExitInvalid,
+			expectedOut:  "~ /name: \"a\" -> \"b\"\n- /old: null\n+ /tags/1: \"y\"\n",
+		},
+		{
+			name:         "json",
+			args:         []string{"diff", "--format", "json", a, b},
+			expectedExit: ExitInvalid,
+			expectedOut: "[\n" +
+				"  {\n    \"new\": \"b\",\n    \"old\": \"a\",\n    \"path\": \"/name\",\n    \"type\": \"changed\"\n  },\n" +
+				"  {\n    \"old\": null,\n    \"path\": \"/old\",\n    \"type\": \"removed\"\n  },\n" +
+				"  {\n    \"new\": \"y\",\n    \"path\": \"/tags/1\",\n    \"type\": \"added\"\n  }\n" +
+				"]\n",
+		},
+		{name: "equal", args: []string{"diff", a, same}, expectedExit: ExitSuccess},
+		{name: "equal json", args: []string{"diff", "--format=json", a, same}, expectedExit: ExitSuccess, expectedOut: "[]\n"},
+		{name: "invalid document", args: []string{"diff", a, invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"diff", a, filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "unknown format", args: []string{"diff", "--format", "yaml", a, b}, expectedExit: ExitInvalid},
+		{name: "one file", args: []string{"diff", a}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// editFlag is a repeatable edit flag, such as --set, adding to a list of
+// edits shared with the other edit flags so they keep their command line
+// order.
+type editFlag struct {
+	edits *[]stream.Edit
+	parse func(arg string) (path string, edit stream.Edit, err error)
+}
+
+// String implements flag.Value.
+func (f editFlag) String() string {
+	return ""
+}
+
+// Set implements flag.Value.
+func (f editFlag) Set(arg string) error {
+	path, edit, err := f.parse(arg)
+	if err != nil {
+		return err
+	}
+	if _, err := pointer.Split(path); err != nil {
+		return err
+	}
+	*f.edits = append(*f.edits, edit)
+	return nil
+}
+
+// parseSet parses a --set argument, `pointer=json`.
+func (inv *invocation) parseSet(arg string) (string, stream.Edit, error) {
+	path, text, ok := strings.Cut(arg, "=")
+	if !ok {
+		return "", stream.Edit{}, fmt.Errorf("expected pointer=json, got %q", arg)
+	}
+	value, err := inv.newHandler().ParseStringValue(text)
+	if err != nil {
+		return "", stream.Edit{}, fmt.Errorf("invalid value for %s: %v", path, err)
+	}
+	return path, stream.Set(path, value), nil
+}
+
+// parseDelete parses a --delete argument, a pointer.
+func parseDelete(arg string) (string, stream.Edit, error) {
+	return arg, stream.Delete(arg), nil
+}
+
+// parseRename parses a --rename argument, `pointer=name`.
+func parseRename(arg string) (string, stream.Edit, error) {
+	path, name, ok := strings.Cut(arg, "=")
+	if !ok {
+		return "", stream.Edit{}, fmt.Errorf("expected pointer=name, got %q", arg)
+	}
+	return path, stream.Rename(path, name), nil
+}
+
+// runEdit implements
+// `json-parser edit [--set pointer=json] [--delete pointer] [--rename pointer=name] <file>`,
+// which prints the document with the values at the given JSON Pointers
+// replaced, deleted or renamed, streaming it so that files of any size can be
+// transformed in constant memory. Each flag may be repeated; a "*" token in a
+// pointer matches any member or index, e.g. `--delete '/users/*/password'`.
+// The output is compact. Pointers end at the first '=' in --set and
+// --rename, so they can't name keys containing '='.
+func (inv *invocation) runEdit(args []string, stdout, stderr io.Writer) int {
+	var edits []stream.Edit
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Var(editFlag{&edits, inv.parseSet}, "set", "replace the values at a pointer: `pointer=json`")
+	fs.Var(editFlag{&edits, parseDelete}, "delete", "delete the members or elements at a `pointer`")
+	fs.Var(editFlag{&edits, parseRename}, "rename", "rename the members at a pointer: `pointer=name`")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: edit [--set pointer=json] [--delete pointer] [--rename pointer=name] <filename>")
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+	defer file.Close()
+
+	if err := stream.Rewrite(file, stdout, edits...); err != nil {
+		printError(stderr, "%v", &ParseError{Err: fmt.Errorf("%s: %w", filename, err)})
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout)
+	return ExitSuccess
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunEdit(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"users": [{"id": 1, "pass": "a"}, {"id": 2, "pass": "b"}], "v": 1}`)
+	invalid := writeFile("invalid.json", `{"users": [}`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "no edits", args: []string{"edit", doc}, expectedExit: ExitSuccess, expectedOut: `{"users":[{"id":1,"pass":"a"},{"id":2,"pass":"b"}],"v":1}` + "\n"},
+		{
+			name:         "edits",
+			args:         []string{"edit", "--delete", "/users/*/pass", "--set", `/v={"major": 2}`, "--rename", "/users=accounts", doc},
+			expectedExit: ExitSuccess,
+			expectedOut:  `{"accounts":[{"id":1},{"id":2}],"v":{"major":2}}` + "\n",
+		},
+		{name: "command line order", args: []string{"edit", "--set", "/v=3", "--set", "/*=4", doc}, expectedExit: ExitSuccess, expectedOut: `{"users":4,"v":3}` + "\n"},
+		{name: "invalid value", args: []string{"edit", "--set", "/v={", doc}, expectedExit: ExitInvalid},
+		{name: "missing value", args: []string{"edit", "--set", "/v", doc}, expectedExit: ExitInvalid},
+		{name: "invalid pointer", args: []string{"edit", "--delete", "v", doc}, expectedExit: ExitInvalid},
+		{name: "root", args: []string{"edit", "--delete", "", doc}, expectedExit: ExitInvalid},
+		{name: "invalid document", args: []string{"edit", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"edit", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"edit", "--delete", "/v"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
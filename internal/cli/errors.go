@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/schema"
+)
+
+// Exit codes returned by the CLI. Keeping I/O failures apart from invalid
+// input lets scripts tell "this JSON is broken" from "this file is missing".
+const (
+	ExitSuccess   = 0 // the input is valid JSON
+	ExitInvalid   = 1 // the input is not valid JSON, or the command line is wrong
+	ExitFileError = 2 // the input could not be read
+	ExitWarnings  = 3 // the input is valid but lint produced warnings (see lint --on-warning)
+	ExitSchema    = 4 // the input is valid JSON but violates its schema (see CLIHandler.ParseFileWithSchema)
+)
+
+// FileError reports that an input file could not be accessed or read.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface, naming the specific access problem.
+func (e *FileError) Error() string {
+	switch {
+	case errors.Is(e.Err, fs.ErrNotExist):
+		return fmt.Sprintf("file '%s' does not exist", e.Path)
+	case errors.Is(e.Err, fs.ErrPermission):
+		return fmt.Sprintf("file '%s' is not readable: permission denied", e.Path)
+	default:
+		return fmt.Sprintf("error reading file: %v", e.Err)
+	}
+}
+
+// Unwrap returns the underlying I/O error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError reports that the input was read but is not valid JSON. The
+// wrapped error is usually a *parser.ParseError carrying position details.
+type ParseError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("JSON parsing failed: %v", e.Err)
+}
+
+// Unwrap returns the underlying parser error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaError reports that a document is valid JSON but does not conform to
+// a JSON Schema.
+type SchemaError struct {
+	Path       string // the document
+	Schema     string // the schema file
+	Violations []schema.Violation
+}
+
+// Error implements the error interface, listing the violations.
+func (e *SchemaError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.String()
+	}
+	return fmt.Sprintf("'%s' does not conform to schema '%s': %s", e.Path, e.Schema, strings.Join(violations, "; "))
+}
+
+// exitCodeFor maps an error returned by the handler to the process exit code.
+func exitCodeFor(err error) int {
+	var fileErr *FileError
+	var schemaErr *SchemaError
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.As(err, &fileErr):
+		return ExitFileError
+	case errors.As(err, &schemaErr):
+		return ExitSchema
+	default:
+		return ExitInvalid
+	}
+}
+
+// extractErrorFormat removes the global --error-format=text|json flag from
+// args, wherever it appears before a "--" terminator, and returns its value
+// and the remaining arguments. The last flag wins.
+func extractErrorFormat(args []string) (string, []string, error) {
+	format := "text"
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "error-format" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("--error-format requires a value: text or json")
+			}
+			i++
+			value = args[i]
+		}
+		if value != "text" && value != "json" {
+			return "", nil, fmt.Errorf("invalid --error-format value %q (expected text or json)", value)
+		}
+		format = value
+	}
+	return format, rest, nil
+}
+
+// errorObject describes an error as a JSON object for --error-format json:
+// its type ("lexical", "syntax" or "semantic" for invalid JSON, "file" for
+// unreadable input, "schema" for schema violations, "error" otherwise) and
+// message and, for invalid JSON, the line, column and byte offset, the
+// expected tokens, what was found and a suggestion, where known. Schema
+// violations are listed under "violations". message is the full text printed
+// otherwise.
+func errorObject(message string, err error) parser.JSONObject {
+	obj := parser.JSONObject{"type": "error", "message": message}
+
+	var parseErr *parser.ParseError
+	var fileErr *FileError
+	var schemaErr *SchemaError
+	switch {
+	case errors.As(err, &parseErr):
+		obj["type"] = strings.ToLower(parseErr.Type.String())
+		obj["message"] = parseErr.Message
+		obj["line"] = int64(parseErr.Position.Line)
+		obj["column"] = int64(parseErr.Position.Column)
+		obj["offset"] = int64(parseErr.Position.Offset)
+		if len(parseErr.Expected) > 0 {
+			expected := make([]any, len(parseErr.Expected))
+			for i, token := range parseErr.Expected {
+				expected[i] = token
+			}
+			obj["expected"] = expected
+		}
+		if parseErr.Found != "" {
+			obj["found"] = parseErr.Found
+		}
+		if parseErr.Suggestion != "" {
+			obj["suggestion"] = parseErr.Suggestion
+		}
+	case errors.As(err, &fileErr):
+		obj["type"] = "file"
+		obj["file"] = fileErr.Path
+	case errors.As(err, &schemaErr):
+		violations := make([]any, len(schemaErr.Violations))
+		for i, violation := range schemaErr.Violations {
+			violations[i] = violation.String()
+		}
+		obj["type"] = "schema"
+		obj["file"] = schemaErr.Path
+		obj["violations"] = violations
+	}
+	return obj
+}
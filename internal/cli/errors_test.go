@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestHandler_ParseFile_ErrorTypes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	invalidFile := filepath.Join(tempDir, "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"a": }`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	handler := New()
+
+	err := handler.ParseFile(filepath.Join(tempDir, "missing.json"))
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) {
+		t.Fatalf("expected *FileError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected error to wrap os.ErrNotExist, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected 'does not exist' message, got %q", err.Error())
+	}
+	if handler.ExitCode() != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, handler.ExitCode())
+	}
+
+	err = handler.ParseFile(invalidFile)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	var detailErr *parser.ParseError
+	if !errors.As(err, &detailErr) {
+		t.Errorf("expected error to wrap *parser.ParseError, got %v", err)
+	}
+	if handler.ExitCode() != ExitInvalid {
+		t.Errorf("expected exit code %d, got %d", ExitInvalid, handler.ExitCode())
+	}
+}
+
+func TestFileError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *FileError
+		expected string
+	}{
+		{
+			name:     "missing file",
+			err:      &FileError{Path: "a.json", Err: os.ErrNotExist},
+			expected: "file 'a.json' does not exist",
+		},
+		{
+			name:     "permission denied",
+			err:      &FileError{Path: "a.json", Err: os.ErrPermission},
+			expected: "file 'a.json' is not readable: permission denied",
+		},
+		{
+			name:     "other read error",
+			err:      &FileError{Path: "a.json", Err: errors.New("disk on fire")},
+			expected: "error reading file: disk on fire",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestExtractErrorFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expected     string
+		expectedRest []string
+		wantErr      bool
+	}{
+		{name: "absent", args: []string{"sort", "-"}, expected: "text", expectedRest: []string{"sort", "-"}},
+		{name: "with equals", args: []string{"--error-format=json", "-"}, expected: "json", expectedRest: []string{"-"}},
+		{name: "separate value", args: []string{"-", "--error-format", "json"}, expected: "json", expectedRest: []string{"-"}},
+		{name: "after terminator", args: []string{"--", "--error-format=json"}, expected: "text", expectedRest: []string{"--", "--error-format=json"}},
+		{name: "missing value", args: []string{"-", "--error-format"}, wantErr: true},
+		{name: "invalid value", args: []string{"--error-format=xml", "-"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, rest, err := extractErrorFormat(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && (format != tt.expected || !slices.Equal(rest, tt.expectedRest)) {
+				t.Errorf("expected %q and rest %q, got %q and %q", tt.expected, tt.expectedRest, format, rest)
+			}
+		})
+	}
+}
+
+func TestErrorFormatJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	invalid := filepath.Join(tempDir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte("{\n  \"a\": 1,,\n}"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	missing := filepath.Join(tempDir, "missing.json")
+	missingColon := filepath.Join(tempDir, "colon.json")
+	if err := os.WriteFile(missingColon, []byte(`{"a" 1}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	trailingComma := filepath.Join(tempDir, "comma.json")
+	if err := os.WriteFile(trailingComma, []byte(`[1, 2,]`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expected     map[string]any
+	}{
+		{
+			name:         "parse error",
+			args:         []string{"--error-format=json", invalid},
+			expectedExit: ExitInvalid,
+			expected:     map[string]any{"type": "syntax", "line": int64(2), "column": int64(10), "offset": int64(11)},
+		},
+		{
+			name:         "missing colon",
+			args:         []string{"--error-format=json", missingColon},
+			expectedExit: ExitInvalid,
+			expected:     map[string]any{"type": "syntax", "expected": []any{"':'"}, "suggestion": parser.SuggestionMissingColon},
+		},
+		{
+			name:         "trailing comma",
+			args:         []string{"--error-format=json", trailingComma},
+			expectedExit: ExitInvalid,
+			expected:     map[string]any{"type": "syntax", "expected": []any{"value"}, "suggestion": parser.SuggestionRemoveTrailingComma},
+		},
+		{
+			name:         "file error",
+			args:         []string{"sort", missing, "--error-format", "json"},
+			expectedExit: ExitFileError,
+			expected:     map[string]any{"type": "file", "file": missing},
+		},
+		{
+			name:         "other error",
+			args:         []string{"--error-format=json", "diff", "--format", "yaml", invalid, invalid},
+			expectedExit: ExitInvalid,
+			expected:     map[string]any{"type": "error", "message": `invalid --format value "yaml" (expected text or json)`},
+		},
+		{
+			name:         "global flag error",
+			args:         []string{"--max-depth=0", "--error-format=json", invalid},
+			expectedExit: ExitInvalid,
+			expected:     map[string]any{"type": "error", "message": `invalid --max-depth value "0" (expected a positive number)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			value, err := New().ParseStringValue(stderr.String())
+			if err != nil {
+				t.Fatalf("expected a JSON object on stderr, got %q: %v", stderr.String(), err)
+			}
+			obj, _ := parser.AsObject(value)
+			if _, ok := obj["message"].(string); !ok {
+				t.Errorf("expected a message, got %v", obj)
+			}
+			for key, want := range tt.expected {
+				if !reflect.DeepEqual(obj[key], want) {
+					t.Errorf("expected %s %v, got %v", key, want, obj[key])
+				}
+			}
+		})
+	}
+
+	// The format of a run doesn't leak into writers it didn't wrap.
+	var w strings.Builder
+	printError(&w, "%s", "oops")
+	if w.String() != "Error: oops\n" {
+		t.Errorf("expected a text error, got %q", w.String())
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// finder holds the patterns of a find invocation. A nil pattern matches
+// everything.
+type finder struct {
+	key   *regexp.Regexp // matched against object keys
+	value *regexp.Regexp // matched against scalar values
+}
+
+// match is a value found by find.
+type match struct {
+	path  string // JSON Pointer
+	key   string // object key, or array index
+	value parser.JSONValue
+}
+
+// runFind implements `json-parser find [--key regex] [--value-regex regex] <file>`,
+// which walks the document and prints the JSON Pointer, key and value of
+// every member whose key matches --key and whose value matches --value-regex,
+// one tab-separated line each, for auditing large payloads. Patterns are
+// unanchored Go regular expressions. --value-regex is matched against
+// strings and the text of numbers, booleans and null; objects and arrays
+// only match when it isn't given. Array elements count as members keyed by
+// their index.
+func (inv *invocation) runFind(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("find", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keyPattern := fs.String("key", "", "`regex` matched against object keys")
+	valuePattern := fs.String("value-regex", "", "`regex` matched against scalar values")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 || (*keyPattern == "" && *valuePattern == "") {
+		fmt.Fprintln(stderr, "Usage: find [--key regex] [--value-regex regex] <filename>")
+		return ExitInvalid
+	}
+
+	var f finder
+	if f.key, err = compilePattern("--key", *keyPattern); err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	if f.value, err = compilePattern("--value-regex", *valuePattern); err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+
+	value, err := inv.newHandler().ParseFileValue(positional[0])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	var matches []match
+	f.walk("", value, &matches)
+	for _, m := range matches {
+		rendered, err := encoder.Marshal(m.value)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return ExitInvalid
+		}
+		fmt.Fprintf(stdout, "%s\t%s\t%s\n", m.path, m.key, rendered)
+	}
+	return ExitSuccess
+}
+
+// compilePattern compiles a non-empty pattern, naming the flag on failure.
+func compilePattern(flagName, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", flagName, err)
+	}
+	return re, nil
+}
+
+// walk appends the matching members below value, which is at path, to
+// matches in document order with object keys sorted.
+func (f *finder) walk(path string, value parser.JSONValue, matches *[]match) {
+	visit := func(key string, child parser.JSONValue) {
+		childPath := path + "/" + pointer.Escape(key)
+		if f.matches(key, child) {
+			*matches = append(*matches, match{path: childPath, key: key, value: child})
+		}
+		f.walk(childPath, child, matches)
+	}
+
+	switch v := value.(type) {
+	case []any:
+		for i, elem := range v {
+			visit(strconv.Itoa(i), elem)
+		}
+	default:
+		if obj, ok := parser.AsObject(v); ok {
+			for _, key := range slices.Sorted(maps.Keys(obj)) {
+				visit(key, obj[key])
+			}
+		}
+	}
+}
+
+// matches reports whether a member with the given key and value matches
+// both patterns.
+func (f *finder) matches(key string, value parser.JSONValue) bool {
+	if f.key != nil && !f.key.MatchString(key) {
+		return false
+	}
+	if f.value == nil {
+		return true
+	}
+
+	switch v := value.(type) {
+	case string:
+		return f.value.MatchString(v)
+	case []any:
+		return false
+	default:
+		if _, ok := parser.AsObject(v); ok {
+			return false
+		}
+		text, err := encoder.Marshal(v)
+		return err == nil && f.value.Match(text)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunFind(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "payload.json")
+	content := `{
+		"auth": {"token": "eyJhbGci", "refresh_token": "rt-123", "expires": 3600},
+		"users": [{"name": "ann", "api_token": "eyJzdWIi"}, {"name": "bob", "api_token": null}],
+		"a/b": true
+	}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{
+			name:         "key pattern",
+			args:         []string{"find", "--key", "token$", file},
+			expectedExit: ExitSuccess,
+			expectedOut: "/auth/refresh_token\trefresh_token\t\"rt-123\"\n" +
+				"/auth/token\ttoken\t\"eyJhbGci\"\n" +
+				"/users/0/api_token\tapi_token\t\"eyJzdWIi\"\n" +
+				"/users/1/api_token\tapi_token\tnull\n",
+		},
+		{
+			name:         "key and value patterns",
+			args:         []string{"find", "--key", "token.*", "--value-regex", "^ey", file},
+			expectedExit: ExitSuccess,
+			expectedOut:  "/auth/token\ttoken\t\"eyJhbGci\"\n/users/0/api_token\tapi_token\t\"eyJzdWIi\"\n",
+		},
+		{
+			name:         "value pattern matches scalars and array elements",
+			args:         []string{"find", file, "--value-regex", "^(3600|true|bob)$"},
+			expectedExit: ExitSuccess,
+			expectedOut:  "/a~1b\ta/b\ttrue\n/auth/expires\texpires\t3600\n/users/1/name\tname\t\"bob\"\n",
+		},
+		{
+			name:         "containers match keys",
+			args:         []string{"find", "--key", "^auth$", file},
+			expectedExit: ExitSuccess,
+			expectedOut:  "/auth\tauth\t{\"expires\":3600,\"refresh_token\":\"rt-123\",\"token\":\"eyJhbGci\"}\n",
+		},
+		{name: "no matches", args: []string{"find", "--key", "password", file}, expectedExit: ExitSuccess},
+		{name: "invalid pattern", args: []string{"find", "--key", "(", file}, expectedExit: ExitInvalid},
+		{name: "no pattern", args: []string{"find", file}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"find", "--key", "x", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// runFormat implements `json-parser format [--indent N | --tab | --minify] <file>`,
+// which pretty-prints the document with N spaces (2 or the project config's
+// indent by default) or a tab per
+// indentation level. With --minify it strips all insignificant whitespace
+// instead, copying strings exactly as written. Unlike sort it
+// keeps object members in their input order, and it streams the document, so
+// files of any size can be formatted. Numbers are written exactly as in the
+// input.
+func (inv *invocation) runFormat(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("format", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	indent := fs.Int("indent", inv.config.indent(), "number of spaces per indentation level")
+	tab := fs.Bool("tab", false, "indent with a tab per level")
+	minify := fs.Bool("minify", false, "strip all insignificant whitespace, keeping strings as written")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: format [--indent N | --tab | --minify] <filename>")
+		return ExitInvalid
+	}
+	if *indent < 0 {
+		printError(stderr, "--indent must not be negative")
+		return ExitInvalid
+	}
+	if *tab && *minify {
+		printError(stderr, "--tab and --minify are mutually exclusive")
+		return ExitInvalid
+	}
+
+	prefix := strings.Repeat(" ", *indent)
+	if *tab {
+		prefix = "\t"
+	}
+
+	filename := positional[0]
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+	defer file.Close()
+
+	if *minify {
+		err = stream.Compact(file, stdout)
+	} else {
+		err = stream.Format(file, stdout, prefix)
+	}
+	if err != nil {
+		printError(stderr, "%v", &ParseError{Err: fmt.Errorf("%s: %w", filename, err)})
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout)
+	return ExitSuccess
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunFormat(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"b": [1.50, {}], "a": null}`)
+	escaped := writeFile("escaped.json", "{ \"caf\\u00e9\" : [ \"a\\/b\" ,\n\t1e3 ] }")
+	invalid := writeFile("invalid.json", `{"a": 1, "b": }`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "default indent", args: []string{"format", doc}, expectedExit: ExitSuccess, expectedOut: "{\n  \"b\": [\n    1.50,\n    {}\n  ],\n  \"a\": null\n}\n"},
+		{name: "indent", args: []string{"format", "--indent", "1", doc}, expectedExit: ExitSuccess, expectedOut: "{\n \"b\": [\n  1.50,\n  {}\n ],\n \"a\": null\n}\n"},
+		{name: "tab", args: []string{"format", doc, "--tab"}, expectedExit: ExitSuccess, expectedOut: "{\n\t\"b\": [\n\t\t1.50,\n\t\t{}\n\t],\n\t\"a\": null\n}\n"},
+		{name: "minify", args: []string{"format", "--minify", doc}, expectedExit: ExitSuccess, expectedOut: "{\"b\":[1.50,{}],\"a\":null}\n"},
+		{name: "minify keeps strings", args: []string{"format", "--minify", escaped}, expectedExit: ExitSuccess, expectedOut: "{\"caf\\u00e9\":[\"a\\/b\",1e3]}\n"},
+		{name: "conflicting flags", args: []string{"format", "--tab", "--minify", doc}, expectedExit: ExitInvalid},
+		{name: "negative indent", args: []string{"format", "--indent", "-1", doc}, expectedExit: ExitInvalid},
+		{name: "invalid document", args: []string{"format", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"format", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"format"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
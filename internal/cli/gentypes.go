@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/typegen"
+)
+
+// runGenTypes implements `json-parser gen-types [--package name] [--type Name] <file>...`,
+// which infers the structure shared by the example documents and prints Go
+// type definitions, with json tags, that can decode them. Give several
+// examples so that optional and nullable members are recognized.
+func (inv *invocation) runGenTypes(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen-types", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	packageName := fs.String("package", "main", "package `name` of the generated file")
+	typeName := fs.String("type", "Root", "`name` of the type of the documents")
+
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "Usage: gen-types [--package name] [--type Name] <filename>...")
+		return ExitInvalid
+	}
+	if !token.IsIdentifier(*packageName) {
+		printError(stderr, "invalid --package value %q", *packageName)
+		return ExitInvalid
+	}
+	if !token.IsIdentifier(*typeName) || !token.IsExported(*typeName) {
+		printError(stderr, "invalid --type value %q (expected an exported Go identifier)", *typeName)
+		return ExitInvalid
+	}
+
+	handler := inv.newHandler()
+	examples := make([]parser.JSONValue, 0, len(files))
+	for _, filename := range files {
+		value, err := handler.ParseFileValue(filename)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return exitCodeFor(err)
+		}
+		examples = append(examples, value)
+	}
+
+	source, err := typegen.Generate(typegen.Infer(examples...), *typeName, typegen.WithPackage(*packageName))
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	stdout.Write(source)
+	return ExitSuccess
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunGenTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	first := writeFile("first.json", `{"id": 1, "name": "a"}`)
+	second := writeFile("second.json", `{"id": 2}`)
+	invalid := writeFile("invalid.json", `{"id": }`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{
+			name:         "examples",
+			args:         []string{"gen-types", "--package", "api", "--type", "User", first, second},
+			expectedExit: ExitSuccess,
+			expectedOut: "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage api\n\n" +
+				"type User struct {\n\tID   int64   `json:\"id\"`\n\tName *string `json:\"name,omitempty\"`\n}\n",
+		},
+		{name: "invalid package", args: []string{"gen-types", "--package", "my-api", first}, expectedExit: ExitInvalid},
+		{name: "unexported type", args: []string{"gen-types", "--type", "user", first}, expectedExit: ExitInvalid},
+		{name: "invalid document", args: []string{"gen-types", first, invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"gen-types", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"gen-types"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
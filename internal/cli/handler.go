@@ -1,17 +1,29 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"unsafe"
 
+	"github.com/VuNe/json-parser/internal/fetch"
 	"github.com/VuNe/json-parser/internal/lexer"
 	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/schema"
+	"github.com/VuNe/json-parser/internal/stream"
 )
 
 // CLIHandler interface defines the contract for handling CLI operations.
 type CLIHandler interface {
 	ParseFile(filename string) error
 	ParseString(input string) error
+	ParseFileValue(filename string) (parser.JSONValue, error)
+	ParseStringValue(input string) (parser.JSONValue, error)
+	ParseFileValues(filename string) ([]parser.JSONValue, error)
+	ParseFileWithSchema(filename, schemaFile string) error
+	ParseFileValueWithSchema(filename, schemaFile string) (parser.JSONValue, error)
+	ParseFileStream(filename string, opts ...lexer.Option) error
 	ExitCode() int
 }
 
@@ -19,72 +31,185 @@ type CLIHandler interface {
 type handler struct {
 	fileReader *FileReader
 	exitCode   int
+	parserOpts []parser.Option
+	inv        *invocation // the global flags the handler parses with
 }
 
-// New creates a new CLI handler instance.
-func New() CLIHandler {
-	return &handler{
-		fileReader: NewFileReader(),
-		exitCode:   0, // Default to success
-	}
+// New creates a new CLI handler instance that parses with the given options.
+func New(opts ...parser.Option) CLIHandler {
+	return newInvocation().newHandler(opts...)
 }
 
-// ParseFile reads a file and parses its JSON content.
+// ParseFile reads a file and parses its JSON content. Failures to read the
+// file are reported as *FileError and invalid content as *ParseError.
 func (h *handler) ParseFile(filename string) error {
-	// Check if file exists first
-	if !h.fileReader.FileExists(filename) {
-		h.exitCode = 1
-		return fmt.Errorf("file '%s' does not exist or is not readable", filename)
+	_, err := h.ParseFileValue(filename)
+	return err
+}
+
+// ParseString parses the given JSON string. Invalid input is reported as *ParseError.
+func (h *handler) ParseString(input string) error {
+	_, err := h.ParseStringValue(input)
+	return err
+}
+
+// ParseFileValue is like ParseFile but also returns the parsed value, so
+// programs embedding the handler can use the data. The file's content isn't
+// copied while it is parsed.
+func (h *handler) ParseFileValue(filename string) (parser.JSONValue, error) {
+	content, err := h.readFile(filename)
+	if err != nil {
+		return nil, err
 	}
 
-	// Read the file content
-	content, err := h.fileReader.ReadFile(filename)
+	return h.parse(h.inv.newBytesLexer(content), sourceOf(content))
+}
+
+// ParseStringValue is like ParseString but also returns the parsed value.
+func (h *handler) ParseStringValue(input string) (parser.JSONValue, error) {
+	return h.parse(h.inv.newLexer(input), input)
+}
+
+// parse parses the document tokenized by lex, whose source is shown in
+// errors.
+func (h *handler) parse(lex lexer.Lexer, source string) (parser.JSONValue, error) {
+	// Create the parser with enhanced error reporting
+	p := parser.NewWithInput(lex, source, h.options()...)
+
+	value, err := p.Parse()
 	if err != nil {
-		h.exitCode = 1
-		return fmt.Errorf("error reading file: %w", err)
+		return nil, h.fail(&ParseError{Err: err})
 	}
 
-	// Parse the content
-	return h.ParseString(content)
+	h.exitCode = ExitSuccess
+	return value, nil
 }
 
-// ParseString parses the given JSON string.
-func (h *handler) ParseString(input string) error {
-	// Create lexer and parser with enhanced error reporting
-	lex := lexer.New(input)
-	p := parser.NewWithInput(lex, input)
+// ParseFileValues reads a file holding any number of concatenated JSON
+// values, such as newline-delimited JSON, and returns them in order.
+func (h *handler) ParseFileValues(filename string) ([]parser.JSONValue, error) {
+	content, err := h.readFile(filename)
+	if err != nil {
+		return nil, err
+	}
 
-	// Parse the JSON
-	_, err := p.Parse()
+	values, err := parser.NewWithInput(h.inv.newBytesLexer(content), sourceOf(content), h.options()...).ParseAll()
 	if err != nil {
-		h.exitCode = 1
-		return fmt.Errorf("JSON parsing failed: %w", err)
+		return nil, h.fail(&ParseError{Err: err})
 	}
 
-	// If we reach here, parsing was successful
-	h.exitCode = 0
-	return nil
+	h.exitCode = ExitSuccess
+	return values, nil
 }
 
-// ExitCode returns the current exit code.
-func (h *handler) ExitCode() int {
-	return h.exitCode
+// ParseFileWithSchema parses filename like ParseFile and validates the
+// document against the JSON Schema in schemaFile. Violations are reported as
+// *SchemaError, which sets the exit code ExitSchema, so scripts can tell
+// invalid JSON from documents breaking the schema. A schema that can't be
+// read, parsed or compiled is reported like a document that can't.
+func (h *handler) ParseFileWithSchema(filename, schemaFile string) error {
+	_, err := h.ParseFileValueWithSchema(filename, schemaFile)
+	return err
 }
 
-// Run is a convenience method that handles command line arguments and exits.
-func Run() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <filename>\n", os.Args[0])
-		os.Exit(1)
+// ParseFileValueWithSchema is like ParseFileWithSchema but also returns the
+// parsed value.
+func (h *handler) ParseFileValueWithSchema(filename, schemaFile string) (parser.JSONValue, error) {
+	doc, err := h.ParseFileValue(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	s, err := schema.Compile(doc)
+	if err != nil {
+		return nil, h.fail(fmt.Errorf("%s: %w", schemaFile, err))
+	}
+
+	value, err := h.ParseFileValue(filename)
+	if err != nil {
+		return nil, err
+	}
+	if result := s.Validate(value); !result.Valid() {
+		return nil, h.fail(&SchemaError{Path: filename, Schema: schemaFile, Violations: result.Errors})
 	}
+	return value, nil
+}
 
-	filename := os.Args[1]
-	handler := New()
+// ParseFileStream validates filename like ParseFile without reading it into
+// memory or building its value: the file is tokenized through a buffered
+// reader keeping only the stack of open containers, so files larger than the
+// available memory can be validated. opts configure the lexer, e.g. with
+// lexer.WithProgress. The global limits apply. Errors have no source snippet,
+// and the parser's option-dependent checks, such as duplicate keys, are not
+// made.
+func (h *handler) ParseFileStream(filename string, opts ...lexer.Option) error {
+	r, err := h.fileReader.Open(filename)
+	if err != nil {
+		return h.fail(&FileError{Path: filename, Err: err})
+	}
+	defer r.Close()
 
-	err := handler.ParseFile(filename)
+	lex := lexer.NewReader(r, append(opts, h.inv.syntaxOptions()...)...)
+	if h.inv.rejectsBOM() && lex.BOM() {
+		message, _ := parser.English.Format(parser.MsgByteOrderMark)
+		return h.fail(&ParseError{Err: fmt.Errorf("%s: %s", filename, message)})
+	}
+	events := stream.NewEventReader(lex)
+	depth := 0
+	for {
+		event, err := events.Next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			err = h.inv.limits.check(event, &depth)
+		}
+		if err != nil {
+			return h.fail(&ParseError{Err: err})
+		}
+	}
+
+	h.exitCode = ExitSuccess
+	return nil
+}
+
+// readFile reads filename, stopping once it exceeds the global --max-bytes
+// limit, which is reported as a *ParseError.
+func (h *handler) readFile(filename string) ([]byte, error) {
+	content, err := readLimited(h.fileReader, filename, h.inv.limits.bytes)
+	if errors.Is(err, ErrTooLarge) || errors.Is(err, fetch.ErrTooLarge) {
+		message, _ := parser.English.Format(parser.MsgMaxBytes, h.inv.limits.bytes)
+		return nil, h.fail(&ParseError{Err: fmt.Errorf("%s: %s", filename, message)})
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, h.fail(&FileError{Path: filename, Err: err})
 	}
+	return content, nil
+}
 
-	os.Exit(handler.ExitCode())
+// sourceOf returns content, which is never modified once read, as a string
+// sharing its memory, for error messages quoting the source.
+func sourceOf(content []byte) string {
+	return unsafe.String(unsafe.SliceData(content), len(content))
+}
+
+// options returns the parser options: those of the invocation's global
+// flags, then the handler's own, which take precedence.
+func (h *handler) options() []parser.Option {
+	return append(h.inv.parserOptions(), h.parserOpts...)
+}
+
+// fail records the exit code matching err and returns it.
+func (h *handler) fail(err error) error {
+	h.exitCode = exitCodeFor(err)
+	return err
+}
+
+// ExitCode returns the current exit code.
+func (h *handler) ExitCode() int {
+	return h.exitCode
+}
+
+// Run is a convenience method that handles command line arguments and exits.
+func Run() {
+	os.Exit(run(os.Args[0], os.Args[1:], os.Stdout, os.Stderr))
 }
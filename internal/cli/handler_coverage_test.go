@@ -15,8 +15,8 @@ func TestHandler_EdgeCases(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for empty filename")
 	}
-	if h.ExitCode() != 1 {
-		t.Errorf("expected exit code 1, got %d", h.ExitCode())
+	if h.ExitCode() != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, h.ExitCode())
 	}
 
 	// Test with directory instead of file
@@ -25,8 +25,8 @@ func TestHandler_EdgeCases(t *testing.T) {
 	if err == nil {
 		t.Error("expected error when parsing directory")
 	}
-	if h.ExitCode() != 1 {
-		t.Errorf("expected exit code 1, got %d", h.ExitCode())
+	if h.ExitCode() != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, h.ExitCode())
 	}
 }
 
@@ -1,9 +1,16 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
 )
 
 func TestNew(t *testing.T) {
@@ -18,6 +25,34 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_IgnoresGlobalFlags(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "comments.json")
+	if err := os.WriteFile(file, []byte("[1 /* c */]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if exitCode := run("json-parser", []string{"--allow-comments", file}, &stdout, &stderr); exitCode != ExitSuccess {
+		t.Fatalf("expected --allow-comments to accept comments, got exit code %d: %s", exitCode, stderr.String())
+	}
+
+	// The flags of a run don't leak into handlers created afterwards.
+	if err := New().ParseFile(file); err == nil {
+		t.Error("expected New() to reject comments")
+	}
+
+	nested := filepath.Join(dir, "nested.json")
+	if err := os.WriteFile(nested, []byte("[[1]]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if exitCode := run("json-parser", []string{"--max-depth=1", nested}, &stdout, &stderr); exitCode == ExitSuccess {
+		t.Fatal("expected --max-depth=1 to reject a nested array")
+	}
+	if err := New().ParseFile(nested); err != nil {
+		t.Errorf("expected New() to have no depth limit, got %v", err)
+	}
+}
+
 func TestHandler_ParseString(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -136,13 +171,13 @@ func TestHandler_ParseFile(t *testing.T) {
 			name:         "non-existent file",
 			filename:     nonExistentFile,
 			expectError:  true,
-			expectedExit: 1,
+			expectedExit: ExitFileError,
 		},
 		{
 			name:         "empty filename",
 			filename:     "",
 			expectError:  true,
-			expectedExit: 1,
+			expectedExit: ExitFileError,
 		},
 	}
 
@@ -208,3 +243,245 @@ func TestHandler_ExitCode(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ParseStringValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expected     any
+		expectError  bool
+		expectedExit int
+	}{
+		{
+			name:         "object",
+			input:        `{"name": "test", "count": 2}`,
+			expected:     parser.JSONObject{"name": "test", "count": int64(2)},
+			expectedExit: ExitSuccess,
+		},
+		{
+			name:         "array",
+			input:        `[true, null]`,
+			expected:     []any{true, nil},
+			expectedExit: ExitSuccess,
+		},
+		{
+			name:         "invalid JSON",
+			input:        `{"name": }`,
+			expectError:  true,
+			expectedExit: ExitInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New()
+
+			value, err := handler.ParseStringValue(tt.input)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				if value != nil {
+					t.Errorf("expected nil value on error, got %v", value)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(value, tt.expected) {
+					t.Errorf("expected %v, got %v", tt.expected, value)
+				}
+			}
+
+			if handler.ExitCode() != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d", tt.expectedExit, handler.ExitCode())
+			}
+		})
+	}
+}
+
+func TestHandler_ParseFileValue(t *testing.T) {
+	tempDir := t.TempDir()
+
+	validFile := filepath.Join(tempDir, "valid.json")
+	if err := os.WriteFile(validFile, []byte(`{"items": [1, 2]}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	handler := New()
+
+	value, err := handler.ParseFileValue(validFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := parser.JSONObject{"items": []any{int64(1), int64(2)}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("expected %v, got %v", expected, value)
+	}
+
+	value, err = handler.ParseFileValue(filepath.Join(tempDir, "missing.json"))
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+	if value != nil {
+		t.Errorf("expected nil value on error, got %v", value)
+	}
+	if handler.ExitCode() != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, handler.ExitCode())
+	}
+}
+
+func TestExtractAllowComments(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expected     bool
+		expectedRest []string
+		wantErr      bool
+	}{
+		{name: "absent", args: []string{"sort", "a.json"}, expectedRest: []string{"sort", "a.json"}},
+		{name: "before the command", args: []string{"--allow-comments", "sort", "a.json"}, expected: true, expectedRest: []string{"sort", "a.json"}},
+		{name: "after the filename", args: []string{"a.json", "--allow-comments"}, expected: true, expectedRest: []string{"a.json"}},
+		{name: "after terminator", args: []string{"--", "--allow-comments"}, expectedRest: []string{"--", "--allow-comments"}},
+		{name: "with a value", args: []string{"--allow-comments=yes", "a.json"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allow, rest, err := extractAllowComments(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if allow != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, allow)
+			}
+			if !tt.wantErr && !slices.Equal(rest, tt.expectedRest) {
+				t.Errorf("expected rest %q, got %q", tt.expectedRest, rest)
+			}
+		})
+	}
+}
+
+func TestRun_AllowComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.jsonc")
+	content := "{\n  // editor\n  \"tabSize\": 2, /* spaces */\n  \"b\": 1\n}\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "rejected by default", args: []string{path}, expectedExit: ExitInvalid},
+		{name: "validated", args: []string{"--allow-comments", path}, expectedExit: ExitSuccess},
+		{name: "command", args: []string{"sort", path, "--allow-comments"}, expectedExit: ExitSuccess, expectedOut: "{\n  \"b\": 1,\n  \"tabSize\": 2\n}\n"},
+		{name: "strict command", args: []string{"validate", path}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ParseFileWithSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	schemaFile := writeFile("schema.json", `{"type": "object", "required": ["name"], "properties": {"port": {"type": "integer", "maximum": 65535}}}`)
+	badSchema := writeFile("bad-schema.json", `{"type": 7}`)
+	valid := writeFile("valid.json", `{"name": "app", "port": 80}`)
+	violating := writeFile("violating.json", `{"port": 70000}`)
+	invalid := writeFile("invalid.json", `{"name": }`)
+
+	tests := []struct {
+		name         string
+		filename     string
+		schemaFile   string
+		expectedExit int
+	}{
+		{name: "conforming", filename: valid, schemaFile: schemaFile, expectedExit: ExitSuccess},
+		{name: "violating", filename: violating, schemaFile: schemaFile, expectedExit: ExitSchema},
+		{name: "invalid JSON", filename: invalid, schemaFile: schemaFile, expectedExit: ExitInvalid},
+		{name: "missing document", filename: filepath.Join(tempDir, "missing.json"), schemaFile: schemaFile, expectedExit: ExitFileError},
+		{name: "missing schema", filename: valid, schemaFile: filepath.Join(tempDir, "missing.json"), expectedExit: ExitFileError},
+		{name: "invalid schema", filename: valid, schemaFile: badSchema, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New()
+			err := handler.ParseFileWithSchema(tt.filename, tt.schemaFile)
+			if (err != nil) != (tt.expectedExit != ExitSuccess) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if handler.ExitCode() != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d", tt.expectedExit, handler.ExitCode())
+			}
+		})
+	}
+
+	var schemaErr *SchemaError
+	if err := New().ParseFileWithSchema(violating, schemaFile); !errors.As(err, &schemaErr) || len(schemaErr.Violations) != 2 {
+		t.Errorf("expected a *SchemaError with 2 violations, got %v", err)
+	}
+}
+
+func TestHandler_ParseFileStream(t *testing.T) {
+	tempDir := t.TempDir()
+	inputs := []string{
+		`{"name": "app", "tags": ["a", "b"], "n": 1.5e3, "ok": true, "none": null}`,
+		`[[], {}, [[1]]]`,
+		`  "scalar"  `,
+		`{"a": 1,}`,
+		`{"a" 1}`,
+		`[1, 2`,
+		`{} extra`,
+		`[01]`,
+		`"unterminated`,
+		``,
+	}
+
+	for i, input := range inputs {
+		filename := filepath.Join(tempDir, fmt.Sprintf("doc%d.json", i))
+		if err := os.WriteFile(filename, []byte(input), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		t.Run(input, func(t *testing.T) {
+			whole, streamed := New(), New()
+			wholeErr := whole.ParseFile(filename)
+			streamErr := streamed.ParseFileStream(filename)
+			if (wholeErr == nil) != (streamErr == nil) {
+				t.Errorf("expected the same outcome as ParseFile (%v), got %v", wholeErr, streamErr)
+			}
+			if whole.ExitCode() != streamed.ExitCode() {
+				t.Errorf("expected exit code %d, got %d", whole.ExitCode(), streamed.ExitCode())
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		handler := New()
+		var fileErr *FileError
+		if err := handler.ParseFileStream(filepath.Join(tempDir, "missing.json")); !errors.As(err, &fileErr) || handler.ExitCode() != ExitFileError {
+			t.Errorf("expected a *FileError, got %v", err)
+		}
+	})
+}
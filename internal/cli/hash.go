@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+)
+
+// runHash implements `json-parser hash <file>`, which prints the SHA-256
+// digest of the canonicalized (RFC 8785) document. Formatting-only edits and
+// key reordering leave the digest unchanged, so scripts can compare digests to
+// detect semantic changes.
+func (inv *invocation) runHash(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("hash", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: hash <filename>")
+		return ExitInvalid
+	}
+
+	value, err := inv.newHandler().ParseFileValue(positional[0])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	digest, err := encoder.Hash(value)
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout, hex.EncodeToString(digest[:]))
+	return ExitSuccess
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHash(t *testing.T) {
+	tempDir := t.TempDir()
+
+	compact := filepath.Join(tempDir, "compact.json")
+	if err := os.WriteFile(compact, []byte(`{"a":1,"b":[true,null]}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	reformatted := filepath.Join(tempDir, "reformatted.json")
+	if err := os.WriteFile(reformatted, []byte("{\n  \"b\": [ true, null ],\n  \"a\": 1.0\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	changed := filepath.Join(tempDir, "changed.json")
+	if err := os.WriteFile(changed, []byte(`{"a":2,"b":[true,null]}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	hashOf := func(filename string) string {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"hash", filename}, &stdout, &stderr); exitCode != 0 {
+			t.Fatalf("hash %s failed with exit code %d: %s", filename, exitCode, stderr.String())
+		}
+		return strings.TrimSpace(stdout.String())
+	}
+
+	compactHash := hashOf(compact)
+	if len(compactHash) != 64 {
+		t.Errorf("expected a 64 character hex digest, got %q", compactHash)
+	}
+	if reformattedHash := hashOf(reformatted); reformattedHash != compactHash {
+		t.Errorf("formatting-only change altered the digest: %s vs %s", compactHash, reformattedHash)
+	}
+	if changedHash := hashOf(changed); changedHash == compactHash {
+		t.Error("semantic change did not alter the digest")
+	}
+}
+
+func TestRunHash_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+	}{
+		{name: "missing filename", args: []string{"hash"}, expectedExit: ExitInvalid},
+		{name: "non-existent file", args: []string{"hash", filepath.Join(t.TempDir(), "missing.json")}, expectedExit: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d", tt.expectedExit, exitCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// runIndex implements `json-parser index [--depth N] <file>`, which scans the
+// document without loading it and prints the JSON Pointer of each value at
+// depth N (1, the top-level members or elements, by default) with the byte
+// offsets where it starts and ends, one tab-separated line each. The end
+// offset is exclusive, so tools can seek to a record and read end-start
+// bytes, e.g. `tail -c +$((start+1)) file | head -c $((end-start))`.
+func (inv *invocation) runIndex(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("index", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	depth := fs.Int("depth", 1, "index the values nested `N` levels deep (0 for the document)")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: index [--depth N] <filename>")
+		return ExitInvalid
+	}
+	if *depth < 0 {
+		printError(stderr, "--depth must not be negative")
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+	defer file.Close()
+
+	for r, err := range stream.Index(file, *depth) {
+		if err != nil {
+			printError(stderr, "%v", &ParseError{Err: fmt.Errorf("%s: %w", filename, err)})
+			return ExitInvalid
+		}
+		fmt.Fprintf(stdout, "%s\t%d\t%d\n", r.Path, r.Start, r.End)
+	}
+	return ExitSuccess
+}
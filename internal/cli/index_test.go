@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunIndex(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"a": [1, 22], "b/c": {"d": true}}`)
+	invalid := writeFile("invalid.json", `{"a": 1, "b": }`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "top level", args: []string{"index", doc}, expectedExit: ExitSuccess, expectedOut: "/a\t6\t13\n/b~1c\t22\t33\n"},
+		{name: "depth", args: []string{"index", doc, "--depth", "2"}, expectedExit: ExitSuccess, expectedOut: "/a/0\t7\t8\n/a/1\t10\t12\n/b~1c/d\t28\t32\n"},
+		{name: "document", args: []string{"index", "--depth", "0", doc}, expectedExit: ExitSuccess, expectedOut: "\t0\t34\n"},
+		{name: "negative depth", args: []string{"index", "--depth", "-1", doc}, expectedExit: ExitInvalid},
+		{name: "invalid document", args: []string{"index", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"index", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"index"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
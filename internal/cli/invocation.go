@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/fetch"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// invocation holds the global flags of one run of the CLI. run sets them
+// before dispatching to a subcommand, which hands them on to the handlers it
+// creates, so that nothing depends on an earlier run.
+type invocation struct {
+	allowComments bool           // --allow-comments
+	profile       parser.Profile // --profile, or the project config's
+	limits        limits
+	stdinTimeout  time.Duration
+	fetchTimeout  time.Duration
+	config        projectConfig // the project config in effect, the zero value if there is none
+}
+
+// newInvocation returns the flag defaults, as used by New.
+func newInvocation() *invocation {
+	return &invocation{fetchTimeout: fetch.DefaultTimeout}
+}
+
+// extractAllowComments removes the global --allow-comments flag from args,
+// wherever it appears before a "--" terminator, and returns whether it was
+// given and the remaining arguments.
+func extractAllowComments(args []string) (bool, []string, error) {
+	allow := false
+	rest := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "allow-comments" {
+			rest = append(rest, arg)
+			continue
+		}
+		if hasValue {
+			return false, nil, fmt.Errorf("--allow-comments does not take a value")
+		}
+		allow = true
+	}
+	return allow, rest, nil
+}
+
+// newHandler returns a handler parsing with the global flags and then opts,
+// which take precedence.
+func (inv *invocation) newHandler(opts ...parser.Option) *handler {
+	return &handler{
+		fileReader: inv.fileReader(),
+		exitCode:   ExitSuccess,
+		parserOpts: opts,
+		inv:        inv,
+	}
+}
+
+// fileReader returns a FileReader applying the global flags.
+func (inv *invocation) fileReader() *FileReader {
+	return &FileReader{maxBytes: inv.limits.bytes, stdinTimeout: inv.stdinTimeout, fetchTimeout: inv.fetchTimeout}
+}
+
+// newLexer returns a lexer for input that accepts the syntax selected by the
+// global flags.
+func (inv *invocation) newLexer(input string) lexer.Lexer {
+	return lexer.New(input, inv.lexerOptions()...)
+}
+
+// newBytesLexer is like newLexer for input read into memory, which it
+// doesn't copy (see lexer.NewBytes).
+func (inv *invocation) newBytesLexer(input []byte) lexer.Lexer {
+	return lexer.NewBytes(input, inv.lexerOptions()...)
+}
+
+// lexerOptions returns the lexer options for the syntax selected by the
+// global flags. Strings aren't copied out of the input, which the CLI never
+// modifies.
+func (inv *invocation) lexerOptions() []lexer.Option {
+	return append([]lexer.Option{lexer.WithZeroCopy()}, inv.syntaxOptions()...)
+}
+
+// syntaxOptions returns the lexer options of the profile, with comments
+// accepted if --allow-comments is given.
+func (inv *invocation) syntaxOptions() []lexer.Option {
+	opts := inv.profile.LexerOptions()
+	if inv.allowComments {
+		opts = append(opts, lexer.WithComments())
+	}
+	return opts
+}
+
+// parserOptions returns the parser options for the limits and profile
+// selected by the global flags.
+func (inv *invocation) parserOptions() []parser.Option {
+	return append(inv.limits.options(), parser.WithProfile(inv.profile))
+}
+
+// rejectsBOM reports whether the profile makes a byte order mark an error,
+// which readers that don't go through the parser check themselves.
+func (inv *invocation) rejectsBOM() bool {
+	return inv.profile == parser.ProfileStrict8259 || inv.profile == parser.ProfileIJSON
+}
@@ -1,30 +1,224 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/fetch"
 )
 
+// StdinName is the filename that stands for standard input.
+const StdinName = "-"
+
+// ErrStdinTerminal is returned when standard input is to be read but is a
+// terminal, where reading would wait for the user to type a document.
+var ErrStdinTerminal = errors.New("standard input is a terminal; pipe a document into it or pass a filename")
+
+// ErrStdinTimeout is returned when no data arrives on standard input for the
+// --stdin-timeout duration.
+var ErrStdinTimeout = errors.New("timed out waiting for standard input")
+
+// expandGlobs replaces the arguments that are glob patterns (see
+// filepath.Match), such as configs/*.json, with the files they match in
+// lexical order, for shells that don't expand patterns themselves or when
+// they are quoted. Arguments naming existing files are kept as they are, even
+// if they contain pattern characters, and so are URLs and arguments without
+// any. Matches ignored by the project config are dropped. A pattern matching
+// nothing is an error.
+func (inv *invocation) expandGlobs(args []string) ([]string, error) {
+	files := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == StdinName || fetch.IsURL(arg) || !strings.ContainsAny(arg, "*?[") {
+			files = append(files, arg)
+			continue
+		}
+		if _, err := os.Stat(arg); err == nil {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		for _, match := range matches {
+			if !inv.config.ignored(match) {
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// walkJSONFiles replaces the arguments that are directories with the *.json
+// files in their trees, in lexical order, skipping the files and directories
+// ignored by the project config. Other arguments are kept as they are.
+func (inv *invocation) walkJSONFiles(args []string) ([]string, error) {
+	files := make([]string, 0, len(args))
+	for _, arg := range args {
+		if info, err := os.Stat(arg); arg == StdinName || err != nil || !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err := filepath.WalkDir(arg, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch {
+			case path != arg && inv.config.ignored(path):
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+			case !entry.IsDir() && filepath.Ext(path) == ".json":
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// extractStdinTimeout removes the global --stdin-timeout=DURATION flag from
+// args, wherever it appears before a "--" terminator, and returns its value
+// and the remaining arguments. The last flag wins.
+func extractStdinTimeout(args []string) (time.Duration, []string, error) {
+	timeout, _, rest, err := extractDuration(args, "stdin-timeout")
+	return timeout, rest, err
+}
+
+// extractFetchTimeout is like extractStdinTimeout for --fetch-timeout, which
+// defaults to fetch.DefaultTimeout.
+func extractFetchTimeout(args []string) (time.Duration, []string, error) {
+	timeout, found, rest, err := extractDuration(args, "fetch-timeout")
+	if !found {
+		timeout = fetch.DefaultTimeout
+	}
+	return timeout, rest, err
+}
+
+// extractDuration removes the global --name=DURATION flag from args,
+// wherever it appears before a "--" terminator, and returns its value,
+// whether it was given and the remaining arguments. The last flag wins.
+func extractDuration(args []string, flagName string) (time.Duration, bool, []string, error) {
+	var (
+		d     time.Duration
+		found bool
+	)
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != flagName {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return 0, false, nil, fmt.Errorf("--%s requires a duration, e.g. 30s", flagName)
+			}
+			i++
+			value = args[i]
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return 0, false, nil, fmt.Errorf("invalid --%s value %q (expected a duration such as 30s)", flagName, value)
+		}
+		d, found = parsed, true
+	}
+	return d, found, rest, nil
+}
+
 // FileReader provides utilities for reading files.
-type FileReader struct{}
+type FileReader struct {
+	maxBytes     int           // --max-bytes, limiting downloads; 0 for none
+	stdinTimeout time.Duration // --stdin-timeout; 0 to wait indefinitely
+	fetchTimeout time.Duration // --fetch-timeout, bounding downloads; 0 for none
+}
 
 // NewFileReader creates a new FileReader instance.
 func NewFileReader() *FileReader {
-	return &FileReader{}
+	return &FileReader{fetchTimeout: fetch.DefaultTimeout}
 }
 
-// ReadFile reads the contents of a file and returns it as a string.
+// ReadFile reads the contents of a file and returns it as a string. The
+// filename "-" reads standard input. Compressed files are decompressed, see
+// Open.
 func (fr *FileReader) ReadFile(filename string) (string, error) {
+	data, err := fr.ReadBytes(filename)
+	return string(data), err
+}
+
+// ReadBytes is like ReadFile but returns the contents as read, without
+// copying them into a string.
+func (fr *FileReader) ReadBytes(filename string) ([]byte, error) {
 	if filename == "" {
-		return "", fmt.Errorf("filename cannot be empty")
+		return nil, fmt.Errorf("filename cannot be empty")
+	}
+
+	r, err := fr.Open(filename)
+	if err != nil {
+		if filename == StdinName {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file '%s': %w", filename, err)
 	}
+	defer r.Close()
 
-	data, err := os.ReadFile(filename)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file '%s': %w", filename, err)
+		if filename == StdinName {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		return nil, fmt.Errorf("failed to read file '%s': %w", filename, err)
 	}
+	return data, nil
+}
 
-	return string(data), nil
+// Open opens a file for streaming. The filename "-" returns standard input,
+// which fails with ErrStdinTerminal if it is a terminal and, with a
+// --stdin-timeout, with ErrStdinTimeout once no data arrives for that long.
+// http and https URLs are downloaded (see fetch.Fetcher), within the
+// --fetch-timeout and --max-bytes limits the reader was created with. gzip- and zstd-compressed input,
+// named *.gz or *.zst or recognized by its magic number, is decompressed on
+// the fly.
+func (fr *FileReader) Open(filename string) (io.ReadCloser, error) {
+	if fetch.IsURL(filename) {
+		body, err := fetch.New(fetch.WithTimeout(fr.fetchTimeout), fetch.WithMaxBytes(int64(fr.maxBytes))).Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		return decompress(body, filename)
+	}
+	if filename != StdinName {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		return decompress(file, filename)
+	}
+	if isTerminal(os.Stdin) {
+		return nil, ErrStdinTerminal
+	}
+	if fr.stdinTimeout <= 0 {
+		return decompress(io.NopCloser(os.Stdin), filename)
+	}
+	return decompress(io.NopCloser(&idleReader{r: os.Stdin, timeout: fr.stdinTimeout}), filename)
 }
 
 // FileExists checks if a file exists and is readable.
@@ -32,3 +226,41 @@ func (fr *FileReader) FileExists(filename string) bool {
 	_, err := os.Stat(filename)
 	return err == nil
 }
+
+// idleReader fails reads from r that don't return within timeout, so that a
+// producer that stalls without closing the pipe doesn't hang the CLI.
+type idleReader struct {
+	r       io.Reader
+	timeout time.Duration
+	buf     []byte
+}
+
+// readResult is the outcome of a Read on the underlying reader.
+type readResult struct {
+	n   int
+	err error
+}
+
+// Read implements io.Reader. A read that times out is abandoned; the
+// goroutine performing it ends with the process.
+func (ir *idleReader) Read(p []byte) (int, error) {
+	if cap(ir.buf) < len(p) {
+		ir.buf = make([]byte, len(p))
+	}
+	buf := ir.buf[:len(p)]
+	done := make(chan readResult, 1)
+	go func() {
+		n, err := ir.r.Read(buf)
+		done <- readResult{n, err}
+	}()
+
+	timer := time.NewTimer(ir.timeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return copy(p, buf[:res.n]), res.err
+	case <-timer.C:
+		ir.buf = nil // still being read into
+		return 0, fmt.Errorf("%w after %v", ErrStdinTimeout, ir.timeout)
+	}
+}
@@ -1,9 +1,19 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/fetch"
 )
 
 func TestNewFileReader(t *testing.T) {
@@ -11,6 +21,9 @@ func TestNewFileReader(t *testing.T) {
 	if fr == nil {
 		t.Fatal("NewFileReader() returned nil")
 	}
+	if fr.fetchTimeout != fetch.DefaultTimeout {
+		t.Errorf("expected the default fetch timeout, got %v", fr.fetchTimeout)
+	}
 }
 
 func TestFileReader_ReadFile(t *testing.T) {
@@ -130,3 +143,199 @@ func TestFileReader_FileExists(t *testing.T) {
 		})
 	}
 }
+
+// withStdin replaces os.Stdin for the duration of the test with a pipe
+// holding content, closed unless keepOpen is set.
+func withStdin(t *testing.T, content string, keepOpen bool) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	if _, err := w.WriteString(content); err != nil {
+		t.Fatalf("failed to write to pipe: %v", err)
+	}
+	if keepOpen {
+		t.Cleanup(func() { w.Close() })
+	} else {
+		w.Close()
+	}
+
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		r.Close()
+	})
+}
+
+func TestExtractStdinTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expected     time.Duration
+		expectedRest []string
+		wantErr      bool
+	}{
+		{name: "absent", args: []string{"sort", "-"}, expectedRest: []string{"sort", "-"}},
+		{name: "with equals", args: []string{"--stdin-timeout=2s", "-"}, expected: 2 * time.Second, expectedRest: []string{"-"}},
+		{name: "separate value", args: []string{"-", "--stdin-timeout", "500ms"}, expected: 500 * time.Millisecond, expectedRest: []string{"-"}},
+		{name: "after terminator", args: []string{"--", "--stdin-timeout=1s"}, expectedRest: []string{"--", "--stdin-timeout=1s"}},
+		{name: "missing value", args: []string{"-", "--stdin-timeout"}, wantErr: true},
+		{name: "invalid value", args: []string{"--stdin-timeout=soon", "-"}, wantErr: true},
+		{name: "negative value", args: []string{"--stdin-timeout=-1s", "-"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, rest, err := extractStdinTimeout(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if timeout != tt.expected {
+				t.Errorf("expected timeout %v, got %v", tt.expected, timeout)
+			}
+			if !tt.wantErr && !slices.Equal(rest, tt.expectedRest) {
+				t.Errorf("expected rest %q, got %q", tt.expectedRest, rest)
+			}
+		})
+	}
+}
+
+func TestExpandGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.json", "c.txt", "[x].json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "plain names", args: []string{"-", path("c.txt"), path("missing.json")}, expected: []string{"-", path("c.txt"), path("missing.json")}},
+		{name: "pattern", args: []string{path("*.json"), path("c.txt")}, expected: []string{path("[x].json"), path("a.json"), path("b.json"), path("c.txt")}},
+		{name: "existing file with pattern characters", args: []string{path("[x].json")}, expected: []string{path("[x].json")}},
+		{name: "no match", args: []string{path("*.yaml")}, wantErr: true},
+		{name: "invalid pattern", args: []string{path("[.json")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := newInvocation().expandGlobs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !slices.Equal(files, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, files)
+			}
+		})
+	}
+}
+
+func TestRun_Stdin(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "validate", args: []string{"-"}, input: `{"a": 1}`, expectedExit: ExitSuccess},
+		{name: "invalid", args: []string{"-"}, input: `{"a": }`, expectedExit: ExitInvalid},
+		{name: "sort", args: []string{"sort", "--indent", "0", "-"}, input: `{"b": 1, "a": 2}`, expectedExit: ExitSuccess, expectedOut: "{\n\"a\": 2,\n\"b\": 1\n}\n"},
+		{name: "streaming", args: []string{"edit", "--delete", "/a", "-"}, input: `{"a": 1, "b": 2}`, expectedExit: ExitSuccess, expectedOut: `{"b":2}` + "\n"},
+		{name: "with timeout", args: []string{"--stdin-timeout=1m", "-"}, input: `[1]`, expectedExit: ExitSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input, false)
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_StdinIdleTimeout(t *testing.T) {
+	withStdin(t, `{"a": `, true)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run("json-parser", []string{"--stdin-timeout=50ms", "-"}, &stdout, &stderr)
+	if exitCode != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, exitCode)
+	}
+	if !strings.Contains(stderr.String(), "timed out waiting for standard input after 50ms") {
+		t.Errorf("expected a timeout error, got %q", stderr.String())
+	}
+}
+
+func TestRun_StdinTerminal(t *testing.T) {
+	// /dev/null is a character device, like a terminal.
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Skipf("cannot open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	stdin := os.Stdin
+	os.Stdin = devNull
+	defer func() { os.Stdin = stdin }()
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run("json-parser", []string{"-"}, &stdout, &stderr); exitCode != ExitInvalid {
+		t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Usage:") {
+		t.Errorf("expected usage, got %q", stderr.String())
+	}
+
+	if _, err := NewFileReader().Open(StdinName); !errors.Is(err, ErrStdinTerminal) {
+		t.Errorf("expected ErrStdinTerminal, got %v", err)
+	}
+}
+
+func TestFileReader_URL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/valid.json":
+			io.WriteString(w, `{"name": "app", "replicas": 3}`)
+		case "/invalid.json":
+			io.WriteString(w, `{"name": }`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+	}{
+		{name: "valid", args: []string{"validate", server.URL + "/valid.json"}, expectedExit: ExitSuccess},
+		{name: "bare form", args: []string{server.URL + "/valid.json?version=2"}, expectedExit: ExitSuccess},
+		{name: "streamed", args: []string{"--stream", server.URL + "/valid.json"}, expectedExit: ExitSuccess},
+		{name: "invalid", args: []string{"validate", server.URL + "/invalid.json"}, expectedExit: ExitInvalid},
+		{name: "not found", args: []string{"validate", server.URL + "/missing.json"}, expectedExit: ExitFileError},
+		{name: "too large", args: []string{"--max-bytes=8", "validate", server.URL + "/valid.json"}, expectedExit: ExitInvalid},
+		{name: "invalid timeout", args: []string{"--fetch-timeout=soon", server.URL + "/valid.json"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// mergeStrategies lists the --merge values for `join --into object`, which
+// decide what happens when several files define the same key.
+var mergeStrategies = map[string]bool{
+	"last":  true, // the later file wins
+	"first": true, // the earlier file wins
+	"error": true, // duplicate keys are an error
+	"deep":  true, // nested objects are merged recursively; otherwise the later file wins
+}
+
+// runJoin implements
+// `json-parser join [--into array|object] [--merge last|first|error|deep] <file>...`,
+// the inverse of split. With --into array (the default) the elements of
+// array files, and object files as single elements, are concatenated into one
+// array that is streamed to stdout as the files are read, so inputs of any
+// size can be joined. With --into object every file must hold an object, and
+// their members are merged into one object according to --merge, which is a
+// usage error with arrays.
+func (inv *invocation) runJoin(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("join", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	into := fs.String("into", "array", "result type: `array` or object")
+	merge := fs.String("merge", "last", "how --into object resolves duplicate keys: `last`, first, error or deep")
+
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if files, err = inv.expandGlobs(files); err != nil {
+		printError(stderr, "%v", err)
+		return ExitFileError
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "Usage: join [--into array|object] [--merge last|first|error|deep] <filename>...")
+		return ExitInvalid
+	}
+	if !mergeStrategies[*merge] {
+		printError(stderr, "invalid --merge value %q (expected last, first, error or deep)", *merge)
+		return ExitInvalid
+	}
+	// Arrays have no keys to merge, so a --merge given with them is a mistake.
+	merged := false
+	fs.Visit(func(f *flag.Flag) { merged = merged || f.Name == "merge" })
+	if merged && *into == "array" {
+		printError(stderr, "--merge requires --into object")
+		return ExitInvalid
+	}
+
+	switch *into {
+	case "array":
+		return inv.joinArrays(files, stdout, stderr)
+	case "object":
+		return inv.joinObjects(files, *merge, stdout, stderr)
+	default:
+		printError(stderr, "invalid --into value %q (expected array or object)", *into)
+		return ExitInvalid
+	}
+}
+
+// joinArrays streams the elements of every file into one array on stdout,
+// one element per line. If a file fails part way, the output is left
+// incomplete and the error is reported.
+func (inv *invocation) joinArrays(files []string, stdout, stderr io.Writer) int {
+	out := stream.NewArrayWriter(stdout)
+	for _, filename := range files {
+		if err := inv.joinFile(filename, out.Write); err != nil {
+			printError(stderr, "%v", err)
+			return exitCodeFor(err)
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	return ExitSuccess
+}
+
+// joinFile passes the elements of the array in filename, or the file's value
+// itself if it isn't an array, to emit. Arrays are streamed.
+func (inv *invocation) joinFile(filename string, emit func(parser.JSONValue) error) error {
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		return &FileError{Path: filename, Err: err}
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	if !startsWithArray(r) {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return &FileError{Path: filename, Err: err}
+		}
+		value, err := inv.newHandler().ParseStringValue(string(content))
+		if err != nil {
+			return err
+		}
+		return emit(value)
+	}
+
+	elements := stream.NewElementReader(r)
+	for {
+		value, err := elements.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &ParseError{Err: fmt.Errorf("%s: %w", filename, err)}
+		}
+		if err := emit(value); err != nil {
+			return err
+		}
+	}
+}
+
+// startsWithArray reports whether the first non-whitespace byte in r opens an
+// array, without consuming it.
+func startsWithArray(r *bufio.Reader) bool {
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch c[0] {
+		case ' ', '\t', '\n', '\r':
+			r.ReadByte()
+		default:
+			return c[0] == '['
+		}
+	}
+}
+
+// joinObjects merges the objects in files into one object and prints it.
+func (inv *invocation) joinObjects(files []string, strategy string, stdout, stderr io.Writer) int {
+	handler := inv.newHandler()
+	result := parser.JSONObject{}
+	for _, filename := range files {
+		value, err := handler.ParseFileValue(filename)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return exitCodeFor(err)
+		}
+		obj, ok := parser.AsObject(value)
+		if !ok {
+			printError(stderr, "%s: --into object requires every file to hold an object", filename)
+			return ExitInvalid
+		}
+		if err := mergeObject(result, obj, strategy, ""); err != nil {
+			printError(stderr, "%s: %v", filename, err)
+			return ExitInvalid
+		}
+	}
+
+	output, err := encoder.MarshalIndent(result, "", "  ")
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout, string(output))
+	return ExitSuccess
+}
+
+// mergeObject copies the members of src into dst, resolving keys present in
+// both according to strategy. path is the JSON Pointer of dst, for errors.
+// Keys are visited in sorted order so the reported duplicate is stable.
+func mergeObject(dst, src map[string]any, strategy, path string) error {
+	for _, key := range slices.Sorted(maps.Keys(src)) {
+		value := src[key]
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+
+		memberPath := path + "/" + pointer.Escape(key)
+		switch strategy {
+		case "first":
+		case "error":
+			return fmt.Errorf("duplicate key at %s", memberPath)
+		case "deep":
+			dstObj, dstIsObj := parser.AsObject(existing)
+			srcObj, srcIsObj := parser.AsObject(value)
+			if !dstIsObj || !srcIsObj {
+				dst[key] = value
+				continue
+			}
+			if err := mergeObject(dstObj, srcObj, strategy, memberPath); err != nil {
+				return err
+			}
+		default:
+			dst[key] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunJoin(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	chunk1 := writeFile("chunk-1.json", `[{"id": 1}, {"id": 2}]`)
+	chunk2 := writeFile("chunk-2.json", "\n [{\"id\": 3}]")
+	emptyArray := writeFile("empty.json", `[]`)
+	base := writeFile("base.json", `{"name": "app", "db": {"host": "localhost", "port": 5432}}`)
+	override := writeFile("override.json", `{"db": {"host": "db.internal"}, "debug": true}`)
+	broken := writeFile("broken.json", `[{"id": 4}, {"id": }]`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+		expectedErr  string
+	}{
+		{
+			name:         "concatenates arrays",
+			args:         []string{"join", chunk1, emptyArray, chunk2},
+			expectedExit: ExitSuccess,
+			expectedOut:  "[\n  {\"id\":1},\n  {\"id\":2},\n  {\"id\":3}\n]\n",
+		},
+		{
+			name:         "objects become elements",
+			args:         []string{"join", chunk2, override},
+			expectedExit: ExitSuccess,
+			expectedOut:  "[\n  {\"id\":3},\n  {\"db\":{\"host\":\"db.internal\"},\"debug\":true}\n]\n",
+		},
+		{name: "nothing to join", args: []string{"join", emptyArray}, expectedExit: ExitSuccess, expectedOut: "[]\n"},
+		{name: "broken array", args: []string{"join", chunk1, broken}, expectedExit: ExitInvalid, expectedErr: "broken.json: array element 1"},
+		{
+			name:         "merge last wins",
+			args:         []string{"join", "--into", "object", base, override},
+			expectedExit: ExitSuccess,
+			expectedOut:  "{\n  \"db\": {\n    \"host\": \"db.internal\"\n  },\n  \"debug\": true,\n  \"name\": \"app\"\n}\n",
+		},
+		{
+			name:         "merge first wins",
+			args:         []string{"join", "--into", "object", "--merge", "first", base, override},
+			expectedExit: ExitSuccess,
+			expectedOut:  "{\n  \"db\": {\n    \"host\": \"localhost\",\n    \"port\": 5432\n  },\n  \"debug\": true,\n  \"name\": \"app\"\n}\n",
+		},
+		{
+			name:         "deep merge",
+			args:         []string{"join", "--into", "object", "--merge", "deep", base, override},
+			expectedExit: ExitSuccess,
+			expectedOut:  "{\n  \"db\": {\n    \"host\": \"db.internal\",\n    \"port\": 5432\n  },\n  \"debug\": true,\n  \"name\": \"app\"\n}\n",
+		},
+		{name: "duplicate keys rejected", args: []string{"join", "--into", "object", "--merge", "error", base, override}, expectedExit: ExitInvalid, expectedErr: "duplicate key at /db"},
+		{name: "object mode requires objects", args: []string{"join", "--into", "object", base, chunk1}, expectedExit: ExitInvalid, expectedErr: "requires every file to hold an object"},
+		{name: "invalid into", args: []string{"join", "--into", "list", chunk1}, expectedExit: ExitInvalid},
+		{name: "invalid merge", args: []string{"join", "--merge", "union", chunk1}, expectedExit: ExitInvalid},
+		{name: "merge with arrays", args: []string{"join", "--merge", "first", chunk1}, expectedExit: ExitInvalid, expectedErr: "--merge requires --into object"},
+		{name: "merge with explicit arrays", args: []string{"join", "--into", "array", "--merge=deep", chunk1}, expectedExit: ExitInvalid, expectedErr: "--merge requires --into object"},
+		{name: "missing file", args: []string{"join", chunk1, filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"join"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedErr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tt.expectedErr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// limits holds the global --max-depth, --max-bytes and --max-string-len
+// settings, which protect the CLI from untrusted documents. Zero fields leave
+// the parser's defaults in place.
+type limits struct {
+	depth     int // see parser.WithMaxDepth
+	bytes     int // see parser.WithMaxBytes
+	stringLen int // see parser.WithMaxStringLength
+}
+
+// ErrTooLarge is returned when an input is longer than the --max-bytes limit.
+// Reading stops there, so huge inputs are never held in memory.
+var ErrTooLarge = errors.New("input too large")
+
+// options returns the parser options applying l.
+func (l limits) options() []parser.Option {
+	var opts []parser.Option
+	if l.depth > 0 {
+		opts = append(opts, parser.WithMaxDepth(l.depth))
+	}
+	if l.bytes > 0 {
+		opts = append(opts, parser.WithMaxBytes(l.bytes))
+	}
+	if l.stringLen > 0 {
+		opts = append(opts, parser.WithMaxStringLength(l.stringLen))
+	}
+	return opts
+}
+
+// check applies l to event, read from a stream while depth containers were
+// open, as the parser would to a document read whole, and updates depth.
+func (l limits) check(event stream.Event, depth *int) error {
+	var message string
+	switch event.Type {
+	case stream.StartObject, stream.StartArray:
+		if *depth++; l.depth > 0 && *depth > l.depth {
+			message, _ = parser.English.Format(parser.MsgMaxDepth, l.depth)
+		}
+	case stream.EndObject, stream.EndArray:
+		*depth--
+	case stream.Key, stream.String:
+		if l.stringLen > 0 && len(event.Value) > l.stringLen {
+			message, _ = parser.English.Format(parser.MsgMaxStringLength, l.stringLen)
+		}
+	}
+	if message == "" && l.bytes > 0 && event.End.Offset > l.bytes {
+		message, _ = parser.English.Format(parser.MsgMaxBytes, l.bytes)
+	}
+	if message != "" {
+		return fmt.Errorf("%s at %s", message, event.Position)
+	}
+	return nil
+}
+
+// extractLimits removes the global --max-depth=N, --max-bytes=N and
+// --max-string-len=N flags from args, wherever they appear before a "--"
+// terminator, and returns their values and the remaining arguments. The last
+// flag of each kind wins.
+func extractLimits(args []string) (limits, []string, error) {
+	var l limits
+	fields := map[string]*int{"max-depth": &l.depth, "max-bytes": &l.bytes, "max-string-len": &l.stringLen}
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		field, ok := fields[name]
+		if !strings.HasPrefix(arg, "-") || !ok {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return limits{}, nil, fmt.Errorf("--%s requires a number", name)
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return limits{}, nil, fmt.Errorf("invalid --%s value %q (expected a positive number)", name, value)
+		}
+		*field = n
+	}
+	return l, rest, nil
+}
+
+// readLimited reads filename like FileReader.ReadBytes, failing with
+// ErrTooLarge once more than n bytes have been read. n <= 0 reads everything.
+func readLimited(fr *FileReader, filename string, n int) ([]byte, error) {
+	if n <= 0 {
+		return fr.ReadBytes(filename)
+	}
+
+	r, err := fr.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, int64(n)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filename, err)
+	}
+	if len(data) > n {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expected     limits
+		expectedRest []string
+		wantErr      bool
+	}{
+		{name: "absent", args: []string{"sort", "a.json"}, expectedRest: []string{"sort", "a.json"}},
+		{
+			name:         "all limits",
+			args:         []string{"--max-depth=8", "a.json", "--max-bytes", "1024", "--max-string-len=64"},
+			expected:     limits{depth: 8, bytes: 1024, stringLen: 64},
+			expectedRest: []string{"a.json"},
+		},
+		{name: "last wins", args: []string{"--max-depth=8", "--max-depth=4", "a.json"}, expected: limits{depth: 4}, expectedRest: []string{"a.json"}},
+		{name: "after terminator", args: []string{"--", "--max-depth=1"}, expectedRest: []string{"--", "--max-depth=1"}},
+		{name: "missing value", args: []string{"a.json", "--max-bytes"}, wantErr: true},
+		{name: "not a number", args: []string{"--max-bytes=1MB", "a.json"}, wantErr: true},
+		{name: "zero", args: []string{"--max-string-len=0", "a.json"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, rest, err := extractLimits(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if l != tt.expected {
+				t.Errorf("expected limits %+v, got %+v", tt.expected, l)
+			}
+			if !tt.wantErr && !slices.Equal(rest, tt.expectedRest) {
+				t.Errorf("expected rest %q, got %q", tt.expectedRest, rest)
+			}
+		})
+	}
+}
+
+func TestLimitFlags(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"name": "app", "tags": [["a"]]}`)
+	large := writeFile("large.json", `{"data": "`+strings.Repeat("x", 4096)+`"}`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStderr string
+	}{
+		{name: "within limits", args: []string{"--max-depth=3", "--max-bytes=64", "--max-string-len=4", doc}, expectedExit: ExitSuccess},
+		{name: "too deep", args: []string{"--max-depth=2", doc}, expectedExit: ExitInvalid, expectedStderr: "nesting exceeds the maximum depth of 2"},
+		{name: "too large", args: []string{"--max-bytes=1024", large}, expectedExit: ExitInvalid, expectedStderr: "document exceeds the maximum size of 1024 bytes"},
+		{name: "string too long", args: []string{"--max-string-len=3", doc}, expectedExit: ExitInvalid, expectedStderr: "string exceeds the maximum length of 3 bytes"},
+		{name: "streamed too deep", args: []string{"--max-depth=2", "--stream", doc}, expectedExit: ExitInvalid, expectedStderr: "nesting exceeds the maximum depth of 2"},
+		{name: "streamed too large", args: []string{"--stream", "--max-bytes=1024", large}, expectedExit: ExitInvalid, expectedStderr: "maximum size"},
+		{name: "streamed string too long", args: []string{"--stream", "--max-string-len=3", doc}, expectedExit: ExitInvalid, expectedStderr: "maximum length"},
+		{name: "subcommand", args: []string{"sort", large, "--max-bytes", "100"}, expectedExit: ExitInvalid, expectedStderr: "maximum size"},
+		{name: "invalid limit", args: []string{"--max-depth=deep", doc}, expectedExit: ExitInvalid, expectedStderr: "invalid --max-depth value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/lint"
+)
+
+// Policies for --on-warning, from most to least lenient, so teams can adopt
+// lint gradually and tighten it over time.
+var warningExitCodes = map[string]int{
+	"ignore": ExitSuccess,  // report warnings but succeed
+	"warn":   ExitWarnings, // succeed with a dedicated exit code
+	"fail":   ExitInvalid,  // treat warnings like invalid input
+}
+
+// runLint implements `json-parser lint [--on-warning ignore|warn|fail] <file>`,
+// which prints lint warnings for a valid document to stdout. The exit code
+// for documents with warnings is chosen by --on-warning.
+func (inv *invocation) runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	onWarning := fs.String("on-warning", "warn", "exit behavior when warnings are found: `ignore` (exit 0), warn (exit 3) or fail (exit 1)")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: lint [--on-warning ignore|warn|fail] <filename>")
+		return ExitInvalid
+	}
+	warningExit, ok := warningExitCodes[*onWarning]
+	if !ok {
+		printError(stderr, "invalid --on-warning value %q (expected ignore, warn or fail)", *onWarning)
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	value, err := inv.newHandler().ParseFileValue(filename)
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	warnings := lint.New().Lint(value)
+	for _, warning := range warnings {
+		fmt.Fprintf(stdout, "%s: %s\n", filename, colorize(stdout, warning.String(), colorYellow))
+	}
+
+	if len(warnings) > 0 {
+		return warningExit
+	}
+	return ExitSuccess
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunLint(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	clean := writeFile("clean.json", `{"id": 1, "tags": ["a", "b"]}`)
+	warnings := writeFile("warnings.json", `{"id": 9007199254740993, "tags": ["a", 1]}`)
+	invalid := writeFile("invalid.json", `{"id": }`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStdout []string
+	}{
+		{name: "clean", args: []string{"lint", clean}, expectedExit: ExitSuccess},
+		{name: "clean with fail policy", args: []string{"lint", "--on-warning", "fail", clean}, expectedExit: ExitSuccess},
+		{
+			name:           "warnings default to dedicated code",
+			args:           []string{"lint", warnings},
+			expectedExit:   ExitWarnings,
+			expectedStdout: []string{"/id: integer 9007199254740993", "[unsafe-integer]", "/tags: array mixes string and number elements [mixed-array]"},
+		},
+		{name: "warnings ignored", args: []string{"lint", "--on-warning", "ignore", warnings}, expectedExit: ExitSuccess, expectedStdout: []string{"[mixed-array]"}},
+		{name: "warnings fail", args: []string{"lint", warnings, "--on-warning=fail"}, expectedExit: ExitInvalid, expectedStdout: []string{"[mixed-array]"}},
+		{name: "invalid policy", args: []string{"lint", "--on-warning", "strict", clean}, expectedExit: ExitInvalid},
+		{name: "invalid JSON", args: []string{"lint", "--on-warning", "ignore", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"lint", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"lint"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			for _, want := range tt.expectedStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("expected stdout to contain %q, got: %s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/patch"
+)
+
+// runPatch implements `json-parser patch <document> <patch>`, which applies
+// a JSON Patch (RFC 6902) to the document and prints the result. The patch is
+// atomic: if any operation fails, including a test, nothing is printed.
+func (inv *invocation) runPatch(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("patch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(stderr, "Usage: patch <document> <patch>")
+		return ExitInvalid
+	}
+
+	handler := inv.newHandler()
+	doc, err := handler.ParseFileValue(positional[0])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+	patchValue, err := handler.ParseFileValue(positional[1])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	ops, err := patch.Parse(patchValue)
+	if err == nil {
+		doc, err = patch.Apply(doc, ops)
+	}
+	if err != nil {
+		printError(stderr, "%s: %v", positional[1], err)
+		return ExitInvalid
+	}
+
+	output, err := encoder.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	fmt.Fprintln(stdout, string(output))
+	return ExitSuccess
+}
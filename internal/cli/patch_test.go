@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunPatch(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"name": "a", "tags": ["x"]}`)
+	ops := writeFile("patch.json", `[{"op": "replace", "path": "/name", "value": "b"}, {"op": "add", "path": "/tags/-", "value": "y"}]`)
+	failing := writeFile("failing.json", `[{"op": "test", "path": "/name", "value": "z"}]`)
+	malformed := writeFile("malformed.json", `[{"op": "add", "path": "/a"}]`)
+	invalid := writeFile("invalid.json", `[{"op": `)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "apply", args: []string{"patch", doc, ops}, expectedExit: ExitSuccess, expectedOut: "{\n  \"name\": \"b\",\n  \"tags\": [\n    \"x\",\n    \"y\"\n  ]\n}\n"},
+		{name: "failed test", args: []string{"patch", doc, failing}, expectedExit: ExitInvalid},
+		{name: "malformed operation", args: []string{"patch", doc, malformed}, expectedExit: ExitInvalid},
+		{name: "invalid patch", args: []string{"patch", doc, invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"patch", filepath.Join(tempDir, "missing.json"), ops}, expectedExit: ExitFileError},
+		{name: "missing patch", args: []string{"patch", doc}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+			if tt.expectedExit != ExitSuccess && stdout.Len() > 0 {
+				t.Errorf("expected no output on failure, got %q", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// outputFormats lists the --output values of the bare form.
+var outputFormats = []string{"pretty", "compact", "go"}
+
+// runDefault implements the bare
+// `json-parser [--stream] [--progress] [--schema schema.json] [--print] [--output pretty|compact|go] <file>`
+// form, which validates the document. With --stream the file is validated as
+// it is read (see CLIHandler.ParseFileStream), so files larger than the
+// available memory can be checked, and --progress also shows a progress bar
+// on stderr. With --schema it also validates the
+// document against a JSON Schema and exits with ExitSchema if it doesn't
+// conform, keeping ExitInvalid for invalid JSON. With --print or --output it
+// also prints the parsed document, so the CLI doubles as a formatter and
+// inspector: indented with two spaces (pretty, the default), on one line
+// (compact) or as a Go composite literal (go), which shows the types the
+// parser produced, such as int64 or float64 for numbers. Object keys are
+// printed in sorted order.
+func (inv *invocation) runDefault(program string, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(program, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	printDoc := fs.Bool("print", false, "print the parsed document")
+	streamed := fs.Bool("stream", false, "validate without reading the whole file into memory")
+	progress := fs.Bool("progress", false, "show the progress of reading the file on stderr (implies --stream)")
+	schemaFile := fs.String("schema", "", "JSON Schema `file` to validate the document against")
+	output := fs.String("output", "", "print the parsed document as `pretty`, compact or go (implies --print)")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 || positional[0] == StdinName && isTerminal(os.Stdin) {
+		// Reading a terminal would wait for a document to be typed.
+		printUsage(program, stderr)
+		return ExitInvalid
+	}
+	if *output != "" && !slices.Contains(outputFormats, *output) {
+		printError(stderr, "invalid --output value %q (expected pretty, compact or go)", *output)
+		return ExitInvalid
+	}
+	if (*streamed || *progress) && (*printDoc || *output != "" || *schemaFile != "") {
+		printError(stderr, "--stream and --progress can't be combined with --print, --output or --schema")
+		return ExitInvalid
+	}
+	if *printDoc && *output == "" {
+		*output = "pretty"
+	}
+
+	handler := inv.newHandler()
+	if *streamed || *progress {
+		var opts []lexer.Option
+		var bar *progressBar
+		if *progress {
+			bar = newProgressBar(stderr, fileSize(positional[0]))
+			opts = append(opts, lexer.WithProgress(bar.update))
+		}
+		err := handler.ParseFileStream(positional[0], opts...)
+		if bar != nil {
+			bar.finish()
+		}
+		if err != nil {
+			printError(stderr, "%v", err)
+		}
+		return handler.ExitCode()
+	}
+	var value parser.JSONValue
+	if *schemaFile != "" {
+		value, err = handler.ParseFileValueWithSchema(positional[0], *schemaFile)
+	} else {
+		value, err = handler.ParseFileValue(positional[0])
+	}
+	if err != nil {
+		printError(stderr, "%v", err)
+		return handler.ExitCode()
+	}
+
+	var rendered []byte
+	switch *output {
+	case "":
+		return handler.ExitCode()
+	case "pretty":
+		rendered, err = encoder.MarshalIndent(value, "", "  ")
+	case "compact":
+		rendered, err = encoder.Marshal(value)
+	case "go":
+		var b strings.Builder
+		writeGoLiteral(&b, value, "")
+		rendered = []byte(b.String())
+	}
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+	fmt.Fprintf(stdout, "%s\n", rendered)
+	return handler.ExitCode()
+}
+
+// writeGoLiteral writes value as a Go expression, one element or member per
+// line indented with tabs below indent.
+func writeGoLiteral(b *strings.Builder, value any, indent string) {
+	if obj, ok := parser.AsObject(value); ok {
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		b.WriteString("map[string]any{")
+		for _, key := range keys {
+			fmt.Fprintf(b, "\n%s\t%s: ", indent, strconv.Quote(key))
+			writeGoLiteral(b, obj[key], indent+"\t")
+			b.WriteByte(',')
+		}
+		if len(keys) > 0 {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteByte('}')
+		return
+	}
+
+	switch v := value.(type) {
+	case []any:
+		b.WriteString("[]any{")
+		for _, elem := range v {
+			b.WriteString("\n" + indent + "\t")
+			writeGoLiteral(b, elem, indent+"\t")
+			b.WriteByte(',')
+		}
+		if len(v) > 0 {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteByte('}')
+	case string:
+		b.WriteString(strconv.Quote(v))
+	case int64:
+		fmt.Fprintf(b, "int64(%d)", v)
+	case float64:
+		fmt.Fprintf(b, "float64(%s)", strconv.FormatFloat(v, 'g', -1, 64))
+	case nil:
+		b.WriteString("nil")
+	default:
+		fmt.Fprintf(b, "%#v", v)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunDefault(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"name": "app", "ports": [80, 1.5], "tls": null, "on": true, "meta": {}}`)
+	invalid := writeFile("invalid.json", `{"name": }`)
+	schemaFile := writeFile("schema.json", `{"required": ["name", "version"]}`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "silent", args: []string{doc}, expectedExit: ExitSuccess},
+		{
+			name:         "print",
+			args:         []string{"--print", doc},
+			expectedExit: ExitSuccess,
+			expectedOut:  "{\n  \"meta\": {},\n  \"name\": \"app\",\n  \"on\": true,\n  \"ports\": [\n    80,\n    1.5\n  ],\n  \"tls\": null\n}\n",
+		},
+		{
+			name:         "compact",
+			args:         []string{doc, "--output", "compact"},
+			expectedExit: ExitSuccess,
+			expectedOut:  "{\"meta\":{},\"name\":\"app\",\"on\":true,\"ports\":[80,1.5],\"tls\":null}\n",
+		},
+		{
+			name:         "go",
+			args:         []string{"--output=go", doc},
+			expectedExit: ExitSuccess,
+			expectedOut: "map[string]any{\n" +
+				"\t\"meta\": map[string]any{},\n" +
+				"\t\"name\": \"app\",\n" +
+				"\t\"on\": true,\n" +
+				"\t\"ports\": []any{\n\t\tint64(80),\n\t\tfloat64(1.5),\n\t},\n" +
+				"\t\"tls\": nil,\n" +
+				"}\n",
+		},
+		{name: "invalid document", args: []string{"--print", invalid}, expectedExit: ExitInvalid},
+		{name: "schema violation", args: []string{"--schema", schemaFile, "--print", doc}, expectedExit: ExitSchema},
+		{name: "invalid document with schema", args: []string{"--schema", schemaFile, invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"--print", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "stream", args: []string{"--stream", doc}, expectedExit: ExitSuccess},
+		{name: "stream invalid document", args: []string{"--stream", invalid}, expectedExit: ExitInvalid},
+		{name: "stream missing file", args: []string{"--stream", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "progress", args: []string{"--progress", doc}, expectedExit: ExitSuccess},
+		{name: "progress invalid document", args: []string{"--progress", invalid}, expectedExit: ExitInvalid},
+		{name: "stream with print", args: []string{"--stream", "--print", doc}, expectedExit: ExitInvalid},
+		{name: "unknown output", args: []string{"--output", "yaml", doc}, expectedExit: ExitInvalid},
+		{name: "no file", args: []string{"--print"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
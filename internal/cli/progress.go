@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// progressWidth is the number of cells of a progress bar.
+const progressWidth = 30
+
+// progressInterval is the shortest time between two redraws of a progress
+// bar whose percentage hasn't changed.
+const progressInterval = 100 * time.Millisecond
+
+// progressBar draws how much of an input has been read on a single line of w,
+// which is redrawn in place, e.g.
+// `[=============>                ]  45%  120.0 MiB / 266.7 MiB`.
+type progressBar struct {
+	w       io.Writer
+	total   int64 // expected size, 0 if unknown
+	percent int   // last percentage drawn
+	drawn   time.Time
+	width   int // length of the line drawn last
+}
+
+// newProgressBar returns a progress bar for an input of total bytes, or of
+// unknown size if total is 0, which then only shows the bytes read.
+func newProgressBar(w io.Writer, total int64) *progressBar {
+	return &progressBar{w: w, total: total, percent: -1}
+}
+
+// update redraws the bar for read bytes when its percentage has changed or
+// it hasn't been drawn for a while. Inputs turning out larger than expected,
+// such as compressed files, are shown as being of unknown size.
+func (p *progressBar) update(read int64) {
+	if read > p.total {
+		p.total = 0
+	}
+	percent := 0
+	if p.total > 0 {
+		percent = int(read * 100 / p.total)
+	}
+	if percent == p.percent && time.Since(p.drawn) < progressInterval {
+		return
+	}
+	p.percent, p.drawn = percent, time.Now()
+
+	line := formatBytes(int(read)) + " read"
+	if p.total > 0 {
+		filled := percent * progressWidth / 100
+		bar := strings.Repeat("=", filled)
+		if filled < progressWidth {
+			bar += ">" + strings.Repeat(" ", progressWidth-filled-1)
+		}
+		line = fmt.Sprintf("[%s] %3d%%  %s / %s", bar, percent, formatBytes(int(read)), formatBytes(int(p.total)))
+	}
+	// Blank out the rest of a longer previous line.
+	fmt.Fprintf(p.w, "\r%-*s", p.width, line)
+	p.width = len(line)
+}
+
+// finish ends the line of the bar, so that later output starts on a new
+// line.
+func (p *progressBar) finish() {
+	if p.percent >= 0 {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// fileSize returns the size of filename, or 0 if it isn't a regular file,
+// such as standard input or a URL.
+func fileSize(filename string) int64 {
+	info, err := os.Stat(filename)
+	if filename == StdinName || err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		reads    []int64
+		expected string
+	}{
+		{
+			name:     "known size",
+			total:    2048,
+			reads:    []int64{512, 2048},
+			expected: "\r[=======>                      ]  25%  512 B / 2.0 KiB\r[==============================] 100%  2.0 KiB / 2.0 KiB\n",
+		},
+		{
+			name:     "unknown size",
+			reads:    []int64{100},
+			expected: "\r100 B read\n",
+		},
+		{
+			name:     "larger than expected",
+			total:    100,
+			reads:    []int64{50, 4096},
+			expected: "\r[===============>              ]  50%  50 B / 100 B\r4.0 KiB read                                       \n",
+		},
+		{name: "nothing read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			bar := newProgressBar(&out, tt.total)
+			for _, read := range tt.reads {
+				bar.update(read)
+			}
+			bar.finish()
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/query"
+)
+
+// runQuery implements `json-parser query [--paths] [--raw] <expression> <file>`,
+// which prints the values selected by a path expression such as
+// `users[0].name`, `items[-1]` or `**.id` (see query.Path), compactly, one
+// per line. --paths prefixes each value with its JSON Pointer and a tab, and
+// --raw prints strings without quotes or escapes, for use in shell scripts.
+func (inv *invocation) runQuery(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	paths := fs.Bool("paths", false, "prefix each value with its JSON Pointer and a tab")
+	raw := fs.Bool("raw", false, "print strings without quotes")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(stderr, "Usage: query [--paths] [--raw] <expression> <filename>")
+		return ExitInvalid
+	}
+
+	path, err := query.Compile(positional[0])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+
+	value, err := inv.newHandler().ParseFileValue(positional[1])
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	for _, result := range path.Get(value) {
+		rendered, err := encoder.Marshal(result.Value)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return ExitInvalid
+		}
+		if s, ok := result.Value.(string); ok && *raw {
+			rendered = []byte(s)
+		}
+		if *paths {
+			fmt.Fprintf(stdout, "%s\t", result.Path)
+		}
+		fmt.Fprintf(stdout, "%s\n", rendered)
+	}
+	return ExitSuccess
+}
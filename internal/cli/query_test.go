@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunQuery(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	doc := writeFile("doc.json", `{"users": [{"id": 1, "name": "ann"}, {"id": 2, "name": "bob\tby"}], "meta": {"id": 3}}`)
+	invalid := writeFile("invalid.json", `{"users": [}`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{name: "member", args: []string{"query", "users[0]", doc}, expectedExit: ExitSuccess, expectedOut: "{\"id\":1,\"name\":\"ann\"}\n"},
+		{name: "several values", args: []string{"query", "**.id", doc}, expectedExit: ExitSuccess, expectedOut: "3\n1\n2\n"},
+		{name: "paths", args: []string{"query", "--paths", "users.*.id", doc}, expectedExit: ExitSuccess, expectedOut: "/users/0/id\t1\n/users/1/id\t2\n"},
+		{name: "raw strings", args: []string{"query", "users[-1].name", doc, "--raw"}, expectedExit: ExitSuccess, expectedOut: "bob\tby\n"},
+		{name: "quoted strings", args: []string{"query", "users[-1].name", doc}, expectedExit: ExitSuccess, expectedOut: "\"bob\\tby\"\n"},
+		{name: "no match", args: []string{"query", "missing", doc}, expectedExit: ExitSuccess, expectedOut: ""},
+		{name: "invalid expression", args: []string{"query", "users[", doc}, expectedExit: ExitInvalid},
+		{name: "invalid document", args: []string{"query", "users", invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"query", "users", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "missing expression", args: []string{"query", doc}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
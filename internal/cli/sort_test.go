@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSort(t *testing.T) {
+	tempDir := t.TempDir()
+
+	file := filepath.Join(tempDir, "data.json")
+	if err := os.WriteFile(file, []byte(`{"b": [3, 1, 2], "a": {"d": true, "c": null}}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	invalidFile := filepath.Join(tempDir, "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"a": }`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	unicodeFile := filepath.Join(tempDir, "unicode.json")
+	if err := os.WriteFile(unicodeFile, []byte(`{"name": "caf\u00e9", "city": "Zürich"}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	numbersFile := filepath.Join(tempDir, "numbers.json")
+	if err := os.WriteFile(numbersFile, []byte(`{"b": [1e3, 2.50, 10], "a": -0.0}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{
+			name:         "sorts keys",
+			args:         []string{"sort", file},
+			expectedExit: 0,
+			expectedOut:  "{\n  \"a\": {\n    \"c\": null,\n    \"d\": true\n  },\n  \"b\": [\n    3,\n    1,\n    2\n  ]\n}\n",
+		},
+		{
+			name:         "sorts arrays with flag after filename",
+			args:         []string{"sort", file, "--arrays", "--indent", "0"},
+			expectedExit: 0,
+			expectedOut:  "{\n\"a\": {\n\"c\": null,\n\"d\": true\n},\n\"b\": [\n1,\n2,\n3\n]\n}\n",
+		},
+		{
+			name:         "collapses escapes to literals",
+			args:         []string{"sort", unicodeFile},
+			expectedExit: 0,
+			expectedOut:  "{\n  \"city\": \"Zürich\",\n  \"name\": \"café\"\n}\n",
+		},
+		{
+			name:         "escapes non-ASCII",
+			args:         []string{"sort", "--escape-unicode", unicodeFile},
+			expectedExit: 0,
+			expectedOut:  "{\n  \"city\": \"Z\\u00fcrich\",\n  \"name\": \"caf\\u00e9\"\n}\n",
+		},
+		{
+			name:         "keeps number lexemes",
+			args:         []string{"sort", "--arrays", "--width", "40", numbersFile},
+			expectedExit: 0,
+			expectedOut:  "{\"a\": -0.0, \"b\": [2.50, 10, 1e3]}\n",
+		},
+		{
+			name:         "keeps short containers on one line",
+			args:         []string{"sort", "--width", "30", file},
+			expectedExit: 0,
+			expectedOut:  "{\n  \"a\": {\"c\": null, \"d\": true},\n  \"b\": [3, 1, 2]\n}\n",
+		},
+		{
+			name:         "negative width",
+			args:         []string{"sort", "--width", "-1", file},
+			expectedExit: 1,
+		},
+		{
+			name:         "invalid JSON",
+			args:         []string{"sort", invalidFile},
+			expectedExit: 1,
+		},
+		{
+			name:         "missing filename",
+			args:         []string{"sort"},
+			expectedExit: 1,
+		},
+		{
+			name:         "unknown flag",
+			args:         []string{"sort", "--bogus", file},
+			expectedExit: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			exitCode := run("json-parser", tt.args, &stdout, &stderr)
+
+			if exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// runSplit implements `json-parser split [--size N] [--out pattern] <file>`,
+// which splits a top-level array into files of at most N elements each, for
+// feeding size-limited downstream APIs. The array is streamed, so only one
+// chunk is held in memory at a time. The "%d" in the output pattern is
+// replaced by the chunk number, starting at 1, and the name of each written
+// file is printed.
+func (inv *invocation) runSplit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	size := fs.Int("size", 1000, "maximum number of elements per chunk")
+	out := fs.String("out", "chunk-%d.json", "output file `pattern`; %d is replaced by the chunk number")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: split [--size N] [--out pattern] <filename>")
+		return ExitInvalid
+	}
+	if *size < 1 {
+		printError(stderr, "--size must be at least 1")
+		return ExitInvalid
+	}
+	if !strings.Contains(*out, "%d") {
+		printError(stderr, "--out must contain %%d for the chunk number")
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	file, err := inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+	defer file.Close()
+
+	elements := stream.NewElementReader(file)
+	chunk := make([]parser.JSONValue, 0, *size)
+	chunks := 0
+	flush := func() error {
+		chunks++
+		name := strings.Replace(*out, "%d", strconv.Itoa(chunks), 1)
+		if err := writeChunk(name, chunk); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, name)
+		clear(chunk)
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		value, err := elements.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			printError(stderr, "%v", &ParseError{Err: err})
+			return ExitInvalid
+		}
+
+		chunk = append(chunk, value)
+		if len(chunk) == *size {
+			if err := flush(); err != nil {
+				printError(stderr, "%v", err)
+				return ExitFileError
+			}
+		}
+	}
+	if len(chunk) > 0 {
+		if err := flush(); err != nil {
+			printError(stderr, "%v", err)
+			return ExitFileError
+		}
+	}
+	return ExitSuccess
+}
+
+// writeChunk writes elements to name as a compact JSON array.
+func writeChunk(name string, elements []parser.JSONValue) error {
+	data, err := encoder.Marshal(elements)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
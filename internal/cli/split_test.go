@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSplit(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	big := writeFile("big.json", `[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}]`)
+	empty := writeFile("empty.json", `[]`)
+	object := writeFile("object.json", `{"id": 1}`)
+	broken := writeFile("broken.json", `[1, 2, {"id": }]`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedChunks []string
+	}{
+		{
+			name:           "chunks with remainder",
+			args:           []string{"split", big, "--size", "2", "--out", filepath.Join(tempDir, "a-%d.json")},
+			expectedExit:   ExitSuccess,
+			expectedChunks: []string{`[{"id":1},{"id":2}]`, `[{"id":3},{"id":4}]`, `[{"id":5}]`},
+		},
+		{
+			name:           "single chunk",
+			args:           []string{"split", "--size=10", "--out", filepath.Join(tempDir, "b-%d.json"), big},
+			expectedExit:   ExitSuccess,
+			expectedChunks: []string{`[{"id":1},{"id":2},{"id":3},{"id":4},{"id":5}]`},
+		},
+		{name: "empty array writes nothing", args: []string{"split", "--out", filepath.Join(tempDir, "c-%d.json"), empty}, expectedExit: ExitSuccess},
+		{name: "not an array", args: []string{"split", "--out", filepath.Join(tempDir, "d-%d.json"), object}, expectedExit: ExitInvalid},
+		{name: "invalid element", args: []string{"split", "--size", "1", "--out", filepath.Join(tempDir, "e-%d.json"), broken}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"split", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "zero size", args: []string{"split", "--size", "0", big}, expectedExit: ExitInvalid},
+		{name: "pattern without number", args: []string{"split", "--out", "chunk.json", big}, expectedExit: ExitInvalid},
+		{name: "missing filename", args: []string{"split"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedExit != ExitSuccess {
+				return
+			}
+
+			written := strings.Fields(stdout.String())
+			if len(written) != len(tt.expectedChunks) {
+				t.Fatalf("expected %d chunks, got %q", len(tt.expectedChunks), written)
+			}
+			for i, name := range written {
+				data, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatalf("failed to read chunk: %v", err)
+				}
+				if got := strings.TrimSpace(string(data)); got != tt.expectedChunks[i] {
+					t.Errorf("chunk %d: expected %s, got %s", i+1, tt.expectedChunks[i], got)
+				}
+			}
+		})
+	}
+}
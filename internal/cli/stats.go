@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// documentStats summarizes the shape of a parsed document.
+type documentStats struct {
+	objects  int
+	arrays   int
+	strings  int
+	numbers  int
+	booleans int
+	nulls    int
+	maxDepth int
+}
+
+// values returns the total number of values in the document.
+func (s documentStats) values() int {
+	return s.objects + s.arrays + s.strings + s.numbers + s.booleans + s.nulls
+}
+
+// runStats implements `json-parser stats <file>`, which prints a summary of
+// the document's structure and the approximate memory it occupies once
+// parsed, to help users reason about caching parsed documents.
+func (inv *invocation) runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Usage: stats <filename>")
+		return ExitInvalid
+	}
+
+	filename := positional[0]
+	value, err := inv.newHandler().ParseFileValue(filename)
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		return ExitFileError
+	}
+
+	var stats documentStats
+	collectStats(value, 1, &stats)
+
+	fmt.Fprintf(stdout, "File size:         %s\n", formatBytes(int(info.Size())))
+	fmt.Fprintf(stdout, "Values:            %d\n", stats.values())
+	fmt.Fprintf(stdout, "  Objects:         %d\n", stats.objects)
+	fmt.Fprintf(stdout, "  Arrays:          %d\n", stats.arrays)
+	fmt.Fprintf(stdout, "  Strings:         %d\n", stats.strings)
+	fmt.Fprintf(stdout, "  Numbers:         %d\n", stats.numbers)
+	fmt.Fprintf(stdout, "  Booleans:        %d\n", stats.booleans)
+	fmt.Fprintf(stdout, "  Nulls:           %d\n", stats.nulls)
+	fmt.Fprintf(stdout, "Max depth:         %d\n", stats.maxDepth)
+	fmt.Fprintf(stdout, "Estimated memory:  %s\n", formatBytes(parser.EstimateSize(value)))
+	return ExitSuccess
+}
+
+// collectStats walks value, counting values by type and tracking nesting depth.
+func collectStats(value parser.JSONValue, depth int, stats *documentStats) {
+	stats.maxDepth = max(stats.maxDepth, depth)
+
+	switch v := value.(type) {
+	case nil:
+		stats.nulls++
+	case bool:
+		stats.booleans++
+	case string:
+		stats.strings++
+	case int64, float64:
+		stats.numbers++
+	case []any:
+		stats.arrays++
+		for _, elem := range v {
+			collectStats(elem, depth+1, stats)
+		}
+	case parser.JSONObject:
+		stats.objects++
+		for _, elem := range v {
+			collectStats(elem, depth+1, stats)
+		}
+	}
+}
+
+// formatBytes renders a byte count using binary units.
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	suffixes := []string{"KiB", "MiB", "GiB"}
+	value := float64(n) / unit
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunStats(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(file, []byte(`{"name": "test", "tags": ["a", "b"], "meta": {"ok": true, "none": null, "n": 1.5}}`), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run("json-parser", []string{"stats", file}, &stdout, &stderr); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitSuccess, exitCode, stderr.String())
+	}
+
+	output := stdout.String()
+	expectedLines := []string{
+		"Values:            9",
+		"  Objects:         2",
+		"  Arrays:          1",
+		"  Strings:         3",
+		"  Numbers:         1",
+		"  Booleans:        1",
+		"  Nulls:           1",
+		"Max depth:         3",
+		"Estimated memory:",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestRunStats_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if exitCode := run("json-parser", []string{"stats"}, &stdout, &stderr); exitCode != ExitInvalid {
+		t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if exitCode := run("json-parser", []string{"stats", missing}, &stdout, &stderr); exitCode != ExitFileError {
+		t.Errorf("expected exit code %d, got %d", ExitFileError, exitCode)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{input: 0, expected: "0 B"},
+		{input: 1023, expected: "1023 B"},
+		{input: 1536, expected: "1.5 KiB"},
+		{input: 5 * 1024 * 1024, expected: "5.0 MiB"},
+		{input: 3 * 1024 * 1024 * 1024, expected: "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := formatBytes(tt.input); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+)
+
+// fileResult is the outcome of validating one file, as shown in the summary
+// of multi-file runs.
+type fileResult struct {
+	File   string  `json:"file"`
+	Status string  `json:"status"` // see statusFor, or statusSkipped
+	Errors int     `json:"errors"` // problems reported for the file
+	TimeMS float64 `json:"time_ms"`
+	Size   int64   `json:"size"` // bytes, 0 if the file couldn't be read
+
+	exitCode int
+	duration time.Duration
+}
+
+// summaryTotals aggregates the results of a multi-file run.
+type summaryTotals struct {
+	Files      int     `json:"files"`
+	Valid      int     `json:"valid"`
+	Invalid    int     `json:"invalid"`
+	Unreadable int     `json:"unreadable"`
+	Skipped    int     `json:"skipped"`
+	Errors     int     `json:"errors"`
+	TimeMS     float64 `json:"time_ms"`
+	Size       int64   `json:"size"`
+
+	duration time.Duration
+}
+
+// statusSkipped is the status of files left unvalidated by --fail-fast.
+const statusSkipped = "skipped"
+
+// slowestCount is the number of files listed as the slowest of a run.
+const slowestCount = 3
+
+// statusFor names the outcome of a file by its exit code.
+func statusFor(exitCode int) string {
+	switch exitCode {
+	case ExitSuccess:
+		return "valid"
+	case ExitFileError:
+		return "unreadable"
+	default:
+		return "invalid"
+	}
+}
+
+// milliseconds converts d to fractional milliseconds with microsecond
+// precision, for machine-readable output.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// totalsFor sums up results.
+func totalsFor(results []fileResult) summaryTotals {
+	totals := summaryTotals{Files: len(results)}
+	for _, r := range results {
+		switch r.Status {
+		case "valid":
+			totals.Valid++
+		case "unreadable":
+			totals.Unreadable++
+		case statusSkipped:
+			totals.Skipped++
+		default:
+			totals.Invalid++
+		}
+		totals.Errors += r.Errors
+		totals.Size += r.Size
+		totals.duration += r.duration
+	}
+	totals.TimeMS = milliseconds(totals.duration)
+	return totals
+}
+
+// slowest returns the results of the slowest validated files,
+// slowest first, at most slowestCount of them.
+func slowest(results []fileResult) []fileResult {
+	validated := slices.DeleteFunc(slices.Clone(results), func(r fileResult) bool {
+		return r.Status == statusSkipped
+	})
+	slices.SortStableFunc(validated, func(a, b fileResult) int {
+		return cmp.Compare(b.duration, a.duration)
+	})
+	return validated[:min(len(validated), slowestCount)]
+}
+
+// writeSummaryTable writes results as an aligned table followed by a totals
+// row with the pass and fail counts, and then lists the slowest files.
+func writeSummaryTable(w io.Writer, results []fileResult) {
+	totals := totalsFor(results)
+	passed := fmt.Sprintf("%d/%d valid", totals.Valid, totals.Files)
+	if totals.Skipped > 0 {
+		passed += fmt.Sprintf(", %d skipped", totals.Skipped)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSTATUS\tERRORS\tTIME\tSIZE")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", r.File, r.Status, r.Errors, r.duration.Round(time.Microsecond), formatBytes(int(r.Size)))
+	}
+	fmt.Fprintf(tw, "TOTAL\t%s\t%d\t%s\t%s\n", passed, totals.Errors, totals.duration.Round(time.Microsecond), formatBytes(int(totals.Size)))
+	tw.Flush()
+
+	if slow := slowest(results); len(slow) > 0 {
+		fmt.Fprintln(w, "\nSlowest files:")
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, r := range slow {
+			fmt.Fprintf(tw, "  %s\t%s\n", r.File, r.duration.Round(time.Microsecond))
+		}
+		tw.Flush()
+	}
+}
+
+// writeSummaryJSON writes results and their totals as a JSON document, for
+// dashboards and other tools.
+func writeSummaryJSON(w io.Writer, results []fileResult) error {
+	for i := range results {
+		results[i].TimeMS = milliseconds(results[i].duration)
+	}
+	var slowestFiles []string
+	for _, r := range slowest(results) {
+		slowestFiles = append(slowestFiles, r.File)
+	}
+	summary := struct {
+		Files   []fileResult  `json:"files"`
+		Totals  summaryTotals `json:"totals"`
+		Slowest []string      `json:"slowest"`
+	}{Files: results, Totals: totalsFor(results), Slowest: slowestFiles}
+
+	data, err := encoder.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
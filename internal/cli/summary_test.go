@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestRunValidate_Summary(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	valid := writeFile("valid.json", `{"port": 80}`)
+	invalid := writeFile("invalid.json", `{"port": }`)
+	missing := filepath.Join(tempDir, "missing.json")
+	schemaFile := writeFile("schema.json", `{"properties": {"port": {"type": "integer", "maximum": 10}}}`)
+
+	t.Run("table", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := run("json-parser", []string{"validate", valid, invalid, missing}, &stdout, &stderr)
+		if exitCode != ExitFileError {
+			t.Errorf("expected the most severe exit code %d, got %d", ExitFileError, exitCode)
+		}
+
+		table, slowestFiles, _ := strings.Cut(stdout.String(), "\n\n")
+		lines := strings.Split(table, "\n")
+		if len(lines) != 5 {
+			t.Fatalf("expected header, 3 rows and totals, got:\n%s", stdout.String())
+		}
+		if !strings.HasPrefix(slowestFiles, "Slowest files:\n") || strings.Count(slowestFiles, "\n") != 4 {
+			t.Errorf("expected the 3 slowest files after the table, got:\n%s", slowestFiles)
+		}
+		column := strings.Index(lines[0], "STATUS")
+		for i, want := range []string{"STATUS", "valid", "invalid", "unreadable", "1/3 valid"} {
+			if !strings.HasPrefix(lines[i][column:], want) {
+				t.Errorf("expected %q aligned under STATUS in line %q", want, lines[i])
+			}
+		}
+		if !strings.HasPrefix(lines[4], "TOTAL") {
+			t.Errorf("expected totals row, got %q", lines[4])
+		}
+		if !strings.Contains(stderr.String(), "does not exist") {
+			t.Errorf("expected per-file errors on stderr, got: %s", stderr.String())
+		}
+	})
+
+	t.Run("single file has no table", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"validate", valid}, &stdout, &stderr); exitCode != ExitSuccess {
+			t.Errorf("expected exit code %d, got %d", ExitSuccess, exitCode)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("expected no output, got: %s", stdout.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := run("json-parser", []string{"validate", "--format", "json", "--schema", schemaFile, valid, invalid}, &stdout, &stderr)
+		if exitCode != ExitSchema {
+			t.Errorf("expected exit code %d, got %d", ExitSchema, exitCode)
+		}
+
+		value, err := New().ParseStringValue(stdout.String())
+		if err != nil {
+			t.Fatalf("summary is not valid JSON: %v\n%s", err, stdout.String())
+		}
+		summary := value.(parser.JSONObject)
+		files := summary["files"].([]any)
+		if len(files) != 2 {
+			t.Fatalf("expected 2 files, got %d", len(files))
+		}
+		first := files[0].(parser.JSONObject)
+		if first["file"] != valid || first["status"] != "invalid" || first["errors"] != int64(1) || first["size"] != int64(12) {
+			t.Errorf("unexpected first entry: %v", first)
+		}
+		totals := summary["totals"].(parser.JSONObject)
+		if totals["files"] != int64(2) || totals["valid"] != int64(0) || totals["invalid"] != int64(2) || totals["errors"] != int64(2) {
+			t.Errorf("unexpected totals: %v", totals)
+		}
+		if slowest := summary["slowest"].([]any); len(slowest) != 2 {
+			t.Errorf("expected both files among the slowest, got %v", slowest)
+		}
+	})
+
+	for _, args := range [][]string{{"--fail-fast"}, {"--keep-going=false"}} {
+		t.Run(args[0], func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"validate", "--jobs", "1", "--format", "json", invalid, valid, missing}, args...)
+			if exitCode := run("json-parser", args, &stdout, &stderr); exitCode != ExitInvalid {
+				t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+			}
+			value, err := New().ParseStringValue(stdout.String())
+			if err != nil {
+				t.Fatalf("summary is not valid JSON: %v\n%s", err, stdout.String())
+			}
+			summary := value.(parser.JSONObject)
+			for i, want := range []string{"invalid", "skipped", "skipped"} {
+				if status := summary["files"].([]any)[i].(parser.JSONObject)["status"]; status != want {
+					t.Errorf("expected file %d to be %s, got %v", i, want, status)
+				}
+			}
+			if skipped := summary["totals"].(parser.JSONObject)["skipped"]; skipped != int64(2) {
+				t.Errorf("expected 2 skipped files, got %v", skipped)
+			}
+			if strings.Contains(stderr.String(), "missing.json") {
+				t.Errorf("expected skipped files not to be read, got: %s", stderr.String())
+			}
+		})
+	}
+
+	t.Run("fail fast table", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"validate", "--fail-fast", "--jobs", "2", invalid, valid, missing}, &stdout, &stderr); exitCode == ExitSuccess {
+			t.Errorf("expected a failing exit code, got %d", exitCode)
+		}
+		if !strings.Contains(stdout.String(), "invalid") || !strings.Contains(stdout.String(), "/3 valid") {
+			t.Errorf("expected the failure in the summary, got:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if exitCode := run("json-parser", []string{"validate", "--format", "yaml", valid}, &stdout, &stderr); exitCode != ExitInvalid {
+			t.Errorf("expected exit code %d, got %d", ExitInvalid, exitCode)
+		}
+	})
+}
@@ -0,0 +1,521 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/decoder"
+	"github.com/VuNe/json-parser/internal/diff"
+	"github.com/VuNe/json-parser/internal/ndjson"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/schema"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// formatModes maps --format-mode values to schema format modes.
+var formatModes = map[string]schema.FormatMode{
+	"annotate": schema.FormatAnnotate,
+	"assert":   schema.FormatAssert,
+}
+
+// changeColors highlights diff lines like a unified diff does.
+var changeColors = map[diff.ChangeType]string{
+	diff.Added:    colorGreen,
+	diff.Removed:  colorRed,
+	diff.Modified: colorYellow,
+}
+
+// validation holds the checks applied to every file given to validate.
+type validation struct {
+	inv            *invocation
+	handler        *handler
+	schema         *schema.Schema  // nil without --schema
+	schemaFile     string          // for messages
+	resolver       schema.Resolver // finds "$schema" schemas, nil with --schema or --no-schema-discovery
+	formatMode     schema.FormatMode
+	expected       parser.JSONValue // golden document, meaningful with --expect
+	expectFile     string           // "" without --expect
+	annotationsOut io.Writer        // where schema annotations are reported
+	eventsOut      io.Writer        // where --events prints event streams, nil without it
+	eventsPrefix   bool             // prefix event lines with the file name
+	lines          bool             // files are JSON Lines, see --lines
+	failFast       bool             // skip the remaining files after a failure, see --fail-fast
+}
+
+// runValidate implements
+// `json-parser validate [--expect expected.json] [--schema schema.json] [--format text|json] [--events] [--lines] [--recursive] [--fail-fast] <file>...`.
+// Without flags it validates each file like the bare `json-parser <file>`
+// form. With --expect it also requires each file to be semantically equal to
+// the expected document and prints a structural diff when it isn't, for
+// snapshot-style checks in test and deployment scripts. With --schema it
+// validates the documents against a JSON Schema. Without it, documents that
+// name their schema in a top-level "$schema" member are validated against
+// that: relative paths and file URIs are read relative to the document,
+// http(s) URIs are fetched once per run, and --schema-map names a JSON
+// object mapping URIs to local files (relative to the map) to use instead.
+// --no-schema-discovery ignores "$schema". Fetched schemas are cached in
+// --schema-cache (by default a directory in the user's cache directory) and
+// fetched again once older than --schema-cache-ttl; with --offline they are
+// never fetched, so a schema missing from the cache is an error. Documents
+// that violate their schema, either way, exit with ExitSchema like with the
+// bare form.
+//
+// Files may be given as glob patterns such as configs/*.json, which are
+// expanded here too, for shells that don't. --recursive replaces directories
+// with the *.json files in their trees, for checking whole config
+// repositories. Files are validated --jobs at a time (by default one per CPU),
+// and their messages are printed in the order the files are given. When
+// several files are given, or with --recursive, a summary table follows the
+// per-file messages, with the pass and fail counts and the slowest files;
+// --format json prints that summary as JSON instead, even for a single file.
+// All files are validated (--keep-going, the default) unless --fail-fast is
+// given: then the files not yet started when one fails are skipped. The exit
+// code is the most severe one among the files.
+//
+// --events also prints the parse event stream of each document to stdout
+// (see stream.EventReader), one event per line preceded by its line:column
+// position and, for several files, the file name. The stream stops at the
+// first syntax error, which is then reported as usual.
+//
+// --lines treats each file as JSON Lines (NDJSON), such as a structured log:
+// every non-blank line must be a document, and with a schema each line is
+// validated against it. Every invalid line is reported with its line number,
+// and the file is streamed, so logs of any size can be checked.
+func (inv *invocation) runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	expect := fs.String("expect", "", "golden `file` the document must be semantically equal to")
+	schemaFile := fs.String("schema", "", "JSON Schema `file` to validate the document against")
+	formatMode := fs.String("format-mode", "annotate", "how schema \"format\" mismatches are treated: `annotate` (report only) or assert (fail)")
+	format := fs.String("format", "text", "summary format: `text` (table for multiple files) or json")
+	events := fs.Bool("events", false, "print the parse event stream of each document")
+	schemaMap := fs.String("schema-map", "", "JSON `file` mapping \"$schema\" URIs to local schema files")
+	noDiscovery := fs.Bool("no-schema-discovery", false, "don't validate against the schema named by \"$schema\"")
+	cacheDir := fs.String("schema-cache", defaultSchemaCache(), "`directory` caching fetched schemas, \"\" for none")
+	cacheTTL := fs.Duration("schema-cache-ttl", 24*time.Hour, "`age` after which cached schemas are fetched again, 0 for never")
+	offline := fs.Bool("offline", false, "never fetch schemas; fail if one isn't cached")
+	lines := fs.Bool("lines", false, "validate each line of the files as a document (JSON Lines / NDJSON)")
+	recursive := fs.Bool("recursive", false, "validate the *.json files in directory trees")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "`number` of files validated concurrently")
+	keepGoing := fs.Bool("keep-going", true, "validate all files even after one fails (the default)")
+	failFast := fs.Bool("fail-fast", false, "skip the remaining files once one fails")
+
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if files, err = inv.expandGlobs(files); err != nil {
+		printError(stderr, "%v", err)
+		return ExitFileError
+	}
+	if *recursive {
+		if files, err = inv.walkJSONFiles(files); err != nil {
+			printError(stderr, "%v", err)
+			return ExitFileError
+		}
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "Usage: validate [--expect expected.json] [--schema schema.json] [--format-mode annotate|assert] [--format text|json] [--events] [--schema-map map.json] [--no-schema-discovery] [--schema-cache dir] [--schema-cache-ttl age] [--offline] [--lines] [--recursive] [--jobs n] [--keep-going | --fail-fast] <filename>...")
+		return ExitInvalid
+	}
+	mode, ok := formatModes[*formatMode]
+	if !ok {
+		printError(stderr, "invalid --format-mode value %q (expected annotate or assert)", *formatMode)
+		return ExitInvalid
+	}
+	if *format != "text" && *format != "json" {
+		printError(stderr, "invalid --format value %q (expected text or json)", *format)
+		return ExitInvalid
+	}
+	if *jobs < 1 {
+		printError(stderr, "--jobs must be at least 1")
+		return ExitInvalid
+	}
+	if *cacheTTL < 0 {
+		printError(stderr, "--schema-cache-ttl must not be negative")
+		return ExitInvalid
+	}
+	if *events && *format == "json" {
+		printError(stderr, "--events can't be combined with --format json")
+		return ExitInvalid
+	}
+	if *lines && (*expect != "" || *events) {
+		printError(stderr, "--lines can't be combined with --expect or --events")
+		return ExitInvalid
+	}
+
+	v := validation{inv: inv, handler: inv.newHandler(), annotationsOut: stdout, formatMode: mode, lines: *lines, failFast: *failFast || !*keepGoing}
+	if *format == "json" {
+		// Keep stdout parseable.
+		v.annotationsOut = stderr
+	}
+	if *events {
+		v.eventsOut, v.eventsPrefix = stdout, len(files) > 1
+	}
+	if *schemaFile != "" {
+		if exitCode := v.loadSchema(*schemaFile, stderr); exitCode != ExitSuccess {
+			return exitCode
+		}
+	} else if !*noDiscovery {
+		var opts []schema.ResolverOption
+		if *cacheDir != "" {
+			opts = append(opts, schema.WithCacheDir(*cacheDir, *cacheTTL))
+		}
+		if *offline {
+			opts = append(opts, schema.WithOffline())
+		}
+		if exitCode := v.loadResolver(*schemaMap, opts, stderr); exitCode != ExitSuccess {
+			return exitCode
+		}
+	}
+	if *expect != "" {
+		expected, err := v.handler.ParseFileValue(*expect)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return exitCodeFor(err)
+		}
+		v.expected, v.expectFile = expected, *expect
+	}
+
+	results := v.validateFiles(files, *jobs, stderr)
+	exitCode := ExitSuccess
+	for _, result := range results {
+		exitCode = max(exitCode, result.exitCode)
+	}
+
+	switch {
+	case *format == "json":
+		if err := writeSummaryJSON(stdout, results); err != nil {
+			printError(stderr, "%v", err)
+			return ExitInvalid
+		}
+	case len(files) > 1 || *recursive:
+		writeSummaryTable(stdout, results)
+	}
+	return exitCode
+}
+
+// validateFiles validates files, up to jobs of them at a time, and returns
+// their results in order. The messages of each file are held back until those
+// of the files before it have been written, so the output doesn't depend on
+// scheduling. With failFast, the files not yet started once a file fails are
+// skipped.
+func (v *validation) validateFiles(files []string, jobs int, stderr io.Writer) []fileResult {
+	results := make([]fileResult, len(files))
+	if jobs == 1 || len(files) < 2 {
+		failed := false
+		for i, filename := range files {
+			if failed {
+				results[i] = fileResult{File: filename, Status: statusSkipped}
+				continue
+			}
+			results[i] = v.validateFile(filename, stderr)
+			failed = v.failFast && results[i].exitCode != ExitSuccess
+		}
+		return results
+	}
+
+	logs := make([]outputLog, len(files))
+	done := make([]chan struct{}, len(files))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range files {
+			next <- i
+		}
+	}()
+	var failed atomic.Bool
+	for range min(jobs, len(files)) {
+		go func() {
+			// The handler records exit codes, so every worker needs its own.
+			w := *v
+			w.handler = v.inv.newHandler()
+			for i := range next {
+				if failed.Load() {
+					results[i] = fileResult{File: files[i], Status: statusSkipped}
+					close(done[i])
+					continue
+				}
+				log := &logs[i]
+				w.annotationsOut = log.writer(v.annotationsOut)
+				if v.eventsOut != nil {
+					w.eventsOut = log.writer(v.eventsOut)
+				}
+				results[i] = w.validateFile(files[i], log.writer(stderr))
+				if v.failFast && results[i].exitCode != ExitSuccess {
+					failed.Store(true)
+				}
+				close(done[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		<-done[i]
+		logs[i].replay()
+	}
+	return results
+}
+
+// outputLog records writes meant for several writers so that they can be
+// replayed later, in the same order, e.g. once earlier output is complete.
+type outputLog struct {
+	writes []loggedWrite
+}
+
+// loggedWrite is a write recorded by an outputLog.
+type loggedWrite struct {
+	w    io.Writer
+	data []byte
+}
+
+// writer returns a writer that records writes meant for w in l. Colors and
+// the error format are kept if w has them.
+func (l *outputLog) writer(w io.Writer) io.Writer {
+	lw := &logWriter{log: l, w: w}
+	if cw, ok := w.(*colorWriter); ok {
+		return &colorWriter{Writer: lw, color: cw.color, jsonErrors: cw.jsonErrors}
+	}
+	return lw
+}
+
+// replay performs the recorded writes.
+func (l *outputLog) replay() {
+	for _, write := range l.writes {
+		write.w.Write(write.data)
+	}
+}
+
+// logWriter is an io.Writer recording writes meant for w in an outputLog.
+type logWriter struct {
+	log *outputLog
+	w   io.Writer
+}
+
+// Write implements io.Writer.
+func (lw *logWriter) Write(p []byte) (int, error) {
+	lw.log.writes = append(lw.log.writes, loggedWrite{w: lw.w, data: bytes.Clone(p)})
+	return len(p), nil
+}
+
+// loadSchema reads and compiles the schema in schemaFile.
+func (v *validation) loadSchema(schemaFile string, stderr io.Writer) int {
+	doc, err := v.handler.ParseFileValue(schemaFile)
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+	s, err := schema.Compile(doc, schema.WithFormatMode(v.formatMode))
+	if err != nil {
+		printError(stderr, "%s: %v", schemaFile, err)
+		return ExitInvalid
+	}
+	v.schema, v.schemaFile = s, schemaFile
+	return ExitSuccess
+}
+
+// defaultSchemaCache returns the default --schema-cache directory, or "" if
+// the user has no cache directory.
+func defaultSchemaCache() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "json-parser", "schemas")
+}
+
+// loadResolver sets up "$schema" discovery with opts, adding the URI
+// mappings in mapFile unless it is "".
+func (v *validation) loadResolver(mapFile string, opts []schema.ResolverOption, stderr io.Writer) int {
+	if mapFile == "" {
+		v.resolver = schema.NewResolver(opts...)
+		return ExitSuccess
+	}
+
+	doc, err := v.handler.ParseFileValue(mapFile)
+	if err != nil {
+		printError(stderr, "%v", err)
+		return exitCodeFor(err)
+	}
+	locations, err := decoder.DecodeMap[string](doc)
+	if err != nil {
+		printError(stderr, "%s: %v", mapFile, err)
+		return ExitInvalid
+	}
+	for uri, path := range locations {
+		if !filepath.IsAbs(path) {
+			locations[uri] = filepath.Join(filepath.Dir(mapFile), path)
+		}
+	}
+	v.resolver = schema.NewResolver(append(opts, schema.WithLocations(locations))...)
+	return ExitSuccess
+}
+
+// discoverSchema returns the compiled schema named by the "$schema" member of
+// value, which was read from filename, or nil if it doesn't name one.
+func (v *validation) discoverSchema(value parser.JSONValue, filename string) (*schema.Schema, string, error) {
+	uri, ok := schema.DeclaredURI(value)
+	if !ok {
+		return nil, "", nil
+	}
+	doc, err := v.resolver.Resolve(uri, filename)
+	if err != nil {
+		return nil, uri, err
+	}
+	s, err := schema.Compile(doc, schema.WithFormatMode(v.formatMode))
+	if err != nil {
+		return nil, uri, err
+	}
+	return s, uri, nil
+}
+
+// validateFile parses filename and applies the schema and expectation checks,
+// reporting problems to stderr.
+func (v *validation) validateFile(filename string, stderr io.Writer) fileResult {
+	start := time.Now()
+	result := fileResult{File: filename}
+	if info, err := os.Stat(filename); err == nil {
+		result.Size = info.Size()
+	}
+
+	if v.lines {
+		v.validateLines(filename, &result, stderr)
+	} else if actual, err := v.parseFile(filename); err != nil {
+		printError(stderr, "%v", err)
+		result.exitCode, result.Errors = exitCodeFor(err), 1
+	} else if v.checkSchema(actual, filename, &result, stderr) {
+		v.checkExpectation(actual, filename, &result, stderr)
+	}
+
+	result.Status = statusFor(result.exitCode)
+	result.duration = time.Since(start)
+	return result
+}
+
+// validateLines validates every line of filename, which holds JSON Lines, as
+// a document, reporting each invalid line and schema violation.
+func (v *validation) validateLines(filename string, result *fileResult, stderr io.Writer) {
+	file, err := v.inv.fileReader().Open(filename)
+	if err != nil {
+		printError(stderr, "%v", &FileError{Path: filename, Err: err})
+		result.exitCode, result.Errors = ExitFileError, 1
+		return
+	}
+	defer file.Close()
+
+	for line, err := range ndjson.NewDecoder(file, ndjson.WithLexerOptions(v.inv.syntaxOptions()...)).Lines() {
+		var lineErr *ndjson.LineError
+		switch {
+		case errors.As(err, &lineErr):
+			printError(stderr, "%v", &ParseError{Err: fmt.Errorf("%s: %w", filename, err)})
+			result.exitCode = max(result.exitCode, ExitInvalid)
+			result.Errors++
+		case err != nil:
+			printError(stderr, "%v", &FileError{Path: filename, Err: err})
+			result.exitCode = ExitFileError
+			result.Errors++
+		default:
+			var lineResult fileResult
+			if !v.checkSchema(line.Value, fmt.Sprintf("%s:%d", filename, line.Number), &lineResult, stderr) {
+				result.exitCode = max(result.exitCode, lineResult.exitCode)
+				result.Errors += lineResult.Errors
+			}
+		}
+	}
+}
+
+// parseFile parses filename like ParseFileValue, first printing its event
+// stream with --events. The file is read once for both, so that standard input
+// and URLs can be given too.
+func (v *validation) parseFile(filename string) (parser.JSONValue, error) {
+	if v.eventsOut == nil {
+		return v.handler.ParseFileValue(filename)
+	}
+	content, err := v.handler.readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	v.printEvents(filename, sourceOf(content))
+	return v.handler.parse(v.inv.newBytesLexer(content), sourceOf(content))
+}
+
+// printEvents prints the event stream of content, read from filename, up to
+// its end or first error. Errors are left to be reported by the validation.
+func (v *validation) printEvents(filename, content string) {
+	prefix := ""
+	if v.eventsPrefix {
+		prefix = filename + ":"
+	}
+	events := stream.NewEventReader(v.inv.newLexer(content))
+	for {
+		event, err := events.Next()
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(v.eventsOut, "%s%d:%d\t%s\n", prefix, event.Position.Line, event.Position.Column, event)
+	}
+}
+
+// checkSchema validates value against the schema given with --schema or
+// named by its "$schema" member, if any. Schema errors go to stderr;
+// annotations are informational and go to v.annotationsOut. It reports
+// whether value conforms.
+func (v *validation) checkSchema(value parser.JSONValue, filename string, result *fileResult, stderr io.Writer) bool {
+	s, schemaName := v.schema, v.schemaFile
+	if s == nil && v.resolver != nil {
+		var err error
+		if s, schemaName, err = v.discoverSchema(value, filename); err != nil {
+			printError(stderr, "%s: cannot load schema %q: %v", filename, schemaName, err)
+			result.exitCode, result.Errors = ExitFileError, 1
+			return false
+		}
+	}
+	if s == nil {
+		return true
+	}
+
+	outcome := s.Validate(value)
+	for _, annotation := range outcome.Annotations {
+		fmt.Fprintf(v.annotationsOut, "%s: %s\n", filename, colorize(v.annotationsOut, annotation.String(), colorYellow))
+	}
+	if outcome.Valid() {
+		return true
+	}
+
+	printError(stderr, "%s does not conform to %s:", filename, schemaName)
+	for _, violation := range outcome.Errors {
+		fmt.Fprintf(stderr, "  %s\n", violation)
+	}
+	result.exitCode, result.Errors = ExitSchema, len(outcome.Errors)
+	return false
+}
+
+// checkExpectation compares actual with the golden document, if any, and
+// prints a structural diff when they differ.
+func (v *validation) checkExpectation(actual parser.JSONValue, filename string, result *fileResult, stderr io.Writer) {
+	if v.expectFile == "" {
+		return
+	}
+
+	changes := diff.Compare(v.expected, actual)
+	if len(changes) == 0 {
+		return
+	}
+
+	printError(stderr, "%s does not match %s:", filename, v.expectFile)
+	for _, change := range changes {
+		fmt.Fprintf(stderr, "  %s\n", colorize(stderr, change.String(), changeColors[change.Type]))
+	}
+	result.exitCode, result.Errors = ExitInvalid, len(changes)
+}
@@ -0,0 +1,421 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunValidate(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	expected := writeFile("expected.json", `{"name": "app", "replicas": 3, "ports": [80, 443]}`)
+	equal := writeFile("equal.json", "{\n  \"ports\": [80, 443],\n  \"replicas\": 3.0,\n  \"name\": \"app\"\n}\n")
+	different := writeFile("different.json", `{"name": "app", "replicas": 2, "ports": [80]}`)
+	invalid := writeFile("invalid.json", `{"name": }`)
+	missing := filepath.Join(tempDir, "missing.json")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStderr []string
+	}{
+		{name: "valid without expectation", args: []string{"validate", different}, expectedExit: ExitSuccess},
+		{name: "invalid without expectation", args: []string{"validate", invalid}, expectedExit: ExitInvalid},
+		{name: "semantically equal", args: []string{"validate", "--expect", expected, equal}, expectedExit: ExitSuccess},
+		{name: "flag after file", args: []string{"validate", equal, "--expect", expected}, expectedExit: ExitSuccess},
+		{
+			name:           "different",
+			args:           []string{"validate", "--expect", expected, different},
+			expectedExit:   ExitInvalid,
+			expectedStderr: []string{"does not match", "- /ports/1: 443", "~ /replicas: 3 -> 2"},
+		},
+		{name: "invalid actual", args: []string{"validate", "--expect", expected, invalid}, expectedExit: ExitInvalid},
+		{name: "invalid expected", args: []string{"validate", "--expect", invalid, equal}, expectedExit: ExitInvalid},
+		{name: "missing expected", args: []string{"validate", "--expect", missing, equal}, expectedExit: ExitFileError},
+		{name: "missing filename", args: []string{"validate", "--expect", expected}, expectedExit: ExitInvalid},
+		{
+			name:           "glob pattern",
+			args:           []string{"validate", filepath.Join(tempDir, "*.json")},
+			expectedExit:   ExitInvalid,
+			expectedStderr: []string{"expected JSON value"},
+		},
+		{name: "pattern matching nothing", args: []string{"validate", filepath.Join(tempDir, "*.yaml")}, expectedExit: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			for _, want := range tt.expectedStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("expected stderr to contain %q, got: %s", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidate_Lines(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	valid := writeFile("valid.log", "{\"level\": \"info\"}\n\n{\"level\": \"warn\"}\n")
+	invalid := writeFile("invalid.log", "{\"level\": \"info\"}\n{\"level\": \n{\"level\": \"warn\"}\nnot json\n")
+	levels := writeFile("levels.json", `{"type": "object", "required": ["level"]}`)
+	noLevel := writeFile("nolevel.log", "{\"level\": \"info\"}\n{\"msg\": \"x\"}\n")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStderr []string
+	}{
+		{name: "valid lines", args: []string{"validate", "--lines", valid}, expectedExit: ExitSuccess},
+		{name: "not one document", args: []string{"validate", valid}, expectedExit: ExitInvalid},
+		{
+			name:           "every invalid line reported",
+			args:           []string{"validate", "--lines", invalid},
+			expectedExit:   ExitInvalid,
+			expectedStderr: []string{"invalid.log: line 2: ", "invalid.log: line 4: "},
+		},
+		{
+			name:           "schema per line",
+			args:           []string{"validate", "--lines", "--schema", levels, noLevel},
+			expectedExit:   ExitSchema,
+			expectedStderr: []string{"nolevel.log:2 does not conform"},
+		},
+		{name: "missing file", args: []string{"validate", "--lines", filepath.Join(tempDir, "missing.log")}, expectedExit: ExitFileError},
+		{name: "with --events", args: []string{"validate", "--lines", "--events", valid}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			for _, want := range tt.expectedStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("expected stderr to contain %q, got: %s", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidate_Recursive(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create test directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	writeFile("configs/app.json", `{"name": "app"}`)
+	writeFile("configs/notes.txt", "not json")
+	writeFile("configs/db/primary.json", `{"host": "db1"}`)
+	writeFile("configs/db/replica.json", `{"host": }`)
+	writeFile("configs/web/site.json", `{"port": 80}`)
+	for i := range 20 {
+		writeFile(fmt.Sprintf("configs/many/%02d.json", i), fmt.Sprintf(`{"n": %d, "bad": %s}`, i, []string{"1", "}"}[i%2]))
+	}
+	configs := filepath.Join(tempDir, "configs")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStdout []string
+	}{
+		{
+			name:           "valid tree",
+			args:           []string{"validate", "--recursive", filepath.Join(configs, "web")},
+			expectedExit:   ExitSuccess,
+			expectedStdout: []string{"site.json", "TOTAL", "1/1 valid"},
+		},
+		{
+			name:           "invalid file in tree",
+			args:           []string{"validate", "--recursive", "--jobs", "4", configs},
+			expectedExit:   ExitInvalid,
+			expectedStdout: []string{"replica.json", "invalid", "13/24 valid"},
+		},
+		{name: "directory without --recursive", args: []string{"validate", configs}, expectedExit: ExitFileError},
+		{name: "missing directory", args: []string{"validate", "--recursive", filepath.Join(tempDir, "missing")}, expectedExit: ExitFileError},
+		{name: "no jobs", args: []string{"validate", "--recursive", "--jobs", "0", configs}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			for _, want := range tt.expectedStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("expected stdout to contain %q, got: %s", want, stdout.String())
+				}
+			}
+		})
+	}
+
+	t.Run("output independent of jobs", func(t *testing.T) {
+		var sequential, concurrent bytes.Buffer
+		run("json-parser", []string{"validate", "--recursive", "--jobs", "1", "--format", "json", configs}, io.Discard, &sequential)
+		run("json-parser", []string{"validate", "--recursive", "--jobs", "8", "--format", "json", configs}, io.Discard, &concurrent)
+		if sequential.String() != concurrent.String() {
+			t.Errorf("expected the same messages with 1 and 8 jobs, got:\n%s\nand:\n%s", sequential.String(), concurrent.String())
+		}
+	})
+}
+
+func TestRunValidate_Schema(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	schemaFile := writeFile("schema.json", `{
+		"type": "object",
+		"required": ["id", "port"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+		}
+	}`)
+	badSchema := writeFile("bad_schema.json", `{"type": "text"}`)
+	conforming := writeFile("conforming.json", `{"id": "123e4567-e89b-12d3-a456-426614174000", "port": 8080}`)
+	badFormat := writeFile("bad_format.json", `{"id": "not-a-uuid", "port": 8080}`)
+	violating := writeFile("violating.json", `{"id": "123e4567-e89b-12d3-a456-426614174000", "port": 70000}`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{name: "conforming", args: []string{"validate", "--schema", schemaFile, conforming}, expectedExit: ExitSuccess},
+		{
+			name:           "format annotated by default",
+			args:           []string{"validate", "--schema", schemaFile, badFormat},
+			expectedExit:   ExitSuccess,
+			expectedStdout: "/id: value is not a valid uuid [format]",
+		},
+		{
+			name:           "format asserted",
+			args:           []string{"validate", "--schema", schemaFile, "--format-mode", "assert", badFormat},
+			expectedExit:   ExitSchema,
+			expectedStderr: "/id: value is not a valid uuid [format]",
+		},
+		{
+			name:           "violation",
+			args:           []string{"validate", violating, "--schema", schemaFile},
+			expectedExit:   ExitSchema,
+			expectedStderr: "/port: value 70000 is greater than the maximum 65535 [maximum]",
+		},
+		{name: "invalid schema", args: []string{"validate", "--schema", badSchema, conforming}, expectedExit: ExitInvalid, expectedStderr: "invalid schema"},
+		{name: "missing schema", args: []string{"validate", "--schema", filepath.Join(tempDir, "missing.json"), conforming}, expectedExit: ExitFileError},
+		{name: "invalid format mode", args: []string{"validate", "--schema", schemaFile, "--format-mode", "strict", conforming}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got: %s", tt.expectedStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunValidate_Events(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	valid := writeFile("valid.json", "{\n  \"name\": \"x\",\n  \"tags\": [1, true]\n}")
+	invalid := writeFile("invalid.json", `[null, }`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedExit   int
+		expectedStdout string
+	}{
+		{
+			name:         "events",
+			args:         []string{"validate", "--events", valid},
+			expectedExit: ExitSuccess,
+			expectedStdout: "1:1\tStartObject\n" +
+				"2:3\tKey \"name\"\n" +
+				"2:11\tString \"x\"\n" +
+				"3:3\tKey \"tags\"\n" +
+				"3:11\tStartArray\n" +
+				"3:12\tNumber 1\n" +
+				"3:15\tBoolean true\n" +
+				"3:19\tEndArray\n" +
+				"4:1\tEndObject\n",
+		},
+		{
+			name:           "stops at the first error",
+			args:           []string{"validate", "--events", invalid},
+			expectedExit:   ExitInvalid,
+			expectedStdout: "1:1\tStartArray\n1:2\tNull\n",
+		},
+		{
+			name:         "several files",
+			args:         []string{"validate", "--events", invalid, invalid},
+			expectedExit: ExitInvalid,
+			expectedStdout: invalid + ":1:1\tStartArray\n" + invalid + ":1:2\tNull\n" +
+				invalid + ":1:1\tStartArray\n" + invalid + ":1:2\tNull\n",
+		},
+		{name: "with json format", args: []string{"validate", "--events", "--format", "json", valid}, expectedExit: ExitInvalid},
+		{
+			name:           "standard input",
+			args:           []string{"validate", "--events", "-"},
+			stdin:          `{"a":1}`,
+			expectedExit:   ExitSuccess,
+			expectedStdout: "1:1\tStartObject\n1:2\tKey \"a\"\n1:6\tNumber 1\n1:7\tEndObject\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.stdin != "" {
+				withStdin(t, tt.stdin, false)
+			}
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedStdout != "" && !strings.HasPrefix(stdout.String(), tt.expectedStdout) {
+				t.Errorf("expected stdout to start with:\n%s\ngot:\n%s", tt.expectedStdout, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunValidate_SchemaDiscovery(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"required": ["name"]}`)
+	}))
+	defer server.Close()
+
+	writeFile("schema.json", `{"properties": {"port": {"type": "integer"}}}`)
+	writeFile("vendored.json", `{"required": ["id"]}`)
+	schemaMap := writeFile("map.json", `{"https://example.com/config.json": "vendored.json"}`)
+
+	local := writeFile("local.json", `{"$schema": "schema.json", "port": 80}`)
+	nonconforming := writeFile("nonconforming.json", `{"$schema": "./schema.json", "port": "80"}`)
+	fetched := writeFile("fetched.json", fmt.Sprintf(`{"$schema": %q}`, server.URL+"/config.json"))
+	mapped := writeFile("mapped.json", `{"$schema": "https://example.com/config.json", "id": 1}`)
+	missing := writeFile("missing.json", `{"$schema": "missing-schema.json"}`)
+	undeclared := writeFile("undeclared.json", `{"port": "80"}`)
+	cache, emptyCache := filepath.Join(tempDir, "cache"), filepath.Join(tempDir, "empty-cache")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedExit   int
+		expectedStderr string
+	}{
+		{name: "relative path", args: []string{"validate", local}, expectedExit: ExitSuccess},
+		{
+			name:           "nonconforming",
+			args:           []string{"validate", nonconforming},
+			expectedExit:   ExitSchema,
+			expectedStderr: "does not conform to ./schema.json",
+		},
+		{
+			name:           "fetched",
+			args:           []string{"validate", "--schema-cache", cache, fetched},
+			expectedExit:   ExitSchema,
+			expectedStderr: `missing required property "name"`,
+		},
+		{
+			name:           "offline uses the cache",
+			args:           []string{"validate", "--schema-cache", cache, "--offline", fetched},
+			expectedExit:   ExitSchema,
+			expectedStderr: `missing required property "name"`,
+		},
+		{
+			name:           "offline without cached schema",
+			args:           []string{"validate", "--schema-cache", emptyCache, "--offline", fetched},
+			expectedExit:   ExitFileError,
+			expectedStderr: "schema not cached",
+		},
+		{name: "offline with mapped schema", args: []string{"validate", "--schema-map", schemaMap, "--offline", mapped}, expectedExit: ExitSuccess},
+		{name: "negative TTL", args: []string{"validate", "--schema-cache-ttl", "-1h", local}, expectedExit: ExitInvalid},
+		{name: "mapped", args: []string{"validate", "--schema-map", schemaMap, mapped}, expectedExit: ExitSuccess},
+		{name: "unresolvable", args: []string{"validate", missing}, expectedExit: ExitFileError, expectedStderr: `cannot load schema "missing-schema.json"`},
+		{name: "no $schema", args: []string{"validate", undeclared}, expectedExit: ExitSuccess},
+		{name: "discovery disabled", args: []string{"validate", "--no-schema-discovery", nonconforming}, expectedExit: ExitSuccess},
+		{name: "--schema wins", args: []string{"validate", "--schema", filepath.Join(tempDir, "vendored.json"), local}, expectedExit: ExitSchema},
+		{name: "missing map", args: []string{"validate", "--schema-map", filepath.Join(tempDir, "nomap.json"), local}, expectedExit: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// runWrap implements `json-parser wrap [--compact] [--indent N] <file>...`, which prints
+// one JSON array holding every value from the given files, in order, to
+// consolidate per-item files into a single payload. A file may hold several
+// concatenated values (e.g. newline-delimited JSON); each becomes its own
+// element.
+func (inv *invocation) runWrap(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("wrap", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	compact := fs.Bool("compact", false, "print the array on a single line")
+	indent := fs.Int("indent", 2, "number of spaces per indentation level")
+
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return flagErrorExitCode(err)
+	}
+	if files, err = inv.expandGlobs(files); err != nil {
+		printError(stderr, "%v", err)
+		return ExitFileError
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "Usage: wrap [--compact] [--indent N] <filename>...")
+		return ExitInvalid
+	}
+	if *indent < 0 {
+		printError(stderr, "--indent must not be negative")
+		return ExitInvalid
+	}
+
+	handler := inv.newHandler()
+	elements := []parser.JSONValue{}
+	for _, filename := range files {
+		values, err := handler.ParseFileValues(filename)
+		if err != nil {
+			printError(stderr, "%v", err)
+			return exitCodeFor(err)
+		}
+		elements = append(elements, values...)
+	}
+
+	var output []byte
+	if *compact {
+		output, err = encoder.Marshal(elements)
+	} else {
+		output, err = encoder.MarshalIndent(elements, "", strings.Repeat(" ", *indent))
+	}
+	if err != nil {
+		printError(stderr, "%v", err)
+		return ExitInvalid
+	}
+
+	fmt.Fprintln(stdout, string(output))
+	return ExitSuccess
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWrap(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	first := writeFile("first.json", `{"id": 1}`)
+	second := writeFile("second.json", `[2, 3]`)
+	stream := writeFile("stream.ndjson", "{\"id\": 4}\n{\"id\": 5}\n")
+	empty := writeFile("empty.json", "\n")
+	invalid := writeFile("invalid.json", `{"id": 6} {"id": }`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		expectedOut  string
+	}{
+		{
+			name:         "files become elements",
+			args:         []string{"wrap", first, second},
+			expectedExit: ExitSuccess,
+			expectedOut:  "[\n  {\n    \"id\": 1\n  },\n  [\n    2,\n    3\n  ]\n]\n",
+		},
+		{
+			name:         "concatenated values become elements",
+			args:         []string{"wrap", "--compact", first, stream},
+			expectedExit: ExitSuccess,
+			expectedOut:  `[{"id":1},{"id":4},{"id":5}]` + "\n",
+		},
+		{name: "empty file", args: []string{"wrap", empty, "--compact"}, expectedExit: ExitSuccess, expectedOut: "[]\n"},
+		{name: "invalid value", args: []string{"wrap", first, invalid}, expectedExit: ExitInvalid},
+		{name: "missing file", args: []string{"wrap", filepath.Join(tempDir, "missing.json")}, expectedExit: ExitFileError},
+		{name: "negative indent", args: []string{"wrap", "--indent", "-1", first}, expectedExit: ExitInvalid},
+		{name: "missing filename", args: []string{"wrap"}, expectedExit: ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if exitCode := run("json-parser", tt.args, &stdout, &stderr); exitCode != tt.expectedExit {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+			if tt.expectedOut != "" && stdout.String() != tt.expectedOut {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tt.expectedOut, stdout.String())
+			}
+			if tt.expectedExit != ExitSuccess && stdout.Len() != 0 {
+				t.Errorf("expected no output on failure, got: %s", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,544 @@
+package decoder
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/fields"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// TypeError reports a parsed value that cannot be stored in the target Go type.
+type TypeError struct {
+	Path  string       // JSON Pointer (RFC 6901) to the offending value, "" for the root
+	Value string       // description of the JSON value, e.g. "string" or "number 300"
+	Type  reflect.Type // Go type the value could not be decoded into
+}
+
+// Error implements the error interface.
+func (e *TypeError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("cannot decode JSON %s into Go value of type %s at %s", e.Value, e.Type, path)
+}
+
+// FractionError reports a number with a fractional part, such as 3.7, that
+// was decoded into an integer type without WithIntegerRounding.
+type FractionError struct {
+	Path  string       // JSON Pointer (RFC 6901) to the offending value, "" for the root
+	Value float64      // the number as parsed
+	Type  reflect.Type // integer type it could not be decoded into
+}
+
+// Error implements the error interface.
+func (e *FractionError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("cannot decode JSON number %s into Go value of type %s at %s without losing its fractional part",
+		strconv.FormatFloat(e.Value, 'g', -1, 64), e.Type, path)
+}
+
+// Decode stores a parsed value in the value pointed to by v, converting
+// objects to structs or maps, arrays to slices or arrays, and numbers to any
+// numeric type they fit in without loss. Struct fields are matched like
+// encoding/json does, including json tags, the string option and fields
+// promoted from embedded structs. Decoding into an empty interface stores
+// the parsed value unchanged. A JSON null leaves non-pointer targets
+// untouched.
+//
+// As in encoding/json, and so that what encoder.Marshal writes decodes back,
+// json.Unmarshaler implementations decode themselves, from the value
+// re-encoded as JSON, encoding.TextUnmarshaler implementations (e.g.
+// time.Time) decode from strings, []byte decodes from base64 strings, and
+// maps may have integer or encoding.TextUnmarshaler keys.
+//
+// A number with a fractional part fails to decode into an integer type with a
+// *FractionError, unless WithIntegerRounding is given.
+func Decode(value parser.JSONValue, v any, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("decoder: Decode requires a non-nil pointer")
+	}
+	d := &decodeState{config: newConfig(opts)}
+	return d.decode("", value, rv.Elem())
+}
+
+// Unmarshal parses the JSON document data and stores it in the value pointed
+// to by v as Decode does, as a replacement for encoding/json's Unmarshal.
+// Invalid input is reported as a *parser.ParseError.
+func Unmarshal(data []byte, v any, opts ...Option) error {
+	input := string(data)
+	var parseOpts []parser.Option
+	if newConfig(opts).useNumber {
+		parseOpts = append(parseOpts, parser.WithRawNumbers())
+	}
+	value, err := parser.NewWithInput(lexer.New(input), input, parseOpts...).Parse()
+	if err != nil {
+		return err
+	}
+	return Decode(value, v, opts...)
+}
+
+// DecodeMap decodes a JSON object whose values all have the same shape into
+// a map[string]T. A JSON null yields a nil map. If a value doesn't fit T, the
+// returned *TypeError's Path names the offending key.
+func DecodeMap[T any](value parser.JSONValue, opts ...Option) (map[string]T, error) {
+	var m map[string]T
+	if err := Decode(value, &m, opts...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeState carries the configuration of a single Decode call.
+type decodeState struct {
+	config
+}
+
+// decode stores value, found at path, in rv.
+//
+// A JSON null sets pointers, interfaces, maps and slices to nil, marks an
+// Optional as explicitly null, and leaves all other values unchanged.
+func (d *decodeState) decode(path string, value parser.JSONValue, rv reflect.Value) error {
+	if rv.CanAddr() && rv.Addr().CanInterface() {
+		if opt, ok := rv.Addr().Interface().(optionalDecoder); ok {
+			return opt.decodeOptional(d, path, value)
+		}
+	}
+
+	if value == nil {
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			rv.SetZero()
+		}
+		return nil
+	}
+
+	if rv.Kind() != reflect.Pointer && rv.CanAddr() && rv.Addr().CanInterface() {
+		switch u := rv.Addr().Interface().(type) {
+		case json.Unmarshaler:
+			data, err := encoder.Marshal(value)
+			if err != nil {
+				return err
+			}
+			return u.UnmarshalJSON(data)
+		case encoding.TextUnmarshaler:
+			s, ok := value.(string)
+			if !ok {
+				return typeError(path, value, rv.Type())
+			}
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return d.decode(path, value, rv.Elem())
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return typeError(path, value, rv.Type())
+		}
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return typeError(path, value, rv.Type())
+		}
+		rv.SetBool(b)
+		return nil
+	case reflect.String:
+		if rv.Type() == numberType {
+			return d.decodeNumber(path, value, rv)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return typeError(path, value, rv.Type())
+		}
+		rv.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.decodeInt(path, value, rv)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return d.decodeUint(path, value, rv)
+	case reflect.Float32, reflect.Float64:
+		return d.decodeFloat(path, value, rv)
+	case reflect.Slice, reflect.Array:
+		return d.decodeArray(path, value, rv)
+	case reflect.Map:
+		return d.decodeMap(path, value, rv)
+	case reflect.Struct:
+		return d.decodeStruct(path, value, rv)
+	default:
+		return typeError(path, value, rv.Type())
+	}
+}
+
+// decodeInt stores an integral number in a signed integer.
+func (d *decodeState) decodeInt(path string, value parser.JSONValue, rv reflect.Value) error {
+	var n int64
+	switch num := value.(type) {
+	case parser.Number:
+		return d.decodeNumberAs(path, num, rv, d.decodeInt)
+	case int64:
+		n = num
+	case float64:
+		num, err := d.integral(path, num, rv.Type())
+		if err != nil {
+			return err
+		}
+		if num < math.MinInt64 || num >= math.MaxInt64 {
+			return typeError(path, value, rv.Type())
+		}
+		n = int64(num)
+	default:
+		return typeError(path, value, rv.Type())
+	}
+
+	if rv.OverflowInt(n) {
+		return typeError(path, value, rv.Type())
+	}
+	rv.SetInt(n)
+	return nil
+}
+
+// decodeUint stores a non-negative integral number in an unsigned integer.
+func (d *decodeState) decodeUint(path string, value parser.JSONValue, rv reflect.Value) error {
+	var n uint64
+	switch num := value.(type) {
+	case parser.Number:
+		// Beyond int64, the parser would read the number as a float64.
+		u, err := strconv.ParseUint(string(num), 10, 64)
+		if err != nil {
+			return d.decodeNumberAs(path, num, rv, d.decodeUint)
+		}
+		n = u
+	case int64:
+		if num < 0 {
+			return typeError(path, value, rv.Type())
+		}
+		n = uint64(num)
+	case float64:
+		num, err := d.integral(path, num, rv.Type())
+		if err != nil {
+			return err
+		}
+		if num < 0 || num >= math.MaxUint64 {
+			return typeError(path, value, rv.Type())
+		}
+		n = uint64(num)
+	default:
+		return typeError(path, value, rv.Type())
+	}
+
+	if rv.OverflowUint(n) {
+		return typeError(path, value, rv.Type())
+	}
+	rv.SetUint(n)
+	return nil
+}
+
+// numberType is the type of the numbers the parser keeps as written.
+var numberType = reflect.TypeFor[parser.Number]()
+
+// decodeNumber stores a number in a parser.Number, as written if it was kept
+// as a Number.
+func (d *decodeState) decodeNumber(path string, value parser.JSONValue, rv reflect.Value) error {
+	var text string
+	switch num := value.(type) {
+	case parser.Number:
+		text = string(num)
+	case int64:
+		text = strconv.FormatInt(num, 10)
+	case float64:
+		text = strconv.FormatFloat(num, 'g', -1, 64)
+	default:
+		return typeError(path, value, rv.Type())
+	}
+	rv.SetString(text)
+	return nil
+}
+
+// decodeNumberAs stores num with decode, as the int64 or float64 the parser
+// produces for it without WithRawNumbers.
+func (d *decodeState) decodeNumberAs(path string, num parser.Number, rv reflect.Value, decode func(string, parser.JSONValue, reflect.Value) error) error {
+	value, err := num.Value()
+	if err != nil {
+		return typeError(path, num, rv.Type())
+	}
+	return decode(path, value, rv)
+}
+
+// integral returns num if it has no fractional part, or num rounded to the
+// nearest integer if rounding is enabled.
+func (d *decodeState) integral(path string, num float64, t reflect.Type) (float64, error) {
+	if num == math.Trunc(num) {
+		return num, nil
+	}
+	if d.roundIntegers {
+		return math.Round(num), nil
+	}
+	return 0, &FractionError{Path: path, Value: num, Type: t}
+}
+
+// decodeFloat stores a number in a floating-point value.
+func (d *decodeState) decodeFloat(path string, value parser.JSONValue, rv reflect.Value) error {
+	var f float64
+	switch num := value.(type) {
+	case parser.Number:
+		return d.decodeNumberAs(path, num, rv, d.decodeFloat)
+	case int64:
+		f = float64(num)
+	case float64:
+		f = num
+	default:
+		return typeError(path, value, rv.Type())
+	}
+
+	if rv.OverflowFloat(f) {
+		return typeError(path, value, rv.Type())
+	}
+	rv.SetFloat(f)
+	return nil
+}
+
+// decodeArray stores a JSON array in a slice or a Go array. Like
+// encoding/json, extra elements are dropped and missing ones are zeroed
+// when the target is a fixed-size array, and a []byte also decodes from a
+// base64 string.
+func (d *decodeState) decodeArray(path string, value parser.JSONValue, rv reflect.Value) error {
+	if s, ok := value.(string); ok && rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("decoder: invalid base64 data at %s: %w", displayPath(path), err)
+		}
+		rv.SetBytes(b)
+		return nil
+	}
+
+	arr, ok := value.([]any)
+	if !ok {
+		return typeError(path, value, rv.Type())
+	}
+
+	if rv.Kind() == reflect.Slice {
+		rv.Set(reflect.MakeSlice(rv.Type(), len(arr), len(arr)))
+	}
+
+	for i := range rv.Len() {
+		if i >= len(arr) {
+			rv.Index(i).SetZero()
+			continue
+		}
+		if err := d.decode(path+"/"+strconv.Itoa(i), arr[i], rv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMap stores a JSON object in a map with string, integer or
+// encoding.TextUnmarshaler keys. Members are decoded in key order so errors
+// are reported deterministically.
+func (d *decodeState) decodeMap(path string, value parser.JSONValue, rv reflect.Value) error {
+	obj, ok := parser.AsObject(value)
+	keyType := rv.Type().Key()
+	if !ok || !mapKeyType(keyType) {
+		return typeError(path, value, rv.Type())
+	}
+
+	m := reflect.MakeMapWithSize(rv.Type(), len(obj))
+	elemType := rv.Type().Elem()
+	for _, key := range sortedKeys(obj) {
+		memberPath := path + "/" + pointer.Escape(key)
+		k := reflect.New(keyType).Elem()
+		if err := decodeMapKey(memberPath, key, k); err != nil {
+			return err
+		}
+		elem := reflect.New(elemType).Elem()
+		if err := d.decode(memberPath, obj[key], elem); err != nil {
+			return err
+		}
+		m.SetMapIndex(k, elem)
+	}
+	rv.Set(m)
+	return nil
+}
+
+// textUnmarshalerType is the type of encoding.TextUnmarshaler.
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// mapKeyType reports whether maps with keys of type t can be decoded.
+func mapKeyType(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// decodeMapKey stores key, the name of the member at path, in k. As in
+// encoding/json, encoding.TextUnmarshaler takes precedence over the kind of
+// the key type.
+func decodeMapKey(path, key string, k reflect.Value) error {
+	if u, ok := k.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(key))
+	}
+	switch k.Kind() {
+	case reflect.String:
+		k.SetString(key)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err == nil && !k.OverflowInt(n) {
+			k.SetInt(n)
+			return nil
+		}
+	default:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err == nil && !k.OverflowUint(n) {
+			k.SetUint(n)
+			return nil
+		}
+	}
+	return &TypeError{Path: path, Value: "key " + strconv.Quote(key), Type: k.Type()}
+}
+
+// decodeStruct stores a JSON object in a struct, matching members to fields
+// by json tag or field name (see fields.For). Members without a matching
+// field are ignored.
+func (d *decodeState) decodeStruct(path string, value parser.JSONValue, rv reflect.Value) error {
+	obj, ok := parser.AsObject(value)
+	if !ok {
+		return typeError(path, value, rv.Type())
+	}
+
+	fieldsOf := fields.For(rv.Type())
+	for _, key := range sortedKeys(obj) {
+		f, ok := fieldsOf.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		fv, err := fieldByIndex(rv, f.Index)
+		if err != nil {
+			return err
+		}
+		decode := d.decode
+		if f.Quoted {
+			decode = d.decodeQuoted
+		}
+		if err := decode(path+"/"+pointer.Escape(key), obj[key], fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeQuoted stores value in rv, a field with the string option: value is
+// a string holding the JSON encoding of a boolean, number or string, which is
+// decoded in its place. As in encoding/json, null is decoded as it is.
+func (d *decodeState) decodeQuoted(path string, value parser.JSONValue, rv reflect.Value) error {
+	if value == nil {
+		return d.decode(path, value, rv)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return typeError(path, value, rv.Type())
+	}
+	inner, err := parser.New(lexer.New(s), parser.WithRawNumbers()).Parse()
+	if err != nil {
+		return typeError(path, value, rv.Type())
+	}
+	switch inner.(type) {
+	case []any, map[string]any:
+		return typeError(path, value, rv.Type())
+	}
+	return d.decode(path, inner, rv)
+}
+
+// fieldByIndex returns the field of rv at index, allocating nil pointers to
+// embedded structs along the way.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("decoder: cannot set embedded pointer to unexported struct %v", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+// sortedKeys returns the keys of obj in sorted order.
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// displayPath returns path for messages, naming the root "(root)".
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// typeError builds a *TypeError for value at path.
+func typeError(path string, value parser.JSONValue, t reflect.Type) error {
+	return &TypeError{Path: path, Value: describe(value), Type: t}
+}
+
+// describe names the JSON type of value, including numbers' values since
+// those are the usual source of range errors.
+func describe(value parser.JSONValue) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64:
+		return "number " + strconv.FormatInt(v, 10)
+	case float64:
+		return "number " + strconv.FormatFloat(v, 'g', -1, 64)
+	case parser.Number:
+		return "number " + string(v)
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
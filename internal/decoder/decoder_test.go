@@ -0,0 +1,418 @@
+package decoder
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+type label string
+
+func TestDecode(t *testing.T) {
+	str := "s"
+	tests := []struct {
+		name     string
+		input    string
+		target   any // pointer to a zero value of the target type
+		expected any
+	}{
+		{name: "bool", input: `true`, target: new(bool), expected: true},
+		{name: "string", input: `"hi"`, target: new(string), expected: "hi"},
+		{name: "named string", input: `"hi"`, target: new(label), expected: label("hi")},
+		{name: "int", input: `42`, target: new(int), expected: 42},
+		{name: "int from integral float", input: `1e3`, target: new(int32), expected: int32(1000)},
+		{name: "uint", input: `7`, target: new(uint8), expected: uint8(7)},
+		{name: "float from int", input: `2`, target: new(float64), expected: 2.0},
+		{name: "float32", input: `1.5`, target: new(float32), expected: float32(1.5)},
+		{name: "slice", input: `[1, 2, 3]`, target: new([]int), expected: []int{1, 2, 3}},
+		{name: "empty slice", input: `[]`, target: new([]string), expected: []string{}},
+		{name: "array pads", input: `[1]`, target: new([3]int), expected: [3]int{1, 0, 0}},
+		{name: "array truncates", input: `[1, 2, 3]`, target: new([2]int), expected: [2]int{1, 2}},
+		{name: "map", input: `{"a": [true], "b": []}`, target: new(map[string][]bool), expected: map[string][]bool{"a": {true}, "b": {}}},
+		{name: "map with named keys", input: `{"a": 1}`, target: new(map[label]int), expected: map[label]int{"a": 1}},
+		{name: "pointer", input: `"s"`, target: new(*string), expected: &str},
+		{name: "null pointer", input: `null`, target: new(*string), expected: (*string)(nil)},
+		{name: "null slice", input: `null`, target: new([]int), expected: []int(nil)},
+		{name: "null leaves scalar", input: `null`, target: new(int), expected: 0},
+		{name: "interface", input: `{"a": [1]}`, target: new(any), expected: parser.JSONObject{"a": []any{int64(1)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Decode(mustParse(t, tt.input), tt.target); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := reflect.ValueOf(tt.target).Elem().Interface()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecode_TypeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   any
+		expected string
+	}{
+		{name: "string into int", input: `"1"`, target: new(int), expected: "cannot decode JSON string into Go value of type int at (root)"},
+		{name: "overflow", input: `300`, target: new(int8), expected: "cannot decode JSON number 300 into Go value of type int8 at (root)"},
+		{name: "negative into uint", input: `-1`, target: new(uint), expected: "cannot decode JSON number -1 into Go value of type uint at (root)"},
+		{name: "float32 overflow", input: `1e300`, target: new(float32), expected: "cannot decode JSON number 1e+300 into Go value of type float32 at (root)"},
+		{name: "object into slice", input: `{}`, target: new([]int), expected: "cannot decode JSON object into Go value of type []int at (root)"},
+		{name: "nested element", input: `[[1], [2, "x"]]`, target: new([][]int), expected: "cannot decode JSON string into Go value of type int at /1/1"},
+		{name: "unsupported map key", input: `{"1": 1}`, target: new(map[float64]int), expected: "cannot decode JSON object into Go value of type map[float64]int at (root)"},
+		{name: "non-integer map key", input: `{"x": 1}`, target: new(map[int]int), expected: "cannot decode JSON key \"x\" into Go value of type int at /x"},
+		{name: "map key overflow", input: `{"300": 1}`, target: new(map[uint8]int), expected: "cannot decode JSON key \"300\" into Go value of type uint8 at /300"},
+		{name: "text unmarshaler from number", input: `1`, target: new(netip.Addr), expected: "cannot decode JSON number 1 into Go value of type netip.Addr at (root)"},
+		{name: "unsupported type", input: `1`, target: new(chan int), expected: "cannot decode JSON number 1 into Go value of type chan int at (root)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(mustParse(t, tt.input), tt.target)
+			var typeErr *TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected a *TypeError, got %v", err)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestDecode_FractionError(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target any
+		path   string
+	}{
+		{name: "int", input: `3.7`, target: new(int), path: ""},
+		{name: "uint", input: `0.5`, target: new(uint16), path: ""},
+		{name: "nested", input: `{"a": [1, 2.5]}`, target: new(map[string][]int64), path: "/a/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(mustParse(t, tt.input), tt.target)
+			var fracErr *FractionError
+			if !errors.As(err, &fracErr) {
+				t.Fatalf("expected a *FractionError, got %v", err)
+			}
+			if fracErr.Path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, fracErr.Path)
+			}
+		})
+	}
+
+	err := Decode(mustParse(t, `{"n": 3.7}`), new(map[string]int))
+	if expected := "cannot decode JSON number 3.7 into Go value of type int at /n without losing its fractional part"; err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestDecode_WithIntegerRounding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{input: `3.7`, expected: 4},
+		{input: `3.2`, expected: 3},
+		{input: `2.5`, expected: 3},
+		{input: `-2.5`, expected: -3},
+		{input: `4`, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var n int
+			if err := Decode(mustParse(t, tt.input), &n, WithIntegerRounding()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, n)
+			}
+		})
+	}
+
+	var u uint8
+	if err := Decode(mustParse(t, `255.7`), &u, WithIntegerRounding()); err == nil {
+		t.Errorf("expected a range error after rounding, got %d", u)
+	}
+	if err := Decode(mustParse(t, `-0.4`), &u, WithIntegerRounding()); err != nil || u != 0 {
+		t.Errorf("expected -0.4 to round to 0, got %d (err %v)", u, err)
+	}
+}
+
+func TestDecode_InvalidTarget(t *testing.T) {
+	var n int
+	for _, target := range []any{nil, n, (*int)(nil)} {
+		if err := Decode(int64(1), target); err == nil {
+			t.Errorf("expected an error for target %#v", target)
+		}
+	}
+}
+
+func TestDecodeMap(t *testing.T) {
+	ports, err := DecodeMap[int](mustParse(t, `{"http": 80, "https": 443}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string]int{"http": 80, "https": 443}; !reflect.DeepEqual(ports, expected) {
+		t.Errorf("expected %v, got %v", expected, ports)
+	}
+
+	nested, err := DecodeMap[[]string](mustParse(t, `{"a": ["x"], "b": null}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string][]string{"a": {"x"}, "b": nil}; !reflect.DeepEqual(nested, expected) {
+		t.Errorf("expected %v, got %v", expected, nested)
+	}
+
+	empty, err := DecodeMap[bool](mustParse(t, `null`))
+	if err != nil || empty != nil {
+		t.Errorf("expected nil map for null, got %v (err %v)", empty, err)
+	}
+}
+
+func TestDecodeMap_ReportsOffendingKey(t *testing.T) {
+	_, err := DecodeMap[int](mustParse(t, `{"http": 80, "https": "443", "a/b": 1}`))
+
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected a *TypeError, got %v", err)
+	}
+	if typeErr.Path != "/https" || typeErr.Value != "string" || typeErr.Type != reflect.TypeFor[int]() {
+		t.Errorf("unexpected error details: %+v", typeErr)
+	}
+
+	if _, err := DecodeMap[int](mustParse(t, `[1]`)); err == nil {
+		t.Error("expected an error when decoding an array")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type person struct {
+		Name    string            `json:"name"`
+		Address address           `json:"address"`
+		Emails  []string          `json:"emails"`
+		Labels  map[string]string `json:"labels"`
+		Manager *person           `json:"manager,omitempty"`
+	}
+
+	var p person
+	input := `{"name": "a", "address": {"city": "x"}, "emails": ["a@x", "b@x"], "labels": {"team": "t"}, "manager": {"name": "b"}}`
+	if err := Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := person{
+		Name:    "a",
+		Address: address{City: "x"},
+		Emails:  []string{"a@x", "b@x"},
+		Labels:  map[string]string{"team": "t"},
+		Manager: &person{Name: "b"},
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("expected %+v, got %+v", expected, p)
+	}
+
+	var parseErr *parser.ParseError
+	if err := Unmarshal([]byte(`{"name": }`), &p); !errors.As(err, &parseErr) {
+		t.Errorf("expected a *parser.ParseError, got %v", err)
+	}
+	var typeErr *TypeError
+	if err := Unmarshal([]byte(`{"emails": "a@x"}`), &p); !errors.As(err, &typeErr) || typeErr.Path != "/emails" {
+		t.Errorf("expected a *TypeError at /emails, got %v", err)
+	}
+}
+
+// celsius decodes itself from JSON, accepting numbers only.
+type celsius float64
+
+func (c *celsius) UnmarshalJSON(data []byte) error {
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("celsius: %w", err)
+	}
+	*c = celsius(f)
+	return nil
+}
+
+func TestUnmarshal_RoundTrip(t *testing.T) {
+	type event struct {
+		When    time.Time          `json:"when"`
+		Until   *time.Time         `json:"until"`
+		Payload []byte             `json:"payload"`
+		Counts  map[int]string     `json:"counts"`
+		Sizes   map[uint16]bool    `json:"sizes"`
+		Hosts   map[netip.Addr]int `json:"hosts"`
+		ID      int64              `json:"id,string"`
+		Label   string             `json:"label,string"`
+		Ratio   *float64           `json:"ratio,string"`
+		Enabled bool               `json:"enabled,string"`
+		Temp    celsius            `json:"temp"`
+	}
+
+	until := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	ratio := 0.25
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{
+			name: "all fields",
+			value: &event{
+				When:    time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+				Until:   &until,
+				Payload: []byte{0, 1, 2, 0xff},
+				Counts:  map[int]string{-1: "neg", 10: "ten"},
+				Sizes:   map[uint16]bool{65535: true},
+				Hosts:   map[netip.Addr]int{netip.MustParseAddr("10.0.0.1"): 1, netip.MustParseAddr("::1"): 6},
+				ID:      1 << 60,
+				Label:   `say "hi"`,
+				Ratio:   &ratio,
+				Enabled: true,
+				Temp:    21.5,
+			},
+		},
+		{name: "zero fields", value: &event{}},
+		{name: "time", value: new(time.Time)},
+		{name: "bytes", value: &[]byte{'h', 'i'}},
+		{name: "integer keys", value: &map[int8][]int{-128: {1}, 127: nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := encoder.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := reflect.New(reflect.TypeOf(tt.value).Elem())
+			if err := Unmarshal(data, got.Interface()); err != nil {
+				t.Fatalf("unexpected error decoding %s: %v", data, err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.value) {
+				t.Errorf("expected %+v, got %+v from %s", tt.value, got.Elem(), data)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_StringOption(t *testing.T) {
+	type quoted struct {
+		N    int      `json:"n,string"`
+		P    *int     `json:"p,string"`
+		Tags []string `json:"tags,string"`
+	}
+	seven := 7
+	tests := []struct {
+		name     string
+		input    string
+		expected quoted
+		wantErr  bool
+	}{
+		{name: "quoted", input: `{"n": "42", "p": "7", "tags": ["a"]}`, expected: quoted{N: 42, P: &seven, Tags: []string{"a"}}},
+		{name: "null", input: `{"n": "1", "p": null}`, expected: quoted{N: 1}},
+		{name: "unquoted number", input: `{"n": 42}`, wantErr: true},
+		{name: "not a number", input: `{"n": "x"}`, wantErr: true},
+		{name: "not a scalar", input: `{"n": "[1]"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got quoted
+			err := Unmarshal([]byte(tt.input), &got)
+			var typeErr *TypeError
+			if tt.wantErr {
+				if !errors.As(err, &typeErr) || typeErr.Path != "/n" {
+					t.Errorf("expected a *TypeError at /n, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InvalidBase64(t *testing.T) {
+	var b []byte
+	err := Unmarshal([]byte(`{"data": "not base64!"}`), &struct {
+		Data *[]byte `json:"data"`
+	}{Data: &b})
+	var corrupt base64.CorruptInputError
+	if !errors.As(err, &corrupt) || !strings.Contains(err.Error(), "/data") {
+		t.Errorf("expected a base64 error at /data, got %v", err)
+	}
+}
+
+func TestUnmarshal_UseNumber(t *testing.T) {
+	type record struct {
+		Big   uint64        `json:"big"`
+		Int   int64         `json:"int"`
+		Float float64       `json:"float"`
+		Raw   parser.Number `json:"raw"`
+		Any   any           `json:"any"`
+	}
+
+	var r record
+	input := `{"big": 18446744073709551615, "int": -42, "float": 1.5e3, "raw": 1.10, "any": 12345678901234567890}`
+	if err := Unmarshal([]byte(input), &r, UseNumber()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := record{
+		Big:   18446744073709551615,
+		Int:   -42,
+		Float: 1500,
+		Raw:   "1.10",
+		Any:   parser.Number("12345678901234567890"),
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("expected %+v, got %+v", expected, r)
+	}
+
+	var fracErr *FractionError
+	if err := Unmarshal([]byte(`{"int": 1.5}`), &r, UseNumber()); !errors.As(err, &fracErr) || fracErr.Path != "/int" {
+		t.Errorf("expected a *FractionError at /int, got %v", err)
+	}
+	var typeErr *TypeError
+	if err := Unmarshal([]byte(`{"raw": "1"}`), &r, UseNumber()); !errors.As(err, &typeErr) || typeErr.Path != "/raw" {
+		t.Errorf("expected a *TypeError at /raw, got %v", err)
+	}
+
+	var n parser.Number
+	if err := Unmarshal([]byte(`7`), &n); err != nil || n != "7" {
+		t.Errorf("expected Number 7 without UseNumber, got %q (%v)", n, err)
+	}
+}
@@ -0,0 +1,62 @@
+package decoder
+
+import (
+	"reflect"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Optional holds a value that may be absent, explicitly null, or present,
+// for tri-state settings where a missing member ("keep the default") must be
+// told apart from null ("unset it"). A pointer field can't do this, because
+// both cases decode to nil.
+//
+// The zero Optional is absent. Decoding a member sets it; members that don't
+// appear in the object leave it untouched.
+type Optional[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Some returns a present Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Null returns an Optional that was explicitly set to null.
+func Null[T any]() Optional[T] {
+	return Optional[T]{set: true, null: true}
+}
+
+// IsSet reports whether the member was present, including as null.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsNull reports whether the member was present and null.
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// Get returns the value and whether one is present. It returns the zero
+// value and false when the member was absent or null.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set && !o.null
+}
+
+// optionalDecoder is implemented by *Optional[T] so decode can recognize
+// optionals of any element type.
+type optionalDecoder interface {
+	decodeOptional(d *decodeState, path string, value parser.JSONValue) error
+}
+
+// decodeOptional marks the optional as set and decodes non-null values into
+// it.
+func (o *Optional[T]) decodeOptional(d *decodeState, path string, value parser.JSONValue) error {
+	*o = Optional[T]{set: true, null: value == nil}
+	if value == nil {
+		return nil
+	}
+	return d.decode(path, value, reflect.ValueOf(&o.value).Elem())
+}
@@ -0,0 +1,86 @@
+package decoder
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Settings struct {
+	Timeout Optional[int]               `json:"timeout"`
+	Labels  Optional[map[string]string] `json:"labels"`
+	Proxy   *string                     `json:"proxy"`
+}
+
+func TestDecode_Optional(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		isSet     bool
+		isNull    bool
+		value     int
+		hasValue  bool
+		proxyNil  bool
+		labelsSet bool
+	}{
+		{name: "absent", input: `{}`, proxyNil: true},
+		{name: "explicit null", input: `{"timeout": null, "proxy": null}`, isSet: true, isNull: true, proxyNil: true},
+		{name: "value", input: `{"timeout": 30, "proxy": "http://proxy", "labels": {}}`, isSet: true, value: 30, hasValue: true, labelsSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Settings
+			if err := Decode(mustParse(t, tt.input), &s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if s.Timeout.IsSet() != tt.isSet || s.Timeout.IsNull() != tt.isNull {
+				t.Errorf("expected set=%v null=%v, got %+v", tt.isSet, tt.isNull, s.Timeout)
+			}
+			if value, ok := s.Timeout.Get(); value != tt.value || ok != tt.hasValue {
+				t.Errorf("expected Get() = %d, %v; got %d, %v", tt.value, tt.hasValue, value, ok)
+			}
+			if (s.Proxy == nil) != tt.proxyNil {
+				t.Errorf("expected proxy nil=%v, got %v", tt.proxyNil, s.Proxy)
+			}
+			if s.Labels.IsSet() != tt.labelsSet {
+				t.Errorf("expected labels set=%v", tt.labelsSet)
+			}
+		})
+	}
+}
+
+func TestDecode_NullResetsPointers(t *testing.T) {
+	proxy := "old"
+	s := Settings{Proxy: &proxy, Timeout: Some(5)}
+	if err := Decode(mustParse(t, `{"proxy": null, "timeout": null}`), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Proxy != nil {
+		t.Errorf("expected null to reset the pointer, got %q", *s.Proxy)
+	}
+	if !reflect.DeepEqual(s.Timeout, Null[int]()) {
+		t.Errorf("expected an explicit null, got %+v", s.Timeout)
+	}
+}
+
+func TestDecode_OptionalErrors(t *testing.T) {
+	var s Settings
+	err := Decode(mustParse(t, `{"timeout": "soon"}`), &s)
+	if err == nil || err.Error() != "cannot decode JSON string into Go value of type int at /timeout" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOptional_Constructors(t *testing.T) {
+	if v, ok := Some("x").Get(); !ok || v != "x" {
+		t.Errorf("Some: got %q, %v", v, ok)
+	}
+	if _, ok := Null[string]().Get(); ok || !Null[string]().IsSet() {
+		t.Error("Null should be set but hold no value")
+	}
+	var absent Optional[string]
+	if absent.IsSet() || absent.IsNull() {
+		t.Error("zero Optional should be absent")
+	}
+}
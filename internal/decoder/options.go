@@ -0,0 +1,41 @@
+package decoder
+
+// config holds settings that control how values are decoded.
+type config struct {
+	roundIntegers bool
+	useNumber     bool // see UseNumber
+}
+
+// Option configures decoding.
+type Option func(*config)
+
+// newConfig returns the default configuration with opts applied.
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithIntegerRounding lets numbers with a fractional part decode into
+// integer types by rounding to the nearest integer (halves away from zero).
+// By default such numbers fail with a *FractionError instead of being
+// silently truncated.
+func WithIntegerRounding() Option {
+	return func(c *config) {
+		c.roundIntegers = true
+	}
+}
+
+// UseNumber makes Unmarshal and Decoder keep numbers as parser.Number, their
+// text as written, wherever the parsed value is stored as is: in empty
+// interfaces, maps of them and the results of Decoder.Token. Like
+// encoding/json's Decoder.UseNumber, it defers the conversion to the caller,
+// so that IDs beyond 2^53 and decimals such as prices don't lose precision
+// as float64. Numeric targets decode from a Number as from any number.
+func UseNumber() Option {
+	return func(c *config) {
+		c.useNumber = true
+	}
+}
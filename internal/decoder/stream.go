@@ -0,0 +1,273 @@
+package decoder
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Token is a token returned by Decoder.Token: a Delim for the start or end
+// of an array or object, a string for an object key or string value, a bool,
+// nil for null, or an int64 or float64 for a number, as parser.ParseNumber
+// reads it, or a parser.Number with UseNumber.
+type Token any
+
+// Delim is one of the array and object delimiters '[', ']', '{' and '}'.
+type Delim rune
+
+// String returns the delimiter as a string, e.g. "{".
+func (d Delim) String() string {
+	return string(d)
+}
+
+// tokenState is what a Decoder expects next.
+type tokenState int
+
+const (
+	stateValue      tokenState = iota // a value
+	stateFirstValue                   // a value or ']'
+	stateKey                          // a key
+	stateFirstKey                     // a key or '}'
+	stateColon                        // ':'
+	stateSeparator                    // ',' or the end of the enclosing container, or another document
+)
+
+// Decoder reads JSON values from an input stream, either token by token or a
+// value at a time, like encoding/json's Decoder. The input is tokenized
+// incrementally, so a large document can be walked without building it in
+// memory, decoding only the parts of interest. The stream may hold several
+// whitespace-separated documents.
+type Decoder struct {
+	lexer lexer.Lexer
+	opts  []Option
+	stack []Delim // open containers, '{' or '['
+	state tokenState
+
+	peeked bool // tok and err hold the next token
+	tok    lexer.Token
+	err    error
+
+	useNumber bool // see UseNumber
+}
+
+// NewDecoder returns a Decoder that reads from r. The options apply to the
+// values stored by Decode.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{lexer: lexer.NewReader(r), opts: opts, useNumber: newConfig(opts).useNumber}
+}
+
+// Token returns the next token, or nil and io.EOF at the end of the input.
+// Commas and colons are checked and consumed rather than returned, so the
+// tokens of an object alternate between keys and values.
+func (d *Decoder) Token() (Token, error) {
+	tok, err := d.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.Type {
+	case lexer.RIGHT_BRACE, lexer.RIGHT_BRACKET:
+		return d.close(tok)
+	case lexer.EOF:
+		if len(d.stack) == 0 && d.state == stateValue {
+			return nil, io.EOF
+		}
+	}
+
+	switch d.state {
+	case stateFirstKey, stateKey:
+		if tok.Type != lexer.STRING {
+			return nil, unexpected(tok, "string key")
+		}
+		d.skip()
+		d.state = stateColon
+		return tok.Value, nil
+	case stateSeparator:
+		if d.stack[len(d.stack)-1] == '{' {
+			return nil, unexpected(tok, "',' or '}'")
+		}
+		return nil, unexpected(tok, "',' or ']'")
+	}
+
+	d.skip()
+	d.state = stateSeparator
+	switch tok.Type {
+	case lexer.LEFT_BRACE:
+		d.stack = append(d.stack, '{')
+		d.state = stateFirstKey
+		return Delim('{'), nil
+	case lexer.LEFT_BRACKET:
+		d.stack = append(d.stack, '[')
+		d.state = stateFirstValue
+		return Delim('['), nil
+	case lexer.STRING:
+		return tok.Value, nil
+	case lexer.NUMBER:
+		if d.useNumber {
+			return parser.Number(tok.Value), nil
+		}
+		return parser.ParseNumber(tok.Value)
+	case lexer.BOOLEAN:
+		return tok.Value == "true", nil
+	case lexer.NULL:
+		return nil, nil
+	default:
+		return nil, unexpected(tok, "value")
+	}
+}
+
+// More reports whether the current array or object has another element, or,
+// between documents, whether another document follows.
+func (d *Decoder) More() bool {
+	tok, err := d.advance()
+	if err != nil {
+		return false
+	}
+	switch tok.Type {
+	case lexer.RIGHT_BRACE, lexer.RIGHT_BRACKET, lexer.EOF:
+		return false
+	default:
+		return true
+	}
+}
+
+// Decode reads the next value, which may be a whole document or an element
+// or member value inside the one being walked with Token, and stores it in
+// the value pointed to by v as Decode does.
+func (d *Decoder) Decode(v any) error {
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+	return Decode(value, v, d.opts...)
+}
+
+// value reads the next value into a parser.JSONValue, using Token so that
+// its syntax is checked the same way.
+func (d *Decoder) value() (parser.JSONValue, error) {
+	tok, err := d.advance()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Type == lexer.RIGHT_BRACE || tok.Type == lexer.RIGHT_BRACKET || d.state == stateFirstKey || d.state == stateKey {
+		return nil, unexpected(tok, "value")
+	}
+
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case Delim('{'):
+		obj := parser.NewJSONObject()
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			if obj[key.(string)], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := d.Token(); err != nil { // '}'
+			return nil, err
+		}
+		return obj, nil
+	case Delim('['):
+		arr := []any{}
+		for d.More() {
+			elem, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, elem)
+		}
+		if _, err := d.Token(); err != nil { // ']'
+			return nil, err
+		}
+		return arr, nil
+	default:
+		return t, nil
+	}
+}
+
+// advance consumes the commas and colons before the next token that Token
+// returns, checking that they are in place, and returns that token without
+// consuming it.
+func (d *Decoder) advance() (lexer.Token, error) {
+	for {
+		tok, err := d.peek()
+		if err != nil {
+			return tok, err
+		}
+		switch d.state {
+		case stateColon:
+			if tok.Type != lexer.COLON {
+				return tok, unexpected(tok, "':'")
+			}
+			d.state = stateValue
+		case stateSeparator:
+			if len(d.stack) == 0 {
+				d.state = stateValue // another document
+				return tok, nil
+			}
+			if tok.Type != lexer.COMMA {
+				return tok, nil
+			}
+			if d.stack[len(d.stack)-1] == '{' {
+				d.state = stateKey
+			} else {
+				d.state = stateValue
+			}
+		default:
+			return tok, nil
+		}
+		d.skip()
+	}
+}
+
+// close consumes the '}' or ']' tok if it ends the innermost container.
+func (d *Decoder) close(tok lexer.Token) (Token, error) {
+	delim := Delim(tok.Value[0])
+	open := Delim('{')
+	if delim == ']' {
+		open = '['
+	}
+
+	switch {
+	case len(d.stack) == 0 || d.stack[len(d.stack)-1] != open:
+		return nil, unexpected(tok, "value")
+	case d.state == stateKey:
+		return nil, unexpected(tok, "string key")
+	case d.state == stateValue:
+		return nil, unexpected(tok, "value")
+	}
+	d.skip()
+	d.stack = d.stack[:len(d.stack)-1]
+	d.state = stateSeparator
+	return delim, nil
+}
+
+// peek returns the next token without consuming it.
+func (d *Decoder) peek() (lexer.Token, error) {
+	if !d.peeked {
+		d.tok, d.err = d.lexer.NextToken()
+		d.peeked = true
+	}
+	return d.tok, d.err
+}
+
+// skip consumes the token returned by peek.
+func (d *Decoder) skip() {
+	d.peeked = false
+}
+
+// unexpected returns the error for finding tok where expected was expected.
+func unexpected(tok lexer.Token, expected string) error {
+	if tok.Type == lexer.EOF {
+		return fmt.Errorf("unexpected end of input at %s, expected %s", tok.Position, expected)
+	}
+	return fmt.Errorf("unexpected %s at %s, expected %s", tok.Type, tok.Position, expected)
+}
@@ -0,0 +1,186 @@
+package decoder
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestDecoder_Token(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{name: "empty", input: "  "},
+		{name: "scalar", input: `"hi"`, expected: []Token{"hi"}},
+		{
+			name:  "document",
+			input: `{"a": [1, 2.5, true, null], "b": {}}`,
+			expected: []Token{
+				Delim('{'), "a", Delim('['), int64(1), 2.5, true, nil, Delim(']'),
+				"b", Delim('{'), Delim('}'), Delim('}'),
+			},
+		},
+		{name: "several documents", input: "1 [] \"x\"\n", expected: []Token{int64(1), Delim('['), Delim(']'), "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(strings.NewReader(tt.input))
+			var tokens []Token
+			for {
+				tok, err := dec.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				tokens = append(tokens, tok)
+			}
+			if !reflect.DeepEqual(tokens, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, tokens)
+			}
+		})
+	}
+}
+
+func TestDecoder_Token_UseNumber(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[12345678901234567890, 0.1]`), UseNumber())
+	var tokens []Token
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tokens = append(tokens, tok)
+	}
+	expected := []Token{Delim('['), parser.Number("12345678901234567890"), parser.Number("0.1"), Delim(']')}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("expected %v, got %v", expected, tokens)
+	}
+}
+
+func TestDecoder_TokenErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "missing colon", input: `{"a" 1}`, expected: "expected ':'"},
+		{name: "missing comma", input: `[1 2]`, expected: "expected ',' or ']'"},
+		{name: "non-string key", input: `{1: 2}`, expected: "expected string key"},
+		{name: "trailing comma", input: `[1,]`, expected: "expected value"},
+		{name: "mismatched close", input: `[1}`, expected: "unexpected RIGHT_BRACE"},
+		{name: "unterminated", input: `{"a": 1`, expected: "unexpected end of input"},
+		{name: "invalid character", input: `[@]`, expected: "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(strings.NewReader(tt.input))
+			var err error
+			for err == nil {
+				_, err = dec.Token()
+			}
+			if err == io.EOF || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	t.Run("elements of a large array", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"count": 2, "users": [{"name": "a", "age": 1}, {"name": "b", "age": 2}]}`))
+		var users []user
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tok == "users" {
+				break
+			}
+		}
+		if tok, err := dec.Token(); err != nil || tok != Delim('[') {
+			t.Fatalf("expected '[', got %v, %v", tok, err)
+		}
+		for dec.More() {
+			var u user
+			if err := dec.Decode(&u); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			users = append(users, u)
+		}
+		if _, err := dec.Token(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []user{{"a", 1}, {"b", 2}}
+		if !reflect.DeepEqual(users, expected) {
+			t.Errorf("expected %v, got %v", expected, users)
+		}
+		if tok, err := dec.Token(); err != nil || tok != Delim('}') {
+			t.Errorf("expected '}', got %v, %v", tok, err)
+		}
+	})
+
+	t.Run("stream of documents", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader("{\"name\": \"a\"}\n{\"name\": \"b\", \"age\": 3}\n"))
+		var users []user
+		for {
+			var u user
+			err := dec.Decode(&u)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			users = append(users, u)
+		}
+		expected := []user{{"a", 0}, {"b", 3}}
+		if !reflect.DeepEqual(users, expected) {
+			t.Errorf("expected %v, got %v", expected, users)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		var n int
+		if err := NewDecoder(strings.NewReader(`2.6`), WithIntegerRounding()).Decode(&n); err != nil || n != 3 {
+			t.Errorf("expected 3, got %d, %v", n, err)
+		}
+		var fracErr *FractionError
+		if err := NewDecoder(strings.NewReader(`2.6`)).Decode(&n); !errors.As(err, &fracErr) {
+			t.Errorf("expected a *FractionError, got %v", err)
+		}
+	})
+
+	t.Run("at the end of a container", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`[]`))
+		if _, err := dec.Token(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var v any
+		if err := dec.Decode(&v); err == nil {
+			t.Fatal("expected an error")
+		}
+		if tok, err := dec.Token(); err != nil || tok != Delim(']') {
+			t.Errorf("expected the failed Decode to leave ']', got %v, %v", tok, err)
+		}
+	})
+}
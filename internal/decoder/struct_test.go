@@ -0,0 +1,167 @@
+package decoder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Base struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type Named struct {
+	Name string
+}
+
+type hidden struct {
+	Secret string `json:"secret"`
+}
+
+type Model struct {
+	Base
+	*Named
+	hidden
+	Title string `json:"title"`
+	Skip  string `json:"-"`
+	lower string
+}
+
+type Outer struct {
+	Name  string // shallower than Named.Name, so it wins
+	Named Named  `json:"named"`
+}
+
+type Tagged struct {
+	Name string `json:"name"`
+}
+
+type Untagged struct {
+	Name string
+}
+
+type TagBeatsUntagged struct {
+	Tagged
+	Untagged
+}
+
+type Left struct{ Value int }
+type Right struct{ Value int }
+
+type Ambiguous struct {
+	Left
+	Right
+	Other int
+}
+
+type Renamed struct {
+	Base `json:"base"`
+}
+
+type Tagged2 struct {
+	Lower string `json:"title"`
+	Upper string `json:"Title"`
+}
+
+type hiddenPointer struct {
+	Value int
+}
+
+type UnexportedPointer struct {
+	*hiddenPointer
+}
+
+type Record struct {
+	At      time.Time      `json:"at"`
+	Blob    []byte         `json:"blob"`
+	Count   int            `json:"count,string"`
+	Flag    *bool          `json:"flag,string"`
+	ByIndex map[int]string `json:"by_index"`
+}
+
+func TestDecode_Structs(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name     string
+		input    string
+		target   any
+		expected any
+	}{
+		{
+			name:   "embedded fields are promoted",
+			input:  `{"id": 1, "created": "today", "Name": "n", "secret": "s", "title": "t", "Skip": "x", "lower": "y", "unknown": 1}`,
+			target: new(Model),
+			expected: Model{
+				Base:   Base{ID: 1, Created: "today"},
+				Named:  &Named{Name: "n"},
+				hidden: hidden{Secret: "s"},
+				Title:  "t",
+			},
+		},
+		{name: "nil embedded pointer stays nil when unused", input: `{"title": "t"}`, target: new(Model), expected: Model{Title: "t"}},
+		{
+			name:     "shallower field wins",
+			input:    `{"Name": "outer", "named": {"Name": "inner"}}`,
+			target:   new(Outer),
+			expected: Outer{Name: "outer", Named: Named{Name: "inner"}},
+		},
+		{name: "tagged field wins at equal depth", input: `{"name": "x"}`, target: new(TagBeatsUntagged), expected: TagBeatsUntagged{Tagged: Tagged{Name: "x"}}},
+		{name: "ambiguous fields are ignored", input: `{"Value": 1, "Other": 2}`, target: new(Ambiguous), expected: Ambiguous{Other: 2}},
+		{name: "tagged embedded struct is a named field", input: `{"base": {"id": 3}, "id": 4}`, target: new(Renamed), expected: Renamed{Base: Base{ID: 3}}},
+		{name: "case-insensitive match", input: `{"TITLE": "t", "ID": 2}`, target: new(Model), expected: Model{Base: Base{ID: 2}, Title: "t"}},
+		{name: "exact match preferred", input: `{"title": "exact", "Title": "folded"}`, target: new(Tagged2), expected: Tagged2{Lower: "exact", Upper: "folded"}},
+		{
+			name:     "struct values in containers",
+			input:    `{"a": [{"id": 1}], "b": null}`,
+			target:   new(map[string][]*Base),
+			expected: map[string][]*Base{"a": {{ID: 1}}, "b": nil},
+		},
+		{
+			name:   "encoding/json conventions",
+			input:  `{"at": "2024-03-01T12:00:00Z", "blob": "aGk=", "count": "12", "flag": "true", "by_index": {"2": "b", "-1": "a"}}`,
+			target: new(Record),
+			expected: Record{
+				At:      time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+				Blob:    []byte("hi"),
+				Count:   12,
+				Flag:    &yes,
+				ByIndex: map[int]string{-1: "a", 2: "b"},
+			},
+		},
+		{name: "bytes from an array", input: `{"blob": [104, 105]}`, target: new(Record), expected: Record{Blob: []byte("hi")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Decode(mustParse(t, tt.input), tt.target); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := reflect.ValueOf(tt.target).Elem().Interface()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecode_StructErrors(t *testing.T) {
+	err := Decode(mustParse(t, `{"id": "one"}`), new(Model))
+	if err == nil || err.Error() != "cannot decode JSON string into Go value of type int at /id" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := Decode(mustParse(t, `[1]`), new(Base)); err == nil {
+		t.Error("expected an error decoding an array into a struct")
+	}
+
+	if err := Decode(mustParse(t, `{"Value": 1}`), new(UnexportedPointer)); err == nil {
+		t.Error("expected an error setting an embedded pointer to an unexported struct")
+	}
+
+	for _, input := range []string{`{"at": "yesterday"}`, `{"blob": "%%"}`, `{"count": 12}`, `{"by_index": {"one": "a"}}`} {
+		if err := Decode(mustParse(t, input), new(Record)); err == nil {
+			t.Errorf("expected an error decoding %s", input)
+		}
+	}
+}
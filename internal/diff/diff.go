@@ -0,0 +1,196 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// ChangeType describes how a value differs between two documents.
+type ChangeType int
+
+const (
+	Added    ChangeType = iota // present only in the second document
+	Removed                    // present only in the first document
+	Modified                   // present in both with different values
+)
+
+// String returns a human-readable representation of the change type.
+func (ct ChangeType) String() string {
+	switch ct {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a single difference between two documents.
+type Change struct {
+	Type ChangeType
+	Path string           // JSON Pointer (RFC 6901) to the value, "" for the root
+	Old  parser.JSONValue // value in the first document, unset for Added
+	New  parser.JSONValue // value in the second document, unset for Removed
+}
+
+// String renders the change on a single line, e.g. `~ /name: "a" -> "b"`.
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", path, render(c.New))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", path, render(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", path, render(c.Old), render(c.New))
+	}
+}
+
+// Object returns the change as a JSON object for machine-readable output,
+// e.g. {"type": "changed", "path": "/name", "old": "a", "new": "b"}; old is
+// left out for added values and new for removed ones.
+func (c Change) Object() parser.JSONObject {
+	obj := parser.JSONObject{"type": c.Type.String(), "path": c.Path}
+	if c.Type != Added {
+		obj["old"] = c.Old
+	}
+	if c.Type != Removed {
+		obj["new"] = c.New
+	}
+	return obj
+}
+
+// Compare returns the differences between a and b, ordered by path. Values
+// are compared semantically: object key order is irrelevant and numbers are
+// compared by value, so 1 and 1.0 are equal. Arrays are compared by index.
+func Compare(a, b parser.JSONValue) []Change {
+	var changes []Change
+	compare("", a, b, &changes)
+	return changes
+}
+
+// Equal reports whether a and b are semantically equal.
+func Equal(a, b parser.JSONValue) bool {
+	return len(Compare(a, b)) == 0
+}
+
+// compare appends the differences between a and b at path to changes.
+func compare(path string, a, b parser.JSONValue, changes *[]Change) {
+	objA, aIsObject := parser.AsObject(a)
+	objB, bIsObject := parser.AsObject(b)
+	if aIsObject && bIsObject {
+		compareObjects(path, objA, objB, changes)
+		return
+	}
+
+	arrA, aIsArray := a.([]any)
+	arrB, bIsArray := b.([]any)
+	if aIsArray && bIsArray {
+		compareArrays(path, arrA, arrB, changes)
+		return
+	}
+
+	if !scalarsEqual(a, b) {
+		*changes = append(*changes, Change{Type: Modified, Path: path, Old: a, New: b})
+	}
+}
+
+// compareObjects compares the members of two objects in key order.
+func compareObjects(path string, a, b map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + pointer.Escape(key)
+		valueA, inA := a[key]
+		valueB, inB := b[key]
+
+		switch {
+		case !inB:
+			*changes = append(*changes, Change{Type: Removed, Path: childPath, Old: valueA})
+		case !inA:
+			*changes = append(*changes, Change{Type: Added, Path: childPath, New: valueB})
+		default:
+			compare(childPath, valueA, valueB, changes)
+		}
+	}
+}
+
+// compareArrays compares two arrays element by element.
+func compareArrays(path string, a, b []any, changes *[]Change) {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		childPath := path + "/" + strconv.Itoa(i)
+
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Type: Removed, Path: childPath, Old: a[i]})
+		case i >= len(a):
+			*changes = append(*changes, Change{Type: Added, Path: childPath, New: b[i]})
+		default:
+			compare(childPath, a[i], b[i], changes)
+		}
+	}
+}
+
+// scalarsEqual compares two values that are not both objects or both arrays.
+func scalarsEqual(a, b parser.JSONValue) bool {
+	numA, aIsNumber := asFloat(a)
+	numB, bIsNumber := asFloat(b)
+	if aIsNumber && bIsNumber {
+		intA, aIsInt := a.(int64)
+		intB, bIsInt := b.(int64)
+		if aIsInt && bIsInt {
+			return intA == intB
+		}
+		return numA == numB
+	}
+
+	switch a.(type) {
+	case nil, bool, string:
+		return a == b
+	default:
+		// Mismatched containers, e.g. an object compared to an array.
+		return false
+	}
+}
+
+// asFloat converts numeric values to float64.
+func asFloat(v parser.JSONValue) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// render formats a value compactly for display.
+func render(v parser.JSONValue) string {
+	encoded, err := encoder.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(encoded)
+}
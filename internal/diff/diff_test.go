@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []string
+	}{
+		{name: "identical", a: `{"a": [1, 2]}`, b: `{"a": [1, 2]}`},
+		{name: "key order and whitespace", a: `{"a":1,"b":2}`, b: "{\n  \"b\": 2,\n  \"a\": 1\n}"},
+		{name: "integer and float", a: `[1, 2.5]`, b: `[1.0, 2.5]`},
+		{name: "empty containers", a: `{"a": {}, "b": []}`, b: `{"b": [], "a": {}}`},
+		{name: "changed scalar", a: `{"name": "a"}`, b: `{"name": "b"}`, expected: []string{`~ /name: "a" -> "b"`}},
+		{name: "added key", a: `{}`, b: `{"x": true}`, expected: []string{`+ /x: true`}},
+		{name: "removed key", a: `{"x": null}`, b: `{}`, expected: []string{`- /x: null`}},
+		{
+			name:     "array length",
+			a:        `[1, 2, 3]`,
+			b:        `[1, 4]`,
+			expected: []string{`~ /1: 2 -> 4`, `- /2: 3`},
+		},
+		{name: "type change", a: `{"a": [1]}`, b: `{"a": {"0": 1}}`, expected: []string{`~ /a: [1] -> {"0":1}`}},
+		{name: "root", a: `1`, b: `"1"`, expected: []string{`~ (root): 1 -> "1"`}},
+		{
+			name:     "escaped pointer",
+			a:        `{"a/b": {"c~d": 1}}`,
+			b:        `{"a/b": {"c~d": 2}}`,
+			expected: []string{`~ /a~1b/c~0d: 1 -> 2`},
+		},
+		{
+			name:     "sorted by key",
+			a:        `{"b": 1, "a": 1}`,
+			b:        `{"c": 1}`,
+			expected: []string{`- /a: 1`, `- /b: 1`, `+ /c: 1`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, change := range Compare(mustParse(t, tt.a), mustParse(t, tt.b)) {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCompare_ChangeFields(t *testing.T) {
+	changes := Compare(mustParse(t, `{"a": 1}`), mustParse(t, `{"a": 2, "b": "x"}`))
+	expected := []Change{
+		{Type: Modified, Path: "/a", Old: int64(1), New: int64(2)},
+		{Type: Added, Path: "/b", New: "x"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal(mustParse(t, `{"a": [1, {"b": null}]}`), mustParse(t, `{"a": [1.0, {"b": null}]}`)) {
+		t.Error("expected documents to be equal")
+	}
+	if Equal(mustParse(t, `[1, 2]`), mustParse(t, `[2, 1]`)) {
+		t.Error("expected arrays in different order to differ")
+	}
+}
+
+func TestChange_Object(t *testing.T) {
+	tests := []struct {
+		change   Change
+		expected parser.JSONObject
+	}{
+		{Change{Type: Added, Path: "/a", New: int64(1)}, parser.JSONObject{"type": "added", "path": "/a", "new": int64(1)}},
+		{Change{Type: Removed, Path: "/b", Old: nil}, parser.JSONObject{"type": "removed", "path": "/b", "old": nil}},
+		{Change{Type: Modified, Path: "", Old: "x", New: []any{}}, parser.JSONObject{"type": "changed", "path": "", "old": "x", "new": []any{}}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.change.Object(); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("expected %v, got %v", tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package encoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Canonical returns the JSON Canonicalization Scheme (RFC 8785) encoding of a
+// parsed value: no insignificant whitespace, object keys sorted by their
+// UTF-16 code units, and numbers serialized the way ECMAScript does. Two
+// documents that differ only in formatting or key order produce identical
+// bytes, which makes the output suitable for hashing and signing.
+func Canonical(v parser.JSONValue, opts ...Option) ([]byte, error) {
+	return marshal(v, layout{canonical: true}, opts)
+}
+
+// Hash returns the SHA-256 digest of the canonical encoding of v, a stable
+// content digest: documents that differ only in formatting or key order, or
+// in how numbers are written (1.0 and 1), hash the same.
+func Hash(v parser.JSONValue, opts ...Option) ([32]byte, error) {
+	canonical, err := Canonical(v, opts...)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(canonical), nil
+}
+
+// canonicalKeys returns the keys of obj ordered by UTF-16 code units as
+// required by RFC 8785, which differs from byte order for characters outside
+// the Basic Multilingual Plane.
+func canonicalKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareCanonical)
+	return keys
+}
+
+// compareCanonical compares two keys by their UTF-16 code units.
+func compareCanonical(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// writeCanonicalNumber writes f using the ECMAScript Number-to-String
+// algorithm that RFC 8785 mandates. Integers are treated as IEEE 754 doubles
+// too, so values beyond 2^53 are rounded exactly as a JavaScript consumer would.
+func (e *encodeState) writeCanonicalNumber(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("unsupported float value %v", f)
+	}
+	if f == 0 {
+		// Covers negative zero, which ECMAScript prints as "0".
+		e.buf.WriteByte('0')
+		return nil
+	}
+
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		e.scratch = strconv.AppendFloat(e.scratch[:0], f, 'f', -1, 64)
+		e.buf.Write(e.scratch)
+		return nil
+	}
+
+	// Go pads exponents to two digits ("1e-07") while ECMAScript doesn't ("1e-7").
+	e.scratch = strconv.AppendFloat(e.scratch[:0], f, 'e', -1, 64)
+	mantissa, exponent, _ := bytes.Cut(e.scratch, []byte{'e'})
+	sign := exponent[0]
+	digits := bytes.TrimLeft(exponent[1:], "0")
+
+	e.buf.Write(mantissa)
+	e.buf.WriteByte('e')
+	e.buf.WriteByte(sign)
+	e.buf.Write(digits)
+	return nil
+}
@@ -0,0 +1,124 @@
+package encoder
+
+import (
+	"crypto/sha256"
+	"math"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestCanonical_Numbers(t *testing.T) {
+	tests := []struct {
+		value    parser.JSONValue
+		expected string
+	}{
+		{value: 0.0, expected: "0"},
+		{value: math.Copysign(0, -1), expected: "0"},
+		{value: 4.50, expected: "4.5"},
+		{value: 2e-3, expected: "0.002"},
+		{value: 1e-6, expected: "0.000001"},
+		{value: 1e-7, expected: "1e-7"},
+		{value: 1e21, expected: "1e+21"},
+		{value: 1e30, expected: "1e+30"},
+		{value: -1.5e-10, expected: "-1.5e-10"},
+		{value: 333333333.3333333, expected: "333333333.3333333"},
+		{value: int64(100), expected: "100"},
+		{value: int64(9007199254740993), expected: "9007199254740992"},
+		{value: parser.Number("1.50e1"), expected: "15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			output, err := Canonical(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestCanonical_KeyOrder(t *testing.T) {
+	// U+FB33 sorts before U+1F600 in UTF-8 byte order but after it in UTF-16
+	// code unit order, which is what RFC 8785 requires.
+	value := parser.JSONObject{
+		"\U0001F600": int64(1),
+		"\uFB33":     int64(2),
+		"\u20AC":     int64(3),
+		"a":          int64(4),
+	}
+
+	output, err := Canonical(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "{\"a\":4,\"\u20AC\":3,\"\U0001F600\":1,\"\uFB33\":2}"
+	if string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+}
+
+func TestCanonical_FormattingIndependent(t *testing.T) {
+	a := "{\n  \"b\": [1, 2.50, {\"y\": null, \"x\": true}],\n  \"a\": \"text\"\n}"
+	b := `{"a":"text","b":[1,2.5,{"x":true,"y":null}]}`
+
+	canonicalA := mustCanonical(t, a)
+	canonicalB := mustCanonical(t, b)
+
+	if canonicalA != canonicalB {
+		t.Errorf("expected identical canonical forms, got %s and %s", canonicalA, canonicalB)
+	}
+	if canonicalB != b {
+		t.Errorf("expected %s, got %s", b, canonicalB)
+	}
+}
+
+func TestHash(t *testing.T) {
+	hash := func(input string) [32]byte {
+		t.Helper()
+		value, err := parser.New(lexer.New(input)).Parse()
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", input, err)
+		}
+		digest, err := Hash(value)
+		if err != nil {
+			t.Fatalf("failed to hash %s: %v", input, err)
+		}
+		return digest
+	}
+
+	digest := hash(`{"a":1,"b":[true,null]}`)
+	if expected := sha256.Sum256([]byte(`{"a":1,"b":[true,null]}`)); digest != expected {
+		t.Errorf("expected the digest of the canonical encoding, got %x", digest)
+	}
+	if other := hash("{\n  \"b\": [ true, null ],\n  \"a\": 1.0\n}"); other != digest {
+		t.Errorf("formatting and key order changed the digest: %x vs %x", digest, other)
+	}
+	if other := hash(`{"a":2,"b":[true,null]}`); other == digest {
+		t.Error("a changed value kept the digest")
+	}
+
+	if _, err := Hash(math.NaN()); err == nil {
+		t.Error("expected an error for NaN")
+	}
+}
+
+func mustCanonical(t *testing.T, input string) string {
+	t.Helper()
+
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", input, err)
+	}
+
+	output, err := Canonical(value)
+	if err != nil {
+		t.Fatalf("failed to canonicalize %s: %v", input, err)
+	}
+	return string(output)
+}
@@ -0,0 +1,80 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// CycleError reports a value that contains itself, e.g. a map stored in one
+// of its own members. Encoding it would never terminate.
+type CycleError struct {
+	Path string // JSON Pointer (RFC 6901) to the member that closes the cycle
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("cycle detected at path %s", path)
+}
+
+// visitKey identifies a map, slice or pointer currently being encoded. Slices
+// include their length, since a slice and a shorter slice of the same array
+// are different values; pointers include their type, since a struct and its
+// first field share an address.
+type visitKey struct {
+	ptr unsafe.Pointer
+	len int
+	typ reflect.Type
+}
+
+// mapKey returns the visit key of a map.
+func mapKey(m reflect.Value) visitKey {
+	return visitKey{ptr: m.UnsafePointer()}
+}
+
+// sliceKey returns the visit key of a non-empty slice.
+func sliceKey(s reflect.Value) visitKey {
+	return visitKey{ptr: s.UnsafePointer(), len: s.Len()}
+}
+
+// pointerKey returns the visit key of a non-nil pointer.
+func pointerKey(p reflect.Value) visitKey {
+	return visitKey{ptr: p.UnsafePointer(), typ: p.Type()}
+}
+
+// enter marks key as being encoded, failing if it already is, which means the
+// value contains itself. Every successful enter must be paired with leave.
+func (e *encodeState) enter(key visitKey) error {
+	if _, ok := e.visiting[key]; ok {
+		return &CycleError{}
+	}
+	if e.visiting == nil {
+		e.visiting = make(map[visitKey]struct{})
+	}
+	e.visiting[key] = struct{}{}
+	return nil
+}
+
+// leave marks key as no longer being encoded. Values may be shared between
+// siblings (a DAG); only revisiting an ancestor is a cycle.
+func (e *encodeState) leave(key visitKey) {
+	delete(e.visiting, key)
+}
+
+// withPath prefixes the path of a *CycleError with token as the error
+// propagates out of a container, so the final path points at the member that
+// closes the cycle. Other errors are returned unchanged.
+func withPath(err error, token string) error {
+	var cycleErr *CycleError
+	if errors.As(err, &cycleErr) {
+		cycleErr.Path = "/" + pointer.Escape(token) + cycleErr.Path
+	}
+	return err
+}
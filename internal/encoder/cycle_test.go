@@ -0,0 +1,93 @@
+package encoder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Next     *node   `json:"next,omitempty"`
+	Children []*node `json:"children,omitempty"`
+}
+
+func TestMarshal_DetectsCycles(t *testing.T) {
+	selfMap := parser.JSONObject{"a": int64(1)}
+	selfMap["self"] = selfMap
+
+	selfArray := []any{int64(1), nil}
+	selfArray[1] = selfArray
+
+	nested := parser.JSONObject{}
+	nested["items"] = []any{parser.JSONObject{"back": nested}}
+
+	loop := &node{Name: "a"}
+	loop.Next = &node{Name: "b", Next: loop}
+
+	parent := &node{Name: "parent"}
+	parent.Children = []*node{{Name: "child", Children: []*node{parent}}}
+
+	typedMap := map[string]any{}
+	typedMap["x/y"] = []any{typedMap}
+
+	tests := []struct {
+		name  string
+		value any
+		path  string
+	}{
+		{name: "map contains itself", value: selfMap, path: "/self"},
+		{name: "array contains itself", value: selfArray, path: "/1"},
+		{name: "nested", value: nested, path: "/items/0/back"},
+		{name: "pointer loop", value: loop, path: "/next/next"},
+		{name: "through typed slices", value: parent, path: "/children/0/children/0"},
+		{name: "escaped path", value: typedMap, path: "/x~1y/0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, marshal := range map[string]func(parser.JSONValue) ([]byte, error){
+				"Marshal":       func(v parser.JSONValue) ([]byte, error) { return Marshal(v) },
+				"MarshalIndent": func(v parser.JSONValue) ([]byte, error) { return MarshalIndent(v, "", "  ") },
+				"Canonical":     func(v parser.JSONValue) ([]byte, error) { return Canonical(v) },
+			} {
+				_, err := marshal(tt.value)
+				var cycleErr *CycleError
+				if !errors.As(err, &cycleErr) {
+					t.Fatalf("%s: expected a *CycleError, got %v", name, err)
+				}
+				if cycleErr.Path != tt.path {
+					t.Errorf("%s: expected path %q, got %q", name, tt.path, cycleErr.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshal_SharedValuesAreNotCycles(t *testing.T) {
+	shared := parser.JSONObject{"k": "v"}
+	sharedArray := []any{int64(1)}
+	leaf := &node{Name: "leaf"}
+
+	value := []any{shared, shared, sharedArray, sharedArray, parser.JSONObject{"a": shared, "b": shared}}
+	result, err := Marshal(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `[{"k":"v"},{"k":"v"},[1],[1],{"a":{"k":"v"},"b":{"k":"v"}}]`; string(result) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	tree := &node{Name: "root", Children: []*node{leaf, leaf}}
+	if _, err := Marshal(tree); err != nil {
+		t.Errorf("unexpected error for a shared pointer: %v", err)
+	}
+}
+
+func TestCycleError_Message(t *testing.T) {
+	err := &CycleError{Path: "/a/0"}
+	if expected := "cycle detected at path /a/0"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,528 @@
+package encoder
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Marshal returns the compact JSON encoding of v, which is either a parsed
+// value or an arbitrary Go value such as a struct (see encodeValue). Map and
+// parsed object keys are emitted in sorted order so the output is
+// deterministic; struct fields keep their declaration order. Parsing the
+// output of a parsed value yields an equal value.
+func Marshal(v parser.JSONValue, opts ...Option) ([]byte, error) {
+	return marshal(v, layout{}, opts)
+}
+
+// MarshalIndent is like Marshal but places each array element and object
+// member on its own line, starting with prefix and indented by indent per
+// nesting level.
+func MarshalIndent(v parser.JSONValue, prefix, indent string, opts ...Option) ([]byte, error) {
+	return marshal(v, layout{prefix: prefix, indent: indent, pretty: true}, opts)
+}
+
+// marshal encodes v with the given layout. The returned slice is always owned
+// by the caller, even when the encoder state came from the pool.
+func marshal(v parser.JSONValue, l layout, opts []Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	// RFC 8785 requires literal UTF-8, so canonical output ignores escaping.
+	l.escapeUnicode = cfg.escapeUnicode && !l.canonical
+	if l.pretty {
+		l.width = cfg.width
+	}
+	e := newEncodeState(l, cfg.pooling)
+	defer e.release()
+
+	if e.replacer = cfg.replacer; e.replacer != nil {
+		var err error
+		if v, err = e.replace(v); err == errOmitted {
+			v = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if err := e.encode(v, 0); err != nil {
+		return nil, err
+	}
+	if !e.pooled {
+		return e.buf.Bytes(), nil
+	}
+	return bytes.Clone(e.buf.Bytes()), nil
+}
+
+// layout holds the output format settings of an encoding.
+type layout struct {
+	prefix        string
+	indent        string
+	pretty        bool
+	canonical     bool // RFC 8785 key order and number formatting
+	escapeUnicode bool // write non-ASCII characters as \u escapes
+	width         int  // when pretty, keep containers fitting in this many columns on one line
+}
+
+// encodeState holds the output buffer, layout settings and scratch space for
+// a single encoding. States are pooled, so the buffers grown by one encoding
+// are reused by the next.
+type encodeState struct {
+	layout
+	buf      bytes.Buffer
+	scratch  []byte                // number formatting space
+	visiting map[visitKey]struct{} // containers on the current path, for cycle detection
+	pooled   bool                  // return to encodeStatePool when done
+	replacer Replacer
+	path     string // JSON Pointer to the value being encoded, tracked for the replacer
+	line     []byte // single-line form of a container, see fitLine
+	overflow bool   // a container written since the enclosing one opened is wider than the width
+}
+
+// maxPooledBufferSize bounds the buffer capacity kept by pooled states, so a
+// single very large document doesn't pin its memory for the life of the
+// process.
+const maxPooledBufferSize = 64 * 1024
+
+// encodeStatePool holds idle encode states.
+var encodeStatePool sync.Pool
+
+// newEncodeState returns a state configured with l, taken from the pool if
+// pooling is enabled.
+func newEncodeState(l layout, pooling bool) *encodeState {
+	if !pooling {
+		return &encodeState{layout: l}
+	}
+	e, ok := encodeStatePool.Get().(*encodeState)
+	if !ok {
+		e = &encodeState{}
+	}
+	e.layout = l
+	e.pooled = true
+	return e
+}
+
+// release resets a pooled state and returns it to the pool.
+func (e *encodeState) release() {
+	if !e.pooled || e.buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	e.buf.Reset()
+	e.scratch = e.scratch[:0]
+	e.line = e.line[:0]
+	e.overflow = false
+	clear(e.visiting) // not empty if encoding failed midway
+	e.layout = layout{}
+	e.replacer = nil
+	encodeStatePool.Put(e)
+}
+
+// encode writes the JSON representation of v at the given nesting depth.
+func (e *encodeState) encode(v parser.JSONValue, depth int) error {
+	switch val := v.(type) {
+	case nil:
+		e.buf.WriteString("null")
+	case bool:
+		e.buf.WriteString(strconv.FormatBool(val))
+	case string:
+		e.writeString(val)
+	case int64:
+		return e.writeInt(val)
+	case int:
+		return e.writeInt(int64(val))
+	case float64:
+		return e.writeFloat(val, 64)
+	case parser.Number:
+		return e.writeNumber(val)
+	case []any:
+		return e.encodeArray(val, depth)
+	case parser.JSONObject:
+		return e.encodeObject(val, depth)
+	case parser.EmptyObject:
+		return e.encodeObject(val, depth)
+	case map[string]any:
+		return e.encodeObject(val, depth)
+	default:
+		return e.encodeValue(reflect.ValueOf(v), depth)
+	}
+	return nil
+}
+
+// encodeArray writes a JSON array.
+func (e *encodeState) encodeArray(arr []any, depth int) error {
+	if len(arr) == 0 {
+		e.buf.WriteString("[]")
+		return nil
+	}
+
+	key := visitKey{ptr: unsafe.Pointer(unsafe.SliceData(arr)), len: len(arr)}
+	if err := e.enter(key); err != nil {
+		return err
+	}
+	defer e.leave(key)
+
+	c := e.openContainer('[')
+	written := 0
+	for i, elem := range arr {
+		mark := e.buf.Len()
+		if written > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		err := e.encodeElement(i, elem, depth+1)
+		if err == errOmitted {
+			e.buf.Truncate(mark)
+			continue
+		}
+		if err != nil {
+			return withPath(err, strconv.Itoa(i))
+		}
+		written++
+	}
+	e.closeContainer(c, ']', written, depth)
+	return nil
+}
+
+// encodeObject writes a JSON object with its keys in sorted order.
+func (e *encodeState) encodeObject(obj map[string]any, depth int) error {
+	if len(obj) == 0 {
+		e.buf.WriteString("{}")
+		return nil
+	}
+
+	visit := mapKey(reflect.ValueOf(obj))
+	if err := e.enter(visit); err != nil {
+		return err
+	}
+	defer e.leave(visit)
+
+	keys := sortedKeys(obj)
+	if e.canonical {
+		keys = canonicalKeys(obj)
+	}
+
+	c := e.openContainer('{')
+	written := 0
+	for _, key := range keys {
+		mark := e.buf.Len()
+		if written > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		e.writeString(key)
+		e.buf.WriteByte(':')
+		if e.pretty {
+			e.buf.WriteByte(' ')
+		}
+		err := e.encodeMember(key, obj[key], depth+1)
+		if err == errOmitted {
+			e.buf.Truncate(mark)
+			continue
+		}
+		if err != nil {
+			return withPath(err, key)
+		}
+		written++
+	}
+	e.closeContainer(c, '}', written, depth)
+	return nil
+}
+
+// encodeElement writes element i of the array being encoded, passing it
+// through the replacer if there is one.
+func (e *encodeState) encodeElement(i int, v parser.JSONValue, depth int) error {
+	if e.replacer == nil {
+		return e.encode(v, depth)
+	}
+	return e.encodeReplaced(strconv.Itoa(i), v, depth)
+}
+
+// encodeMember writes the member key of the object being encoded, passing it
+// through the replacer if there is one.
+func (e *encodeState) encodeMember(key string, v parser.JSONValue, depth int) error {
+	if e.replacer == nil {
+		return e.encode(v, depth)
+	}
+	return e.encodeReplaced(key, v, depth)
+}
+
+// container records where a container being written started.
+type container struct {
+	start    int  // offset of the opening bracket
+	overflow bool // e.overflow when it was opened
+}
+
+// openContainer writes the opening bracket of a container.
+func (e *encodeState) openContainer(bracket byte) container {
+	c := container{start: e.buf.Len(), overflow: e.overflow}
+	e.overflow = false
+	e.buf.WriteByte(bracket)
+	return c
+}
+
+// closeContainer writes the closing bracket of container c with the given
+// number of written members, which is zero if the replacer omitted them all.
+func (e *encodeState) closeContainer(c container, bracket byte, written, depth int) {
+	if written > 0 {
+		e.newline(depth)
+	}
+	e.buf.WriteByte(bracket)
+	if e.width > 0 {
+		e.fitLine(c.start)
+	}
+	e.overflow = e.overflow || c.overflow
+}
+
+// fitLine rewrites the container just written from start on a single line,
+// e.g. `{"a": [1, 2]}`, if the line it is on then fits within the width. If
+// the container is wider than the width by itself, it sets e.overflow so that
+// enclosing containers don't try again.
+func (e *encodeState) fitLine(start int) {
+	if e.overflow {
+		return // a nested container didn't fit
+	}
+	var ok bool
+	if e.line, ok = e.singleLine(e.line[:0], e.buf.Bytes()[start:]); !ok {
+		e.overflow = true
+		return
+	}
+	lineStart := bytes.LastIndexByte(e.buf.Bytes()[:start], '\n') + 1
+	if utf8.RuneCount(e.buf.Bytes()[lineStart:start])+utf8.RuneCount(e.line) > e.width {
+		return
+	}
+	e.buf.Truncate(start)
+	e.buf.Write(e.line)
+}
+
+// singleLine appends the indented container text to dst with the line breaks
+// and indentation removed, leaving a space after commas. It reports false,
+// stopping early, if the result is wider than the width.
+func (e *encodeState) singleLine(dst, text []byte) ([]byte, bool) {
+	columns := 0
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '\n':
+			// Strings can't contain raw line breaks, so this one is layout.
+			rest := bytes.TrimPrefix(text[i+1:], []byte(e.prefix))
+			for e.indent != "" && bytes.HasPrefix(rest, []byte(e.indent)) {
+				rest = rest[len(e.indent):]
+			}
+			i = len(text) - len(rest) - 1
+			if dst[len(dst)-1] != ',' {
+				continue
+			}
+			c = ' '
+		}
+		dst = append(dst, c)
+		if utf8.RuneStart(c) {
+			columns++
+		}
+		if columns > e.width {
+			return dst, false
+		}
+	}
+	return dst, true
+}
+
+// newline starts a new indented line when pretty printing.
+func (e *encodeState) newline(depth int) {
+	if !e.pretty {
+		return
+	}
+	e.buf.WriteByte('\n')
+	e.buf.WriteString(e.prefix)
+	for i := 0; i < depth; i++ {
+		e.buf.WriteString(e.indent)
+	}
+}
+
+// sortedKeys returns the keys of obj in ascending byte order.
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeInt writes an integer, as a double in canonical mode.
+func (e *encodeState) writeInt(n int64) error {
+	if e.canonical {
+		return e.writeCanonicalNumber(float64(n))
+	}
+	e.scratch = strconv.AppendInt(e.scratch[:0], n, 10)
+	e.buf.Write(e.scratch)
+	return nil
+}
+
+// writeUint writes an unsigned integer, as a double in canonical mode.
+func (e *encodeState) writeUint(n uint64) error {
+	if e.canonical {
+		return e.writeCanonicalNumber(float64(n))
+	}
+	e.scratch = strconv.AppendUint(e.scratch[:0], n, 10)
+	e.buf.Write(e.scratch)
+	return nil
+}
+
+// writeFloat writes f using the shortest representation that round-trips at
+// the given bit size (32 or 64), switching to exponent notation for very
+// large or small values.
+func (e *encodeState) writeFloat(f float64, bits int) error {
+	if e.canonical {
+		return e.writeCanonicalNumber(f)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("unsupported float value %v", f)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	e.scratch = strconv.AppendFloat(e.scratch[:0], f, format, -1, bits)
+	e.buf.Write(e.scratch)
+	return nil
+}
+
+// writeNumber writes the literal text of a parsed number verbatim, or as a
+// double in canonical mode.
+func (e *encodeState) writeNumber(n parser.Number) error {
+	if !validNumber(string(n)) {
+		return fmt.Errorf("invalid number literal %q", string(n))
+	}
+	if e.canonical {
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("unsupported number %s", n)
+		}
+		return e.writeCanonicalNumber(f)
+	}
+	e.buf.WriteString(string(n))
+	return nil
+}
+
+// validNumber reports whether s follows the JSON number grammar.
+func validNumber(s string) bool {
+	i := 0
+	digits := func() bool {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i > start
+	}
+
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	if i < len(s) && s[i] == '0' {
+		i++
+	} else if !digits() {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if !digits() {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if !digits() {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeString writes s as a quoted JSON string, escaping only what the
+// grammar requires so non-ASCII text stays readable, unless escapeUnicode
+// asks for pure ASCII output.
+func (e *encodeState) writeString(s string) {
+	buf := &e.buf
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			switch {
+			case e.escapeUnicode:
+				e.writeUnicodeEscape(r)
+			case r == utf8.RuneError && size == 1:
+				buf.WriteString("\ufffd")
+			default:
+				buf.WriteString(s[i : i+size])
+			}
+			i += size
+			continue
+		}
+
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// writeUnicodeEscape writes r as a \uXXXX escape, or as a UTF-16 surrogate
+// pair for characters outside the Basic Multilingual Plane. Invalid UTF-8
+// decodes to utf8.RuneError and is written as \ufffd.
+func (e *encodeState) writeUnicodeEscape(r rune) {
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		e.writeUnicodeEscape(r1)
+		e.writeUnicodeEscape(r2)
+		return
+	}
+	e.buf.WriteString(`\u`)
+	for shift := 12; shift >= 0; shift -= 4 {
+		e.buf.WriteByte(hexDigits[r>>shift&0xf])
+	}
+}
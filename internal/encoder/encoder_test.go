@@ -0,0 +1,289 @@
+package encoder
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    parser.JSONValue
+		expected string
+	}{
+		{name: "null", value: nil, expected: `null`},
+		{name: "true", value: true, expected: `true`},
+		{name: "integer", value: int64(-42), expected: `-42`},
+		{name: "float", value: 3.25, expected: `3.25`},
+		{name: "large float", value: 1e21, expected: `1e+21`},
+		{name: "small float", value: 1e-7, expected: `1e-07`},
+		{name: "raw number", value: []any{parser.Number("1e3"), parser.Number("-1.50")}, expected: `[1e3,-1.50]`},
+		{name: "raw number field", value: struct{ N parser.Number }{N: "0.10"}, expected: `{"N":0.10}`},
+		{name: "big integer", value: []any{new(big.Int).Lsh(big.NewInt(1), 100)}, expected: `[1267650600228229401496703205376]`},
+		{name: "big float", value: big.NewFloat(1.5), expected: `1.5`},
+		{name: "big integer field", value: struct{ N, M *big.Int }{N: big.NewInt(-7)}, expected: `{"N":-7,"M":null}`},
+		{name: "string with escapes", value: "a\"b\\c\n\t\x01", expected: `"a\"b\\c\n\t\u0001"`},
+		{name: "unicode string", value: "héllo 世界", expected: `"héllo 世界"`},
+		{name: "empty array", value: []any{}, expected: `[]`},
+		{name: "empty object", value: parser.NewJSONObject(), expected: `{}`},
+		{
+			name:     "object keys sorted",
+			value:    parser.JSONObject{"b": int64(1), "a": []any{true, nil}, "c": "x"},
+			expected: `{"a":[true,null],"b":1,"c":"x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value parser.JSONValue
+	}{
+		{name: "NaN", value: math.NaN()},
+		{name: "infinity", value: math.Inf(1)},
+		{name: "unsupported type", value: complex(1, 2)},
+		{name: "unsupported map key", value: map[float64]int{1: 1}},
+		{name: "nested unsupported type", value: []any{make(chan int)}},
+		{name: "empty number", value: parser.Number("")},
+		{name: "number without fraction digits", value: parser.Number("1.")},
+		{name: "number with plus sign", value: parser.Number("+1")},
+		{name: "number with leading zero", value: parser.Number("01")},
+		{name: "hex number", value: parser.Number("0x1F")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Marshal(tt.value); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestMarshalIndent(t *testing.T) {
+	value := parser.JSONObject{
+		"name":  "test",
+		"items": []any{int64(1), int64(2)},
+		"empty": []any{},
+	}
+
+	expected := "{\n  \"empty\": [],\n  \"items\": [\n    1,\n    2\n  ],\n  \"name\": \"test\"\n}"
+
+	output, err := MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(output) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	inputs := []string{
+		`{"a":1,"b":[1,2.5,"three",true,false,null],"c":{"d":{}}}`,
+		`[[],[[]],{"nested":[{"x":-1e-10}]}]`,
+		`"line\nbreak \"quoted\" é"`,
+		`[9223372036854775807,-9223372036854775808,0.1,1e300,5e-324,123456789.123456789]`,
+		`{"\u0000\u001f\u2028":"\ud83d\ude00","":[""]}`,
+	}
+	encodings := []struct {
+		name    string
+		marshal func(parser.JSONValue) ([]byte, error)
+	}{
+		{"compact", func(v parser.JSONValue) ([]byte, error) { return Marshal(v) }},
+		{"indented", func(v parser.JSONValue) ([]byte, error) { return MarshalIndent(v, "", "  ") }},
+	}
+
+	for _, input := range inputs {
+		for _, enc := range encodings {
+			t.Run(enc.name+" "+input, func(t *testing.T) {
+				first, err := parser.New(lexer.New(input)).Parse()
+				if err != nil {
+					t.Fatalf("failed to parse input: %v", err)
+				}
+
+				encoded, err := enc.marshal(first)
+				if err != nil {
+					t.Fatalf("failed to marshal: %v", err)
+				}
+
+				second, err := parser.New(lexer.New(string(encoded))).Parse()
+				if err != nil {
+					t.Fatalf("failed to parse encoded output %s: %v", encoded, err)
+				}
+				if !reflect.DeepEqual(first, second) {
+					t.Errorf("parsed values differ: %#v vs %#v", first, second)
+				}
+
+				reencoded, err := enc.marshal(second)
+				if err != nil {
+					t.Fatalf("failed to marshal again: %v", err)
+				}
+				if string(encoded) != string(reencoded) {
+					t.Errorf("round trip mismatch: %s vs %s", encoded, reencoded)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshal_BigNumbers(t *testing.T) {
+	input := `[340282366920938463463374607431768211455,3.141592653589793238462643383279502884197,1e+400,42]`
+	value, err := parser.New(lexer.New(input), parser.WithBigNumbers()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	output, err := Marshal(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(output) != input {
+		t.Errorf("expected %s, got %s", input, output)
+	}
+}
+
+func TestMarshal_WithEscapedUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    parser.JSONValue
+		opts     []Option
+		expected string
+	}{
+		{name: "literal by default", value: "caf\u00e9 \U0001F600", expected: "\"caf\u00e9 \U0001F600\""},
+		{name: "escaped", value: "caf\u00e9", opts: []Option{WithEscapedUnicode()}, expected: `"caf\u00e9"`},
+		{name: "surrogate pair", value: "\U0001F600", opts: []Option{WithEscapedUnicode()}, expected: `"\ud83d\ude00"`},
+		{name: "invalid UTF-8", value: "a\xffb", opts: []Option{WithEscapedUnicode()}, expected: `"a\ufffdb"`},
+		{name: "keys", value: parser.JSONObject{"\u00f1": "\u2028"}, opts: []Option{WithEscapedUnicode()}, expected: `{"\u00f1":"\u2028"}`},
+		{name: "ASCII untouched", value: "tab\there", opts: []Option{WithEscapedUnicode()}, expected: `"tab\there"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Marshal(tt.value, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("canonical stays literal", func(t *testing.T) {
+		result, err := Canonical("caf\u00e9", WithEscapedUnicode())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(result) != "\"caf\u00e9\"" {
+			t.Errorf("expected literal UTF-8, got %s", result)
+		}
+	})
+}
+
+func TestMarshalIndent_WithWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		prefix   string
+		width    int
+		expected string
+	}{
+		{name: "fits", input: `{"a": [1, 2], "b": "x"}`, width: 40, expected: `{"a": [1, 2], "b": "x"}`},
+		{
+			name:     "mixed sizes",
+			input:    `{"tags": ["a", "b"], "point": {"x": 1, "y": 2}, "text": "a long string that does not fit"}`,
+			width:    30,
+			expected: "{\n  \"point\": {\"x\": 1, \"y\": 2},\n  \"tags\": [\"a\", \"b\"],\n  \"text\": \"a long string that does not fit\"\n}",
+		},
+		{
+			name:     "exact width",
+			input:    `{"key": [1, 2, 3]}`,
+			width:    18,
+			expected: `{"key": [1, 2, 3]}`,
+		},
+		{
+			name:     "one column short",
+			input:    `{"key": [1, 2, 3]}`,
+			width:    17,
+			expected: "{\n  \"key\": [\n    1,\n    2,\n    3\n  ]\n}",
+		},
+		{
+			name:     "counts the indentation",
+			input:    `{"a": {"bb": [1, 2]}}`,
+			width:    16,
+			expected: "{\n  \"a\": {\n    \"bb\": [1, 2]\n  }\n}",
+		},
+		{
+			name:     "wide child keeps parents indented",
+			input:    `[[1, 2, 3, 4, 5, 6, 7, 8, 9, 10], [1]]`,
+			width:    20,
+			expected: "[\n  [\n    1,\n    2,\n    3,\n    4,\n    5,\n    6,\n    7,\n    8,\n    9,\n    10\n  ],\n  [1]\n]",
+		},
+		{name: "strings with layout characters", input: `["a,\n b", "{"]`, width: 20, expected: `["a,\n b", "{"]`},
+		{name: "counts runes", input: "[\"\u00e9\u00e9\u00e9\"]", width: 7, expected: "[\"\u00e9\u00e9\u00e9\"]"},
+		{name: "prefix", input: `[1, 2]`, prefix: "// ", width: 9, expected: "[1, 2]"},
+		{
+			name:     "prefix counted on inner lines",
+			input:    `[[1, 2], 3, 4]`,
+			prefix:   "// ",
+			width:    11,
+			expected: "[\n//   [1, 2],\n//   3,\n//   4\n// ]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parser.New(lexer.New(tt.input)).Parse()
+			if err != nil {
+				t.Fatalf("failed to parse input: %v", err)
+			}
+			output, err := MarshalIndent(value, tt.prefix, "  ", WithWidth(tt.width))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, output)
+			}
+		})
+	}
+
+	t.Run("structs", func(t *testing.T) {
+		type point struct {
+			X, Y int
+		}
+		output, err := MarshalIndent(map[string]any{"p": point{1, 2}, "list": []int{1}}, "", "  ", WithWidth(80))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := `{"list": [1], "p": {"X": 1, "Y": 2}}`; string(output) != expected {
+			t.Errorf("expected %s, got %s", expected, output)
+		}
+	})
+
+	t.Run("compact ignores width", func(t *testing.T) {
+		output, err := Marshal([]any{int64(1), int64(2)}, WithWidth(80))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(output) != "[1,2]" {
+			t.Errorf("expected [1,2], got %s", output)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package encoder
+
+// config holds settings that control how values are encoded.
+type config struct {
+	pooling       bool
+	escapeUnicode bool
+	replacer      Replacer
+	width         int
+}
+
+// Option configures encoding.
+type Option func(*config)
+
+// newConfig returns the default configuration with opts applied.
+func newConfig(opts []Option) config {
+	cfg := config{pooling: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithoutPooling makes the encoding allocate fresh buffers instead of reusing
+// pooled ones. Pooling saves allocations for services that encode many
+// documents, but costs a copy of the output; callers encoding one large
+// document, or measuring allocations, may prefer to skip it.
+func WithoutPooling() Option {
+	return func(c *config) {
+		c.pooling = false
+	}
+}
+
+// WithEscapedUnicode writes every non-ASCII character as a \u escape (using
+// surrogate pairs beyond U+FFFF) instead of literal UTF-8, for consumers that
+// mangle non-ASCII bytes or teams standardizing on ASCII-only files. By
+// default non-ASCII text is written literally. Since parsing decodes escapes,
+// either way re-encoding a document gives all its strings one consistent
+// representation. Canonical ignores this option, as RFC 8785 requires literal
+// UTF-8.
+func WithEscapedUnicode() Option {
+	return func(c *config) {
+		c.escapeUnicode = true
+	}
+}
+
+// WithWidth makes MarshalIndent keep arrays and objects on a single line, as
+// in `"tags": ["a", "b"]`, when that line fits within width columns (counted
+// in runes, including the prefix and indentation), like code formatters do.
+// Containers that don't fit are indented as usual, so data mixing tiny and
+// large structures stays readable. Marshal ignores this option.
+func WithWidth(width int) Option {
+	return func(c *config) {
+		c.width = width
+	}
+}
@@ -0,0 +1,109 @@
+package encoder
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestMarshal_PooledOutputIsOwnedByCaller(t *testing.T) {
+	first, err := Marshal(parser.JSONObject{"a": "first"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Marshal(parser.JSONObject{"b": "second, longer value"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(first) != `{"a":"first"}` {
+		t.Errorf("earlier output was overwritten by a later encoding: %s", first)
+	}
+}
+
+func TestMarshal_WithoutPooling(t *testing.T) {
+	value := parser.JSONObject{"n": []any{int64(1), 2.5, "x", nil, true}}
+	pooled, err := MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unpooled, err := MarshalIndent(value, "", "  ", WithoutPooling())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(pooled) != string(unpooled) {
+		t.Errorf("pooling changed the output: %s vs %s", pooled, unpooled)
+	}
+}
+
+func TestMarshal_PoolRecoversAfterErrors(t *testing.T) {
+	cyclic := parser.JSONObject{}
+	cyclic["self"] = cyclic
+	for range 3 {
+		if _, err := Canonical(cyclic); err == nil {
+			t.Fatal("expected a cycle error")
+		}
+		// A state left dirty by the failed encoding must not leak layout,
+		// output or visited containers into the next one.
+		result, err := Marshal(parser.JSONObject{"self": int64(1)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(result) != `{"self":1}` {
+			t.Errorf("unexpected output: %s", result)
+		}
+	}
+}
+
+func TestMarshal_ConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				expected := fmt.Sprintf(`{"i":%d,"j":%d}`, i, j)
+				result, err := Marshal(parser.JSONObject{"i": int64(i), "j": int64(j)})
+				if err != nil || string(result) != expected {
+					errs <- fmt.Errorf("expected %s, got %s (err %v)", expected, result, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func benchmarkValue() parser.JSONValue {
+	items := make([]any, 50)
+	for i := range items {
+		items[i] = parser.JSONObject{"id": int64(i), "name": "item", "price": 9.99, "tags": []any{"a", "b"}}
+	}
+	return parser.JSONObject{"items": items, "total": int64(len(items))}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	value := benchmarkValue()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Marshal(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshal_WithoutPooling(b *testing.B) {
+	value := benchmarkValue()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Marshal(value, WithoutPooling()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package encoder
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/fields"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// numberType is the type of parsed numbers kept as text.
+var numberType = reflect.TypeFor[parser.Number]()
+
+// bigIntType and bigFloatType are the types of the numbers parsed with
+// parser.WithBigNumbers.
+var (
+	bigIntType   = reflect.TypeFor[*big.Int]()
+	bigFloatType = reflect.TypeFor[*big.Float]()
+)
+
+// member is an object member waiting to be written.
+type member struct {
+	key    string
+	value  reflect.Value
+	quoted bool // written as a string, see fields.Field.Quoted
+}
+
+// encodeValue writes Go values that aren't parsed JSON values, such as
+// structs and typed slices or maps, following encoding/json's conventions:
+// struct fields are named by json tags and honor the omitempty, omitzero and
+// string options, encoding.TextMarshaler implementations (e.g. time.Time)
+// become strings, also as map keys, []byte is base64 encoded, and nil
+// pointers, slices and maps are null. *big.Int and *big.Float are numbers rather than strings.
+func (e *encodeState) encodeValue(v reflect.Value, depth int) error {
+	if n, ok := bigNumber(v); ok {
+		return e.writeNumber(n)
+	}
+	if m, ok := textMarshaler(v); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling %s: %w", v.Type(), err)
+		}
+		e.writeString(string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		e.buf.WriteString("null")
+	case reflect.Bool:
+		e.buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.String:
+		if v.Type() == numberType {
+			return e.writeNumber(parser.Number(v.String()))
+		}
+		e.writeString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.writeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return e.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return e.writeFloat(v.Float(), v.Type().Bits())
+	case reflect.Pointer:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		key := pointerKey(v)
+		if err := e.enter(key); err != nil {
+			return err
+		}
+		defer e.leave(key)
+		// Keep the element addressable so pointer-receiver IsZero methods
+		// of its fields are visible to omitzero.
+		return e.encodeValue(v.Elem(), depth)
+	case reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		if elem := v.Elem(); elem.CanInterface() {
+			// Takes the fast path for parsed values stored in Go values.
+			return e.encode(elem.Interface(), depth)
+		}
+		return e.encodeValue(v.Elem(), depth)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.writeString(base64.StdEncoding.EncodeToString(v.Bytes()))
+			return nil
+		}
+		if v.Len() == 0 {
+			return e.encodeList(v, depth)
+		}
+		key := sliceKey(v)
+		if err := e.enter(key); err != nil {
+			return err
+		}
+		defer e.leave(key)
+		return e.encodeList(v, depth)
+	case reflect.Array:
+		return e.encodeList(v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		key := mapKey(v)
+		if err := e.enter(key); err != nil {
+			return err
+		}
+		defer e.leave(key)
+		return e.encodeMap(v, depth)
+	case reflect.Struct:
+		return e.encodeStruct(v, depth)
+	default:
+		return fmt.Errorf("unsupported value type %s", v.Type())
+	}
+	return nil
+}
+
+// bigNumber returns the literal text of v if it is a non-nil *big.Int or
+// *big.Float.
+func bigNumber(v reflect.Value) (parser.Number, bool) {
+	if !v.IsValid() || v.Kind() != reflect.Pointer || v.IsNil() {
+		return "", false
+	}
+	switch v.Type() {
+	case bigIntType:
+		return parser.Number(v.Interface().(*big.Int).String()), true
+	case bigFloatType:
+		return parser.Number(v.Interface().(*big.Float).Text('g', -1)), true
+	}
+	return "", false
+}
+
+// textMarshaler returns v as an encoding.TextMarshaler if it or, when
+// addressable, its pointer implements the interface. Nil pointers are left to
+// encode as null.
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return nil, false
+	}
+	if v.CanInterface() {
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			return m, true
+		}
+	}
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// encodeList writes a slice or array as a JSON array.
+func (e *encodeState) encodeList(v reflect.Value, depth int) error {
+	if v.Len() == 0 {
+		e.buf.WriteString("[]")
+		return nil
+	}
+
+	c := e.openContainer('[')
+	written := 0
+	for i := range v.Len() {
+		mark := e.buf.Len()
+		if written > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		err := e.encodeValueElement(i, v.Index(i), depth+1)
+		if err == errOmitted {
+			e.buf.Truncate(mark)
+			continue
+		}
+		if err != nil {
+			return withPath(err, strconv.Itoa(i))
+		}
+		written++
+	}
+	e.closeContainer(c, ']', written, depth)
+	return nil
+}
+
+// encodeMap writes a map with string, integer or encoding.TextMarshaler keys
+// as a JSON object with sorted keys. As in encoding/json, keys of a string
+// kind are written as they are even if they are TextMarshalers.
+func (e *encodeState) encodeMap(v reflect.Value, depth int) error {
+	members := make([]member, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var key string
+		k := iter.Key()
+		if m, ok := textMarshaler(k); ok && k.Kind() != reflect.String {
+			text, err := m.MarshalText()
+			if err != nil {
+				return fmt.Errorf("marshaling %s: %w", k.Type(), err)
+			}
+			members = append(members, member{key: string(text), value: iter.Value()})
+			continue
+		}
+		switch k.Kind() {
+		case reflect.String:
+			key = k.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			key = strconv.FormatInt(k.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return fmt.Errorf("unsupported map key type %s", k.Type())
+		}
+		members = append(members, member{key: key, value: iter.Value()})
+	}
+
+	slices.SortFunc(members, func(a, b member) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return e.encodeMembers(members, depth)
+}
+
+// encodeStruct writes a struct as a JSON object with members in field
+// declaration order, skipping fields marked omitempty or omitzero whose
+// values are empty or zero.
+func (e *encodeState) encodeStruct(v reflect.Value, depth int) error {
+	list := fields.For(v.Type()).List
+	members := make([]member, 0, len(list))
+	for _, f := range list {
+		fv, ok := fieldByIndex(v, f.Index)
+		if !ok {
+			continue
+		}
+		if (f.OmitEmpty && isEmptyValue(fv)) || (f.OmitZero && isZeroValue(fv)) {
+			continue
+		}
+		members = append(members, member{key: f.Name, value: fv, quoted: f.Quoted})
+	}
+	return e.encodeMembers(members, depth)
+}
+
+// encodeMembers writes members as a JSON object. In canonical mode the
+// members are reordered by UTF-16 code units as RFC 8785 requires.
+func (e *encodeState) encodeMembers(members []member, depth int) error {
+	if len(members) == 0 {
+		e.buf.WriteString("{}")
+		return nil
+	}
+	if e.canonical {
+		slices.SortFunc(members, func(a, b member) int {
+			return compareCanonical(a.key, b.key)
+		})
+	}
+
+	c := e.openContainer('{')
+	written := 0
+	for _, m := range members {
+		mark := e.buf.Len()
+		if written > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		e.writeString(m.key)
+		e.buf.WriteByte(':')
+		if e.pretty {
+			e.buf.WriteByte(' ')
+		}
+		start := e.buf.Len()
+		err := e.encodeValueMember(m.key, m.value, depth+1)
+		if err == nil && m.quoted {
+			e.quoteFrom(start)
+		}
+		if err == errOmitted {
+			e.buf.Truncate(mark)
+			continue
+		}
+		if err != nil {
+			return withPath(err, m.key)
+		}
+		written++
+	}
+	e.closeContainer(c, '}', written, depth)
+	return nil
+}
+
+// quoteFrom rewrites the value written since start as a JSON string, for
+// fields with the string option. A null, written for a nil pointer, stays
+// null as in encoding/json.
+func (e *encodeState) quoteFrom(start int) {
+	text := string(e.buf.Bytes()[start:])
+	if text == "null" {
+		return
+	}
+	e.buf.Truncate(start)
+	e.writeString(text)
+}
+
+// encodeValueElement is encodeElement for the elements of Go slices and
+// arrays.
+func (e *encodeState) encodeValueElement(i int, v reflect.Value, depth int) error {
+	if e.replacer == nil || !v.CanInterface() {
+		return e.encodeValue(v, depth)
+	}
+	return e.encodeReplaced(strconv.Itoa(i), v.Interface(), depth)
+}
+
+// encodeValueMember is encodeMember for the members of Go structs and maps.
+func (e *encodeState) encodeValueMember(key string, v reflect.Value, depth int) error {
+	if e.replacer == nil || !v.CanInterface() {
+		return e.encodeValue(v, depth)
+	}
+	return e.encodeReplaced(key, v.Interface(), depth)
+}
+
+// fieldByIndex returns the field of v at index. It reports false if the field
+// is promoted through a nil embedded pointer, in which case it is omitted.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty in the omitempty sense: false, 0,
+// a nil pointer or interface, or an empty array, slice, map or string.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// isZeroer is implemented by types with their own notion of zero, such as
+// time.Time.
+type isZeroer interface {
+	IsZero() bool
+}
+
+// isZeroValue reports whether v is zero in the omitzero sense: its IsZero
+// method returns true if it has one, and otherwise it is the zero value of
+// its type. A nil pointer whose element type has IsZero counts as zero
+// rather than having the method called on it.
+func isZeroValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if v.CanInterface() {
+		if z, ok := v.Interface().(isZeroer); ok {
+			return z.IsZero()
+		}
+	}
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if z, ok := v.Addr().Interface().(isZeroer); ok {
+			return z.IsZero()
+		}
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,178 @@
+package encoder
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+type user struct {
+	Audit
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty"`
+	Email    *string           `json:"email"`
+	Tags     []string          `json:"tags"`
+	Address  address           `json:"address"`
+	Extra    parser.JSONObject `json:"extra,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+// window has a custom notion of zero that differs from its zero value.
+type window struct {
+	Start, End int
+}
+
+func (w window) IsZero() bool {
+	return w.End <= w.Start
+}
+
+// pointerZero implements IsZero on its pointer receiver.
+type pointerZero struct {
+	N int
+}
+
+func (p *pointerZero) IsZero() bool {
+	return p.N <= 0
+}
+
+type omitZero struct {
+	Created  time.Time         `json:"created,omitzero"`
+	Window   window            `json:"window,omitzero"`
+	Pointer  pointerZero       `json:"pointer,omitzero"`
+	Count    int               `json:"count,omitzero"`
+	Tags     []string          `json:"tags,omitzero"`
+	Empty    []string          `json:"empty,omitempty"`
+	EmptyTag []string          `json:"empty_zero,omitzero"`
+	Address  address           `json:"address,omitzero"`
+	Options  map[string]string `json:"options,omitzero"`
+	Ref      *window           `json:"ref,omitzero"`
+}
+
+// quoted has fields with the string option.
+type quoted struct {
+	ID    int64    `json:"id,string"`
+	Name  string   `json:"name,string"`
+	Ratio *float64 `json:"ratio,string"`
+	On    bool     `json:"on,string"`
+	Tags  []string `json:"tags,string"`
+}
+
+func TestMarshal_GoValues(t *testing.T) {
+	email := "a@example.com"
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "typed slice", value: []int{1, 2}, expected: `[1,2]`},
+		{name: "nil slice", value: []int(nil), expected: `null`},
+		{name: "array", value: [2]bool{true, false}, expected: `[true,false]`},
+		{name: "bytes", value: []byte("hi"), expected: `"aGk="`},
+		{name: "float32", value: float32(0.1), expected: `0.1`},
+		{name: "uint", value: uint8(200), expected: `200`},
+		{name: "typed map", value: map[string]int{"b": 2, "a": 1}, expected: `{"a":1,"b":2}`},
+		{name: "integer keys", value: map[int]string{10: "x", 2: "y"}, expected: `{"10":"x","2":"y"}`},
+		{name: "text marshaler keys", value: map[netip.Addr]int{netip.MustParseAddr("10.0.0.2"): 2, netip.MustParseAddr("10.0.0.1"): 1}, expected: `{"10.0.0.1":1,"10.0.0.2":2}`},
+		{
+			name:     "string option",
+			value:    quoted{ID: 1 << 60, Name: "x", On: true, Tags: []string{"a"}},
+			expected: `{"id":"1152921504606846976","name":"\"x\"","ratio":null,"on":"true","tags":["a"]}`,
+		},
+		{name: "nil pointer", value: (*int)(nil), expected: `null`},
+		{
+			name:     "struct",
+			value:    user{Name: "Ann", Email: &email, Tags: []string{"x"}, Address: address{City: "Oslo"}, Ignored: "no", internal: "no"},
+			expected: `{"name":"Ann","email":"a@example.com","tags":["x"],"address":{"city":"Oslo"}}`,
+		},
+		{
+			name:     "struct with embedded and parsed values",
+			value:    user{Audit: Audit{CreatedBy: "ops"}, Age: 30, Extra: parser.JSONObject{"k": []any{int64(1)}}},
+			expected: `{"created_by":"ops","name":"","age":30,"email":null,"tags":null,"address":{"city":""},"extra":{"k":[1]}}`,
+		},
+		{name: "parsed values containing structs", value: []any{address{City: "Rome"}}, expected: `[{"city":"Rome"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarshal_OmitZero(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    omitZero
+		expected string
+	}{
+		{name: "all zero", value: omitZero{}, expected: `{}`},
+		{
+			name:     "IsZero method decides",
+			value:    omitZero{Window: window{Start: 5, End: 5}, Pointer: pointerZero{N: -1}},
+			expected: `{}`,
+		},
+		{
+			name:     "non-zero by IsZero",
+			value:    omitZero{Window: window{Start: 0, End: 1}, Pointer: pointerZero{N: 1}},
+			expected: `{"window":{"Start":0,"End":1},"pointer":{"N":1}}`,
+		},
+		{
+			name:     "empty but non-nil values are not zero",
+			value:    omitZero{Tags: []string{}, Empty: []string{}, EmptyTag: []string{}, Options: map[string]string{}},
+			expected: `{"tags":[],"empty_zero":[],"options":{}}`,
+		},
+		{
+			name:     "time",
+			value:    omitZero{Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Count: 1, Address: address{City: "x"}},
+			expected: `{"created":"2024-01-02T03:04:05Z","count":1,"address":{"city":"x"}}`,
+		},
+		{name: "nil pointer is zero", value: omitZero{Ref: nil}, expected: `{}`},
+		{name: "IsZero called through pointer", value: omitZero{Ref: &window{Start: 3, End: 3}}, expected: `{}`},
+		{name: "non-zero pointer", value: omitZero{Ref: &window{End: 1}}, expected: `{"ref":{"Start":0,"End":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Marshal a pointer so pointer-receiver IsZero methods are reachable.
+			result, err := Marshal(&tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCanonical_Structs(t *testing.T) {
+	type pair struct {
+		Z int     `json:"z"`
+		A float64 `json:"a"`
+	}
+
+	result, err := Canonical(pair{Z: 1, A: 1e-7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"a":1e-7,"z":1}`; string(result) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
@@ -0,0 +1,79 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Replacer transforms a value before it is written, like the replacer
+// argument of JavaScript's JSON.stringify. path is the JSON Pointer (RFC
+// 6901) to v, "" for the root. Return SkipValue to omit v from its object or
+// array; an omitted root is written as null.
+type Replacer func(path string, v parser.JSONValue) (parser.JSONValue, error)
+
+// SkipValue is returned by a Replacer to omit the value it was given. It is
+// parser.SkipValue, so the same sentinel serves revivers and replacers.
+var SkipValue = parser.SkipValue
+
+// ReplacerError reports an error returned by a Replacer.
+type ReplacerError struct {
+	Path string // JSON Pointer (RFC 6901) to the value, "" for the root
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ReplacerError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("replacer failed at %s: %v", path, e.Err)
+}
+
+// Unwrap returns the replacer's error.
+func (e *ReplacerError) Unwrap() error {
+	return e.Err
+}
+
+// WithReplacer calls replace on every value before writing it, containers
+// before their members, so values can be transformed or omitted per path
+// during output, e.g. to strip internal fields or truncate long strings. The
+// members of Go structs and maps are passed as their Go values.
+func WithReplacer(replace Replacer) Option {
+	return func(c *config) {
+		c.replacer = replace
+	}
+}
+
+// errOmitted is returned by encodeReplaced when the replacer omits the
+// value, so the container can take back the separator written for it.
+var errOmitted = errors.New("value omitted by the replacer")
+
+// encodeReplaced writes the member or element token of the container at
+// e.path, as returned by the replacer.
+func (e *encodeState) encodeReplaced(token string, v parser.JSONValue, depth int) error {
+	parent := e.path
+	e.path += "/" + pointer.Escape(token)
+	defer func() { e.path = parent }()
+
+	v, err := e.replace(v)
+	if err != nil {
+		return err
+	}
+	return e.encode(v, depth)
+}
+
+// replace passes the value at e.path to the replacer.
+func (e *encodeState) replace(v parser.JSONValue) (parser.JSONValue, error) {
+	replaced, err := e.replacer(e.path, v)
+	if errors.Is(err, SkipValue) {
+		return nil, errOmitted
+	}
+	if err != nil {
+		return nil, &ReplacerError{Path: e.path, Err: err}
+	}
+	return replaced, nil
+}
@@ -0,0 +1,159 @@
+package encoder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestMarshal_WithReplacer(t *testing.T) {
+	stripInternal := func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+		if strings.Contains(path, "/_") {
+			return nil, SkipValue
+		}
+		return v, nil
+	}
+	truncate := func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+		if s, ok := v.(string); ok && len(s) > 3 {
+			return s[:3] + "...", nil
+		}
+		return v, nil
+	}
+
+	type account struct {
+		Name   string `json:"name"`
+		Secret string `json:"_secret"`
+		Tags   []string
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		replace  Replacer
+		expected string
+	}{
+		{
+			name:     "omits members",
+			value:    parser.JSONObject{"_id": 1, "name": "x", "nested": parser.JSONObject{"_rev": 2, "ok": true}},
+			replace:  stripInternal,
+			expected: `{"name":"x","nested":{"ok":true}}`,
+		},
+		{
+			name:     "omits every member",
+			value:    parser.JSONObject{"list": []any{parser.JSONObject{"_a": 1}}, "_b": 2},
+			replace:  stripInternal,
+			expected: `{"list":[{}]}`,
+		},
+		{
+			name:  "omits array elements",
+			value: []any{int64(1), int64(2), int64(3), int64(4)},
+			replace: func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+				if n, ok := v.(int64); ok && n%2 == 0 {
+					return nil, SkipValue
+				}
+				return v, nil
+			},
+			expected: `[1,3]`,
+		},
+		{
+			name:     "transforms values",
+			value:    parser.JSONObject{"long": "abcdef", "short": "ab", "list": []any{"ghijkl"}},
+			replace:  truncate,
+			expected: `{"list":["ghi..."],"long":"abc...","short":"ab"}`,
+		},
+		{
+			name:  "containers before members",
+			value: parser.JSONObject{"a": parser.JSONObject{"b": 1}},
+			replace: func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+				if path == "/a" {
+					return parser.JSONObject{"c": 2}, nil
+				}
+				return v, nil
+			},
+			expected: `{"a":{"c":2}}`,
+		},
+		{
+			name:     "struct fields",
+			value:    account{Name: "abcdef", Secret: "hunter2", Tags: []string{"x", "_y"}},
+			replace:  stripInternal,
+			expected: `{"name":"abcdef","Tags":["x","_y"]}`,
+		},
+		{
+			name:     "omitted root",
+			value:    parser.JSONObject{"a": 1},
+			replace:  func(path string, v parser.JSONValue) (parser.JSONValue, error) { return nil, SkipValue },
+			expected: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Marshal(tt.value, WithReplacer(tt.replace))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarshal_WithReplacer_Paths(t *testing.T) {
+	var paths []string
+	replace := func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+		paths = append(paths, path)
+		return v, nil
+	}
+
+	if _, err := Marshal(parser.JSONObject{"a/b": []any{true, nil}, "c": 1}, WithReplacer(replace)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"", "/a~1b", "/a~1b/0", "/a~1b/1", "/c"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %q, got %q", expected, paths)
+	}
+}
+
+func TestMarshalIndent_WithReplacer(t *testing.T) {
+	value := parser.JSONObject{"_a": 1, "b": []any{int64(1), "_"}, "c": parser.JSONObject{"_d": 1}}
+	omit := func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+		if strings.HasPrefix(path, "/_") || v == "_" || strings.Contains(path, "/_d") {
+			return nil, SkipValue
+		}
+		return v, nil
+	}
+
+	result, err := MarshalIndent(value, "", "  ", WithReplacer(omit))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "{\n  \"b\": [\n    1\n  ],\n  \"c\": {}\n}"
+	if string(result) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestMarshal_WithReplacer_Error(t *testing.T) {
+	errBad := errors.New("bad value")
+	replace := func(path string, v parser.JSONValue) (parser.JSONValue, error) {
+		if v == "bad" {
+			return nil, errBad
+		}
+		return v, nil
+	}
+
+	_, err := Marshal(parser.JSONObject{"list": []any{"ok", "bad"}}, WithReplacer(replace))
+	var replacerErr *ReplacerError
+	if !errors.As(err, &replacerErr) || !errors.Is(err, errBad) {
+		t.Fatalf("expected a ReplacerError wrapping %v, got %v", errBad, err)
+	}
+	if replacerErr.Path != "/list/1" {
+		t.Errorf("expected path /list/1, got %s", replacerErr.Path)
+	}
+}
@@ -0,0 +1,131 @@
+// Package fetch downloads documents over HTTP and HTTPS, bounding the time
+// and size of each download, so remote documents can be read like local
+// files.
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a whole download, body included, unless WithTimeout
+// sets another limit.
+const DefaultTimeout = 30 * time.Second
+
+// ErrTooLarge is returned when a response body is longer than the limit set
+// with WithMaxBytes.
+var ErrTooLarge = errors.New("response too large")
+
+// Fetcher downloads documents.
+type Fetcher interface {
+	// Open requests url and returns the response body for streaming. It fails
+	// for responses other than 200 OK, and reading fails with ErrTooLarge once
+	// the body exceeds the size limit.
+	Open(url string) (io.ReadCloser, error)
+}
+
+// fetcher is the concrete implementation of Fetcher.
+type fetcher struct {
+	client   *http.Client
+	timeout  time.Duration
+	maxBytes int64 // 0 for no limit
+}
+
+// Option configures a Fetcher.
+type Option func(*fetcher)
+
+// WithTimeout bounds each download, from the request until the body has been
+// read, to d instead of DefaultTimeout. d <= 0 removes the limit.
+func WithTimeout(d time.Duration) Option {
+	return func(f *fetcher) {
+		f.timeout = d
+	}
+}
+
+// WithMaxBytes fails downloads whose body is longer than n bytes, without
+// reading further. n <= 0, the default, removes the limit.
+func WithMaxBytes(n int64) Option {
+	return func(f *fetcher) {
+		f.maxBytes = n
+	}
+}
+
+// WithHTTPClient sends requests with client, e.g. to use a proxy or custom
+// TLS settings. Its Timeout is replaced by the WithTimeout setting.
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *fetcher) {
+		f.client = client
+	}
+}
+
+// New returns a Fetcher with the given options.
+func New(opts ...Option) Fetcher {
+	f := &fetcher{client: http.DefaultClient, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(f)
+	}
+	client := *f.client
+	client.Timeout = max(f.timeout, 0)
+	f.client = &client
+	return f
+}
+
+// IsURL reports whether name is an http or https URL, which a Fetcher can
+// open, rather than a file name.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// Open implements Fetcher.
+func (f *fetcher) Open(url string) (io.ReadCloser, error) {
+	resp, err := f.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	if f.maxBytes > 0 && resp.ContentLength > f.maxBytes {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %w (%d bytes)", url, ErrTooLarge, resp.ContentLength)
+	}
+	if f.maxBytes <= 0 {
+		return resp.Body, nil
+	}
+	return &limitedBody{body: resp.Body, url: url, remaining: f.maxBytes}, nil
+}
+
+// limitedBody reads a response body, failing once it exceeds the limit.
+type limitedBody struct {
+	body      io.ReadCloser
+	url       string
+	remaining int64 // bytes that may still be read
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, fmt.Errorf("fetching %s: %w", b.url, ErrTooLarge)
+	}
+	// Read one byte past the limit to tell a body of exactly the limit from
+	// a longer one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n - 1, fmt.Errorf("fetching %s: %w", b.url, ErrTooLarge)
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,86 @@
+package fetch
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetcher_Open(t *testing.T) {
+	const body = `{"name": "app"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/doc.json":
+			io.WriteString(w, body)
+		case "/chunked.json":
+			// Flushing before writing the body leaves the length unknown.
+			w.(http.Flusher).Flush()
+			io.WriteString(w, body)
+		case "/slow.json":
+			time.Sleep(200 * time.Millisecond)
+			io.WriteString(w, body)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		opts        []Option
+		wantErr     string
+		wantTooLong bool
+	}{
+		{name: "ok", path: "/doc.json"},
+		{name: "at size limit", path: "/doc.json", opts: []Option{WithMaxBytes(int64(len(body)))}},
+		{name: "declared too large", path: "/doc.json", opts: []Option{WithMaxBytes(4)}, wantTooLong: true},
+		{name: "streamed too large", path: "/chunked.json", opts: []Option{WithMaxBytes(4)}, wantTooLong: true},
+		{name: "streamed at size limit", path: "/chunked.json", opts: []Option{WithMaxBytes(int64(len(body)))}},
+		{name: "not found", path: "/missing.json", wantErr: "404 Not Found"},
+		{name: "timeout", path: "/slow.json", opts: []Option{WithTimeout(50 * time.Millisecond)}, wantErr: "Timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content []byte
+			r, err := New(tt.opts...).Open(server.URL + tt.path)
+			if err == nil {
+				content, err = io.ReadAll(r)
+				r.Close()
+			}
+			switch {
+			case tt.wantTooLong:
+				if !errors.Is(err, ErrTooLarge) {
+					t.Errorf("expected ErrTooLarge, got %v", err)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case string(content) != body:
+				t.Errorf("expected %q, got %q", body, content)
+			}
+		})
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for name, want := range map[string]bool{
+		"https://example.com/data.json": true,
+		"http://localhost:8080/a":       true,
+		"data.json":                     false,
+		"-":                             false,
+		"file:///tmp/data.json":         false,
+	} {
+		if got := IsURL(name); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
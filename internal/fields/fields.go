@@ -0,0 +1,227 @@
+package fields
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Field is a JSON-visible struct field, possibly promoted from an embedded
+// struct.
+type Field struct {
+	Name      string       // JSON member name
+	Index     []int        // index sequence for reflect.Value.FieldByIndex
+	Type      reflect.Type // field type
+	Tagged    bool         // name came from a json tag
+	OmitEmpty bool         // tag has the omitempty option
+	OmitZero  bool         // tag has the omitzero option
+	Quoted    bool         // tag has the string option and the field is a bool, number or string
+}
+
+// Struct lists the JSON-visible fields of a struct type.
+type Struct struct {
+	List   []Field        // fields in declaration order
+	byName map[string]int // exact name -> position in List
+}
+
+// Lookup returns the field for a JSON member name, preferring an exact match
+// and falling back to a case-insensitive one, as encoding/json does.
+func (s *Struct) Lookup(name string) (*Field, bool) {
+	if i, ok := s.byName[name]; ok {
+		return &s.List[i], true
+	}
+	for i := range s.List {
+		if strings.EqualFold(s.List[i].Name, name) {
+			return &s.List[i], true
+		}
+	}
+	return nil, false
+}
+
+// cache maps struct types to their *Struct.
+var cache sync.Map
+
+// For returns the JSON-visible fields of struct type t. Results are cached,
+// so callers must not modify them.
+func For(t reflect.Type) *Struct {
+	if s, ok := cache.Load(t); ok {
+		return s.(*Struct)
+	}
+	s, _ := cache.LoadOrStore(t, typeFields(t))
+	return s.(*Struct)
+}
+
+// typeFields returns the fields that JSON members map to for struct type t,
+// following encoding/json's rules:
+//
+//   - exported fields are visible under their name or their json tag name;
+//     a tag of "-" hides the field; tag options after the name (",omitempty",
+//     ",omitzero", ",string") are recorded on the field
+//   - untagged embedded structs (or pointers to structs) have their fields
+//     promoted, even when the embedded type itself is unexported
+//   - when several fields share a name, the shallowest wins; at equal depth a
+//     tagged field beats untagged ones, and otherwise the name is ambiguous
+//     and all of them are ignored
+func typeFields(t reflect.Type) *Struct {
+	var current []Field
+	next := []Field{{Type: t}}
+
+	// Number of times a struct type appears at the current and next depth;
+	// a type embedded twice at the same depth makes its fields ambiguous.
+	var count map[reflect.Type]int
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+
+	var fields []Field
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.Type] {
+				continue
+			}
+			visited[f.Type] = true
+
+			for i := range f.Type.NumField() {
+				sf := f.Type.Field(i)
+				if sf.Anonymous {
+					embedded := sf.Type
+					if embedded.Kind() == reflect.Pointer {
+						embedded = embedded.Elem()
+					}
+					if !sf.IsExported() && embedded.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(f.Index), i)
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, Field{
+						Name:      name,
+						Index:     index,
+						Type:      sf.Type,
+						Tagged:    tagged,
+						OmitEmpty: hasOption(options, "omitempty"),
+						OmitZero:  hasOption(options, "omitzero"),
+						Quoted:    hasOption(options, "string") && quotable(ft),
+					})
+					if count[f.Type] > 1 {
+						// The enclosing struct was embedded more than once at
+						// this depth; add a duplicate so the field is dropped
+						// as ambiguous below.
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, Field{Name: ft.Name(), Index: index, Type: ft})
+				}
+			}
+		}
+	}
+
+	// Order by name, then depth, then tagged first, so the dominant field of
+	// each name comes first in its group.
+	slices.SortFunc(fields, func(a, b Field) int {
+		return cmp.Or(
+			strings.Compare(a.Name, b.Name),
+			cmp.Compare(len(a.Index), len(b.Index)),
+			compareTagged(a, b),
+			slices.Compare(a.Index, b.Index),
+		)
+	})
+
+	var dominant []Field
+	for start := 0; start < len(fields); {
+		end := start + 1
+		for end < len(fields) && fields[end].Name == fields[start].Name {
+			end++
+		}
+		if f, ok := dominantField(fields[start:end]); ok {
+			dominant = append(dominant, f)
+		}
+		start = end
+	}
+
+	// Restore declaration order.
+	slices.SortFunc(dominant, func(a, b Field) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+
+	byName := make(map[string]int, len(dominant))
+	for i, f := range dominant {
+		byName[f.Name] = i
+	}
+	return &Struct{List: dominant, byName: byName}
+}
+
+// hasOption reports whether a comma-separated list of tag options contains
+// option.
+func hasOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+// quotable reports whether the string option applies to fields of type t,
+// which encoding/json limits to booleans, numbers and strings.
+func quotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	}
+	return false
+}
+
+// compareTagged orders tagged fields before untagged ones.
+func compareTagged(a, b Field) int {
+	switch {
+	case a.Tagged == b.Tagged:
+		return 0
+	case a.Tagged:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// dominantField returns the field that wins among fields sharing a name,
+// which are sorted by depth and taggedness. It reports false if the name is
+// ambiguous.
+func dominantField(fields []Field) (Field, bool) {
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) && fields[0].Tagged == fields[1].Tagged {
+		return Field{}, false
+	}
+	return fields[0], true
+}
@@ -0,0 +1,107 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Base struct {
+	ID      int    `json:"id"`
+	Created string `json:"created,omitempty"`
+}
+
+type named struct {
+	Name string
+}
+
+type Left struct{ Value int }
+type Right struct{ Value int }
+
+type Model struct {
+	Base
+	*named
+	Left
+	Right
+	Title   string `json:"title,omitzero,omitempty"`
+	Skip    string `json:"-"`
+	Dash    string `json:"-,"`
+	private string
+}
+
+func TestFor(t *testing.T) {
+	s := For(reflect.TypeFor[Model]())
+
+	var names []string
+	for _, f := range s.List {
+		names = append(names, f.Name)
+	}
+	// Value is ambiguous between Left and Right and is dropped.
+	expected := []string{"id", "created", "Name", "title", "-"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	created, _ := s.Lookup("created")
+	if !created.OmitEmpty || created.OmitZero || !reflect.DeepEqual(created.Index, []int{0, 1}) {
+		t.Errorf("unexpected created field: %+v", created)
+	}
+	title, _ := s.Lookup("title")
+	if !title.OmitEmpty || !title.OmitZero || !title.Tagged {
+		t.Errorf("unexpected title field: %+v", title)
+	}
+	name, _ := s.Lookup("Name")
+	if name.Tagged || !reflect.DeepEqual(name.Index, []int{1, 0}) {
+		t.Errorf("unexpected Name field: %+v", name)
+	}
+
+	if For(reflect.TypeFor[Model]()) != s {
+		t.Error("expected cached result")
+	}
+}
+
+func TestFor_Quoted(t *testing.T) {
+	type quoted struct {
+		Count   int      `json:"count,string"`
+		Ratio   *float64 `json:"ratio,omitempty,string"`
+		Tags    []string `json:"tags,string"`
+		Enabled bool     `json:"enabled"`
+	}
+	s := For(reflect.TypeFor[quoted]())
+
+	expected := map[string]bool{"count": true, "ratio": true, "tags": false, "enabled": false}
+	for name, want := range expected {
+		f, ok := s.Lookup(name)
+		if !ok || f.Quoted != want {
+			t.Errorf("expected %s to be quoted %v, got %+v", name, want, f)
+		}
+	}
+}
+
+func TestStruct_Lookup(t *testing.T) {
+	s := For(reflect.TypeFor[Model]())
+
+	tests := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{key: "id", expected: "id", found: true},
+		{key: "ID", expected: "id", found: true},
+		{key: "name", expected: "Name", found: true},
+		{key: "Value", found: false},
+		{key: "Skip", found: false},
+		{key: "private", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			f, ok := s.Lookup(tt.key)
+			if ok != tt.found {
+				t.Fatalf("expected found=%v, got %v", tt.found, ok)
+			}
+			if ok && f.Name != tt.expected {
+				t.Errorf("expected field %q, got %q", tt.expected, f.Name)
+			}
+		})
+	}
+}
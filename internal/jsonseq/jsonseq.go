@@ -0,0 +1,155 @@
+// Package jsonseq reads and writes JSON text sequences (RFC 7464, media type
+// application/json-seq), as used by logging pipelines: each record is an
+// ASCII record separator (RS, 0x1E), a JSON text and a line feed. Unlike
+// JSON Lines, records may span several lines, and a record truncated by a
+// crashed writer is detected and skipped rather than corrupting the next.
+package jsonseq
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// RS is the record separator that starts every record.
+const RS = 0x1E
+
+// ErrTruncated reports a record holding a number, true, false or null that
+// isn't followed by whitespace, so it may have been cut short, e.g. 12 of
+// 123 (RFC 7464, section 2.1).
+var ErrTruncated = errors.New("record may be truncated: a top-level scalar must be followed by whitespace")
+
+// ErrMissingRS reports content before the first record separator.
+var ErrMissingRS = errors.New("content before the first record separator")
+
+// Record is the value of a record.
+type Record struct {
+	Number int // 1-based index of the record in the sequence
+	Value  parser.JSONValue
+}
+
+// RecordError reports a record that doesn't hold exactly one valid JSON
+// text. Number is 0 for content before the first record separator.
+type RecordError struct {
+	Number int
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Number, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads the values of a JSON text sequence.
+type Decoder interface {
+	// Records returns an iterator over the values of the records, in order.
+	// Consecutive separators don't delimit empty records. An invalid record
+	// yields a zero Record and a *RecordError, after which iteration
+	// continues with the next record, as RFC 7464 recommends; an error
+	// reading the input is yielded last.
+	Records() iter.Seq2[Record, error]
+}
+
+// decoder is the concrete implementation of Decoder.
+type decoder struct {
+	r    *bufio.Reader
+	opts []parser.Option
+}
+
+// NewDecoder returns a Decoder for the JSON text sequence read from r, whose
+// records are parsed with opts.
+func NewDecoder(r io.Reader, opts ...parser.Option) Decoder {
+	return &decoder{r: bufio.NewReader(r), opts: opts}
+}
+
+// Records implements Decoder.
+func (d *decoder) Records() iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		// Content before the first RS isn't a record.
+		data, err := d.r.ReadBytes(RS)
+		if err != nil && !errors.Is(err, io.EOF) {
+			yield(Record{}, err)
+			return
+		}
+		if len(bytes.TrimSpace(bytes.TrimSuffix(data, []byte{RS}))) > 0 {
+			if !yield(Record{}, &RecordError{Number: 0, Err: ErrMissingRS}) {
+				return
+			}
+		}
+
+		for number := 1; err == nil; {
+			data, err = d.r.ReadBytes(RS)
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(Record{}, err)
+				return
+			}
+
+			text := string(bytes.TrimSuffix(data, []byte{RS}))
+			if text == "" {
+				continue // consecutive separators
+			}
+			if !yield(d.record(number, text)) {
+				return
+			}
+			number++
+		}
+	}
+}
+
+// record parses the text of the record with the given number.
+func (d *decoder) record(number int, text string) (Record, error) {
+	value, err := parser.NewWithInput(lexer.New(text), text, d.opts...).Parse()
+	if err != nil {
+		return Record{}, &RecordError{Number: number, Err: err}
+	}
+	switch value.(type) {
+	case parser.JSONObject, []any, string:
+	default:
+		if last := text[len(text)-1]; last != ' ' && last != '\t' && last != '\n' && last != '\r' {
+			return Record{}, &RecordError{Number: number, Err: ErrTruncated}
+		}
+	}
+	return Record{Number: number, Value: value}, nil
+}
+
+// Encoder writes values as a JSON text sequence.
+type Encoder interface {
+	// Encode writes v as one record: RS, its compact encoding and a line
+	// feed, in a single write to the underlying writer.
+	Encode(v parser.JSONValue) error
+}
+
+// seqEncoder is the concrete implementation of Encoder.
+type seqEncoder struct {
+	w    io.Writer
+	opts []encoder.Option
+	buf  []byte
+}
+
+// NewEncoder returns an Encoder writing to w, encoding values with opts.
+func NewEncoder(w io.Writer, opts ...encoder.Option) Encoder {
+	return &seqEncoder{w: w, opts: opts}
+}
+
+// Encode implements Encoder.
+func (e *seqEncoder) Encode(v parser.JSONValue) error {
+	data, err := encoder.Marshal(v, e.opts...)
+	if err != nil {
+		return err
+	}
+	e.buf = append(append(append(e.buf[:0], RS), data...), '\n')
+	_, err = e.w.Write(e.buf)
+	return err
+}
@@ -0,0 +1,104 @@
+package jsonseq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestDecoder_Records(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string // "number=value" or "error@number"
+	}{
+		{name: "empty", input: ""},
+		{
+			name:     "records",
+			input:    "\x1e{\"a\": 1}\n\x1e[1,\n 2]\n\x1e\"text\"\n",
+			expected: []string{"1=map[a:1]", "2=[1 2]", "3=text"},
+		},
+		{name: "consecutive separators", input: "\x1e\x1e\x1etrue\n\x1e\x1e", expected: []string{"1=true"}},
+		{name: "scalars followed by whitespace", input: "\x1e123\n\x1enull \x1e-1.5\t", expected: []string{"1=123", "2=<nil>", "3=-1.5"}},
+		{
+			name:     "truncated scalar",
+			input:    "\x1e12\x1e{\"a\": 1}\n\x1etru\x1enull",
+			expected: []string{"error@1", "2=map[a:1]", "error@3", "error@4"},
+		},
+		{
+			name:     "invalid records skipped",
+			input:    "\x1e{\"a\": \n\x1e[1] [2]\n\x1e{}\n",
+			expected: []string{"error@1", "error@2", "3=map[]"},
+		},
+		{name: "content before the first separator", input: "junk\x1e1\n", expected: []string{"error@0", "1=1"}},
+		{name: "whitespace before the first separator", input: " \n\x1e1\n", expected: []string{"1=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for record, err := range NewDecoder(strings.NewReader(tt.input)).Records() {
+				var recordErr *RecordError
+				switch {
+				case errors.As(err, &recordErr):
+					got = append(got, fmt.Sprintf("error@%d", recordErr.Number))
+				case err != nil:
+					t.Fatalf("unexpected error: %v", err)
+				default:
+					got = append(got, fmt.Sprintf("%d=%v", record.Number, record.Value))
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("truncation error", func(t *testing.T) {
+		for _, err := range NewDecoder(strings.NewReader("\x1e42")).Records() {
+			if !errors.Is(err, ErrTruncated) {
+				t.Errorf("expected ErrTruncated, got %v", err)
+			}
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("disk on fire")
+		var lastErr error
+		for _, err := range NewDecoder(io.MultiReader(strings.NewReader("\x1e1\n"), iotest.ErrReader(readErr))).Records() {
+			lastErr = err
+		}
+		if !errors.Is(lastErr, readErr) {
+			t.Errorf("expected the read error, got %v", lastErr)
+		}
+	})
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var out strings.Builder
+	enc := NewEncoder(&out)
+	for _, v := range []parser.JSONValue{parser.JSONObject{"a": []any{int64(1), "x"}}, int64(2), nil} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if expected := "\x1e{\"a\":[1,\"x\"]}\n\x1e2\n\x1enull\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+
+	var got []string
+	for record, err := range NewDecoder(strings.NewReader(out.String())).Records() {
+		if err != nil {
+			t.Fatalf("failed to decode encoded output: %v", err)
+		}
+		got = append(got, fmt.Sprint(record.Value))
+	}
+	if expected := "[map[a:[1 x]] 2 <nil>]"; fmt.Sprint(got) != expected {
+		t.Errorf("expected records %s, got %v", expected, got)
+	}
+}
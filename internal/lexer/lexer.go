@@ -1,28 +1,56 @@
 package lexer
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"iter"
+	"strings"
 	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
+	"unsafe"
+
+	"github.com/VuNe/json-parser/internal/arena"
 )
 
 // Lexer interface defines the contract for tokenizing JSON input.
 type Lexer interface {
 	NextToken() (Token, error)
+	// Tokens returns an iterator over the remaining tokens, for use with
+	// range. It stops before the EOF token, or after yielding the first
+	// error together with the INVALID token that caused it.
+	Tokens() iter.Seq2[Token, error]
 	HasMore() bool
 	Position() Position
+	// BOM reports whether the input starts with a UTF-8 byte order mark,
+	// which the lexer skips: RFC 8259 forbids it, but parsers may ignore it.
+	BOM() bool
+	Reset(input string)
 }
 
 // lexer is the concrete implementation of the Lexer interface.
 type lexer struct {
-	input    string
-	position Position
-	current  int  // current position in input (points to current char)
-	ch       byte // current char under examination
+	input      string
+	reader     *bufio.Reader // source of the input instead of input, see NewReader
+	readErr    error         // error other than io.EOF returned by reader
+	position   Position
+	current    int    // current position in input (points to current char)
+	ch         byte   // current char under examination
+	buf        []byte // scratch buffer reused while decoding strings
+	arena      *arena.Arena
+	numbers    NumberExtension // accepted non-standard literals, see WithNumberExtensions
+	comments   bool            // skip comments, see WithComments
+	progress   func(int64)     // see WithProgress
+	zeroCopy   bool            // see WithZeroCopy
+	strict     bool            // reject lone surrogates, see WithStrictSurrogates
+	strictUTF8 bool            // reject invalid UTF-8 in strings, see WithStrictUTF8
+	bom        bool            // the input started with a byte order mark, see BOM
+	controls   bool            // accept control characters in strings, see WithControlCharacters
 }
 
 // New creates a new lexer instance for the given input string.
-func New(input string) Lexer {
+func New(input string, opts ...Option) Lexer {
 	l := &lexer{
 		input: input,
 		position: Position{
@@ -31,33 +59,195 @@ func New(input string) Lexer {
 			Offset: 0,
 		},
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
 	l.readChar()
+	l.skipBOM()
 	return l
 }
 
-// readChar reads the next character and advances the position in the input.
-func (l *lexer) readChar() {
-	if l.current >= len(l.input) {
-		l.ch = 0 // ASCII NUL character represents EOF
+// NewBytes creates a lexer for input like New, without copying it: the
+// values of number and keyword tokens, and of strings with WithZeroCopy, refer
+// to input, so input must not be modified while they are in use. It suits
+// documents read into memory, such as files.
+func NewBytes(input []byte, opts ...Option) Lexer {
+	return New(unsafe.String(unsafe.SliceData(input), len(input)), opts...)
+}
+
+// NewReader creates a lexer that reads its input incrementally from r, so
+// documents of any size can be tokenized without holding them in memory.
+// Errors reading from r are returned by NextToken with an INVALID token.
+func NewReader(r io.Reader, opts ...Option) Lexer {
+	l := &lexer{
+		position: Position{Line: 1, Column: 1, Offset: 0},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.progress != nil {
+		r = &progressReader{r: r, report: l.progress}
+	}
+	l.reader = bufio.NewReader(r)
+	l.readChar()
+	l.skipBOM()
+	return l
+}
+
+// Reset prepares the lexer to tokenize a new input, keeping its options and
+// internal buffers so that a single lexer can serve many small documents.
+func (l *lexer) Reset(input string) {
+	l.input = input
+	l.reader, l.readErr = nil, nil
+	l.position = Position{Line: 1, Column: 1, Offset: 0}
+	l.current = 0
+	l.ch = 0
+	l.readChar()
+	l.skipBOM()
+}
+
+// bom is the UTF-8 encoding of U+FEFF, the byte order mark.
+const bom = "\xEF\xBB\xBF"
+
+// skipBOM moves past a byte order mark at the start of the input, the
+// current character.
+func (l *lexer) skipBOM() {
+	if l.reader == nil {
+		l.bom = strings.HasPrefix(l.input, bom)
 	} else {
-		l.ch = l.input[l.current]
+		next, err := l.reader.Peek(len(bom) - 1)
+		l.bom = err == nil && l.ch == bom[0] && string(next) == bom[1:]
 	}
+	if l.bom {
+		for range len(bom) {
+			l.readChar()
+		}
+	}
+}
 
-	// Update position tracking
-	if l.current > 0 && l.input[l.current-1] == '\n' {
+// BOM implements Lexer.
+func (l *lexer) BOM() bool {
+	return l.bom
+}
+
+// readChar reads the next character and advances the position in the input.
+func (l *lexer) readChar() {
+	// Update position tracking; l.ch is the character being left
+	if l.current > 0 && l.ch == '\n' {
 		l.position.Line++
 		l.position.Column = 1
 	} else if l.current > 0 {
 		l.position.Column++
 	}
 
+	l.ch = l.next()
 	l.position.Offset = l.current
 	l.current++
 }
 
-// skipWhitespace skips whitespace characters (space, tab, newline, carriage return).
-func (l *lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+// advance moves n characters forward in an in-memory input, like n calls of
+// readChar.
+func (l *lexer) advance(n int) {
+	left := l.input[l.position.Offset : l.position.Offset+n]
+	if i := strings.LastIndexByte(left, '\n'); i >= 0 {
+		l.position.Line += strings.Count(left, "\n")
+		l.position.Column = n - i
+	} else {
+		l.position.Column += n
+	}
+	l.current = l.position.Offset + n
+	l.ch = l.next()
+	l.position.Offset = l.current
+	l.current++
+}
+
+// tokenValue returns the text of the token that started at start and ends
+// before the current character, all of whose characters were appended to
+// value: for an in-memory input a substring, which costs no allocation,
+// otherwise a copy of value.
+func (l *lexer) tokenValue(value []byte, start Position) string {
+	if l.reader == nil {
+		return l.input[start.Offset:l.position.Offset]
+	}
+	return string(value)
+}
+
+// next returns the character at l.current, or 0 (ASCII NUL), which
+// represents EOF, at the end of the input.
+func (l *lexer) next() byte {
+	if l.reader == nil {
+		if l.current >= len(l.input) {
+			return 0
+		}
+		return l.input[l.current]
+	}
+
+	c, err := l.reader.ReadByte()
+	if err != nil {
+		if err != io.EOF {
+			l.readErr = err
+		}
+		return 0
+	}
+	return c
+}
+
+// peekChar returns the character after the current one without consuming
+// it, or 0 at the end of the input.
+func (l *lexer) peekChar() byte {
+	if l.reader == nil {
+		if l.current >= len(l.input) {
+			return 0
+		}
+		return l.input[l.current]
+	}
+
+	b, err := l.reader.Peek(1)
+	if err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+// skipWhitespace skips whitespace characters (space, tab, newline, carriage
+// return), and comments with WithComments.
+func (l *lexer) skipWhitespace() error {
+	for {
+		for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+			l.readChar()
+		}
+		if !l.comments || l.ch != '/' {
+			return nil
+		}
+		switch l.peekChar() {
+		case '/':
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+		case '*':
+			if err := l.skipBlockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil // a stray '/', reported as unexpected
+		}
+	}
+}
+
+// skipBlockComment skips the /* */ comment starting at the current '/'.
+func (l *lexer) skipBlockComment() error {
+	start := l.position
+	l.readChar() // '/'
+	l.readChar() // '*'
+	for {
+		switch {
+		case l.ch == 0:
+			return fmt.Errorf("unterminated comment starting at %s", start)
+		case l.ch == '*' && l.peekChar() == '/':
+			l.readChar()
+			l.readChar()
+			return nil
+		}
 		l.readChar()
 	}
 }
@@ -66,33 +256,38 @@ func (l *lexer) skipWhitespace() {
 func (l *lexer) NextToken() (Token, error) {
 	var tok Token
 
-	l.skipWhitespace()
+	if err := l.skipWhitespace(); err != nil {
+		return Token{Type: INVALID, Value: "", Position: l.position}, err
+	}
 
 	// Capture the current position for the token
 	tok.Position = l.position
 
 	switch l.ch {
 	case '{':
-		tok = Token{Type: LEFT_BRACE, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: LEFT_BRACE, Value: "{", Position: l.position}
 		l.readChar()
 	case '}':
-		tok = Token{Type: RIGHT_BRACE, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: RIGHT_BRACE, Value: "}", Position: l.position}
 		l.readChar()
 	case '[':
-		tok = Token{Type: LEFT_BRACKET, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: LEFT_BRACKET, Value: "[", Position: l.position}
 		l.readChar()
 	case ']':
-		tok = Token{Type: RIGHT_BRACKET, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: RIGHT_BRACKET, Value: "]", Position: l.position}
 		l.readChar()
 	case ':':
-		tok = Token{Type: COLON, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: COLON, Value: ":", Position: l.position}
 		l.readChar()
 	case ',':
-		tok = Token{Type: COMMA, Value: string(l.ch), Position: l.position}
+		tok = Token{Type: COMMA, Value: ",", Position: l.position}
 		l.readChar()
 	case '"':
 		return l.readString()
 	case 0:
+		if l.readErr != nil {
+			return Token{Type: INVALID, Value: "", Position: l.position}, l.readErr
+		}
 		tok = Token{Type: EOF, Value: "", Position: l.position}
 	default:
 		// Handle numbers, booleans, and null
@@ -115,6 +310,22 @@ func (l *lexer) NextToken() (Token, error) {
 	return tok, nil
 }
 
+// Tokens implements Lexer.
+func (l *lexer) Tokens() iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		for {
+			tok, err := l.NextToken()
+			if err != nil {
+				yield(tok, err)
+				return
+			}
+			if tok.Type == EOF || !yield(tok, nil) {
+				return
+			}
+		}
+	}
+}
+
 // HasMore returns true if there are more tokens to process.
 func (l *lexer) HasMore() bool {
 	return l.ch != 0
@@ -128,11 +339,22 @@ func (l *lexer) Position() Position {
 // readString reads a JSON string token with escape sequence support.
 func (l *lexer) readString() (Token, error) {
 	position := l.position // Save the starting position
-	var value []byte
+	value := l.buf[:0]
 
 	// Skip opening quote
 	l.readChar()
 
+	// Without escapes, the value of a string in memory can be a substring of
+	// the input. NUL ends the input, see next.
+	if l.zeroCopy && l.reader == nil && l.arena == nil {
+		rest := l.input[l.position.Offset:]
+		if end := strings.IndexAny(rest, "\"\\\x00"); end >= 0 && rest[end] == '"' && l.plain(rest[:end]) {
+			l.advance(end + 1)
+			return Token{Type: STRING, Value: rest[:end], Position: position}, nil
+		}
+	}
+
+	ascii := true // whether the unescaped characters so far are all ASCII
 	for l.ch != '"' && l.ch != 0 {
 		if l.ch == '\\' {
 			l.readChar()
@@ -160,43 +382,131 @@ func (l *lexer) readString() (Token, error) {
 				value = append(value, '\t')
 			case 'u':
 				// Handle Unicode escape sequence \uXXXX
-				unicode, err := l.readUnicodeEscape()
-				if err != nil {
+				var err error
+				if value, err = l.readUnicodeEscape(value); err != nil {
 					return Token{Type: INVALID, Value: string(value), Position: position}, err
 				}
-				value = append(value, unicode...)
 			default:
 				return Token{Type: INVALID, Value: string(value), Position: position},
 					fmt.Errorf("invalid escape sequence '\\%c' at %s", l.ch, l.position)
 			}
 		} else {
+			if l.ch < 0x20 && !l.controls {
+				return Token{Type: INVALID, Value: string(value), Position: position},
+					fmt.Errorf("unescaped control character %U in string at %s", l.ch, l.position)
+			}
+			ascii = ascii && l.ch < utf8.RuneSelf
 			value = append(value, l.ch)
 		}
 		l.readChar()
 	}
 
 	if l.ch != '"' {
+		if l.readErr != nil {
+			return Token{Type: INVALID, Value: string(value), Position: position}, l.readErr
+		}
 		return Token{Type: INVALID, Value: string(value), Position: position},
 			fmt.Errorf("unterminated string at %s", position)
 	}
 
+	// Escapes decode to valid UTF-8, so only the characters copied from the
+	// input can make the value invalid.
+	if !ascii && !utf8.Valid(value) {
+		if l.strictUTF8 {
+			return Token{Type: INVALID, Value: string(value), Position: position},
+				fmt.Errorf("invalid UTF-8 in string at %s", position)
+		}
+		value = replaceInvalidUTF8(value)
+	}
+
 	// Skip closing quote
 	l.readChar()
 
-	return Token{Type: STRING, Value: string(value), Position: position}, nil
+	l.buf = value
+	return Token{Type: STRING, Value: l.makeString(value), Position: position}, nil
+}
+
+// plain reports whether the contents of a string without escapes can be its
+// value as they are: valid UTF-8 without control characters, unless
+// WithControlCharacters accepts them.
+func (l *lexer) plain(contents string) bool {
+	if !l.controls {
+		for i := 0; i < len(contents); i++ {
+			if contents[i] < 0x20 {
+				return false
+			}
+		}
+	}
+	return utf8.ValidString(contents)
+}
+
+// replaceInvalidUTF8 returns value with each byte that isn't part of a valid
+// UTF-8 sequence replaced by U+FFFD, as encoding/json does.
+func replaceInvalidUTF8(value []byte) []byte {
+	valid := make([]byte, 0, len(value)+8)
+	for len(value) > 0 {
+		r, size := utf8.DecodeRune(value)
+		if r == utf8.RuneError && size == 1 {
+			valid = utf8.AppendRune(valid, utf8.RuneError)
+		} else {
+			valid = append(valid, value[:size]...)
+		}
+		value = value[size:]
+	}
+	return valid
+}
+
+// makeString converts decoded string bytes into a string, allocating from the
+// arena when one is configured.
+func (l *lexer) makeString(value []byte) string {
+	if l.arena != nil {
+		return l.arena.String(value)
+	}
+	return string(value)
 }
 
-// readUnicodeEscape reads a Unicode escape sequence \uXXXX and returns the UTF-8 bytes.
-func (l *lexer) readUnicodeEscape() ([]byte, error) {
+// readUnicodeEscape reads a Unicode escape sequence \uXXXX, or a UTF-16
+// surrogate pair of two, and appends its UTF-8 encoding to value. A surrogate
+// that isn't half of a pair is appended as U+FFFD, as encoding/json does, or
+// rejected with WithStrictSurrogates.
+func (l *lexer) readUnicodeEscape(value []byte) ([]byte, error) {
+	codePoint, err := l.readHex4()
+	if err != nil {
+		return value, err
+	}
+	for utf16.IsSurrogate(codePoint) {
+		if codePoint >= 0xDC00 || !l.peekEscape() {
+			return l.loneSurrogate(value, codePoint)
+		}
+		high := codePoint
+		l.readChar() // skip '\\'
+		l.readChar() // move to 'u'
+		if codePoint, err = l.readHex4(); err != nil {
+			return value, err
+		}
+		if r := utf16.DecodeRune(high, codePoint); r != utf8.RuneError {
+			return utf8.AppendRune(value, r), nil
+		}
+		// The second escape is decoded on its own, and may start a pair.
+		if value, err = l.loneSurrogate(value, high); err != nil {
+			return value, err
+		}
+	}
+	return utf8.AppendRune(value, codePoint), nil
+}
+
+// readHex4 reads the four hex digits following the 'u' of a Unicode escape,
+// leaving the lexer on the last one.
+func (l *lexer) readHex4() (rune, error) {
 	l.readChar() // skip 'u'
 
 	var hexDigits [4]byte
 	for i := 0; i < 4; i++ {
 		if l.ch == 0 {
-			return nil, fmt.Errorf("incomplete Unicode escape sequence at %s", l.position)
+			return 0, fmt.Errorf("incomplete Unicode escape sequence at %s", l.position)
 		}
 		if !isHexDigit(l.ch) {
-			return nil, fmt.Errorf("invalid Unicode escape sequence '\\u%s' at %s", string(hexDigits[:i]), l.position)
+			return 0, fmt.Errorf("invalid Unicode escape sequence '\\u%s' at %s", string(hexDigits[:i]), l.position)
 		}
 		hexDigits[i] = l.ch
 		if i < 3 { // Don't advance past the last digit
@@ -217,11 +527,26 @@ func (l *lexer) readUnicodeEscape() ([]byte, error) {
 			codePoint += rune(digit - 'a' + 10)
 		}
 	}
+	return codePoint, nil
+}
+
+// peekEscape reports whether the two characters after the current one start
+// a Unicode escape.
+func (l *lexer) peekEscape() bool {
+	if l.reader == nil {
+		return l.current <= len(l.input) && strings.HasPrefix(l.input[l.current:], `\u`)
+	}
+	b, err := l.reader.Peek(2)
+	return err == nil && b[0] == '\\' && b[1] == 'u'
+}
 
-	// Convert rune to UTF-8 bytes
-	result := make([]byte, 4)
-	n := utf8.EncodeRune(result, codePoint)
-	return result[:n], nil
+// loneSurrogate appends U+FFFD for a surrogate escape that isn't half of a
+// pair, or returns an error with WithStrictSurrogates.
+func (l *lexer) loneSurrogate(value []byte, codePoint rune) ([]byte, error) {
+	if l.strict {
+		return value, fmt.Errorf("lone surrogate '\\u%04X' at %s", codePoint, l.position)
+	}
+	return utf8.AppendRune(value, utf8.RuneError), nil
 }
 
 // isHexDigit returns true if the character is a valid hexadecimal digit.
@@ -234,6 +559,11 @@ func isAlpha(ch byte) bool {
 	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
 }
 
+// isBinaryDigit returns true if the character is '0' or '1'.
+func isBinaryDigit(ch byte) bool {
+	return ch == '0' || ch == '1'
+}
+
 // isDigit returns true if the character is a digit.
 func isDigit(ch byte) bool {
 	return ch >= '0' && ch <= '9'
@@ -241,8 +571,66 @@ func isDigit(ch byte) bool {
 
 // readNumber reads a JSON number token with support for integers, floats, and scientific notation.
 func (l *lexer) readNumber() (Token, error) {
+	// Standard numbers in memory are found by their offsets and returned as
+	// substrings; readNumberText handles the rest and reports the errors.
+	if l.reader == nil && l.numbers == 0 {
+		if end, ok := numberEnd(l.input, l.position.Offset); ok {
+			position := l.position
+			l.advance(end - position.Offset)
+			return Token{Type: NUMBER, Value: l.input[position.Offset:end], Position: position}, nil
+		}
+	}
+	return l.readNumberText()
+}
+
+// numberEnd returns the offset just past the JSON number starting at start
+// in input, -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?, and reports
+// false if it is invalid.
+func numberEnd(input string, start int) (int, bool) {
+	digits := func(i int) int {
+		for i < len(input) && isDigit(input[i]) {
+			i++
+		}
+		return i
+	}
+
+	i := start
+	if i < len(input) && input[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(input) && input[i] == '0':
+		if i++; i < len(input) && isDigit(input[i]) {
+			return 0, false
+		}
+	case i < len(input) && isDigit(input[i]):
+		i = digits(i)
+	default:
+		return 0, false
+	}
+	if i < len(input) && input[i] == '.' {
+		if i++; i == len(input) || !isDigit(input[i]) {
+			return 0, false
+		}
+		i = digits(i)
+	}
+	if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+		if i++; i < len(input) && (input[i] == '+' || input[i] == '-') {
+			i++
+		}
+		if i == len(input) || !isDigit(input[i]) {
+			return 0, false
+		}
+		i = digits(i)
+	}
+	return i, true
+}
+
+// readNumberText reads a number character by character, appending it to the
+// scratch buffer, for input read from a reader and number extensions.
+func (l *lexer) readNumberText() (Token, error) {
 	position := l.position // Save the starting position
-	var value []byte
+	value := l.buf[:0]
 
 	// Handle optional minus sign
 	if l.ch == '-' {
@@ -256,6 +644,20 @@ func (l *lexer) readNumber() (Token, error) {
 		}
 	}
 
+	// Handle hexadecimal and binary literals when enabled
+	if l.ch == '0' {
+		switch l.peekChar() {
+		case 'x', 'X':
+			if l.numbers&HexNumbers != 0 {
+				return l.readRadixNumber(value, position, isHexDigit)
+			}
+		case 'b', 'B':
+			if l.numbers&BinaryNumbers != 0 {
+				return l.readRadixNumber(value, position, isBinaryDigit)
+			}
+		}
+	}
+
 	// Handle the integer part
 	if l.ch == '0' {
 		// If it starts with 0, it must be 0, 0.x, or 0ex (no leading zeros allowed)
@@ -269,9 +671,9 @@ func (l *lexer) readNumber() (Token, error) {
 		}
 	} else {
 		// Read all digits for the integer part
-		for isDigit(l.ch) {
-			value = append(value, l.ch)
-			l.readChar()
+		var ok bool
+		if value, ok = l.readDigits(value, isDigit); !ok {
+			return l.misplacedSeparator(value, position)
 		}
 	}
 
@@ -287,9 +689,9 @@ func (l *lexer) readNumber() (Token, error) {
 		}
 
 		// Read all fractional digits
-		for isDigit(l.ch) {
-			value = append(value, l.ch)
-			l.readChar()
+		var ok bool
+		if value, ok = l.readDigits(value, isDigit); !ok {
+			return l.misplacedSeparator(value, position)
 		}
 	}
 
@@ -311,19 +713,66 @@ func (l *lexer) readNumber() (Token, error) {
 		}
 
 		// Read all exponent digits
+		var ok bool
+		if value, ok = l.readDigits(value, isDigit); !ok {
+			return l.misplacedSeparator(value, position)
+		}
+	}
+
+	l.buf = value
+	return Token{Type: NUMBER, Value: l.tokenValue(value, position), Position: position}, nil
+}
+
+// readRadixNumber reads the rest of a hexadecimal or binary literal, whose
+// '0' is the current character, appending it to value.
+func (l *lexer) readRadixNumber(value []byte, position Position, isRadixDigit func(byte) bool) (Token, error) {
+	value = append(value, l.ch, l.peekChar())
+	l.readChar()
+	l.readChar()
+
+	if !isRadixDigit(l.ch) {
+		return Token{Type: INVALID, Value: string(value), Position: position},
+			fmt.Errorf("invalid number format: missing digits after %q at %s", value, position)
+	}
+	value, ok := l.readDigits(value, isRadixDigit)
+	if !ok {
+		return l.misplacedSeparator(value, position)
+	}
+	l.buf = value
+	return Token{Type: NUMBER, Value: l.tokenValue(value, position), Position: position}, nil
+}
+
+// readDigits appends a run of digits to value. With DigitSeparators enabled
+// the run may contain single underscores between digits; it reports false
+// if an underscore isn't followed by a digit.
+func (l *lexer) readDigits(value []byte, isDigit func(byte) bool) ([]byte, bool) {
+	for {
 		for isDigit(l.ch) {
 			value = append(value, l.ch)
 			l.readChar()
 		}
+		if l.ch != '_' || l.numbers&DigitSeparators == 0 {
+			return value, true
+		}
+		value = append(value, l.ch)
+		l.readChar()
+		if !isDigit(l.ch) {
+			return value, false
+		}
 	}
+}
 
-	return Token{Type: NUMBER, Value: string(value), Position: position}, nil
+// misplacedSeparator returns the error for a digit separator that isn't
+// between two digits.
+func (l *lexer) misplacedSeparator(value []byte, position Position) (Token, error) {
+	return Token{Type: INVALID, Value: string(value), Position: position},
+		fmt.Errorf("invalid number format: '_' must separate digits at %s", position)
 }
 
 // readKeyword reads a JSON keyword (true, false, null).
 func (l *lexer) readKeyword() (Token, error) {
 	position := l.position // Save the starting position
-	var value []byte
+	value := l.buf[:0]
 
 	// Read all alphabetic characters
 	for isAlpha(l.ch) {
@@ -331,7 +780,8 @@ func (l *lexer) readKeyword() (Token, error) {
 		l.readChar()
 	}
 
-	keyword := string(value)
+	l.buf = value
+	keyword := l.tokenValue(value, position)
 
 	// Validate the keyword
 	switch keyword {
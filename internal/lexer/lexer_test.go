@@ -1,7 +1,12 @@
 package lexer
 
 import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNew(t *testing.T) {
@@ -270,6 +275,87 @@ func TestLexer_StringTokenization(t *testing.T) {
 				Position: Position{Line: 1, Column: 1, Offset: 0},
 			},
 		},
+		{
+			name:  "string with surrogate pair",
+			input: `"\uD83D\uDE00!"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "\U0001F600!",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with lone high surrogate",
+			input: `"a\uD83Db"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "a\uFFFDb",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with lone low surrogate",
+			input: `"\uDE00"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "\uFFFD",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with high surrogate before other escape",
+			input: `"\uD83D\u0041"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "\uFFFDA",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with high surrogate before pair",
+			input: `"\uD800\uD83D\uDE00"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "\uFFFD\U0001F600",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with reversed surrogate pair",
+			input: `"\uDE00\uD83D"`,
+			expected: Token{
+				Type:     STRING,
+				Value:    "\uFFFD\uFFFD",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with multibyte characters",
+			input: "\"caf\u00e9 \u4e2d \U0001F600\"",
+			expected: Token{
+				Type:     STRING,
+				Value:    "caf\u00e9 \u4e2d \U0001F600",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with invalid UTF-8",
+			input: "\"a\xffb\xe4\xb8\\u0041\"",
+			expected: Token{
+				Type:     STRING,
+				Value:    "a\uFFFDb\uFFFD\uFFFDA",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
+		{
+			name:  "string with encoded surrogate",
+			input: "\"\xed\xa0\x80\"",
+			expected: Token{
+				Type:     STRING,
+				Value:    "\uFFFD\uFFFD\uFFFD",
+				Position: Position{Line: 1, Column: 1, Offset: 0},
+			},
+		},
 		{
 			name:  "string with forward slash escape",
 			input: `"hello\/world"`,
@@ -306,10 +392,24 @@ func TestLexer_StringTokenization(t *testing.T) {
 	}
 }
 
+func TestWithControlCharacters(t *testing.T) {
+	for _, opts := range [][]Option{{WithControlCharacters()}, {WithControlCharacters(), WithZeroCopy()}} {
+		l := New("\"a\tb\nc\x1f\" 1", opts...)
+		tok, err := l.NextToken()
+		if err != nil || tok.Value != "a\tb\nc\x1f" {
+			t.Errorf("expected the control characters in the value, got %q, %v", tok.Value, err)
+		}
+		if tok, _ := l.NextToken(); tok.Position != (Position{Line: 2, Column: 5, Offset: 9}) {
+			t.Errorf("expected the next token on line 2, got %v", tok.Position)
+		}
+	}
+}
+
 func TestLexer_StringErrors(t *testing.T) {
 	tests := []struct {
 		name          string
 		input         string
+		opts          []Option
 		expectedError string
 	}{
 		{
@@ -337,11 +437,62 @@ func TestLexer_StringErrors(t *testing.T) {
 			input:         `"hello\uGGGG"`,
 			expectedError: "invalid Unicode escape sequence",
 		},
+		{
+			name:          "invalid low surrogate escape",
+			input:         `"\uD83D\uDEXX"`,
+			expectedError: "invalid Unicode escape sequence",
+		},
+		{
+			name:          "strict lone high surrogate",
+			input:         `"a\uD83Db"`,
+			opts:          []Option{WithStrictSurrogates()},
+			expectedError: "lone surrogate '\\uD83D' at line 1, column 8",
+		},
+		{
+			name:          "unescaped tab",
+			input:         "\"a\tb\"",
+			expectedError: "unescaped control character U+0009 in string at line 1, column 3",
+		},
+		{
+			name:          "unescaped newline",
+			input:         "\"a\nb\"",
+			opts:          []Option{WithZeroCopy()},
+			expectedError: "unescaped control character U+000A in string at line 1, column 3",
+		},
+		{
+			name:          "unescaped control character after escape",
+			input:         "\"\\n\x1f\"",
+			expectedError: "unescaped control character U+001F in string at line 1, column 4",
+		},
+		{
+			name:          "strict invalid UTF-8",
+			input:         "\"a\xffb\"",
+			opts:          []Option{WithStrictUTF8()},
+			expectedError: "invalid UTF-8 in string at line 1, column 1",
+		},
+		{
+			name:          "strict truncated UTF-8 sequence",
+			input:         "\"\xe4\xb8\"",
+			opts:          []Option{WithStrictUTF8()},
+			expectedError: "invalid UTF-8 in string",
+		},
+		{
+			name:          "strict lone low surrogate",
+			input:         `"\uDE00"`,
+			opts:          []Option{WithStrictSurrogates()},
+			expectedError: "lone surrogate '\\uDE00'",
+		},
+		{
+			name:          "strict reversed surrogate pair",
+			input:         `"\uDE00\uD83D"`,
+			opts:          []Option{WithStrictSurrogates()},
+			expectedError: "lone surrogate '\\uDE00'",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			l := New(tt.input)
+			l := New(tt.input, tt.opts...)
 			token, err := l.NextToken()
 
 			if err == nil {
@@ -640,6 +791,55 @@ func TestLexer_InvalidNumbers(t *testing.T) {
 	}
 }
 
+// TestLexer_NumberExtensions tests the non-standard numeric literals enabled by
+// WithNumberExtensions.
+func TestLexer_NumberExtensions(t *testing.T) {
+	all := HexNumbers | BinaryNumbers | DigitSeparators
+
+	tests := []struct {
+		name     string
+		input    string
+		ext      NumberExtension
+		expected string // token value, or "" if an error is expected
+		errorMsg string
+	}{
+		{name: "hex", input: "0xFF", ext: HexNumbers, expected: "0xFF"},
+		{name: "upper case hex prefix", input: "0X1a", ext: HexNumbers, expected: "0X1a"},
+		{name: "negative hex", input: "-0x10", ext: HexNumbers, expected: "-0x10"},
+		{name: "binary", input: "0b1010", ext: BinaryNumbers, expected: "0b1010"},
+		{name: "separators", input: "1_000_000", ext: DigitSeparators, expected: "1_000_000"},
+		{name: "separators in fraction and exponent", input: "1_0.2_5e1_0", ext: DigitSeparators, expected: "1_0.2_5e1_0"},
+		{name: "separators in hex", input: "0xFF_FF", ext: all, expected: "0xFF_FF"},
+		{name: "hex disabled", input: "0xFF", ext: BinaryNumbers, expected: "0"},
+		{name: "separators disabled", input: "1_000", ext: HexNumbers, expected: "1"},
+		{name: "hex without digits", input: "0x", ext: HexNumbers, errorMsg: "missing digits after"},
+		{name: "invalid binary digit", input: "0b2", ext: BinaryNumbers, errorMsg: "missing digits after"},
+		{name: "trailing separator", input: "1_", ext: DigitSeparators, errorMsg: "'_' must separate digits"},
+		{name: "double separator", input: "1__0", ext: DigitSeparators, errorMsg: "'_' must separate digits"},
+		{name: "separator before decimal point", input: "1_.5", ext: DigitSeparators, errorMsg: "'_' must separate digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := New(tt.input, WithNumberExtensions(tt.ext)).NextToken()
+			if tt.errorMsg != "" {
+				if err == nil {
+					t.Errorf("Expected error but got none. Token: %v", token)
+				} else if !containsSubstring(err.Error(), tt.errorMsg) {
+					t.Errorf("Error message %q does not contain %q", err.Error(), tt.errorMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token.Type != NUMBER || token.Value != tt.expected {
+				t.Errorf("Expected NUMBER %q, got %v %q", tt.expected, token.Type, token.Value)
+			}
+		})
+	}
+}
+
 // TestLexer_BooleanAndNull tests the lexer's ability to tokenize boolean and null values.
 func TestLexer_BooleanAndNull(t *testing.T) {
 	tests := []struct {
@@ -835,3 +1035,285 @@ func TestLexer_InvalidKeywords(t *testing.T) {
 		})
 	}
 }
+
+func TestLexer_Reset(t *testing.T) {
+	l := New(`{"first": 1}`)
+	for l.HasMore() {
+		if _, err := l.NextToken(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	l.Reset("\n[\"second\"]")
+
+	expected := []Token{
+		{Type: LEFT_BRACKET, Value: "[", Position: Position{Line: 2, Column: 1, Offset: 1}},
+		{Type: STRING, Value: "second", Position: Position{Line: 2, Column: 2, Offset: 2}},
+		{Type: RIGHT_BRACKET, Value: "]", Position: Position{Line: 2, Column: 10, Offset: 10}},
+		{Type: EOF, Value: "", Position: Position{Line: 2, Column: 11, Offset: 11}},
+	}
+
+	for i, want := range expected {
+		tok, err := l.NextToken()
+		if err != nil {
+			t.Fatalf("token %d: unexpected error: %v", i, err)
+		}
+		if tok != want {
+			t.Errorf("token %d: expected %v, got %v", i, want, tok)
+		}
+	}
+}
+
+func TestLexer_BOM(t *testing.T) {
+	l := New("\uFEFF[1]")
+	if !l.BOM() {
+		t.Error("expected BOM to report the byte order mark")
+	}
+	want := Token{Type: LEFT_BRACKET, Value: "[", Position: Position{Line: 1, Column: 4, Offset: 3}}
+	if tok, err := l.NextToken(); err != nil || tok != want {
+		t.Errorf("expected %v after the byte order mark, got %v, %v", want, tok, err)
+	}
+
+	l.Reset("[1]")
+	if l.BOM() {
+		t.Error("expected no byte order mark after Reset")
+	}
+
+	if l := New(" \uFEFF[1]"); l.BOM() {
+		t.Error("expected a byte order mark after whitespace not to count")
+	}
+}
+
+func TestLexer_Tokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []TokenType
+		wantErr  bool
+	}{
+		{name: "empty", input: "  "},
+		{name: "document", input: `{"a": [1, true]}`, expected: []TokenType{LEFT_BRACE, STRING, COLON, LEFT_BRACKET, NUMBER, COMMA, BOOLEAN, RIGHT_BRACKET, RIGHT_BRACE}},
+		{name: "stops at the first error", input: `[1, @, 2]`, expected: []TokenType{LEFT_BRACKET, NUMBER, COMMA, INVALID}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var types []TokenType
+			var lastErr error
+			for tok, err := range New(tt.input).Tokens() {
+				types = append(types, tok.Type)
+				lastErr = err
+			}
+			if len(types) != len(tt.expected) {
+				t.Fatalf("expected tokens %v, got %v", tt.expected, types)
+			}
+			for i := range types {
+				if types[i] != tt.expected[i] {
+					t.Errorf("token %d: expected %v, got %v", i, tt.expected[i], types[i])
+				}
+			}
+			if (lastErr != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, lastErr)
+			}
+		})
+	}
+
+	t.Run("break", func(t *testing.T) {
+		l := New(`[1, 2]`)
+		for tok := range l.Tokens() {
+			if tok.Type == NUMBER {
+				break
+			}
+		}
+		if tok, _ := l.NextToken(); tok.Type != COMMA {
+			t.Errorf("expected the lexer to resume after the break, got %v", tok.Type)
+		}
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{name: "empty", input: ""},
+		{name: "document", input: "{\n  \"a\": [1, -2.5e3, true, null],\n  \"b\": \"x\\u00e9\"\n}"},
+		{name: "number at end of input", input: "0"},
+		{name: "numbers", input: "[0, -0, 12, -3.25, 1e10, 2E-3,\n 4.5e+6, 1.2.3]"},
+		{name: "leading zero", input: "[01]"},
+		{name: "missing fraction digits", input: "[1.]"},
+		{name: "missing exponent digits", input: "[1e+]"},
+		{name: "lone minus", input: "[-]"},
+		{name: "hex number without extensions", input: "[0x1]"},
+		{name: "hex number", input: "[0xFF, 0b101]", opts: []Option{WithNumberExtensions(HexNumbers | BinaryNumbers)}},
+		{name: "unterminated string", input: `["abc`},
+		{name: "surrogates", input: `["\uD83D\uDE00", "\uD83D", "\uD83D\u0041", "\uD83D\`},
+		{name: "invalid UTF-8", input: "[\"a\xffb\", \"\xe4\xb8\xad\"]"},
+		{name: "byte order mark", input: "\uFEFF{\"a\": 1}"},
+		{name: "control characters", input: "[\"a\tb\"]"},
+		{name: "accepted control characters", input: "[\"a\tb\nc\x01\"]", opts: []Option{WithControlCharacters()}},
+		{name: "partial byte order mark", input: "\xEF\xBB[1]"},
+		{name: "strict UTF-8", input: "[\"\xe4\xb8\xad\", \"\xe4\xb8\"]", opts: []Option{WithStrictUTF8()}},
+		{name: "strict surrogates", input: `["\uD83D\uDE00", "\uD83D"]`, opts: []Option{WithStrictSurrogates()}},
+		{name: "invalid character", input: "[1,\n @]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := New(tt.input, tt.opts...)
+			got := NewReader(iotest.OneByteReader(strings.NewReader(tt.input)), tt.opts...)
+			for {
+				wantTok, wantErr := want.NextToken()
+				gotTok, gotErr := got.NextToken()
+				if gotTok != wantTok {
+					t.Fatalf("expected token %+v, got %+v", wantTok, gotTok)
+				}
+				if (gotErr == nil) != (wantErr == nil) || gotErr != nil && gotErr.Error() != wantErr.Error() {
+					t.Fatalf("expected error %v, got %v", wantErr, gotErr)
+				}
+				if wantErr != nil || wantTok.Type == EOF {
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("disk on fire")
+		l := NewReader(io.MultiReader(strings.NewReader(`["a", "b`), iotest.ErrReader(readErr)))
+		var lastErr error
+		for _, err := range l.Tokens() {
+			lastErr = err
+		}
+		if !errors.Is(lastErr, readErr) {
+			t.Errorf("expected the read error, got %v", lastErr)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		l := NewReader(strings.NewReader("[1]"))
+		l.Reset("true")
+		if tok, err := l.NextToken(); err != nil || tok.Type != BOOLEAN {
+			t.Errorf("expected BOOLEAN after Reset, got %v, %v", tok.Type, err)
+		}
+	})
+}
+
+func TestNewBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{name: "empty", input: ""},
+		{name: "document", input: "{\n  \"a\": [1, -2.5e3, true, null],\n  \"b\": \"x\\u00e9\"\n}"},
+		{name: "string spanning lines", input: "[\"a\nbc\", \"d\"]", opts: []Option{WithZeroCopy()}},
+		{name: "NUL in string", input: "[\"a\x00b\"]", opts: []Option{WithZeroCopy()}},
+		{name: "zero copy", input: "{\"a\": \"x\\ty\", \"b\": \"\"}", opts: []Option{WithZeroCopy()}},
+		{name: "zero copy invalid UTF-8", input: "[\"a\xffb\", \"\u00e9\"]", opts: []Option{WithZeroCopy()}},
+		{name: "digit separators", input: "[1_000, 2]", opts: []Option{WithNumberExtensions(DigitSeparators)}},
+		{name: "unterminated string", input: `["abc`},
+		{name: "invalid keyword", input: "[nul]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := NewReader(strings.NewReader(tt.input), tt.opts...)
+			got := NewBytes([]byte(tt.input), tt.opts...)
+			for {
+				wantTok, wantErr := want.NextToken()
+				gotTok, gotErr := got.NextToken()
+				if gotTok != wantTok {
+					t.Fatalf("expected token %+v, got %+v", wantTok, gotTok)
+				}
+				if (gotErr == nil) != (wantErr == nil) || gotErr != nil && gotErr.Error() != wantErr.Error() {
+					t.Fatalf("expected error %v, got %v", wantErr, gotErr)
+				}
+				if wantErr != nil || wantTok.Type == EOF {
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("zero copy", func(t *testing.T) {
+		input := []byte(`{"name": "value", "count": 12345, "ok": true}`)
+		allocs := testing.AllocsPerRun(10, func() {
+			l := NewBytes(input, WithZeroCopy())
+			for tok, err := l.NextToken(); tok.Type != EOF; tok, err = l.NextToken() {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+		if allocs > 2 {
+			t.Errorf("expected only the lexer and its buffer to be allocated, got %v allocations", allocs)
+		}
+	})
+}
+
+func TestLexer_Comments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected []TokenType
+		wantErr  bool
+	}{
+		{
+			name:     "line and block comments",
+			input:    "// settings\n{\n  \"a\": 1, // trailing\n  /* block\n  comment */ \"b\": [/**/]\n}// end",
+			opts:     []Option{WithComments()},
+			expected: []TokenType{LEFT_BRACE, STRING, COLON, NUMBER, COMMA, STRING, COLON, LEFT_BRACKET, RIGHT_BRACKET, RIGHT_BRACE},
+		},
+		{name: "slashes in strings", input: `"// not /* a comment */"`, opts: []Option{WithComments()}, expected: []TokenType{STRING}},
+		{name: "star in block comment", input: "/* a ** b */ 1", opts: []Option{WithComments()}, expected: []TokenType{NUMBER}},
+		{name: "unterminated block comment", input: "1 /* open", opts: []Option{WithComments()}, expected: []TokenType{NUMBER, INVALID}, wantErr: true},
+		{name: "stray slash", input: "/ 1", opts: []Option{WithComments()}, expected: []TokenType{INVALID}, wantErr: true},
+		{name: "rejected by default", input: "// comment\n1", expected: []TokenType{INVALID}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, l := range []Lexer{New(tt.input, tt.opts...), NewReader(strings.NewReader(tt.input), tt.opts...)} {
+				var types []TokenType
+				var lastErr error
+				for tok, err := range l.Tokens() {
+					types = append(types, tok.Type)
+					lastErr = err
+				}
+				if !slices.Equal(types, tt.expected) {
+					t.Errorf("expected tokens %v, got %v", tt.expected, types)
+				}
+				if (lastErr != nil) != tt.wantErr {
+					t.Errorf("expected error %v, got %v", tt.wantErr, lastErr)
+				}
+			}
+		})
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	input := `{"items": [` + strings.Repeat(`"abcdefgh", `, 100) + `1]}`
+
+	var reports []int64
+	l := NewReader(iotest.OneByteReader(strings.NewReader(input)), WithProgress(func(read int64) {
+		reports = append(reports, read)
+	}))
+	for _, err := range l.Tokens() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(reports) < 2 {
+		t.Fatalf("expected several progress reports, got %v", reports)
+	}
+	if !slices.IsSorted(reports) {
+		t.Errorf("expected increasing progress, got %v", reports)
+	}
+	if last := reports[len(reports)-1]; last != int64(len(input)) {
+		t.Errorf("expected the last report to be %d, got %d", len(input), last)
+	}
+}
@@ -0,0 +1,115 @@
+package lexer
+
+import (
+	"io"
+
+	"github.com/VuNe/json-parser/internal/arena"
+)
+
+// Option configures optional lexer behavior.
+type Option func(*lexer)
+
+// WithArena makes the lexer allocate string token values from a instead of
+// the heap. The strings are only valid until a is reset; see arena.Arena.
+func WithArena(a *arena.Arena) Option {
+	return func(l *lexer) {
+		l.arena = a
+	}
+}
+
+// WithZeroCopy makes the values of string tokens without escapes substrings
+// of the input instead of copies, which saves an allocation per string. A
+// value kept after parsing then keeps the whole input in memory, and the input
+// of NewBytes must not be modified while the values are in use. Lexers created
+// with NewReader or WithArena copy strings regardless.
+func WithZeroCopy() Option {
+	return func(l *lexer) {
+		l.zeroCopy = true
+	}
+}
+
+// NumberExtension selects a non-standard numeric literal syntax accepted by
+// WithNumberExtensions.
+type NumberExtension int
+
+const (
+	HexNumbers      NumberExtension = 1 << iota // 0xFF
+	BinaryNumbers                               // 0b1010
+	DigitSeparators                             // 1_000_000, a single '_' between two digits
+)
+
+// WithNumberExtensions accepts the selected numeric literals, which are
+// rejected by default, for validating relaxed config dialects emitted by
+// other tooling. Hexadecimal and binary literals are integers and may be
+// negative. The token value keeps the literal as written; the parser reads it
+// with Go's integer syntax.
+func WithNumberExtensions(ext NumberExtension) Option {
+	return func(l *lexer) {
+		l.numbers = ext
+	}
+}
+
+// WithComments skips // line comments and /* */ block comments wherever
+// whitespace is allowed, as in JSONC config files such as tsconfig.json or
+// VS Code settings. Comments are rejected by default, as JSON requires.
+func WithComments() Option {
+	return func(l *lexer) {
+		l.comments = true
+	}
+}
+
+// WithStrictSurrogates rejects \u escapes of UTF-16 surrogates that aren't
+// half of a high-low pair, such as "\uD83D" on its own, which have no UTF-8
+// encoding. By default they decode to U+FFFD, the replacement character, as
+// with encoding/json.
+func WithStrictSurrogates() Option {
+	return func(l *lexer) {
+		l.strict = true
+	}
+}
+
+// WithStrictUTF8 rejects strings containing bytes that aren't valid UTF-8,
+// which RFC 8259 requires of JSON text. By default each such byte decodes to
+// U+FFFD, the replacement character, as with encoding/json.
+func WithStrictUTF8() Option {
+	return func(l *lexer) {
+		l.strictUTF8 = true
+	}
+}
+
+// WithControlCharacters accepts the control characters U+0001 to U+001F,
+// such as raw tabs and newlines, unescaped in strings, as some legacy
+// producers write them. RFC 8259 requires them to be escaped, so by default
+// they are rejected. NUL always ends the input.
+func WithControlCharacters() Option {
+	return func(l *lexer) {
+		l.controls = true
+	}
+}
+
+// WithProgress calls report with the number of bytes read so far each time a
+// lexer created with NewReader reads from its input, e.g. to show the progress
+// of validating a large file. report runs on the goroutine calling NextToken.
+// Lexers of strings, created with New, never call it.
+func WithProgress(report func(read int64)) Option {
+	return func(l *lexer) {
+		l.progress = report
+	}
+}
+
+// progressReader counts the bytes read from r for WithProgress.
+type progressReader struct {
+	r      io.Reader
+	read   int64
+	report func(read int64)
+}
+
+// Read implements io.Reader.
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.read += int64(n)
+		pr.report(pr.read)
+	}
+	return n, err
+}
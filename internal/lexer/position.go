@@ -4,9 +4,9 @@ import "fmt"
 
 // Position represents a position in the source text with line and column numbers.
 type Position struct {
-	Line   int // 1-based line number
-	Column int // 1-based column number
-	Offset int // 0-based byte offset
+	Line   int `json:"line"`   // 1-based line number
+	Column int `json:"column"` // 1-based column number
+	Offset int `json:"offset"` // 0-based byte offset
 }
 
 // String returns a human-readable representation of the position.
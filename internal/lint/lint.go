@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Warning is a non-fatal finding about a valid document. It is the parser's
+// warning type, so a Linter's Lint method can be passed to parser.WithChecks.
+type Warning = parser.Warning
+
+// Node is a value visited by the linter, together with its location.
+type Node struct {
+	Path  string // JSON Pointer to the value
+	Depth int    // nesting depth, 1 for the root value
+	Value parser.JSONValue
+}
+
+// Rule is a named check applied to every value of a document. Check returns
+// one message per problem found at the node, or nil if there is none.
+type Rule struct {
+	Name        string
+	Description string
+	Check       func(node Node) []string
+}
+
+// Linter interface defines the contract for checking parsed documents.
+type Linter interface {
+	Lint(value parser.JSONValue) []Warning
+}
+
+// linter is the concrete implementation of the Linter interface.
+type linter struct {
+	rules []Rule
+}
+
+// New creates a linter that applies the given rules, or DefaultRules if none
+// are given.
+func New(rules ...Rule) Linter {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &linter{rules: rules}
+}
+
+// Lint walks the document and returns the warnings produced by all rules,
+// ordered by path (object keys in sorted order) and then by rule.
+func (l *linter) Lint(value parser.JSONValue) []Warning {
+	var warnings []Warning
+	l.walk(Node{Path: "", Depth: 1, Value: value}, &warnings)
+	return warnings
+}
+
+// walk applies all rules to node and then to its children.
+func (l *linter) walk(node Node, warnings *[]Warning) {
+	for _, rule := range l.rules {
+		for _, message := range rule.Check(node) {
+			*warnings = append(*warnings, Warning{Rule: rule.Name, Path: node.Path, Message: message})
+		}
+	}
+
+	switch v := node.Value.(type) {
+	case []any:
+		for i, elem := range v {
+			l.walk(Node{Path: node.Path + "/" + strconv.Itoa(i), Depth: node.Depth + 1, Value: elem}, warnings)
+		}
+	case parser.JSONObject:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			l.walk(Node{Path: node.Path + "/" + pointer.Escape(key), Depth: node.Depth + 1, Value: v[key]}, warnings)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestLinter_DefaultRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "clean document", input: `{"id": 1, "tags": ["a", "b", null], "nested": {"ok": true}}`},
+		{name: "empty key", input: `{"": 1}`, expected: []string{"(root): object contains an empty key [empty-key]"}},
+		{
+			name:     "unsafe integer",
+			input:    `{"id": 9007199254740993}`,
+			expected: []string{"/id: integer 9007199254740993 is outside the range that doubles represent exactly [unsafe-integer]"},
+		},
+		{name: "safe integer bound", input: `[9007199254740991, -9007199254740991]`},
+		{
+			name:     "mixed array",
+			input:    `{"values": [1, "two", 3]}`,
+			expected: []string{"/values: array mixes number and string elements [mixed-array]"},
+		},
+		{
+			name:     "ordered by path",
+			input:    `{"b": [true, 1], "a": {"": null}}`,
+			expected: []string{"/a: object contains an empty key [empty-key]", "/b: array mixes boolean and number elements [mixed-array]"},
+		},
+		{
+			name:     "escaped path",
+			input:    `{"a/b": [[], {}]}`,
+			expected: []string{"/a~1b: array mixes array and object elements [mixed-array]"},
+		},
+		{name: "normalized strings", input: `{"caf\u00e9": "na\u00efve"}`},
+		{
+			name:  "unnormalized strings",
+			input: `{"cafe\u0301": ["nai\u0308ve"]}`,
+			expected: []string{
+				"(root): key \"cafe\u0301\" is not NFC-normalized [unnormalized-string]",
+				"/cafe\u0301/0: string is not NFC-normalized [unnormalized-string]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, warning := range New().Lint(mustParse(t, tt.input)) {
+				got = append(got, warning.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeepNestingRule(t *testing.T) {
+	input := strings.Repeat("[", 5) + strings.Repeat("]", 5)
+	warnings := New(DeepNestingRule(3)).Lint(mustParse(t, input))
+
+	expected := []Warning{{Rule: "deep-nesting", Path: "/0/0/0", Message: "value is nested more than 3 levels deep"}}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected %v, got %v", expected, warnings)
+	}
+}
+
+func TestLinter_CustomRule(t *testing.T) {
+	noNulls := Rule{
+		Name: "no-null",
+		Check: func(node Node) []string {
+			if node.Value == nil {
+				return []string{"null value"}
+			}
+			return nil
+		},
+	}
+
+	warnings := New(noNulls).Lint(mustParse(t, `{"a": null, "b": [null], "c": ""}`))
+	if len(warnings) != 2 || warnings[0].Path != "/a" || warnings[1].Path != "/b/0" {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestLinter_ParserCheck(t *testing.T) {
+	input := `{"id": 9007199254740993, "id": 1}`
+	p := parser.New(lexer.New(input),
+		parser.WithDuplicateKeys(parser.DuplicateKeysWarn, 0),
+		parser.WithChecks(New().Lint))
+	doc, err := p.ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The later duplicate wins, so the unsafe integer is no longer linted.
+	expected := []Warning{{Rule: parser.RuleDuplicateKey, Path: "/id", Message: `duplicate key "id"`}}
+	if !reflect.DeepEqual(doc.Warnings, expected) {
+		t.Errorf("expected %v, got %v", expected, doc.Warnings)
+	}
+
+	doc, err = parser.New(lexer.New(`[9007199254740993]`), parser.WithChecks(New().Lint)).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Warnings) != 1 || doc.Warnings[0].Rule != "unsafe-integer" {
+		t.Errorf("expected an unsafe-integer warning, got %v", doc.Warnings)
+	}
+}
@@ -0,0 +1,157 @@
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+const (
+	// maxSafeInteger is the largest integer that survives a round trip
+	// through an IEEE 754 double, as used by JavaScript (Number.MAX_SAFE_INTEGER).
+	maxSafeInteger = 1<<53 - 1
+
+	// maxRecommendedDepth is the nesting depth above which documents are
+	// flagged as hard to read and likely to hit limits in other parsers.
+	maxRecommendedDepth = 32
+)
+
+// DefaultRules returns the rules applied when no rules are configured.
+func DefaultRules() []Rule {
+	return []Rule{
+		EmptyKeyRule(),
+		UnsafeIntegerRule(),
+		MixedArrayRule(),
+		DeepNestingRule(maxRecommendedDepth),
+		UnnormalizedStringRule(),
+	}
+}
+
+// EmptyKeyRule flags objects that contain the empty string as a key, which
+// many tools and path syntaxes can't address.
+func EmptyKeyRule() Rule {
+	return Rule{
+		Name:        "empty-key",
+		Description: "objects should not use the empty string as a key",
+		Check: func(node Node) []string {
+			obj, ok := node.Value.(parser.JSONObject)
+			if !ok {
+				return nil
+			}
+			if _, ok := obj[""]; ok {
+				return []string{"object contains an empty key"}
+			}
+			return nil
+		},
+	}
+}
+
+// UnsafeIntegerRule flags integers that lose precision when read by parsers
+// that store all numbers as doubles, such as JavaScript's JSON.parse.
+func UnsafeIntegerRule() Rule {
+	return Rule{
+		Name:        "unsafe-integer",
+		Description: "integers should be within ±(2^53-1) to survive double-precision parsers",
+		Check: func(node Node) []string {
+			n, ok := node.Value.(int64)
+			if !ok || (n <= maxSafeInteger && n >= -maxSafeInteger) {
+				return nil
+			}
+			return []string{fmt.Sprintf("integer %d is outside the range that doubles represent exactly", n)}
+		},
+	}
+}
+
+// MixedArrayRule flags arrays whose elements have different types, ignoring
+// nulls, which usually indicates a modeling mistake.
+func MixedArrayRule() Rule {
+	return Rule{
+		Name:        "mixed-array",
+		Description: "array elements should share a single type",
+		Check: func(node Node) []string {
+			arr, ok := node.Value.([]any)
+			if !ok {
+				return nil
+			}
+
+			first := ""
+			for _, elem := range arr {
+				kind := kindOf(elem)
+				if kind == "null" {
+					continue
+				}
+				if first == "" {
+					first = kind
+				} else if kind != first {
+					return []string{fmt.Sprintf("array mixes %s and %s elements", first, kind)}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// DeepNestingRule flags values nested more than limit levels deep. Only the
+// first value past the limit on each path is reported.
+func DeepNestingRule(limit int) Rule {
+	return Rule{
+		Name:        "deep-nesting",
+		Description: fmt.Sprintf("values should not be nested more than %d levels deep", limit),
+		Check: func(node Node) []string {
+			if node.Depth != limit+1 {
+				return nil
+			}
+			return []string{fmt.Sprintf("value is nested more than %d levels deep", limit)}
+		},
+	}
+}
+
+// UnnormalizedStringRule flags keys and string values that aren't in Unicode
+// Normalization Form C, such as text copied from macOS filenames. They look
+// identical to their normalized form but don't compare equal to it (see
+// parser.WithNFC).
+func UnnormalizedStringRule() Rule {
+	return Rule{
+		Name:        "unnormalized-string",
+		Description: "keys and strings should be NFC-normalized",
+		Check: func(node Node) []string {
+			switch v := node.Value.(type) {
+			case string:
+				if !norm.NFC.IsNormalString(v) {
+					return []string{"string is not NFC-normalized"}
+				}
+			case parser.JSONObject:
+				var messages []string
+				for _, key := range slices.Sorted(maps.Keys(v)) {
+					if !norm.NFC.IsNormalString(key) {
+						messages = append(messages, fmt.Sprintf("key %q is not NFC-normalized", key))
+					}
+				}
+				return messages
+			}
+			return nil
+		},
+	}
+}
+
+// kindOf returns the JSON type name of a parsed value.
+func kindOf(value parser.JSONValue) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64, float64:
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
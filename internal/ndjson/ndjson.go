@@ -0,0 +1,140 @@
+// Package ndjson reads newline-delimited JSON (NDJSON, also known as JSON
+// Lines), the format of structured logs and many data exports: one JSON
+// value per line. Lines are read and parsed one at a time, so files of any
+// size can be processed, and an invalid line doesn't stop the lines after it
+// from being read.
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Line is the value of a line.
+type Line struct {
+	Number int // 1-based line number in the input
+	Value  parser.JSONValue
+}
+
+// LineError reports a line that doesn't hold exactly one valid JSON value.
+type LineError struct {
+	Line int   // 1-based line number in the input
+	Err  error // the *parser.ParseError, positioned in the input
+}
+
+// Error implements the error interface.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// config holds the settings applied by Options.
+type config struct {
+	lexerOpts  []lexer.Option
+	parserOpts []parser.Option
+}
+
+// Option configures a Decoder.
+type Option func(*config)
+
+// WithLexerOptions tokenizes every line with opts, e.g. lexer.WithComments.
+func WithLexerOptions(opts ...lexer.Option) Option {
+	return func(c *config) {
+		c.lexerOpts = append(c.lexerOpts, opts...)
+	}
+}
+
+// WithParserOptions parses every line with opts.
+func WithParserOptions(opts ...parser.Option) Option {
+	return func(c *config) {
+		c.parserOpts = append(c.parserOpts, opts...)
+	}
+}
+
+// Decoder reads the values of newline-delimited JSON.
+type Decoder interface {
+	// Lines returns an iterator over the values of the lines, in order.
+	// Blank lines are skipped but counted. An invalid line yields a zero
+	// Line and a *LineError, after which iteration continues with the next
+	// line; an error reading the input is yielded last.
+	Lines() iter.Seq2[Line, error]
+}
+
+// decoder is the concrete implementation of Decoder.
+type decoder struct {
+	r      *bufio.Reader
+	config config
+}
+
+// NewDecoder returns a Decoder for the newline-delimited JSON read from r.
+func NewDecoder(r io.Reader, opts ...Option) Decoder {
+	d := &decoder{r: bufio.NewReader(r)}
+	for _, opt := range opts {
+		opt(&d.config)
+	}
+	return d
+}
+
+// Lines implements Decoder.
+func (d *decoder) Lines() iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		for number := 1; ; number++ {
+			data, err := d.r.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(Line{}, err)
+				return
+			}
+
+			if len(bytes.TrimSpace(data)) > 0 {
+				text := string(data) // untrimmed, so that columns are right
+				value, parseErr := parser.NewWithInput(lexer.New(text, d.config.lexerOpts...), text, d.config.parserOpts...).Parse()
+				more := false
+				if parseErr != nil {
+					var pe *parser.ParseError
+					if errors.As(parseErr, &pe) {
+						pe.Position.Line = number
+					}
+					more = yield(Line{}, &LineError{Line: number, Err: parseErr})
+				} else {
+					more = yield(Line{Number: number, Value: value}, nil)
+				}
+				if !more {
+					return
+				}
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Validate checks every line of the newline-delimited JSON read from r and
+// returns the errors of the invalid lines, in order, and the number of values
+// read. The error is that of reading r, if any.
+func Validate(r io.Reader, opts ...Option) (values int, lineErrs []*LineError, err error) {
+	for _, lineErr := range NewDecoder(r, opts...).Lines() {
+		var le *LineError
+		switch {
+		case lineErr == nil:
+			values++
+		case errors.As(lineErr, &le):
+			lineErrs = append(lineErrs, le)
+		default:
+			return values, lineErrs, lineErr
+		}
+	}
+	return values, lineErrs, nil
+}
@@ -0,0 +1,93 @@
+package ndjson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestDecoder_Lines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected []string // "number=value" or "error@line"
+	}{
+		{name: "empty", input: ""},
+		{
+			name:     "values",
+			input:    "{\"level\": \"info\"}\n[1, 2]\r\n\"text\"\n",
+			expected: []string{"1=map[level:info]", "2=[1 2]", "3=text"},
+		},
+		{name: "no final newline", input: "1\n2", expected: []string{"1=1", "2=2"}},
+		{name: "blank lines counted", input: "\n  \ntrue\n\n", expected: []string{"3=true"}},
+		{
+			name:     "errors don't stop reading",
+			input:    "{\"a\": 1}\n{\"a\": \n[1] [2]\nnull\n",
+			expected: []string{"1=map[a:1]", "error@2", "error@3", "4=<nil>"},
+		},
+		{
+			name:     "options",
+			input:    "[1,] // comment\n",
+			opts:     []Option{WithLexerOptions(lexer.WithComments()), WithParserOptions(parser.WithTrailingCommas())},
+			expected: []string{"1=[1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for line, err := range NewDecoder(strings.NewReader(tt.input), tt.opts...).Lines() {
+				var lineErr *LineError
+				switch {
+				case errors.As(err, &lineErr):
+					got = append(got, fmt.Sprintf("error@%d", lineErr.Line))
+				case err != nil:
+					t.Fatalf("unexpected error: %v", err)
+				default:
+					got = append(got, fmt.Sprintf("%d=%v", line.Number, line.Value))
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecoder_LinesReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	var lastErr error
+	for _, err := range NewDecoder(io.MultiReader(strings.NewReader("1\n"), iotest.ErrReader(readErr))).Lines() {
+		lastErr = err
+	}
+	if !errors.Is(lastErr, readErr) {
+		t.Errorf("expected the read error, got %v", lastErr)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	values, lineErrs, err := Validate(strings.NewReader("{}\n{\n[]\n\"unterminated\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values != 2 {
+		t.Errorf("expected 2 values, got %d", values)
+	}
+	if len(lineErrs) != 2 || lineErrs[0].Line != 2 || lineErrs[1].Line != 4 {
+		t.Fatalf("expected errors on lines 2 and 4, got %v", lineErrs)
+	}
+	var parseErr *parser.ParseError
+	if !errors.As(lineErrs[0], &parseErr) || parseErr.Position.Line != 2 {
+		t.Errorf("expected a *parser.ParseError on line 2, got %v", lineErrs[0].Err)
+	}
+	if !strings.HasPrefix(lineErrs[0].Error(), "line 2: ") {
+		t.Errorf("expected the line number in %q", lineErrs[0].Error())
+	}
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/arena"
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithArena(t *testing.T) {
+	inputs := []string{
+		`{"name": "test", "items": [1, "two", [3, [4, 5]], {"six": [true, null]}], "empty": []}`,
+		`[[], [[]], ["a", "b"], {"k": ["v"]}]`,
+		`"top-level string"`,
+	}
+
+	a := arena.New()
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			expected, err := New(lexer.New(input)).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result, err := New(lexer.New(input, lexer.WithArena(a)), WithArena(a)).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error with arena: %v", err)
+			}
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %v, got %v", expected, result)
+			}
+			a.Reset()
+		})
+	}
+}
+
+func TestParser_WithArenaError(t *testing.T) {
+	a := arena.New()
+	input := `{"items": [1, 2, [3, }`
+
+	_, err := New(lexer.New(input, lexer.WithArena(a)), WithArena(a)).Parse()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
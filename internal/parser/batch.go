@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ParseAll parses each of inputs like Parse, concurrently on at most workers
+// goroutines, GOMAXPROCS if workers <= 0, and returns their results in the
+// order of inputs, like Session.ParseBatch, so batch pipelines can use every core without a pool of
+// their own. Each worker reuses one pooled parser for all the documents it
+// parses. As with lexer.NewBytes, the inputs aren't copied, so they must not
+// be modified while the values are in use. If ctx is done before every
+// document was parsed, the remaining results carry ctx.Err(), and documents
+// being parsed stop as with ParseContext.
+func ParseAll(ctx context.Context, inputs [][]byte, workers int, opts ...Option) []Result {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = max(1, min(workers, len(inputs)))
+
+	c := newConfig(opts)
+	pool := poolFor(c)
+	results := make([]Result, len(inputs))
+	var next atomic.Int64 // the index of the next document to parse
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			p := pool.Get().(*parser)
+			defer release(pool, p)
+			p.config = c
+			p.ctx = ctx
+
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(inputs) {
+					return
+				}
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				p.Reset(unsafe.String(unsafe.SliceData(inputs[i]), len(inputs[i])))
+				results[i].Value, results[i].Err = p.Parse()
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
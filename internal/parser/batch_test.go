@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	inputs := [][]byte{
+		[]byte(`{"id": 1}`),
+		[]byte(`[1, 2,]`),
+		[]byte(`"text"`),
+		[]byte(``),
+		[]byte(`{"a": {"b": [true, null]}}`),
+	}
+	expected := []JSONValue{JSONObject{"id": int64(1)}, nil, "text", nil, JSONObject{"a": JSONObject{"b": []any{true, nil}}}}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results := ParseAll(context.Background(), inputs, workers)
+			if len(results) != len(inputs) {
+				t.Fatalf("expected %d results, got %d", len(inputs), len(results))
+			}
+			for i, r := range results {
+				_, expectedErr := Parse(string(inputs[i]))
+				if (r.Err != nil) != (expectedErr != nil) || r.Err != nil && r.Err.Error() != expectedErr.Error() {
+					t.Errorf("result %d: expected error %v, got %v", i, expectedErr, r.Err)
+				}
+				if !reflect.DeepEqual(r.Value, expected[i]) {
+					t.Errorf("result %d: expected %v, got %v", i, expected[i], r.Value)
+				}
+			}
+		})
+	}
+
+	t.Run("options", func(t *testing.T) {
+		results := ParseAll(context.Background(), inputs[:2], 2, WithTrailingCommas(), WithMaxDepth(1))
+		if results[0].Err != nil || results[1].Err != nil {
+			t.Errorf("expected trailing commas to be accepted, got %v, %v", results[0].Err, results[1].Err)
+		}
+		if r := ParseAll(context.Background(), inputs[4:], 1, WithMaxDepth(1)); r[0].Err == nil {
+			t.Error("expected the depth limit to apply")
+		}
+	})
+
+	t.Run("no inputs", func(t *testing.T) {
+		if results := ParseAll(context.Background(), nil, 4); len(results) != 0 {
+			t.Errorf("expected no results, got %v", results)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for i, r := range ParseAll(ctx, inputs, 2) {
+			if !errors.Is(r.Err, context.Canceled) || r.Value != nil {
+				t.Errorf("result %d: expected context.Canceled, got %v, %v", i, r.Value, r.Err)
+			}
+		}
+	})
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	inputs := make([][]byte, 1000)
+	for i := range inputs {
+		inputs[i] = []byte(`{"id": ` + fmt.Sprint(i) + `, "tags": ["a", "b"], "payload": "` + strings.Repeat("x", 200) + `"}`)
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, input := range inputs {
+				if _, err := Parse(string(input)); err != nil {
+					b.Fatalf("Parse failed: %v", err)
+				}
+			}
+		}
+	})
+
+	b.Run("ParseAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, r := range ParseAll(context.Background(), inputs, 0) {
+				if r.Err != nil {
+					b.Fatalf("ParseAll failed: %v", r.Err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"math/big"
+	"strings"
+)
+
+// WithBigNumbers makes the parser return numbers that int64 and float64
+// can't hold as written as *big.Int or *big.Float, instead of rounding them
+// to a float64: integers beyond the int64 range, such as 128-bit IDs,
+// become *big.Int, and other numbers with more digits than a double keeps,
+// or a magnitude beyond its range like 1e400, become *big.Float with
+// enough precision for every digit written. *big.Float exponents are
+// limited to ±1000; numbers beyond fail with a ParseError with the key
+// MsgNumberOutOfRange. Numbers that fit are still int64 and float64, and
+// WithRawNumbers takes precedence.
+func WithBigNumbers() Option {
+	return func(c *config) {
+		c.bigNumbers = true
+	}
+}
+
+// bigNumber returns text as a *big.Int or *big.Float if big numbers are
+// kept and value, what ParseNumber returned for text, doesn't hold it
+// exactly. value is nil if ParseNumber failed, as it does for numbers out
+// of the float64 range. The result is nil for numbers out of range for
+// *big.Float too, see maxBigExponent.
+func (c *config) bigNumber(text string, value JSONValue) (JSONValue, bool) {
+	if !c.bigNumbers {
+		return nil, false
+	}
+	if _, ok := value.(int64); ok {
+		return nil, false
+	}
+	// Literals enabled by lexer.WithNumberExtensions are integers in Go
+	// syntax, which base 0 accepts.
+	if strings.ContainsAny(text, "xXbB_") {
+		n, ok := new(big.Int).SetString(text, 0)
+		return n, ok
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		n, ok := new(big.Int).SetString(text, 10)
+		return n, ok
+	}
+	if f, ok := value.(float64); ok && exactFloat(text, f) {
+		return nil, false
+	}
+	// Four bits per character is more than the log2(10) bits each decimal
+	// digit needs.
+	if f, ok := bigFloat(text, max(64, 4*uint(len(text)))); ok {
+		return f, true
+	}
+	return nil, true
+}
+
+// maxBigExponent bounds the decimal exponent of the numbers kept as
+// *big.Float: converting them between decimal and binary, as parsing and
+// writing them out does, takes time growing with the exponent, which is the
+// sender's to choose.
+const maxBigExponent = 1000
+
+// bigFloat returns text, a number in JSON syntax, as a *big.Float with prec
+// bits of mantissa, or false if its exponent is beyond maxBigExponent.
+func bigFloat(text string, prec uint) (*big.Float, bool) {
+	_, digits, exp, ok := splitDecimal(text)
+	if !ok {
+		return nil, false
+	}
+	if exp += len(digits) - 1; digits != "" && (exp > maxBigExponent || exp < -maxBigExponent) {
+		return nil, false
+	}
+	f, _, err := big.ParseFloat(text, 10, prec, big.ToNearestEven)
+	return f, err == nil
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// describeNumber formats v with its type, writing big numbers in full.
+func describeNumber(v JSONValue) string {
+	switch n := v.(type) {
+	case *big.Int:
+		return "big.Int " + n.String()
+	case *big.Float:
+		return "big.Float " + n.Text('g', -1)
+	default:
+		return fmt.Sprintf("%T %v", v, v)
+	}
+}
+
+func TestParser_WithBigNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []lexer.Option
+		expected string
+	}{
+		{name: "small integer", input: `42`, expected: "int64 42"},
+		{name: "exact float", input: `0.1`, expected: "float64 0.1"},
+		{name: "128-bit id", input: `340282366920938463463374607431768211455`, expected: "big.Int 340282366920938463463374607431768211455"},
+		{name: "negative integer", input: `-9223372036854775809`, expected: "big.Int -9223372036854775809"},
+		{name: "integer a double holds", input: `100000000000000000000`, expected: "big.Int 100000000000000000000"},
+		{name: "40-digit decimal", input: `3.141592653589793238462643383279502884197`, expected: "big.Float 3.141592653589793238462643383279502884197"},
+		{name: "beyond float64 range", input: `1e400`, expected: "big.Float 1e+400"},
+		{name: "below float64 range", input: `-1.5e-1000`, expected: "big.Float -1.5e-1000"},
+		{name: "zero with a huge exponent", input: `0.0e-999999999999`, expected: "float64 0"},
+		{name: "hex integer", input: `0xFFFFFFFFFFFFFFFFFF`, opts: []lexer.Option{lexer.WithNumberExtensions(lexer.HexNumbers)}, expected: "big.Int 4722366482869645213695"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := New(lexer.New(tt.input, tt.opts...), WithBigNumbers()).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describeNumber(value); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+			if tt.opts != nil {
+				return
+			}
+			value, err = NewFast(tt.input, WithBigNumbers()).Parse()
+			if err != nil {
+				t.Fatalf("fast parser: unexpected error: %v", err)
+			}
+			if got := describeNumber(value); got != tt.expected {
+				t.Errorf("fast parser: expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParser_WithBigNumbers_Precedence(t *testing.T) {
+	input := `[12345678901234567890]`
+	value, err := New(lexer.New(input), WithBigNumbers(), WithRawNumbers()).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := describeNumber(value.([]any)[0]); got != "parser.Number 12345678901234567890" {
+		t.Errorf("expected WithRawNumbers to win, got %s", got)
+	}
+
+	_, err = New(lexer.New(input), WithBigNumbers(), WithIJSON()).Parse()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != MsgImpreciseNumber {
+		t.Errorf("expected WithIJSON to reject the number, got %v", err)
+	}
+
+	if _, err := New(lexer.New(`1e400`)).Parse(); err == nil {
+		t.Error("expected 1e400 to fail without WithBigNumbers")
+	}
+}
+
+func TestParser_WithBigNumbers_OutOfRange(t *testing.T) {
+	for _, input := range []string{`[1.5e-100000000]`, `[-1.5e1001]`, `[0.01e-999]`, `[1e-999999999999]`} {
+		start := time.Now()
+		for _, p := range []Parser{
+			NewWithInput(lexer.New(input), input, WithBigNumbers()),
+			NewFast(input, WithBigNumbers()),
+		} {
+			_, err := p.Parse()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Key != MsgNumberOutOfRange {
+				t.Errorf("%s: expected an error with the key %q, got %v", input, MsgNumberOutOfRange, err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: parsing took %v", input, elapsed)
+		}
+	}
+}
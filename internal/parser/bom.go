@@ -0,0 +1,38 @@
+package parser
+
+import "github.com/VuNe/json-parser/internal/lexer"
+
+// BOMPolicy decides what happens to a UTF-8 byte order mark at the start of
+// the input, which RFC 8259 forbids generators to add but allows parsers to
+// ignore. Files saved by some Windows editors start with one.
+type BOMPolicy int
+
+const (
+	BOMWarn   BOMPolicy = iota // the mark is skipped, with a Document warning (default)
+	BOMIgnore                  // the mark is skipped silently
+	BOMError                   // the mark is a semantic error
+)
+
+// WithBOM sets the byte order mark policy. The lexer skips the mark in any
+// case; see lexer.Lexer.BOM.
+func WithBOM(policy BOMPolicy) Option {
+	return func(c *config) {
+		c.bom = policy
+	}
+}
+
+// checkBOM applies the byte order mark policy to a mark the lexer skipped,
+// before the first value is parsed.
+func (p *parser) checkBOM() error {
+	switch p.config.bom {
+	case BOMError:
+		mark := lexer.Token{Type: lexer.INVALID, Value: "\uFEFF", Position: lexer.Position{Line: 1, Column: 1}}
+		return p.newSemanticErrorAt(mark, MsgByteOrderMark, MsgSuggestRemoveBOM)
+	case BOMWarn:
+		if p.doc != nil {
+			p.warn(RuleByteOrderMark, "", "the input starts with a byte order mark, which was ignored")
+		}
+	}
+	p.bom = false
+	return nil
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithBOM(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		opts         []Option
+		wantErr      bool
+		wantWarnings []Warning
+	}{
+		{
+			name:         "skipped with a warning by default",
+			input:        "\uFEFF{\"a\": 1}",
+			wantWarnings: []Warning{{Rule: RuleByteOrderMark, Message: "the input starts with a byte order mark, which was ignored"}},
+		},
+		{name: "ignored", input: "\uFEFF{\"a\": 1}", opts: []Option{WithBOM(BOMIgnore)}},
+		{name: "rejected", input: "\uFEFF{\"a\": 1}", opts: []Option{WithBOM(BOMError)}, wantErr: true},
+		{name: "no mark", input: `{"a": 1}`, opts: []Option{WithBOM(BOMError)}},
+		{name: "mark after whitespace", input: " \uFEFF{\"a\": 1}", wantErr: true},
+		{name: "mark inside a string", input: "{\"a\": \"\uFEFF\"}", opts: []Option{WithBOM(BOMError)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...).ParseDocument()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(doc.Warnings, tt.wantWarnings) {
+				t.Errorf("expected warnings %v, got %v", tt.wantWarnings, doc.Warnings)
+			}
+		})
+	}
+}
+
+func TestParser_WithBOM_Error(t *testing.T) {
+	input := "\uFEFF\n[1]"
+	_, err := NewWithInput(lexer.New(input), input, WithBOM(BOMError)).Parse()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Key != MsgByteOrderMark || parseErr.Position.Line != 1 || parseErr.Position.Column != 1 {
+		t.Errorf("expected a byte order mark error at line 1, column 1, got %v", err)
+	}
+}
+
+func TestParser_WithBOM_Reader(t *testing.T) {
+	value, err := NewFromReader(strings.NewReader("\uFEFF[1, 2]")).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []any{int64(1), int64(2)}; !reflect.DeepEqual(value, expected) {
+		t.Errorf("expected %v, got %v", expected, value)
+	}
+
+	if _, err := NewFromReader(strings.NewReader("\uFEFF[1, 2]"), WithBOM(BOMError)).Parse(); err == nil {
+		t.Error("expected an error with BOMError")
+	}
+}
+
+func TestParser_WithBOM_ParseAll(t *testing.T) {
+	input := "\uFEFF1 2"
+	values, err := NewWithInput(lexer.New(input), input, WithBOM(BOMError)).ParseAll()
+	if err == nil {
+		t.Fatalf("expected an error, got %v", values)
+	}
+
+	p := NewWithInput(lexer.New(input), input)
+	p.Reset("3 4")
+	if values, err = p.ParseAll(); err != nil || len(values) != 2 {
+		t.Errorf("expected 2 values after Reset, got %v, %v", values, err)
+	}
+}
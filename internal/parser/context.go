@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+)
+
+// contextCheckInterval is the number of values parsed between checks of the
+// context of ParseContext, which keep the cost of checking negligible.
+const contextCheckInterval = 1024
+
+// ParseContext is Parse with a context: parsing stops once ctx is done, with
+// an error wrapping ctx.Err() that gives the position reached, so long
+// parses of huge inputs can be canceled or bounded in time. ctx is checked
+// before parsing and then every thousand or so values; a single huge string or
+// number is read whole before the next check.
+func ParseContext(ctx context.Context, input string, opts ...Option) (JSONValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c := newConfig(opts)
+	pool := poolFor(c)
+	p := pool.Get().(*parser)
+	defer release(pool, p)
+
+	p.config = c
+	p.ctx = ctx
+	p.Reset(input)
+	return p.Parse()
+}
+
+// checkContext fails once the context is done, checking it every
+// contextCheckInterval calls.
+func (p *parser) checkContext() error {
+	if p.ctx == nil {
+		return nil
+	}
+	if p.values++; p.values%contextCheckInterval != 0 {
+		return nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		return fmt.Errorf("parsing stopped at %s: %w", p.currentToken.Position, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cancelAfter is a context that is canceled once Err has been called n times.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	huge := `[` + strings.Repeat(`{"id": 1, "tags": ["a", "b"]}, `, 10000) + `null]`
+
+	t.Run("completes", func(t *testing.T) {
+		value, err := ParseContext(context.Background(), `{"a": [1, 2]}`, WithMaxDepth(2))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := (JSONObject{"a": []any{int64(1), int64(2)}}); !reflect.DeepEqual(value, expected) {
+			t.Errorf("expected %v, got %v", expected, value)
+		}
+	})
+
+	t.Run("canceled before parsing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := ParseContext(ctx, `{}`); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("canceled while parsing", func(t *testing.T) {
+		ctx := &cancelAfter{Context: context.Background(), n: 3}
+		_, err := ParseContext(ctx, huge)
+		if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "parsing stopped at line 1") {
+			t.Errorf("expected parsing to stop with context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		if _, err := ParseContext(ctx, huge); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("pooled parsers forget the context", func(t *testing.T) {
+		ParseContext(&cancelAfter{Context: context.Background(), n: 1}, huge)
+		if _, err := Parse(huge); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
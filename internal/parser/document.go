@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Warning is a non-fatal finding about a parsed document.
+type Warning struct {
+	Rule    string // name of the check that produced the warning
+	Path    string // JSON Pointer (RFC 6901) to the offending value, "" for the root
+	Message string
+}
+
+// String renders the warning on a single line, e.g. `/id: ... [unsafe-integer]`.
+func (w Warning) String() string {
+	path := w.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s [%s]", path, w.Message, w.Rule)
+}
+
+// Names of the warnings the parser itself produces.
+const (
+	RuleDuplicateKey  = "duplicate-key"   // with DuplicateKeysWarn
+	RulePrecisionLoss = "precision-loss"  // a number float64 can't represent exactly as written
+	RuleByteOrderMark = "byte-order-mark" // with BOMWarn
+)
+
+// Check inspects a parsed document and returns warnings about it, e.g. the
+// Lint method of a lint.Linter.
+type Check func(value JSONValue) []Warning
+
+// WithChecks runs checks on every document parsed with ParseDocument and
+// adds their warnings to the Document's.
+func WithChecks(checks ...Check) Option {
+	return func(c *config) {
+		c.checks = append(c.checks, checks...)
+	}
+}
+
+// Metrics describes the work of parsing a document.
+type Metrics struct {
+	Bytes    int           // size of the input
+	Values   int           // values parsed, counting containers and their contents
+	MaxDepth int           // deepest nesting of objects and arrays, 0 for a scalar
+	Duration time.Duration // time spent parsing and running checks
+}
+
+// Document is a parsed value together with what was learned while parsing
+// it.
+type Document struct {
+	Value    JSONValue
+	Warnings []Warning // in document order, followed by those of the checks
+	Metrics  Metrics
+}
+
+// ParseDocument implements Parser.
+func (p *parser) ParseDocument() (*Document, error) {
+	start := time.Now()
+	doc := &Document{}
+	p.doc = doc
+	defer func() { p.doc = nil }()
+
+	value, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+	doc.Value = value
+	for _, check := range p.checks {
+		doc.Warnings = append(doc.Warnings, check(value)...)
+	}
+	doc.Metrics.Bytes = p.lexer.Position().Offset
+	doc.Metrics.Duration = time.Since(start)
+	return doc, nil
+}
+
+// warn records a warning about the value at path while parsing a Document.
+func (p *parser) warn(rule, path, message string) {
+	p.doc.Warnings = append(p.doc.Warnings, Warning{Rule: rule, Path: path, Message: message})
+}
+
+// tracksPath reports whether p.path must be maintained, for the reviver or
+// for warnings.
+func (p *parser) tracksPath() bool {
+	return p.reviver != nil || p.doc != nil
+}
+
+// countValue counts a parsed value when parsing a Document.
+func (p *parser) countValue() {
+	if p.doc != nil {
+		p.doc.Metrics.Values++
+	}
+}
+
+// enterContainer is called when an object or array opens. It fails if the
+// container would exceed the maximum depth, see WithMaxDepth.
+func (p *parser) enterContainer() error {
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		return p.newSemanticError(MsgMaxDepth, MsgSuggestReduceNesting, p.maxDepth)
+	}
+	p.depth++
+	if p.doc != nil {
+		p.doc.Metrics.MaxDepth = max(p.doc.Metrics.MaxDepth, p.depth)
+	}
+	return nil
+}
+
+// leaveContainer is called when an object or array ends.
+func (p *parser) leaveContainer() {
+	p.depth--
+}
+
+// checkSize fails once the input read so far, up to the token after the
+// current one, exceeds the maximum size, see WithMaxBytes.
+func (p *parser) checkSize() error {
+	if p.maxBytes > 0 && p.peekToken.Position.Offset > p.maxBytes {
+		return p.newSemanticError(MsgMaxBytes, MsgSuggestReduceSize, p.maxBytes)
+	}
+	return nil
+}
+
+// checkString fails if the current token, a string, exceeds the maximum
+// length, see WithMaxStringLength, or isn't allowed in I-JSON, see WithIJSON.
+func (p *parser) checkString() error {
+	if p.maxStringLen > 0 && len(p.currentToken.Value) > p.maxStringLen {
+		return p.newSemanticError(MsgMaxStringLength, MsgSuggestShortenString, p.maxStringLen)
+	}
+	return p.checkIJSONString()
+}
+
+// checkPrecision warns if the float64 f, parsed from text, differs from the
+// number as written, such as integers beyond int64 or digits past float64's
+// precision. Decimal fractions like 0.1 don't count: they read back as
+// written.
+func (p *parser) checkPrecision(text string, f float64) {
+	if !exactFloat(text, f) {
+		p.warn(RulePrecisionLoss, p.path, fmt.Sprintf("%s is stored as %s", text, strconv.FormatFloat(f, 'g', -1, 64)))
+	}
+}
+
+// exactFloat reports whether the float64 f, parsed from text, is the number
+// as written, see checkPrecision. Both are compared as normalized decimals,
+// which takes time linear in the length of text whatever its exponent.
+func exactFloat(text string, f float64) bool {
+	neg, digits, exp, ok := splitDecimal(text)
+	if !ok {
+		return true
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return false
+	}
+	storedNeg, storedDigits, storedExp, _ := splitDecimal(strconv.FormatFloat(f, 'e', -1, 64))
+	if digits == "" || storedDigits == "" {
+		return digits == storedDigits // both zero, whatever their signs
+	}
+	return neg == storedNeg && digits == storedDigits && exp == storedExp
+}
+
+// maxDecimalExponent bounds the exponents splitDecimal reads: any larger
+// magnitude is far beyond float64's range, and counting on would overflow.
+const maxDecimalExponent = 1 << 30
+
+// splitDecimal splits a number in JSON syntax into its sign and the digits
+// and exponent with digits * 10^exp equal to it, without leading or
+// trailing zeros in digits, which is empty for zero. ok is false if text
+// isn't in JSON syntax, such as lexer.WithNumberExtensions literals.
+func splitDecimal(text string) (neg bool, digits string, exp int, ok bool) {
+	i := 0
+	if i < len(text) && text[i] == '-' {
+		neg = true
+		i++
+	}
+	var buf []byte
+	start := i
+	for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+		i++
+	}
+	if i == start {
+		return false, "", 0, false
+	}
+	buf = append(buf, text[start:i]...)
+	if i < len(text) && text[i] == '.' {
+		i++
+		start = i
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false, "", 0, false
+		}
+		buf = append(buf, text[start:i]...)
+		exp = start - i
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		i++
+		expNeg := false
+		if i < len(text) && (text[i] == '+' || text[i] == '-') {
+			expNeg = text[i] == '-'
+			i++
+		}
+		start = i
+		e := 0
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			e = min(e*10+int(text[i]-'0'), maxDecimalExponent)
+			i++
+		}
+		if i == start {
+			return false, "", 0, false
+		}
+		if expNeg {
+			e = -e
+		}
+		exp += e
+	}
+	if i != len(text) {
+		return false, "", 0, false
+	}
+	trimmed := strings.TrimRight(string(buf), "0")
+	exp += len(buf) - len(trimmed)
+	return neg, strings.TrimLeft(trimmed, "0"), exp, true
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_ParseDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected []Warning
+	}{
+		{name: "clean", input: `{"a": [1, 2.5, 0.1]}`},
+		{
+			name:  "duplicate keys warn",
+			input: `{"a": {"b": 1, "b": 2}, "a": 3}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysWarn, 0)},
+			expected: []Warning{
+				{Rule: RuleDuplicateKey, Path: "/a/b", Message: `duplicate key "b"`},
+				{Rule: RuleDuplicateKey, Path: "/a", Message: `duplicate key "a"`},
+			},
+		},
+		{
+			name:     "matching duplicate keys",
+			input:    `{"ID": 1, "id": 2}`,
+			opts:     []Option{WithDuplicateKeys(DuplicateKeysWarn, MatchCaseFolded)},
+			expected: []Warning{{Rule: RuleDuplicateKey, Path: "/id", Message: `duplicate key "id" (matches "ID")`}},
+		},
+		{
+			name:  "precision loss",
+			input: `[12345678901234567890, 0.10000000000000000001, 1e2]`,
+			expected: []Warning{
+				{Rule: RulePrecisionLoss, Path: "/0", Message: "12345678901234567890 is stored as 1.2345678901234567e+19"},
+				{Rule: RulePrecisionLoss, Path: "/1", Message: "0.10000000000000000001 is stored as 0.1"},
+			},
+		},
+		{
+			name:  "checks",
+			input: `{"a": null}`,
+			opts: []Option{WithChecks(func(value JSONValue) []Warning {
+				return []Warning{{Rule: "custom", Message: "checked"}}
+			})},
+			expected: []Warning{{Rule: "custom", Message: "checked"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := New(lexer.New(tt.input), tt.opts...).ParseDocument()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(doc.Warnings, tt.expected) {
+				t.Errorf("expected warnings %v, got %v", tt.expected, doc.Warnings)
+			}
+		})
+	}
+}
+
+func TestExactFloat(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{text: "0.1", expected: true},
+		{text: "1.50e2", expected: true},
+		{text: "-0.0", expected: true},
+		{text: "0e999999999999", expected: true},
+		{text: "100000000000000000000", expected: true},
+		{text: "9007199254740993", expected: false},
+		{text: "0.10000000000000000001", expected: false},
+		{text: "1.1e-999999", expected: false},
+		{text: "1e400", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			f, _ := strconv.ParseFloat(tt.text, 64)
+			if got := exactFloat(tt.text, f); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParser_ParseDocument_HugeExponents(t *testing.T) {
+	// Comparing these as exact rationals used to take milliseconds each.
+	input := "[" + strings.Repeat("1.1e-999999,", 999) + "0]"
+	start := time.Now()
+	doc, err := New(lexer.New(input)).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("parsing took %v", elapsed)
+	}
+	if len(doc.Warnings) != 999 {
+		t.Errorf("expected 999 precision warnings, got %d", len(doc.Warnings))
+	}
+}
+
+func TestParser_ParseDocument_Metrics(t *testing.T) {
+	input := ` {"a": [1, {"b": []}], "c": "x"} `
+	doc, err := New(lexer.New(input)).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.Metrics.Bytes != len(input) || doc.Metrics.Values != 6 || doc.Metrics.MaxDepth != 4 {
+		t.Errorf("expected %d bytes, 6 values and depth 4, got %+v", len(input), doc.Metrics)
+	}
+	if _, ok := doc.Value.(JSONObject)["c"]; !ok {
+		t.Errorf("expected the parsed value, got %v", doc.Value)
+	}
+}
+
+func TestParser_DuplicateKeysWarn_Parse(t *testing.T) {
+	// Outside ParseDocument, the later value wins silently.
+	value, err := New(lexer.New(`{"a": 1, "a": 2}`), WithDuplicateKeys(DuplicateKeysWarn, 0)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(value, JSONObject{"a": int64(2)}) {
+		t.Errorf("expected the later value, got %v", value)
+	}
+}
+
+func TestParser_ParseDocument_Error(t *testing.T) {
+	if _, err := New(lexer.New(`{"a": }`)).ParseDocument(); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// DuplicateKeyPolicy decides what happens when an object defines the same key
+// more than once, which RFC 8259 leaves to the implementation.
+type DuplicateKeyPolicy int
+
+const (
+	DuplicateKeysLast  DuplicateKeyPolicy = iota // the later value wins (default)
+	DuplicateKeysError                           // the duplicate is a semantic error
+	DuplicateKeysWarn                            // the later value wins, with a Document warning
+)
+
+// KeyMatching selects how keys are compared when looking for duplicates.
+type KeyMatching int
+
+const (
+	MatchNormalized KeyMatching = 1 << iota // compare keys in Unicode Normalization Form C
+	MatchCaseFolded                         // compare keys after Unicode case folding
+)
+
+// WithDuplicateKeys sets the duplicate key policy. By default keys are
+// compared byte for byte; match widens the comparison so that keys which
+// look the same, such as "café" spelled with a precomposed and with a
+// combining accent, or "ID" and "id" with MatchCaseFolded, count as
+// duplicates too. Matching only affects detection: members are stored under
+// their keys as written (see WithNFC to normalize them).
+func WithDuplicateKeys(policy DuplicateKeyPolicy, match KeyMatching) Option {
+	return func(c *config) {
+		c.duplicateKeys = policy
+		c.keyMatching = match
+	}
+}
+
+// checkDuplicateKey returns an error if obj, whose keys so far are indexed
+// by their matching form in seen, already has a member matching key, or
+// records a warning under DuplicateKeysWarn. seen is only used, and allocated
+// on first use, when keys aren't compared exactly.
+func (p *parser) checkDuplicateKey(obj JSONObject, key string, seen *map[string]string) error {
+	if p.duplicateKeys == DuplicateKeysLast || (p.duplicateKeys == DuplicateKeysWarn && p.doc == nil) {
+		return nil
+	}
+	if p.keyMatching == 0 {
+		if _, ok := obj[key]; ok {
+			return p.duplicateKey(key, key)
+		}
+		return nil
+	}
+
+	form := p.matchingForm(key)
+	if *seen == nil {
+		*seen = make(map[string]string)
+	}
+	if previous, ok := (*seen)[form]; ok {
+		return p.duplicateKey(key, previous)
+	}
+	(*seen)[form] = key
+	return nil
+}
+
+// duplicateKey reports key, which matches the previous key of its object.
+func (p *parser) duplicateKey(key, previous string) error {
+	msg, args := MsgDuplicateKey, []any{key}
+	if previous != key {
+		msg, args = MsgDuplicateKeyMatches, []any{key, previous}
+	}
+	if p.duplicateKeys == DuplicateKeysWarn {
+		message, _ := English.Format(msg, args...)
+		p.warn(RuleDuplicateKey, p.path+"/"+pointer.Escape(key), message)
+		return nil
+	}
+	return p.newSemanticError(msg, MsgSuggestDuplicateKey, args...)
+}
+
+// matchingForm returns the form of key that duplicates are compared by.
+func (p *parser) matchingForm(key string) string {
+	if p.keyMatching&MatchCaseFolded != 0 {
+		key = cases.Fold().String(key)
+	}
+	if p.keyMatching&MatchNormalized != 0 {
+		key = norm.NFC.String(key)
+	}
+	return key
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []Option
+		wantErr string // substring of the error, or "" for success
+	}{
+		{name: "last wins by default", input: `{"a": 1, "a": 2}`},
+		{
+			name:    "error policy",
+			input:   `{"a": 1, "b": {"c": 1, "c": 2}}`,
+			opts:    []Option{WithDuplicateKeys(DuplicateKeysError, 0)},
+			wantErr: `duplicate key "c"`,
+		},
+		{
+			name:  "same key in different objects",
+			input: `{"a": {"id": 1}, "b": {"id": 2}}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysError, 0)},
+		},
+		{
+			name:  "differently encoded keys are distinct by default",
+			input: `{"cafe\u0301": 1, "caf\u00e9": 2}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysError, 0)},
+		},
+		{
+			name:    "normalized matching",
+			input:   `{"cafe\u0301": 1, "caf\u00e9": 2}`,
+			opts:    []Option{WithDuplicateKeys(DuplicateKeysError, MatchNormalized)},
+			wantErr: "duplicate key \"caf\u00e9\" (matches \"cafe\u0301\")",
+		},
+		{
+			name:    "keys normalized while parsing",
+			input:   `{"cafe\u0301": 1, "caf\u00e9": 2}`,
+			opts:    []Option{WithNFC(NormalizeKeys), WithDuplicateKeys(DuplicateKeysError, 0)},
+			wantErr: "duplicate key \"caf\u00e9\"",
+		},
+		{
+			name:  "case differs without folding",
+			input: `{"ID": 1, "id": 2}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysError, MatchNormalized)},
+		},
+		{
+			name:    "case folded matching",
+			input:   `{"ID": 1, "id": 2}`,
+			opts:    []Option{WithDuplicateKeys(DuplicateKeysError, MatchCaseFolded)},
+			wantErr: `duplicate key "id" (matches "ID")`,
+		},
+		{
+			name:    "folding and normalization",
+			input:   `{"CAFE\u0301": 1, "caf\u00e9": 2}`,
+			opts:    []Option{WithDuplicateKeys(DuplicateKeysError, MatchNormalized|MatchCaseFolded)},
+			wantErr: "duplicate key",
+		},
+		{
+			name:    "exact duplicate with matching",
+			input:   `{"a": 1, "a": 2}`,
+			opts:    []Option{WithDuplicateKeys(DuplicateKeysError, MatchCaseFolded)},
+			wantErr: `duplicate key "a"`,
+		},
+		{
+			name:  "matching without the error policy",
+			input: `{"ID": 1, "id": 2}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysLast, MatchCaseFolded)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(lexer.New(tt.input), tt.opts...).Parse()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || !strings.Contains(parseErr.Message, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParser_WithDuplicateKeys_KeepsKeysAsWritten(t *testing.T) {
+	result, err := New(lexer.New(`{"ID": 1, "name": 2}`), WithDuplicateKeys(DuplicateKeysError, MatchCaseFolded)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := JSONObject{"ID": int64(1), "name": int64(2)}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestParser_WithDuplicateKeys_ErrorPosition(t *testing.T) {
+	input := "{\n  \"a\": 1,\n  \"a\": 2\n}"
+	_, err := NewWithInput(lexer.New(input), input, WithDuplicateKeys(DuplicateKeysError, 0)).Parse()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Type != SemanticError || parseErr.Position.Line != 3 {
+		t.Errorf("expected a semantic error on line 3, got %s error at %s", parseErr.Type, parseErr.Position)
+	}
+}
@@ -30,6 +30,20 @@ func (et ErrorType) String() string {
 	}
 }
 
+// messageKey returns the catalog key of the error type's name.
+func (et ErrorType) messageKey() MessageKey {
+	switch et {
+	case LexicalError:
+		return MsgLexicalError
+	case SyntaxError:
+		return MsgSyntaxError
+	case SemanticError:
+		return MsgSemanticError
+	default:
+		return MsgUnknownError
+	}
+}
+
 // ParseError represents an enhanced error that occurred during parsing.
 type ParseError struct {
 	Type        ErrorType
@@ -41,34 +55,70 @@ type ParseError struct {
 	JSONSnippet string   // Snippet of JSON around the error
 	Suggestion  string   // Recovery suggestion
 	SourceInput string   // Original input for context
+
+	// Key identifies Message, formatted with Args, for Localize. Errors
+	// created outside this package may leave it empty.
+	Key           MessageKey
+	Args          []any
+	SuggestionKey MessageKey // identifies Suggestion, if any
 }
 
 // Error implements the error interface with enhanced formatting.
 func (e ParseError) Error() string {
+	return e.Localize(English)
+}
+
+// Localize formats the error like Error, with the messages, suggestion and
+// labels taken from c. Messages without a key, or missing from c and
+// English, are used as they are.
+func (e ParseError) Localize(c Catalog) string {
+	text := func(key MessageKey, args ...any) string {
+		s, _ := c.Format(key, args...)
+		return s
+	}
+	message := e.Message
+	if s, ok := c.Format(e.Key, e.Args...); ok && e.Key != "" {
+		message = s
+	}
+	suggestion := e.Suggestion
+	if s, ok := c.Format(e.SuggestionKey); ok && e.SuggestionKey != "" && suggestion != "" {
+		suggestion = s
+	}
+
 	var parts []string
 
 	// Start with error type and basic message
-	parts = append(parts, fmt.Sprintf("%s error at %s: %s", e.Type, e.Position, e.Message))
+	position := text(MsgPosition, e.Position.Line, e.Position.Column)
+	parts = append(parts, text(MsgErrorHeader, text(e.Type.messageKey()), position, message))
 
 	// Add expected vs found context
 	if len(e.Expected) > 0 && e.Found != "" {
-		expectedStr := strings.Join(e.Expected, " or ")
-		parts = append(parts, fmt.Sprintf("Expected %s, but found %s", expectedStr, e.Found))
+		expectedStr := strings.Join(e.Expected, text(MsgExpectedOr))
+		parts = append(parts, text(MsgExpectedFound, expectedStr, e.Found))
 	}
 
 	// Add JSON snippet with position marker
 	if e.JSONSnippet != "" {
-		parts = append(parts, fmt.Sprintf("Near: %s", e.JSONSnippet))
+		parts = append(parts, text(MsgNear, e.JSONSnippet))
 	}
 
 	// Add recovery suggestion
-	if e.Suggestion != "" {
-		parts = append(parts, fmt.Sprintf("Suggestion: %s", e.Suggestion))
+	if suggestion != "" {
+		parts = append(parts, text(MsgSuggestion, suggestion))
 	}
 
 	return strings.Join(parts, "\n")
 }
 
+// newKeyedError creates a ParseError whose message is the English text for
+// key, keeping the key and args for Localize.
+func newKeyedError(key MessageKey, token lexer.Token, args ...any) *ParseError {
+	message, _ := English.Format(key, args...)
+	pe := NewParseError(message, token)
+	pe.Key, pe.Args = key, args
+	return pe
+}
+
 // NewParseError creates a basic ParseError (backward compatibility).
 func NewParseError(message string, token lexer.Token) *ParseError {
 	return &ParseError{
@@ -156,4 +206,14 @@ const (
 	SuggestionCloseArray          = "Add a closing ']' to complete the array"
 	SuggestionStringKey           = "Object keys must be strings enclosed in double quotes"
 	SuggestionValidKeyword        = "Use lowercase for JSON keywords: 'true', 'false', 'null'"
+	SuggestionDuplicateKey        = "Remove or rename the duplicate key"
+	SuggestionRemoveExtraContent  = "Remove any extra content after the JSON value"
+	SuggestionReduceNesting       = "Reduce the nesting of the document, or raise the limit with WithMaxDepth"
+	SuggestionReduceSize          = "Split the document, or raise the limit with WithMaxBytes"
+	SuggestionShortenString       = "Shorten the string, or raise the limit with WithMaxStringLength"
+	SuggestionReduceMemory        = "Split the document, or raise the limit with WithMaxMemory"
+	SuggestionRemoveBOM           = "Save the file as UTF-8 without a byte order mark"
+	SuggestionQuoteNumber         = "Write the number as a string to keep its precision"
+	SuggestionRemoveNoncharacter  = "Remove the noncharacter, which is reserved for internal use"
+	SuggestionWrapValue           = "Wrap the value in an object or array"
 )
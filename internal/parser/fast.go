@@ -0,0 +1,565 @@
+package parser
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// fastParser is the Parser returned by NewFast. It parses in two stages, like
+// simdjson: indexStructure first finds the structural characters and the
+// starts of strings and scalars in a single pass over the input, skipping
+// whitespace and string contents in bulk, and then the values are built by
+// walking that index, without a lexer producing tokens character by
+// character. Anything the fast path doesn't handle, including every error, is
+// handed to the standard parser, see standard.
+type fastParser struct {
+	config
+	opts     []Option
+	input    string
+	index    []uint32 // offsets into input, see indexStructure
+	indexed  bool     // index holds the structure of input
+	next     int      // the entry of index to parse next
+	depth    int      // number of open objects and arrays
+	consumed int      // values returned so far
+	buf      []byte   // scratch buffer for unescaping strings
+	elements []any    // pending elements of all open arrays
+	members  []member // pending members of all open objects
+	std      Parser   // the standard parser, once it has taken over
+}
+
+// member is a pending member of an object.
+type member struct {
+	key   string
+	value JSONValue
+}
+
+// errFallback makes the fast path hand over to the standard parser.
+var errFallback = errors.New("parser: fall back to the standard parser")
+
+// NewFast creates a parser for input, which is parsed like with
+//
+//	NewWithInput(lexer.New(input), input, opts...)
+//
+// producing the same values and errors, but faster on valid
+// documents: the input is first indexed and values are then built from the
+// index (see fastParser). Only standard JSON syntax is accepted, as the
+// lexer has no options. WithArena, WithNFC, WithReviver, WithMaxMemory,
+// WithIJSON, duplicate key policies other than DuplicateKeysLast, WithMaxBytes
+// limits the input exceeds, ParseDocument, input starting with a byte order
+// mark and invalid input, including input that isn't valid UTF-8, are handled
+// by the standard parser, so they cost no more than with it.
+func NewFast(input string, opts ...Option) Parser {
+	p := &fastParser{config: newConfig(opts), opts: opts}
+	p.Reset(input)
+	return p
+}
+
+// Reset implements Parser.
+func (p *fastParser) Reset(input string) {
+	p.input = input
+	p.index = p.index[:0]
+	p.indexed = false
+	p.next, p.depth, p.consumed = 0, 0, 0
+	p.std = nil
+}
+
+// Parse implements Parser.
+func (p *fastParser) Parse() (JSONValue, error) {
+	if p.fast() {
+		if value, err := p.value(); err == nil && p.next == len(p.index) {
+			p.consumed++
+			return value, nil
+		}
+	}
+	return p.standard().Parse()
+}
+
+// ParseValue implements Parser.
+func (p *fastParser) ParseValue() (JSONValue, error) {
+	if p.fast() {
+		if value, err := p.value(); err == nil {
+			p.consumed++
+			return value, nil
+		}
+	}
+	return p.standard().ParseValue()
+}
+
+// ParseAll implements Parser.
+func (p *fastParser) ParseAll() ([]JSONValue, error) {
+	if p.fast() {
+		var values []JSONValue
+		var err error
+		for p.next < len(p.index) && err == nil {
+			var value JSONValue
+			if value, err = p.value(); err == nil {
+				values = append(values, value)
+			}
+		}
+		if err == nil {
+			p.consumed += len(values)
+			return values, nil
+		}
+	}
+	return p.standard().ParseAll()
+}
+
+// ParseDocument implements Parser. Documents are always parsed by the
+// standard parser, which collects their warnings and metrics.
+func (p *fastParser) ParseDocument() (*Document, error) {
+	return p.standard().ParseDocument()
+}
+
+// fast reports whether the fast path can parse the rest of the input,
+// indexing it first if needed.
+func (p *fastParser) fast() bool {
+	if p.std != nil || p.arena != nil || p.normalize != 0 || p.reviver != nil || p.duplicateKeys != DuplicateKeysLast ||
+		p.maxMemory > 0 || p.ijson || p.maxBytes > 0 && len(p.input) > p.maxBytes {
+		return false
+	}
+	if !p.indexed {
+		// Strings are built from the input as is, so it must be valid UTF-8;
+		// the lexer replaces or rejects invalid bytes. A byte order mark is
+		// left to the standard parser's WithBOM policy.
+		if !utf8.ValidString(p.input) || strings.HasPrefix(p.input, "\uFEFF") {
+			return false
+		}
+		var ok bool
+		if p.index, ok = indexStructure(p.input, p.index); !ok {
+			return false
+		}
+		p.indexed = true
+	}
+	p.depth = 0
+	return true
+}
+
+// standard returns the standard parser, creating it on first use in the state
+// the fast path left off: past the values returned so far, which it parses
+// again.
+func (p *fastParser) standard() Parser {
+	if p.std != nil {
+		return p.std
+	}
+	var lexOpts []lexer.Option
+	if p.zeroCopy {
+		lexOpts = append(lexOpts, lexer.WithZeroCopy())
+	}
+	p.std = NewWithInput(lexer.New(p.input, lexOpts...), p.input, p.opts...)
+	for range p.consumed {
+		p.std.ParseValue()
+	}
+	return p.std
+}
+
+// Character classes for indexStructure.
+const (
+	classScalar = iota // starts or continues a number or keyword
+	classSpace
+	classStructural
+	classQuote
+)
+
+// charClasses maps bytes to their class.
+var charClasses = func() (classes [256]uint8) {
+	for _, c := range " \t\n\r" {
+		classes[c] = classSpace
+	}
+	for _, c := range "{}[]:," {
+		classes[c] = classStructural
+	}
+	classes['"'] = classQuote
+	return classes
+}()
+
+// indexStructure appends to index, in order, the offsets in input of the
+// structural characters {}[]:, outside strings, of both quotes of strings
+// and of the first characters of scalars. It reports false if a string
+// is unterminated or contains NUL, which ends the input for the lexer, or if
+// input is too long for the offsets.
+func indexStructure(input string, index []uint32) ([]uint32, bool) {
+	if len(input) > math.MaxUint32 {
+		return index, false
+	}
+	for i := 0; i < len(input); {
+		switch charClasses[input[i]] {
+		case classSpace:
+			// Indentation comes in runs of spaces; skip them eight at a time.
+			for i+8 <= len(input) && input[i:i+8] == "        " {
+				i += 8
+			}
+			for i < len(input) && charClasses[input[i]] == classSpace {
+				i++
+			}
+		case classStructural:
+			index = append(index, uint32(i))
+			i++
+		case classQuote:
+			end, ok := stringEnd(input, i+1)
+			if !ok {
+				return index, false
+			}
+			index = append(index, uint32(i), uint32(end))
+			i = end + 1
+		default:
+			index = append(index, uint32(i))
+			for i < len(input) && charClasses[input[i]] == classScalar {
+				i++
+			}
+		}
+	}
+	return index, true
+}
+
+// stringEnd returns the offset of the quote closing the string whose contents
+// start at start. It reports false if there is none or the contents include
+// control characters, which the lexer rejects or accepts depending on its
+// options.
+func stringEnd(input string, start int) (int, bool) {
+	for i := start; ; {
+		q := strings.IndexByte(input[i:], '"')
+		if q < 0 {
+			return 0, false
+		}
+		end := i + q
+		// The quote is escaped if an odd number of backslashes precede it.
+		backslashes := 0
+		for end-backslashes-1 >= start && input[end-backslashes-1] == '\\' {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			return end, !hasControlCharacter(input[start:end])
+		}
+		i = end + 1
+	}
+}
+
+// hasControlCharacter reports whether s contains a byte below 0x20.
+func hasControlCharacter(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 {
+			return true
+		}
+	}
+	return false
+}
+
+// current returns the character at the current entry of the index, or 0 at
+// the end of the input.
+func (p *fastParser) current() byte {
+	if p.next == len(p.index) {
+		return 0
+	}
+	return p.input[p.index[p.next]]
+}
+
+// value builds the value starting at the current entry of the index.
+func (p *fastParser) value() (JSONValue, error) {
+	switch p.current() {
+	case '{':
+		return p.object()
+	case '[':
+		return p.array()
+	case '"':
+		return p.string()
+	case 0, '}', ']', ':', ',':
+		return nil, errFallback
+	default:
+		return p.scalar()
+	}
+}
+
+// enter is called when an object or array opens. It fails if the container
+// would exceed the maximum depth, see WithMaxDepth.
+func (p *fastParser) enter() error {
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		return errFallback
+	}
+	p.depth++
+	p.next++
+	return nil
+}
+
+// object builds the object starting at the current entry.
+func (p *fastParser) object() (JSONValue, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	if p.current() == '}' {
+		p.next++
+		return NewJSONObject(), nil
+	}
+
+	// Members are staged on a shared stack and the object is created once
+	// they are all known, with room for exactly as many.
+	start := len(p.members)
+	defer func() {
+		clear(p.members[start:])
+		p.members = p.members[:start]
+	}()
+	for {
+		if p.current() != '"' {
+			return nil, errFallback
+		}
+		key, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		if p.current() != ':' {
+			return nil, errFallback
+		}
+		p.next++
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		p.members = append(p.members, member{key, value})
+
+		switch p.current() {
+		case '}':
+			p.next++
+			return p.newObject(start), nil
+		case ',':
+			p.next++
+			if p.trailingCommas && p.current() == '}' {
+				p.next++
+				return p.newObject(start), nil
+			}
+		default:
+			return nil, errFallback
+		}
+	}
+}
+
+// array builds the array starting at the current entry.
+func (p *fastParser) array() (JSONValue, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	if p.current() == ']' {
+		p.next++
+		return []any(nil), nil
+	}
+
+	// As with objects, elements are staged and copied into an exactly sized
+	// slice.
+	start := len(p.elements)
+	defer func() {
+		clear(p.elements[start:])
+		p.elements = p.elements[:start]
+	}()
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		p.elements = append(p.elements, value)
+
+		switch p.current() {
+		case ']':
+			p.next++
+			return slices.Clone(p.elements[start:]), nil
+		case ',':
+			p.next++
+			if p.trailingCommas && p.current() == ']' {
+				p.next++
+				return slices.Clone(p.elements[start:]), nil
+			}
+		default:
+			return nil, errFallback
+		}
+	}
+}
+
+// newObject creates the object of the members staged from start.
+func (p *fastParser) newObject(start int) JSONObject {
+	obj := make(JSONObject, len(p.members)-start)
+	for _, m := range p.members[start:] {
+		obj[m.key] = m.value
+	}
+	return obj
+}
+
+// string builds the string whose quotes are the current and the next entry.
+func (p *fastParser) string() (string, error) {
+	start, end := int(p.index[p.next])+1, int(p.index[p.next+1])
+	p.next += 2
+
+	raw := p.input[start:end]
+	if strings.IndexByte(raw, '\\') < 0 {
+		if p.maxStringLen > 0 && len(raw) > p.maxStringLen {
+			return "", errFallback
+		}
+		if p.zeroCopy {
+			return raw, nil
+		}
+		return strings.Clone(raw), nil
+	}
+
+	value, ok := unescape(p.buf[:0], raw)
+	p.buf = value
+	if !ok || p.maxStringLen > 0 && len(value) > p.maxStringLen {
+		return "", errFallback
+	}
+	return string(value), nil
+}
+
+// unescape appends the contents of a string with escape sequences to buf,
+// decoding them like the lexer. It reports false for invalid escapes.
+func unescape(buf []byte, raw string) ([]byte, bool) {
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' {
+			buf = append(buf, c)
+			continue
+		}
+		if i++; i == len(raw) {
+			return buf, false
+		}
+		switch raw[i] {
+		case '"', '\\', '/':
+			buf = append(buf, raw[i])
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			codePoint, ok := hex4(raw, i+1)
+			if !ok {
+				return buf, false
+			}
+			i += 4
+			// A high surrogate followed by an escaped low one is a pair; other
+			// surrogates decode to U+FFFD, as in the lexer.
+			if utf16.IsSurrogate(codePoint) && strings.HasPrefix(raw[i+1:], `\u`) {
+				if low, ok := hex4(raw, i+3); ok {
+					if r := utf16.DecodeRune(codePoint, low); r != utf8.RuneError {
+						codePoint = r
+						i += 6
+					}
+				}
+			}
+			buf = utf8.AppendRune(buf, codePoint)
+		default:
+			return buf, false
+		}
+	}
+	return buf, true
+}
+
+// hex4 decodes the four hex digits of a Unicode escape starting at raw[i].
+func hex4(raw string, i int) (rune, bool) {
+	if i+4 > len(raw) {
+		return 0, false
+	}
+	var codePoint rune
+	for _, digit := range []byte(raw[i : i+4]) {
+		codePoint <<= 4
+		switch {
+		case digit >= '0' && digit <= '9':
+			codePoint += rune(digit - '0')
+		case digit >= 'A' && digit <= 'F':
+			codePoint += rune(digit - 'A' + 10)
+		case digit >= 'a' && digit <= 'f':
+			codePoint += rune(digit - 'a' + 10)
+		default:
+			return 0, false
+		}
+	}
+	return codePoint, true
+}
+
+// scalar builds the number or keyword starting at the current entry.
+func (p *fastParser) scalar() (JSONValue, error) {
+	text := p.scalarText()
+	switch text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if !isNumber(text) {
+		return nil, errFallback
+	}
+	value, _, failure := p.convertNumber(text)
+	if failure != "" {
+		return nil, errFallback // reported by the standard parser
+	}
+	return value, nil
+}
+
+// scalarText returns the text of the number or keyword starting at the
+// current entry and moves past it.
+func (p *fastParser) scalarText() string {
+	start := int(p.index[p.next])
+	end := start
+	for end < len(p.input) && charClasses[p.input[end]] == classScalar {
+		end++
+	}
+	p.next++
+	return p.input[start:end]
+}
+
+// isNumber reports whether text is a JSON number:
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func isNumber(text string) bool {
+	i := 0
+	if i < len(text) && text[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(text) && text[i] == '0':
+		i++
+	case i < len(text) && text[i] >= '1' && text[i] <= '9':
+		i = skipDigits(text, i)
+	default:
+		return false
+	}
+	if i < len(text) && text[i] == '.' {
+		if i++; i == len(text) || !isDigit(text[i]) {
+			return false
+		}
+		i = skipDigits(text, i)
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		if i++; i < len(text) && (text[i] == '+' || text[i] == '-') {
+			i++
+		}
+		if i == len(text) || !isDigit(text[i]) {
+			return false
+		}
+		i = skipDigits(text, i)
+	}
+	return i == len(text)
+}
+
+// skipDigits returns the offset of the first non-digit in text from i.
+func skipDigits(text string, i int) int {
+	for i < len(text) && isDigit(text[i]) {
+		i++
+	}
+	return i
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
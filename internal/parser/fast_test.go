@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// sameResult fails t unless the value and error match those of the standard
+// parser.
+func sameResult(t *testing.T, value, expected JSONValue, err, expectedErr error) {
+	t.Helper()
+	if (err != nil) != (expectedErr != nil) || err != nil && err.Error() != expectedErr.Error() {
+		t.Fatalf("expected error %v, got %v", expectedErr, err)
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("expected %#v, got %#v", expected, value)
+	}
+}
+
+func TestNewFast(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{name: "object", input: `{"name": "test", "items": [1, -2.5, 3e2, "four", true, false, null], "nested": {"a": {"b": []}}}`},
+		{name: "empty containers", input: `[{}, [], [[]], {"a": {}}]`},
+		{name: "indented", input: "{\n        \"a\": [\n                1,\n                2\n        ]\n}\n"},
+		{name: "escapes", input: `["a\"b", "\\", "\/", "\b\f\n\r\t", "é中", "😀", "x\\"]`},
+		{name: "unicode escapes", input: `["\u00e9\u4E2D", "\uD83D\uDE00", "\uD83D", "\uDE00\uD83D", "\uD83D\u0041", "\uD800\uD83D\uDE00", "\uD83D\"]`},
+		{name: "escaped quote before end", input: `{"k\\\"": "v\\\\"}`},
+		{name: "large numbers", input: `[9223372036854775807, 9223372036854775808, -0, 0.1, 1E-7, 1e+2]`},
+		{name: "duplicate keys", input: `{"a": 1, "a": 2}`},
+		{name: "top-level scalar", input: ` "only" `},
+		{name: "utf-8", input: `{"日本": "語", "emoji": "😀"}`},
+		{name: "control character", input: "[\"a\tb\"]"},
+		{name: "invalid UTF-8", input: "{\"k\xff\": \"a\xe4\xb8b\"}"},
+		{name: "byte order mark", input: "\uFEFF[1]"},
+		{name: "rejected byte order mark", input: "\uFEFF[1]", opts: []Option{WithBOM(BOMError)}},
+		{name: "raw numbers", input: `[1.50, 1e3, 7]`, opts: []Option{WithRawNumbers()}},
+		{name: "trailing commas", input: `{"a": [1, 2,], "b": {"c": 1,},}`, opts: []Option{WithTrailingCommas()}},
+		{name: "zero copy", input: `{"a": "b", "c": "d\n"}`, opts: []Option{WithZeroCopy()}},
+		{name: "within limits", input: `[[1]]`, opts: []Option{WithMaxDepth(2), WithMaxBytes(5), WithMaxStringLength(1)}},
+
+		{name: "empty", input: ""},
+		{name: "whitespace", input: "  \n"},
+		{name: "trailing comma", input: `[1, 2,]`},
+		{name: "missing comma", input: `[1 2]`},
+		{name: "missing colon", input: `{"a" 1}`},
+		{name: "non-string key", input: `{1: 2}`},
+		{name: "unterminated string", input: `["abc`},
+		{name: "unterminated array", input: `[1, 2`},
+		{name: "unterminated object", input: `{"a": 1`},
+		{name: "invalid escape", input: `["\x"]`},
+		{name: "short unicode escape", input: `["\u12"]`},
+		{name: "invalid unicode escape", input: `["\u12G4"]`},
+		{name: "invalid low surrogate escape", input: `["\uD83D\uDE0"]`},
+		{name: "leading zero", input: `[01]`},
+		{name: "bare fraction", input: `[1.]`},
+		{name: "bare exponent", input: `[1e]`},
+		{name: "plus sign", input: `[+1]`},
+		{name: "out of range", input: `[1e400]`},
+		{name: "invalid keyword", input: `[nul]`},
+		{name: "keyword run", input: `[truefalse]`},
+		{name: "number then keyword", input: `[12true]`},
+		{name: "extra content", input: `{} []`},
+		{name: "comment", input: `[1 /* c */]`},
+		{name: "NUL in string", input: "[\"a\x00\"]"},
+		{name: "NUL ends input", input: "[1]\x00 garbage"},
+		{name: "too deep", input: `[[[1]]]`, opts: []Option{WithMaxDepth(2)}},
+		{name: "too large", input: `[1, 2, 3]`, opts: []Option{WithMaxBytes(4)}},
+		{name: "string too long", input: `["abc"]`, opts: []Option{WithMaxStringLength(2)}},
+		{name: "escaped string too long", input: `["\n\n\n"]`, opts: []Option{WithMaxStringLength(2)}},
+		{name: "duplicate key error", input: `{"a": 1, "a": 2}`, opts: []Option{WithDuplicateKeys(DuplicateKeysError, 0)}},
+		{name: "normalized", input: `{"é": "é"}`, opts: []Option{WithNFC(NormalizeKeys | NormalizeValues)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, expectedErr := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...).Parse()
+			value, err := NewFast(tt.input, tt.opts...).Parse()
+			sameResult(t, value, expected, err, expectedErr)
+		})
+	}
+}
+
+func TestNewFast_Options(t *testing.T) {
+	inputs := []string{
+		`{"a": [1, -2.5, 0.1, 1.50, 1e3, "x", true, null], "b": {}}`,
+		`[9223372036854775807, 9223372036854775808, -9223372036854775809, 18446744073709551615]`,
+		`[3.141592653589793238462643383279502884197, 1e-400]`,
+		`[1e400]`,
+		`[-1e400]`,
+		`[1e1001]`,
+		`{"a": 1, "a": [2,]}`,
+		`["a\tb", "\u00e9"]`,
+	}
+	options := map[string][]Option{
+		"default":           nil,
+		"raw numbers":       {WithRawNumbers()},
+		"big numbers":       {WithBigNumbers()},
+		"raw and big":       {WithRawNumbers(), WithBigNumbers()},
+		"overflow error":    {WithIntegerOverflow(OverflowError)},
+		"overflow saturate": {WithIntegerOverflow(OverflowSaturate)},
+		"overflow big":      {WithIntegerOverflow(OverflowBig)},
+		"overflow number":   {WithIntegerOverflow(OverflowNumber), WithRawNumbers()},
+		"overflow and big":  {WithIntegerOverflow(OverflowError), WithBigNumbers()},
+		"trailing commas":   {WithTrailingCommas()},
+		"zero copy":         {WithZeroCopy()},
+		"profile":           {WithProfile(ProfilePermissive)},
+		"i-json profile":    {WithProfile(ProfileIJSON)},
+	}
+
+	for name, opts := range options {
+		for _, input := range inputs {
+			t.Run(name+" "+input, func(t *testing.T) {
+				expected, expectedErr := NewWithInput(lexer.New(input), input, opts...).Parse()
+				value, err := NewFast(input, opts...).Parse()
+				sameResult(t, value, expected, err, expectedErr)
+			})
+		}
+	}
+}
+
+func TestNewFast_Files(t *testing.T) {
+	var files []string
+	for _, pattern := range []string{
+		filepath.Join("..", "..", "test", "testdata", "*.json"),
+		filepath.Join("..", "..", "test", "external", "test", "external", "json_org", "*.json"),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Skip("no test data")
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input := string(data)
+			expected, expectedErr := NewWithInput(lexer.New(input), input).Parse()
+			value, err := NewFast(input).Parse()
+			sameResult(t, value, expected, err, expectedErr)
+		})
+	}
+}
+
+func TestNewFast_ParseAll(t *testing.T) {
+	for _, input := range []string{
+		"{\"id\": 1}\n{\"id\": 2}\n",
+		`[1][2] "three" 4`,
+		`12true`,
+		`{"id": 1} {"id": }`,
+		"",
+	} {
+		t.Run(input, func(t *testing.T) {
+			expected, expectedErr := NewWithInput(lexer.New(input), input).ParseAll()
+			values, err := NewFast(input).ParseAll()
+			sameResult(t, values, expected, err, expectedErr)
+		})
+	}
+}
+
+func TestNewFast_ParseValue(t *testing.T) {
+	// The standard parser takes over after the fast path returned values.
+	input := `{"a": 1} [2] [3,] 4`
+	p := NewFast(input)
+	std := NewWithInput(lexer.New(input), input)
+	for range 4 {
+		expected, expectedErr := std.ParseValue()
+		value, err := p.ParseValue()
+		sameResult(t, value, expected, err, expectedErr)
+		if expectedErr != nil {
+			break
+		}
+	}
+}
+
+func TestNewFast_Reset(t *testing.T) {
+	p := NewFast(`[1, `)
+	if _, err := p.Parse(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	p.Reset(`{"b": [true]}`)
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatalf("unexpected error after Reset: %v", err)
+	}
+	if expected := (JSONObject{"b": []any{true}}); !reflect.DeepEqual(value, expected) {
+		t.Errorf("expected %v, got %v", expected, value)
+	}
+
+	doc, err := NewFast(`{"n": 1}`, WithChecks()).ParseDocument()
+	if err != nil || doc.Metrics.Values != 2 {
+		t.Errorf("expected a document with 2 values, got %+v, %v", doc, err)
+	}
+}
+
+func TestIndexStructure(t *testing.T) {
+	input := `{"a\"": [1, true]}`
+	index, ok := indexStructure(input, nil)
+	if !ok {
+		t.Fatal("expected the input to be indexed")
+	}
+	var got []string
+	for _, offset := range index {
+		got = append(got, input[offset:offset+1])
+	}
+	if expected := `{ " " : [ 1 , t ] }`; strings.Join(got, " ") != expected {
+		t.Errorf("expected %s, got %s", expected, strings.Join(got, " "))
+	}
+}
+
+func TestErrFallbackNotReturned(t *testing.T) {
+	inputs := []string{
+		`{"id": 123456789012345678901234567890}`,
+		`[1e400, -1e400]`,
+		`[1e1001]`,
+		`{"a": "\x"}`,
+		`{"a" 1}`,
+		`[1, 2,]`,
+		`[[[1]]]`,
+		`["a\tbc"]`,
+		`{"a": nul}`,
+		`{} []`,
+		"",
+	}
+	optionSets := [][]Option{
+		nil,
+		{WithIntegerOverflow(OverflowError)},
+		{WithBigNumbers()},
+		{WithMaxDepth(2), WithMaxStringLength(3)},
+		{WithTrailingCommas()},
+	}
+
+	check := func(t *testing.T, api string, err error) {
+		t.Helper()
+		if errors.Is(err, errFallback) {
+			t.Errorf("%s returned errFallback", api)
+		}
+	}
+	for _, input := range inputs {
+		for _, opts := range optionSets {
+			_, err := NewFast(input, opts...).Parse()
+			check(t, "Parse", err)
+			_, err = NewFast(input, opts...).ParseValue()
+			check(t, "ParseValue", err)
+			_, err = NewFast(input, opts...).ParseAll()
+			check(t, "ParseAll", err)
+
+			doc, err := ParseLazy(input, opts...)
+			check(t, "ParseLazy", err)
+			if err != nil {
+				continue
+			}
+			for _, path := range []string{"", "/id", "/0", "/a"} {
+				_, _, err := doc.Get(path)
+				check(t, "Get", err)
+			}
+		}
+	}
+
+	// Values Get can't build from the tape are built by the standard parser.
+	doc, err := ParseLazy(`{"a": "abc"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc.fast.maxStringLen = 1
+	if value, found, err := doc.Get("/a"); value != "abc" || !found || err != nil {
+		t.Errorf("expected \"abc\", got %#v, %v, %v", value, found, err)
+	}
+}
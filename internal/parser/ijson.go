@@ -0,0 +1,76 @@
+package parser
+
+import "github.com/VuNe/json-parser/internal/lexer"
+
+// maxSafeInteger is the largest integer n such that n and n+1 are both
+// exactly representable as IEEE 754 doubles, 2^53 - 1.
+const maxSafeInteger = 1<<53 - 1
+
+// WithIJSON restricts documents to I-JSON (RFC 7493), the profile of JSON
+// for messages that every implementation reads the same way. On top of RFC
+// 8259 it rejects:
+//
+//   - duplicate object keys, as with DuplicateKeysError
+//   - numbers an IEEE 754 double can't hold as written: integers beyond
+//     ±(2^53 - 1) and other numbers with more precision or magnitude
+//   - strings and keys containing Unicode noncharacters, such as U+FFFF
+//   - top-level values other than objects and arrays
+//
+// Strings must also be valid UTF-8 without lone surrogates, which is the
+// lexer's to enforce: combine WithIJSON with lexer.WithStrictUTF8 and
+// lexer.WithStrictSurrogates.
+func WithIJSON() Option {
+	return func(c *config) {
+		c.ijson = true
+		c.duplicateKeys = DuplicateKeysError
+	}
+}
+
+// checkIJSONTopLevel fails, with WithIJSON, if the current token doesn't
+// start an object or array.
+func (p *parser) checkIJSONTopLevel() error {
+	if !p.ijson || p.depth > 0 {
+		return nil
+	}
+	switch p.currentToken.Type {
+	case lexer.LEFT_BRACE, lexer.LEFT_BRACKET, lexer.EOF, lexer.INVALID:
+		return nil
+	}
+	return p.newSemanticError(MsgTopLevelScalar, MsgSuggestWrapValue)
+}
+
+// checkIJSONNumber fails, with WithIJSON, if value, parsed from the current
+// token, isn't the number as written or is an integer beyond the range
+// doubles hold exactly.
+func (p *parser) checkIJSONNumber(value JSONValue) error {
+	if !p.ijson {
+		return nil
+	}
+	text := p.currentToken.Value
+	switch n := value.(type) {
+	case int64:
+		if n >= -maxSafeInteger && n <= maxSafeInteger {
+			return nil
+		}
+	case float64:
+		if exactFloat(text, n) {
+			return nil
+		}
+	}
+	return p.newSemanticError(MsgImpreciseNumber, MsgSuggestQuoteNumber, text)
+}
+
+// checkIJSONString fails, with WithIJSON, if the current token, a string,
+// contains a Unicode noncharacter: U+FDD0 to U+FDEF, or the last two code
+// points of a plane, such as U+FFFE and U+FFFF.
+func (p *parser) checkIJSONString() error {
+	if !p.ijson {
+		return nil
+	}
+	for _, r := range p.currentToken.Value {
+		if r >= 0xFDD0 && r <= 0xFDEF || r&0xFFFE == 0xFFFE {
+			return p.newSemanticError(MsgNoncharacter, MsgSuggestRemoveNoncharacter, r)
+		}
+	}
+	return nil
+}
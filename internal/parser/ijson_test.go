@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithIJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantKey MessageKey // key of the error, or "" for success
+	}{
+		{name: "object", input: `{"a": [1, -2.5, 0.1, 9007199254740991, -9007199254740991, 1e300, "x\uFFFD"]}`},
+		{name: "array", input: `[]`},
+		{name: "top-level string", input: `"a"`, wantKey: MsgTopLevelScalar},
+		{name: "top-level number", input: `42`, wantKey: MsgTopLevelScalar},
+		{name: "top-level null", input: `null`, wantKey: MsgTopLevelScalar},
+		{name: "duplicate key", input: `{"a": 1, "a": 2}`, wantKey: MsgDuplicateKey},
+		{name: "unsafe integer", input: `[9007199254740992]`, wantKey: MsgImpreciseNumber},
+		{name: "unsafe negative integer", input: `[-9007199254740992]`, wantKey: MsgImpreciseNumber},
+		{name: "beyond int64", input: `[18446744073709551616]`, wantKey: MsgImpreciseNumber},
+		{name: "too precise", input: `[3.141592653589793238462643383279]`, wantKey: MsgImpreciseNumber},
+		{name: "noncharacter", input: `["\uFFFF"]`, wantKey: MsgNoncharacter},
+		{name: "noncharacter in key", input: `{"\uFDD0": 1}`, wantKey: MsgNoncharacter},
+		{name: "supplementary noncharacter", input: `["\uD83F\uDFFE"]`, wantKey: MsgNoncharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithInput(lexer.New(tt.input), tt.input, WithIJSON()).Parse()
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Key != tt.wantKey {
+				t.Errorf("expected an error with the key %q, got %v", tt.wantKey, err)
+			}
+		})
+	}
+}
+
+func TestParser_WithIJSON_ErrorPosition(t *testing.T) {
+	input := "{\n  \"a\": 1,\n  \"b\": 12345678901234567890\n}"
+	_, err := NewWithInput(lexer.New(input), input, WithIJSON()).Parse()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Position.Line != 3 || parseErr.Position.Column != 8 {
+		t.Errorf("expected the error at line 3, column 8, got %s", parseErr.Position)
+	}
+	if want := "number 12345678901234567890 exceeds the precision of an IEEE 754 double"; parseErr.Message != want {
+		t.Errorf("expected message %q, got %q", want, parseErr.Message)
+	}
+}
+
+func TestParser_WithIJSON_Fast(t *testing.T) {
+	input := `{"a": [9007199254740993]}`
+	if _, err := NewFast(input, WithIJSON(), WithDuplicateKeys(DuplicateKeysLast, 0)).Parse(); err == nil {
+		t.Error("expected NewFast to apply WithIJSON")
+	}
+}
@@ -0,0 +1,276 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// LazyDocument is a parsed document whose values are built only when they are
+// accessed with Get, for callers that extract a few values from a large
+// document and have no use for the rest of its tree. It keeps a compact tape
+// of the document: the structural index of NewFast, an offset into the input
+// per token, and for each object and array the tape entry following it, so
+// that lookups skip over the members and elements that aren't on the path.
+//
+// A LazyDocument refers to its input and is safe for concurrent use.
+type LazyDocument struct {
+	fast  fastParser // the input, its index and the options values are built with
+	ends  []uint32   // for the entries opening objects and arrays, the entry after their end
+	value JSONValue  // the whole document, when it was built up front
+	built bool
+}
+
+// ParseLazy checks that input is a single JSON document, like
+//
+//	NewWithInput(lexer.New(input), input, opts...).Parse()
+//
+// returning the same errors, and records its tape, but builds none of its
+// values: see LazyDocument. The options apply to the values Get builds. With
+// the options NewFast hands over to the standard parser, and for input it
+// doesn't index, the whole document is built up front instead.
+func ParseLazy(input string, opts ...Option) (*LazyDocument, error) {
+	d := &LazyDocument{fast: fastParser{config: newConfig(opts), opts: opts, input: input}}
+	if d.fast.fast() {
+		d.ends = make([]uint32, len(d.fast.index))
+		if err := d.skip(); err == nil && d.fast.next == len(d.fast.index) {
+			return d, nil
+		}
+	}
+
+	value, err := d.fast.standard().Parse()
+	if err != nil {
+		return nil, err
+	}
+	d.value, d.built = value, true
+	return d, nil
+}
+
+// skip checks the value starting at the current entry of the tape without
+// building it, records the ends of its objects and arrays and moves past it.
+func (d *LazyDocument) skip() error {
+	p := &d.fast
+	start := p.next
+	switch c := p.current(); c {
+	case '{', '[':
+		if err := p.enter(); err != nil {
+			return err
+		}
+		err := d.skipContents(c == '{')
+		p.depth--
+		d.ends[start] = uint32(p.next)
+		return err
+	case '"':
+		return d.skipString()
+	case 0, '}', ']', ':', ',':
+		return errFallback
+	default:
+		switch text := p.scalarText(); text {
+		case "true", "false", "null":
+		default:
+			// Numbers are converted like Get will, so that those it can't
+			// build, such as integers overflowing with OverflowError, are
+			// reported by the standard parser now.
+			if !isNumber(text) {
+				return errFallback
+			}
+			if _, _, failure := p.convertNumber(text); failure != "" {
+				return errFallback
+			}
+		}
+	}
+	return nil
+}
+
+// skipContents is skip for the members of an object or the elements of an
+// array, up to and including its end.
+func (d *LazyDocument) skipContents(object bool) error {
+	p := &d.fast
+	end := byte(']')
+	if object {
+		end = '}'
+	}
+	if p.current() == end {
+		p.next++
+		return nil
+	}
+	for {
+		if object {
+			if p.current() != '"' {
+				return errFallback
+			}
+			if err := d.skipString(); err != nil {
+				return err
+			}
+			if p.current() != ':' {
+				return errFallback
+			}
+			p.next++
+		}
+		if err := d.skip(); err != nil {
+			return err
+		}
+
+		switch p.current() {
+		case end:
+			p.next++
+			return nil
+		case ',':
+			p.next++
+			if p.trailingCommas && p.current() == end {
+				p.next++
+				return nil
+			}
+		default:
+			return errFallback
+		}
+	}
+}
+
+// skipString checks the string whose quotes are the current and the next
+// entry of the tape and moves past it.
+func (d *LazyDocument) skipString() error {
+	p := &d.fast
+	raw := p.input[p.index[p.next]+1 : p.index[p.next+1]]
+	p.next += 2
+
+	length := len(raw)
+	if strings.IndexByte(raw, '\\') >= 0 {
+		value, ok := unescape(p.buf[:0], raw)
+		if p.buf = value; !ok {
+			return errFallback
+		}
+		length = len(value)
+	}
+	if p.maxStringLen > 0 && length > p.maxStringLen {
+		return errFallback
+	}
+	return nil
+}
+
+// Get returns the value at path, a JSON Pointer (RFC 6901), building it
+// from the tape; "" returns the whole document. found is false if there is
+// no such value. As with the parser, a name selects the last member with that
+// name. err is only set for invalid pointers.
+func (d *LazyDocument) Get(path string) (value JSONValue, found bool, err error) {
+	tokens, err := pointer.Split(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if d.built {
+		value, found = lookup(d.value, tokens)
+		return value, found, nil
+	}
+
+	p := d.fast // each call builds with its own position and buffers
+	p.next, p.depth = 0, 0
+	p.buf, p.elements, p.members = nil, nil, nil
+	for _, token := range tokens {
+		if !d.enter(&p, token) {
+			return nil, false, nil
+		}
+	}
+	if value, err = p.value(); err != nil {
+		// As with NewFast, what the fast path can't build is left to the
+		// standard parser.
+		p.std, p.consumed = nil, 0
+		whole, err := p.standard().Parse()
+		if err != nil {
+			return nil, false, nil
+		}
+		value, found = lookup(whole, tokens)
+		return value, found, nil
+	}
+	return value, true, nil
+}
+
+// enter moves p from the entry opening an object or array to the entry
+// starting its member or element token. It reports false if there is none.
+func (d *LazyDocument) enter(p *fastParser, token string) bool {
+	index, input := d.fast.index, d.fast.input
+	i := p.next
+	switch input[index[i]] {
+	case '{':
+		found := false
+		for i++; input[index[i]] != '}'; {
+			// i and i+1 are the key's quotes, i+2 the colon.
+			if p.keyIs(input[index[i]+1:index[i+1]], token) {
+				p.next, found = i+3, true
+			}
+			if i = d.after(i + 3); input[index[i]] == ',' {
+				i++
+			}
+		}
+		return found
+	case '[':
+		n, ok := arrayIndex(token)
+		if !ok {
+			return false
+		}
+		for i++; input[index[i]] != ']'; n-- {
+			if n == 0 {
+				p.next = i
+				return true
+			}
+			if i = d.after(i); input[index[i]] == ',' {
+				i++
+			}
+		}
+	}
+	return false
+}
+
+// after returns the tape entry following the value starting at entry i.
+func (d *LazyDocument) after(i int) int {
+	switch d.fast.input[d.fast.index[i]] {
+	case '{', '[':
+		return int(d.ends[i])
+	case '"':
+		return i + 2
+	default:
+		return i + 1
+	}
+}
+
+// keyIs reports whether the key with the contents raw, as written in the
+// input, is name.
+func (p *fastParser) keyIs(raw, name string) bool {
+	if strings.IndexByte(raw, '\\') < 0 {
+		return raw == name
+	}
+	p.buf, _ = unescape(p.buf[:0], raw)
+	return string(p.buf) == name
+}
+
+// lookup returns the value at the reference tokens path in value.
+func lookup(value JSONValue, path []string) (JSONValue, bool) {
+	for _, token := range path {
+		if obj, ok := AsObject(value); ok {
+			if value, ok = obj[token]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		arr, ok := value.([]any)
+		if !ok {
+			return nil, false
+		}
+		n, ok := arrayIndex(token)
+		if !ok || n >= len(arr) {
+			return nil, false
+		}
+		value = arr[n]
+	}
+	return value, true
+}
+
+// arrayIndex parses an array index reference token, which has no leading
+// zeros (RFC 6901, section 4).
+func arrayIndex(token string) (int, bool) {
+	if token == "" || len(token) > 1 && token[0] == '0' || strings.Trim(token, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(token)
+	return n, err == nil
+}
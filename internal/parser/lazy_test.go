@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestLazyDocument_Get(t *testing.T) {
+	input := `{
+		"users": [
+			{"name": "Alice", "tags": ["admin", "dev"], "address": {"city": "Paris"}},
+			{"name": "Bob", "tags": [], "address": null}
+		],
+		"a/b": 1, "m~n": 2, "escaped": 3, "": 4,
+		"dup": 1, "dup": 2,
+		"count": 2
+	}`
+	doc, err := ParseLazy(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected JSONValue
+		found    bool
+	}{
+		{path: "/count", expected: int64(2), found: true},
+		{path: "/users/1/name", expected: "Bob", found: true},
+		{path: "/users/0/tags/1", expected: "dev", found: true},
+		{path: "/users/0/address", expected: JSONObject{"city": "Paris"}, found: true},
+		{path: "/users/1/tags", expected: []any(nil), found: true},
+		{path: "/users/1/address", expected: nil, found: true},
+		{path: "/a~1b", expected: int64(1), found: true},
+		{path: "/m~0n", expected: int64(2), found: true},
+		{path: "/escaped", expected: int64(3), found: true},
+		{path: "/", expected: int64(4), found: true},
+		{path: "/dup", expected: int64(2), found: true},
+		{path: "/missing"},
+		{path: "/users/2"},
+		{path: "/users/01"},
+		{path: "/users/-"},
+		{path: "/users/name"},
+		{path: "/count/0"},
+		{path: "/users/0/name/first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found, err := doc.Get(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tt.found || !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("expected %#v, %v, got %#v, %v", tt.expected, tt.found, value, found)
+			}
+		})
+	}
+
+	whole, found, err := doc.Get("")
+	expected, _ := NewWithInput(lexer.New(input), input).Parse()
+	if err != nil || !found || !reflect.DeepEqual(whole, expected) {
+		t.Errorf("expected the whole document %v, got %v, %v, %v", expected, whole, found, err)
+	}
+	if _, _, err := doc.Get("users"); err == nil {
+		t.Error("expected an error for a pointer without a leading '/'")
+	}
+}
+
+func TestParseLazy(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		path  string
+	}{
+		{name: "scalar", input: ` 42 `, path: ""},
+		{name: "raw numbers", input: `{"n": 1.50}`, opts: []Option{WithRawNumbers()}, path: "/n"},
+		{name: "trailing commas", input: `{"a": [1, 2,], "b": 3,}`, opts: []Option{WithTrailingCommas()}, path: "/a"},
+		{name: "normalized", input: `{"é": "é"}`, opts: []Option{WithNFC(NormalizeKeys | NormalizeValues)}, path: "/é"},
+		{name: "duplicate keys", input: `{"a": 1, "a": 2}`, opts: []Option{WithDuplicateKeys(DuplicateKeysWarn, 0)}, path: "/a"},
+		{name: "invalid UTF-8", input: "{\"a\": \"\xffb\"}", path: "/a"},
+		{name: "integer overflow as float", input: `{"id": 123456789012345678901234567890}`, opts: []Option{WithIntegerOverflow(OverflowFloat)}, path: "/id"},
+
+		{name: "empty", input: ""},
+		{name: "trailing comma", input: `[1, 2,]`},
+		{name: "missing colon", input: `{"a" 1}`},
+		{name: "invalid escape", input: `{"a": "\x"}`},
+		{name: "invalid number", input: `[01]`},
+		{name: "out of range", input: `[1e400]`},
+		{name: "negative out of range", input: `{"a": [-1e400]}`},
+		{name: "integer overflow", input: `{"id": 123456789012345678901234567890}`, opts: []Option{WithIntegerOverflow(OverflowError)}},
+		{name: "big number out of range", input: `[1e1001]`, opts: []Option{WithBigNumbers()}},
+		{name: "invalid keyword", input: `{"a": nul}`},
+		{name: "extra content", input: `{} []`},
+		{name: "too deep", input: `[[[1]]]`, opts: []Option{WithMaxDepth(2)}},
+		{name: "string too long", input: `["a\tbc"]`, opts: []Option{WithMaxStringLength(3)}},
+		{name: "duplicate key error", input: `{"a": 1, "a": 2}`, opts: []Option{WithDuplicateKeys(DuplicateKeysError, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, expectedErr := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...).Parse()
+			doc, err := ParseLazy(tt.input, tt.opts...)
+			if expectedErr != nil {
+				if err == nil || err.Error() != expectedErr.Error() {
+					t.Fatalf("expected error %v, got %v", expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tokens := []string{}
+			if tt.path != "" {
+				tokens = []string{tt.path[1:]}
+			}
+			expected, _ = lookup(expected, tokens)
+			value, found, err := doc.Get(tt.path)
+			if err != nil || !found || !reflect.DeepEqual(value, expected) {
+				t.Errorf("expected %#v, got %#v, %v, %v", expected, value, found, err)
+			}
+		})
+	}
+}
+
+func TestLazyDocument_Concurrent(t *testing.T) {
+	doc, err := ParseLazy(`{"items": [{"id": 0, "label": "a\nb"}, {"id": 1, "label": "c\td"}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for range 100 {
+				path := "/items/" + string(rune('0'+i%2))
+				value, found, err := doc.Get(path + "/label")
+				if err != nil || !found || value != []string{"a\nb", "c\td"}[i%2] {
+					t.Errorf("unexpected value %q, %v, %v", value, found, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,27 @@
+package parser
+
+// WithMaxMemory limits the values built for a document to approximately n
+// bytes of heap memory, counted with the per-value costs of EstimateSize as
+// strings, numbers, arrays and object members are built. Documents needing
+// more fail with a ParseError with the key MsgMaxMemory as soon as the count
+// passes the limit, before the rest of the document is built, protecting
+// services that parse untrusted payloads from documents that are small on
+// the wire but large in memory. The memory of the parser itself and of its
+// lexer isn't counted. n <= 0, the default, removes the limit.
+func WithMaxMemory(n int) Option {
+	return func(c *config) {
+		c.maxMemory = n
+	}
+}
+
+// charge adds n bytes to the memory of the document being parsed and fails
+// once it exceeds the limit, see WithMaxMemory.
+func (p *parser) charge(n int) error {
+	if p.maxMemory <= 0 {
+		return nil
+	}
+	if p.memory += n; p.memory > p.maxMemory {
+		return p.newSemanticError(MsgMaxMemory, MsgSuggestReduceMemory, p.maxMemory)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// MessageKey identifies a diagnostic message, suggestion or piece of the
+// error layout independently of its wording, so that it can be looked up in
+// a Catalog.
+type MessageKey string
+
+// Messages.
+const (
+	MsgUnexpectedEOF          MessageKey = "unexpected_eof"
+	MsgExtraContent           MessageKey = "extra_content"
+	MsgExpectedValue          MessageKey = "expected_value"
+	MsgExpectedObject         MessageKey = "expected_object"
+	MsgUnterminatedObject     MessageKey = "unterminated_object"
+	MsgExpectedStringKey      MessageKey = "expected_string_key"
+	MsgExpectedColon          MessageKey = "expected_colon"
+	MsgExpectedCommaOrBrace   MessageKey = "expected_comma_or_brace"
+	MsgExpectedArray          MessageKey = "expected_array"
+	MsgExpectedBracket        MessageKey = "expected_bracket"
+	MsgExpectedCommaOrBracket MessageKey = "expected_comma_or_bracket"
+	MsgTrailingComma          MessageKey = "trailing_comma"
+	MsgDuplicateKey           MessageKey = "duplicate_key"         // key
+	MsgDuplicateKeyMatches    MessageKey = "duplicate_key_matches" // key, matching key
+	MsgInvalidNumber          MessageKey = "invalid_number"
+	MsgLeadingZeros           MessageKey = "leading_zeros"
+	MsgMissingFractionDigits  MessageKey = "missing_fraction_digits"
+	MsgMissingExponentDigits  MessageKey = "missing_exponent_digits"
+	MsgInvalidBoolean         MessageKey = "invalid_boolean"
+	MsgInvalidNull            MessageKey = "invalid_null"
+	MsgInvalidKeyword         MessageKey = "invalid_keyword"
+	MsgUnterminatedString     MessageKey = "unterminated_string"
+	MsgControlCharacter       MessageKey = "control_character"
+	MsgInvalidEscape          MessageKey = "invalid_escape" // escaped character
+	MsgInvalidUnicodeEscape   MessageKey = "invalid_unicode_escape"
+	MsgMaxDepth               MessageKey = "max_depth"         // limit
+	MsgMaxBytes               MessageKey = "max_bytes"         // limit
+	MsgMaxStringLength        MessageKey = "max_string_length" // limit
+	MsgMaxMemory              MessageKey = "max_memory"        // limit
+	MsgByteOrderMark          MessageKey = "byte_order_mark"
+	MsgImpreciseNumber        MessageKey = "imprecise_number" // number
+	MsgNoncharacter           MessageKey = "noncharacter"     // code point
+	MsgTopLevelScalar         MessageKey = "top_level_scalar"
+	MsgIntegerOverflow        MessageKey = "integer_overflow"    // number
+	MsgNumberOutOfRange       MessageKey = "number_out_of_range" // number
+)
+
+// Suggestions.
+const (
+	MsgSuggestMissingColon        MessageKey = "suggest_missing_colon"
+	MsgSuggestMissingComma        MessageKey = "suggest_missing_comma"
+	MsgSuggestRemoveTrailingComma MessageKey = "suggest_remove_trailing_comma"
+	MsgSuggestCloseString         MessageKey = "suggest_close_string"
+	MsgSuggestEscapeCharacter     MessageKey = "suggest_escape_character"
+	MsgSuggestValidNumber         MessageKey = "suggest_valid_number"
+	MsgSuggestCloseObject         MessageKey = "suggest_close_object"
+	MsgSuggestCloseArray          MessageKey = "suggest_close_array"
+	MsgSuggestStringKey           MessageKey = "suggest_string_key"
+	MsgSuggestValidKeyword        MessageKey = "suggest_valid_keyword"
+	MsgSuggestDuplicateKey        MessageKey = "suggest_duplicate_key"
+	MsgSuggestRemoveExtraContent  MessageKey = "suggest_remove_extra_content"
+	MsgSuggestReduceNesting       MessageKey = "suggest_reduce_nesting"
+	MsgSuggestReduceSize          MessageKey = "suggest_reduce_size"
+	MsgSuggestShortenString       MessageKey = "suggest_shorten_string"
+	MsgSuggestReduceMemory        MessageKey = "suggest_reduce_memory"
+	MsgSuggestRemoveBOM           MessageKey = "suggest_remove_bom"
+	MsgSuggestQuoteNumber         MessageKey = "suggest_quote_number"
+	MsgSuggestRemoveNoncharacter  MessageKey = "suggest_remove_noncharacter"
+	MsgSuggestWrapValue           MessageKey = "suggest_wrap_value"
+)
+
+// Layout of ParseError.Error.
+const (
+	MsgErrorHeader   MessageKey = "error_header"   // error type, position, message
+	MsgPosition      MessageKey = "position"       // line, column
+	MsgExpectedFound MessageKey = "expected_found" // expected tokens, found token
+	MsgExpectedOr    MessageKey = "expected_or"    // separator of the expected tokens
+	MsgNear          MessageKey = "near"           // snippet
+	MsgSuggestion    MessageKey = "suggestion"     // suggestion
+	MsgLexicalError  MessageKey = "lexical_error"
+	MsgSyntaxError   MessageKey = "syntax_error"
+	MsgSemanticError MessageKey = "semantic_error"
+	MsgUnknownError  MessageKey = "unknown_error"
+)
+
+// Catalog maps message keys to fmt format strings in one language. Formats
+// receive the arguments documented next to their key, and may reorder them
+// with explicit indexes such as %[2]s. Keys missing from a catalog fall back
+// to English, so translations can be partial.
+type Catalog map[MessageKey]string
+
+// English is the default catalog, which ParseError.Error uses.
+var English = Catalog{
+	MsgUnexpectedEOF:          "unexpected end of input",
+	MsgExtraContent:           "unexpected content after JSON value",
+	MsgExpectedValue:          "expected JSON value",
+	MsgExpectedObject:         "expected '{'",
+	MsgUnterminatedObject:     "unterminated object",
+	MsgExpectedStringKey:      "expected string key",
+	MsgExpectedColon:          "expected ':'",
+	MsgExpectedCommaOrBrace:   "expected ',' or '}'",
+	MsgExpectedArray:          "expected '['",
+	MsgExpectedBracket:        "expected ']'",
+	MsgExpectedCommaOrBracket: "expected ',' or ']'",
+	MsgTrailingComma:          "trailing comma not allowed",
+	MsgDuplicateKey:           "duplicate key %q",
+	MsgDuplicateKeyMatches:    "duplicate key %q (matches %q)",
+	MsgInvalidNumber:          "invalid number format",
+	MsgLeadingZeros:           "numbers cannot have leading zeros",
+	MsgMissingFractionDigits:  "invalid number format: missing digits after decimal point",
+	MsgMissingExponentDigits:  "invalid number format: missing digits in exponent",
+	MsgInvalidBoolean:         "invalid boolean value",
+	MsgInvalidNull:            "invalid null value",
+	MsgInvalidKeyword:         "invalid keyword",
+	MsgUnterminatedString:     "unterminated string",
+	MsgControlCharacter:       "unescaped control character in string",
+	MsgInvalidEscape:          "invalid escape sequence '\\%c'",
+	MsgInvalidUnicodeEscape:   "invalid Unicode escape sequence",
+	MsgMaxDepth:               "nesting exceeds the maximum depth of %d",
+	MsgMaxBytes:               "document exceeds the maximum size of %d bytes",
+	MsgMaxStringLength:        "string exceeds the maximum length of %d bytes",
+	MsgMaxMemory:              "document exceeds the memory limit of %d bytes",
+	MsgByteOrderMark:          "byte order mark not allowed at the start of JSON text",
+	MsgImpreciseNumber:        "number %s exceeds the precision of an IEEE 754 double",
+	MsgNoncharacter:           "string contains the Unicode noncharacter %U",
+	MsgTopLevelScalar:         "top-level value must be an object or array",
+	MsgIntegerOverflow:        "integer %s overflows int64",
+	MsgNumberOutOfRange:       "number %s is out of range",
+
+	MsgSuggestMissingColon:        SuggestionMissingColon,
+	MsgSuggestMissingComma:        SuggestionMissingComma,
+	MsgSuggestRemoveTrailingComma: SuggestionRemoveTrailingComma,
+	MsgSuggestCloseString:         SuggestionCloseString,
+	MsgSuggestEscapeCharacter:     SuggestionEscapeCharacter,
+	MsgSuggestValidNumber:         SuggestionValidNumber,
+	MsgSuggestCloseObject:         SuggestionCloseObject,
+	MsgSuggestCloseArray:          SuggestionCloseArray,
+	MsgSuggestStringKey:           SuggestionStringKey,
+	MsgSuggestValidKeyword:        SuggestionValidKeyword,
+	MsgSuggestDuplicateKey:        SuggestionDuplicateKey,
+	MsgSuggestRemoveExtraContent:  SuggestionRemoveExtraContent,
+	MsgSuggestReduceNesting:       SuggestionReduceNesting,
+	MsgSuggestReduceSize:          SuggestionReduceSize,
+	MsgSuggestShortenString:       SuggestionShortenString,
+	MsgSuggestReduceMemory:        SuggestionReduceMemory,
+	MsgSuggestRemoveBOM:           SuggestionRemoveBOM,
+	MsgSuggestQuoteNumber:         SuggestionQuoteNumber,
+	MsgSuggestRemoveNoncharacter:  SuggestionRemoveNoncharacter,
+	MsgSuggestWrapValue:           SuggestionWrapValue,
+
+	MsgErrorHeader:   "%s error at %s: %s",
+	MsgPosition:      "line %d, column %d",
+	MsgExpectedFound: "Expected %s, but found %s",
+	MsgExpectedOr:    " or ",
+	MsgNear:          "Near: %s",
+	MsgSuggestion:    "Suggestion: %s",
+	MsgLexicalError:  "Lexical",
+	MsgSyntaxError:   "Syntax",
+	MsgSemanticError: "Semantic",
+	MsgUnknownError:  "Unknown",
+}
+
+// Format formats the message for key with args, falling back to English if
+// the catalog has no translation. It reports false for unknown keys.
+func (c Catalog) Format(key MessageKey, args ...any) (string, bool) {
+	format, ok := c[key]
+	if !ok {
+		if format, ok = English[key]; !ok {
+			return "", false
+		}
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+// catalogs is the registry of catalogs by language, English first as the
+// fallback.
+var catalogs = struct {
+	sync.RWMutex
+	tags     []language.Tag
+	catalogs []Catalog
+}{
+	tags:     []language.Tag{language.English},
+	catalogs: []Catalog{English},
+}
+
+// RegisterCatalog makes c the catalog for tag, replacing any catalog
+// registered for it before, so that CatalogFor can select it.
+func RegisterCatalog(tag language.Tag, c Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+	for i, registered := range catalogs.tags {
+		if registered == tag {
+			catalogs.catalogs[i] = c
+			return
+		}
+	}
+	catalogs.tags = append(catalogs.tags, tag)
+	catalogs.catalogs = append(catalogs.catalogs, c)
+}
+
+// CatalogFor returns the registered catalog that best matches the preferred
+// languages, given as BCP 47 tags or Accept-Language header values such as
+// "de-CH, fr;q=0.8", or English if none matches.
+func CatalogFor(preferred ...string) Catalog {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+	_, i := language.MatchStrings(language.NewMatcher(catalogs.tags), preferred...)
+	return catalogs.catalogs[i]
+}
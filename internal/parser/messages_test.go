@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"golang.org/x/text/language"
+)
+
+func TestParseError_Keys(t *testing.T) {
+	tests := []struct {
+		input         string
+		withSource    bool
+		expectedKey   MessageKey
+		expectedArgs  []any
+		suggestionKey MessageKey
+	}{
+		{input: `{"a" 1}`, expectedKey: MsgExpectedColon, suggestionKey: MsgSuggestMissingColon},
+		{input: `[1,]`, expectedKey: MsgTrailingComma, suggestionKey: MsgSuggestRemoveTrailingComma},
+		{input: ``, expectedKey: MsgUnexpectedEOF},
+		{input: `{} 1`, withSource: true, expectedKey: MsgExtraContent, suggestionKey: MsgSuggestRemoveExtraContent},
+		{input: `{"a": 1, "a": 2}`, withSource: true, expectedKey: MsgDuplicateKey, expectedArgs: []any{"a"}, suggestionKey: MsgSuggestDuplicateKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input), WithDuplicateKeys(DuplicateKeysError, 0))
+			if tt.withSource {
+				p = NewWithInput(lexer.New(tt.input), tt.input, WithDuplicateKeys(DuplicateKeysError, 0))
+			}
+			_, err := p.Parse()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if parseErr.Key != tt.expectedKey || parseErr.SuggestionKey != tt.suggestionKey {
+				t.Errorf("expected keys %s and %q, got %s and %q", tt.expectedKey, tt.suggestionKey, parseErr.Key, parseErr.SuggestionKey)
+			}
+			if len(parseErr.Args) != len(tt.expectedArgs) || (len(tt.expectedArgs) > 0 && parseErr.Args[0] != tt.expectedArgs[0]) {
+				t.Errorf("expected args %v, got %v", tt.expectedArgs, parseErr.Args)
+			}
+			if message, _ := English.Format(parseErr.Key, parseErr.Args...); message != parseErr.Message {
+				t.Errorf("expected message %q, got %q", message, parseErr.Message)
+			}
+		})
+	}
+}
+
+func TestParseError_Expected(t *testing.T) {
+	tests := []struct {
+		input      string
+		expected   []string
+		suggestion string
+	}{
+		{input: `{"a" 1}`, expected: []string{"':'"}, suggestion: SuggestionMissingColon},
+		{input: `{"a": 1,}`, expected: []string{"STRING"}, suggestion: SuggestionRemoveTrailingComma},
+		{input: `[1,]`, expected: []string{"value"}, suggestion: SuggestionRemoveTrailingComma},
+		{input: `{"a": 1 "b": 2}`, expected: []string{"','", "'}'"}, suggestion: SuggestionMissingComma},
+		{input: `[1 2]`, expected: []string{"','", "']'"}, suggestion: SuggestionMissingComma},
+		{input: `{1: 2}`, expected: []string{"STRING"}, suggestion: SuggestionStringKey},
+		{input: `[`, expected: []string{"value", "']'"}, suggestion: SuggestionCloseArray},
+		{input: `{"a": }`, expected: []string{"value"}},
+	}
+
+	for _, tt := range tests {
+		for _, p := range []Parser{New(lexer.New(tt.input)), NewWithInput(lexer.New(tt.input), tt.input)} {
+			_, err := p.Parse()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("%s: expected *ParseError, got %v", tt.input, err)
+			}
+			if !slices.Equal(parseErr.Expected, tt.expected) || parseErr.Suggestion != tt.suggestion {
+				t.Errorf("%s: expected %q and suggestion %q, got %q and %q", tt.input, tt.expected, tt.suggestion, parseErr.Expected, parseErr.Suggestion)
+			}
+		}
+	}
+}
+
+func TestParseError_Localize(t *testing.T) {
+	german := Catalog{
+		MsgErrorHeader:         "%[1]s-Fehler in %[2]s: %[3]s",
+		MsgPosition:            "Zeile %d, Spalte %d",
+		MsgSemanticError:       "Semantik",
+		MsgDuplicateKey:        "doppelter Schl\u00fcssel %q",
+		MsgSuggestion:          "Vorschlag: %s",
+		MsgSuggestDuplicateKey: "Entfernen oder benennen Sie den doppelten Schl\u00fcssel um",
+	}
+
+	input := `{"a": 1, "a": 2}`
+	_, err := NewWithInput(lexer.New(input), input, WithDuplicateKeys(DuplicateKeysError, 0)).Parse()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+
+	// "Near:" isn't translated, so it falls back to English.
+	expected := "Semantik-Fehler in Zeile 1, Spalte 10: doppelter Schl\u00fcssel \"a\"\n" +
+		"Near: 1| {\"a\": 1, \"a\": 2}\n            ^\n" +
+		"Vorschlag: Entfernen oder benennen Sie den doppelten Schl\u00fcssel um"
+	if got := parseErr.Localize(german); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if parseErr.Error() != parseErr.Localize(English) {
+		t.Errorf("expected Error to match the English localization, got %q", parseErr.Error())
+	}
+}
+
+func TestParseError_LocalizeWithoutKey(t *testing.T) {
+	err := ParseError{Type: LexicalError, Message: "custom problem", Position: lexer.Position{Line: 2, Column: 3}}
+	expected := "Lexikalischer Fehler: custom problem"
+	got := err.Localize(Catalog{MsgErrorHeader: "%[1]s Fehler: %[3]s", MsgLexicalError: "Lexikalischer"})
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestCatalogFor(t *testing.T) {
+	french := Catalog{MsgExpectedColon: "':' attendu"}
+	RegisterCatalog(language.French, french)
+
+	tests := []struct {
+		preferred []string
+		expected  string
+	}{
+		{preferred: nil, expected: "expected ':'"},
+		{preferred: []string{"fr-CA"}, expected: "':' attendu"},
+		{preferred: []string{"ja, fr;q=0.5"}, expected: "':' attendu"},
+		{preferred: []string{"ja"}, expected: "expected ':'"},
+	}
+
+	for _, tt := range tests {
+		message, _ := CatalogFor(tt.preferred...).Format(MsgExpectedColon)
+		if message != tt.expected {
+			t.Errorf("CatalogFor(%q): expected %q, got %q", tt.preferred, tt.expected, message)
+		}
+	}
+}
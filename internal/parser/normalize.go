@@ -0,0 +1,31 @@
+package parser
+
+import "golang.org/x/text/unicode/norm"
+
+// Normalization selects which decoded strings WithNFC normalizes.
+type Normalization int
+
+const (
+	NormalizeKeys   Normalization = 1 << iota // object keys
+	NormalizeValues                           // string values
+)
+
+// WithNFC normalizes the selected strings to Unicode Normalization Form C
+// while parsing, so text that looks identical compares equal regardless of
+// its source. For example, macOS filenames spell "é" as "e" followed by a
+// combining accent, while most other sources use the single precomposed
+// character. Keys that become equal after normalization collapse into one
+// member according to the duplicate key policy (see WithDuplicateKeys).
+func WithNFC(what Normalization) Option {
+	return func(c *config) {
+		c.normalize = what
+	}
+}
+
+// normalized returns s in NFC if strings of the given kind are normalized.
+func (p *parser) normalized(s string, kind Normalization) string {
+	if p.normalize&kind == 0 {
+		return s
+	}
+	return norm.NFC.String(s)
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithNFC(t *testing.T) {
+	// "é" spelled as "e" + combining acute accent, as in macOS filenames.
+	input := `{"cafe\u0301": "cre\u0300me", "list": ["nai\u0308ve"]}`
+	const (
+		decomposedKey = "cafe\u0301"
+		composedKey   = "caf\u00e9"
+	)
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected JSONValue
+	}{
+		{
+			name:     "not normalized by default",
+			expected: JSONObject{decomposedKey: "cre\u0300me", "list": []any{"nai\u0308ve"}},
+		},
+		{
+			name:     "keys",
+			opts:     []Option{WithNFC(NormalizeKeys)},
+			expected: JSONObject{composedKey: "cre\u0300me", "list": []any{"nai\u0308ve"}},
+		},
+		{
+			name:     "values",
+			opts:     []Option{WithNFC(NormalizeValues)},
+			expected: JSONObject{decomposedKey: "cr\u00e8me", "list": []any{"na\u00efve"}},
+		},
+		{
+			name:     "keys and values",
+			opts:     []Option{WithNFC(NormalizeKeys | NormalizeValues)},
+			expected: JSONObject{composedKey: "cr\u00e8me", "list": []any{"na\u00efve"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := New(lexer.New(input), tt.opts...).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+q, got %+q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParser_WithNFC_CollapsesKeys(t *testing.T) {
+	input := `{"cafe\u0301": 1, "caf\u00e9": 2}`
+	result, err := New(lexer.New(input), WithNFC(NormalizeKeys)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := JSONObject{"caf\u00e9": int64(2)}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+q, got %+q", expected, result)
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Number is the literal text of a JSON number, as produced by the parser
+// with WithRawNumbers. The encoder writes it back verbatim, so formatting a
+// document keeps "1e3" and "1.50" instead of rewriting them as 1000 and 1.5.
+type Number string
+
+// String returns the literal text of the number.
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Value returns the number as the int64 or float64 the parser produces
+// without WithRawNumbers.
+func (n Number) Value() (JSONValue, error) {
+	return ParseNumber(string(n))
+}
+
+// WithRawNumbers makes the parser return numbers as Number, keeping their
+// exact text, instead of converting them to int64 or float64. Numbers
+// written with lexer.WithNumberExtensions syntax, which JSON can't
+// represent, are still converted.
+func WithRawNumbers() Option {
+	return func(c *config) {
+		c.rawNumbers = true
+	}
+}
+
+// rawNumber returns the Number for text, which ParseNumber accepted, if
+// numbers are kept raw and text is plain JSON.
+func (c *config) rawNumber(text string) (Number, bool) {
+	if !c.rawNumbers || strings.ContainsAny(text, "xXbB_") {
+		return "", false
+	}
+	return Number(text), true
+}
+
+// convertNumber returns the value of text, the text of a NUMBER token, under
+// the number options: WithRawNumbers, WithIntegerOverflow and
+// WithBigNumbers, in that order of precedence. parsed is what ParseNumber
+// returned for text, nil if it failed. A non-empty failure is the key of the
+// error for text: MsgInvalidNumber if ParseNumber failed and no option took
+// the number, or the key of the option that rejected it.
+func (c *config) convertNumber(text string) (value, parsed JSONValue, failure MessageKey) {
+	parsed, err := ParseNumber(text)
+	if raw, ok := c.rawNumber(text); ok && err == nil {
+		return raw, parsed, ""
+	}
+	if n, ok := c.overflowInteger(text, parsed); ok {
+		if n == nil {
+			return nil, parsed, MsgIntegerOverflow
+		}
+		return n, parsed, ""
+	}
+	if n, ok := c.bigNumber(text, parsed); ok {
+		if n == nil {
+			return nil, parsed, MsgNumberOutOfRange
+		}
+		return n, parsed, ""
+	}
+	if err != nil {
+		return nil, nil, MsgInvalidNumber
+	}
+	return parsed, parsed, ""
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithRawNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []lexer.Option
+		expected JSONValue
+	}{
+		{name: "exponent", input: `1e3`, expected: Number("1e3")},
+		{name: "trailing zeros", input: `[1.50, -0.0]`, expected: []any{Number("1.50"), Number("-0.0")}},
+		{name: "big integer", input: `{"id": 12345678901234567890}`, expected: JSONObject{"id": Number("12345678901234567890")}},
+		{name: "extension syntax", input: `0xFF`, opts: []lexer.Option{lexer.WithNumberExtensions(lexer.HexNumbers)}, expected: int64(255)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := New(lexer.New(tt.input, tt.opts...), WithRawNumbers()).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestNumber_Conversions(t *testing.T) {
+	if n, err := Number("42").Int64(); err != nil || n != 42 {
+		t.Errorf("expected 42, got %d (%v)", n, err)
+	}
+	if _, err := Number("1.5").Int64(); err == nil {
+		t.Error("expected an error converting 1.5 to int64")
+	}
+	if f, err := Number("1.50e1").Float64(); err != nil || f != 15 {
+		t.Errorf("expected 15, got %v (%v)", f, err)
+	}
+	if v, err := Number("1e3").Value(); err != nil || v != float64(1000) {
+		t.Errorf("expected float64 1000, got %#v (%v)", v, err)
+	}
+}
+
+func TestSortArrays_RawNumbers(t *testing.T) {
+	sorted := SortArrays([]any{Number("1e1"), Number("2"), int64(3), Number("-0.5")})
+	expected := []any{Number("-0.5"), Number("2"), int64(3), Number("1e1")}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("expected %v, got %v", expected, sorted)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		text      string
+		expected  JSONValue
+		maxAllocs float64
+	}{
+		{text: "42", expected: int64(42)},
+		{text: "-7000", expected: int64(-7000), maxAllocs: 1},
+		{text: "9223372036854775808", expected: float64(9223372036854775808), maxAllocs: 4},
+		{text: "1.5", expected: 1.5, maxAllocs: 1},
+		{text: "-2.5e3", expected: -2500.0, maxAllocs: 1},
+		{text: "1E2", expected: 100.0, maxAllocs: 1},
+		{text: "0xE", expected: int64(14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			value, err := ParseNumber(tt.text)
+			if err != nil || value != tt.expected {
+				t.Fatalf("expected %#v, got %#v (%v)", tt.expected, value, err)
+			}
+			// Only boxing the result allocates, not a failed attempt to parse
+			// a float64 as an integer.
+			if allocs := testing.AllocsPerRun(10, func() { ParseNumber(tt.text) }); allocs > tt.maxAllocs {
+				t.Errorf("expected at most %v allocations, got %v", tt.maxAllocs, allocs)
+			}
+		})
+	}
+
+	if _, err := ParseNumber("1e400"); err == nil {
+		t.Error("expected an error for a number out of range")
+	}
+}
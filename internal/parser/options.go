@@ -0,0 +1,103 @@
+package parser
+
+import "github.com/VuNe/json-parser/internal/arena"
+
+// config holds the settings applied by Options. It is resolved once when a
+// parser or Session is created and then shared by every document it parses.
+type config struct {
+	arena     *arena.Arena
+	normalize Normalization // strings normalized to NFC, see WithNFC
+
+	duplicateKeys  DuplicateKeyPolicy // see WithDuplicateKeys
+	keyMatching    KeyMatching
+	reviver        Reviver
+	rawNumbers     bool    // see WithRawNumbers
+	bigNumbers     bool    // see WithBigNumbers
+	checks         []Check // see WithChecks
+	maxDepth       int     // see WithMaxDepth
+	maxBytes       int     // see WithMaxBytes
+	maxStringLen   int     // see WithMaxStringLength
+	maxMemory      int     // see WithMaxMemory
+	trailingCommas bool    // see WithTrailingCommas
+	zeroCopy       bool    // see WithZeroCopy
+	sizeHint       int     // see WithSizeHint
+
+	bom      BOMPolicy      // see WithBOM
+	ijson    bool           // see WithIJSON
+	overflow OverflowPolicy // see WithIntegerOverflow
+}
+
+// Option configures optional parser behavior.
+type Option func(*config)
+
+// DefaultMaxDepth is the nesting depth allowed unless WithMaxDepth sets
+// another limit. It is far deeper than real documents nest, and shallow
+// enough that parsing can't exhaust the stack.
+const DefaultMaxDepth = 10000
+
+// newConfig applies opts on top of the default configuration.
+func newConfig(opts []Option) config {
+	c := config{maxDepth: DefaultMaxDepth}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithArena makes the parser allocate array storage from a instead of the
+// heap. Pair it with lexer.WithArena so strings come from the same arena; the
+// parsed document is then only valid until a is reset.
+func WithArena(a *arena.Arena) Option {
+	return func(c *config) {
+		c.arena = a
+	}
+}
+
+// WithMaxDepth limits the nesting of objects and arrays to n levels, instead
+// of DefaultMaxDepth. Deeper documents fail with a ParseError with the key
+// MsgMaxDepth rather than exhausting the stack. n <= 0 removes the limit,
+// which is only safe for trusted input.
+func WithMaxDepth(n int) Option {
+	return func(c *config) {
+		c.maxDepth = n
+	}
+}
+
+// WithMaxBytes limits documents to n bytes of input, including whitespace.
+// Longer documents fail with a ParseError with the key MsgMaxBytes, reported
+// at the first value reaching past the limit, so that reading from a stream
+// stops there. n <= 0, the default, removes the limit.
+func WithMaxBytes(n int) Option {
+	return func(c *config) {
+		c.maxBytes = n
+	}
+}
+
+// WithMaxStringLength limits strings, both values and object keys, to n bytes
+// once unescaped. Longer strings fail with a ParseError with the key
+// MsgMaxStringLength. n <= 0, the default, removes the limit.
+func WithMaxStringLength(n int) Option {
+	return func(c *config) {
+		c.maxStringLen = n
+	}
+}
+
+// WithTrailingCommas accepts a comma after the last member of an object or
+// element of an array, as in {"a": 1,} or [1, 2,], which hand-written config
+// files often contain. They are rejected by default, as JSON requires.
+func WithTrailingCommas() Option {
+	return func(c *config) {
+		c.trailingCommas = true
+	}
+}
+
+// WithZeroCopy makes string values and object keys without escapes share
+// memory with the input instead of being copied, which cuts allocations on
+// string-heavy documents. The lexer does the copying, so pass it
+// lexer.WithZeroCopy too; a Session applies that to its own lexer. A value
+// kept after parsing then keeps the whole input in memory.
+func WithZeroCopy() Option {
+	return func(c *config) {
+		c.zeroCopy = true
+	}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// OverflowPolicy decides what happens to an integer literal beyond the
+// int64 range, such as a 64-bit unsigned ID above math.MaxInt64.
+type OverflowPolicy int
+
+const (
+	OverflowFloat    OverflowPolicy = iota // rounded to the nearest float64, losing precision (default)
+	OverflowError                          // the integer is a semantic error
+	OverflowSaturate                       // clamped to math.MaxInt64 or math.MinInt64
+	OverflowBig                            // kept exactly as a *big.Int
+	OverflowNumber                         // kept exactly as a Number
+)
+
+// WithIntegerOverflow sets the integer overflow policy. It only concerns
+// integers, written without a fraction or exponent; use WithBigNumbers to
+// keep other numbers exact. For integers, a policy other than OverflowFloat
+// takes precedence over WithBigNumbers, and WithRawNumbers over both.
+// Documents record a precision loss warning for saturated integers.
+func WithIntegerOverflow(policy OverflowPolicy) Option {
+	return func(c *config) {
+		c.overflow = policy
+	}
+}
+
+// overflowInteger applies the overflow policy to text if it is an integer
+// literal that value, what ParseNumber returned for text, doesn't hold as
+// an int64. It reports false for other numbers and under OverflowFloat, and
+// returns a nil value under OverflowError.
+func (c *config) overflowInteger(text string, value JSONValue) (JSONValue, bool) {
+	if c.overflow == OverflowFloat {
+		return nil, false
+	}
+	if _, ok := value.(int64); ok {
+		return nil, false
+	}
+	// Literals enabled by lexer.WithNumberExtensions are integers in Go
+	// syntax, which base 0 accepts.
+	base := 10
+	if strings.ContainsAny(text, "xXbB_") {
+		base = 0
+	} else if strings.ContainsAny(text, ".eE") {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(text, base)
+	if !ok {
+		return nil, false
+	}
+	switch c.overflow {
+	case OverflowSaturate:
+		if n.Sign() < 0 {
+			return int64(math.MinInt64), true
+		}
+		return int64(math.MaxInt64), true
+	case OverflowBig:
+		return n, true
+	case OverflowNumber:
+		// Extension syntax isn't JSON, so such integers are written in
+		// decimal.
+		return Number(n.String()), true
+	}
+	return nil, true
+}
+
+// checkSaturation warns if n, the value of text, is an integer saturated
+// under OverflowSaturate rather than parsed, what ParseNumber returned.
+func (p *parser) checkSaturation(text string, n int64, parsed JSONValue) {
+	if _, ok := parsed.(int64); !ok {
+		p.warn(RulePrecisionLoss, p.path, fmt.Sprintf("%s is stored as %d", text, n))
+	}
+}
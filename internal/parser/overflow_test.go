@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithIntegerOverflow(t *testing.T) {
+	const input = `[18446744073709551615, -9223372036854775809, 42, 1e30]`
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected []any
+	}{
+		{name: "float", policy: OverflowFloat, expected: []any{1.8446744073709552e19, -9.223372036854776e18, int64(42), 1e30}},
+		{name: "saturate", policy: OverflowSaturate, expected: []any{int64(math.MaxInt64), int64(math.MinInt64), int64(42), 1e30}},
+		{
+			name:     "big",
+			policy:   OverflowBig,
+			expected: []any{new(big.Int).SetUint64(math.MaxUint64), new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1)), int64(42), 1e30},
+		},
+		{name: "number", policy: OverflowNumber, expected: []any{Number("18446744073709551615"), Number("-9223372036854775809"), int64(42), 1e30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := New(lexer.New(input), WithIntegerOverflow(tt.policy)).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+			value, err = NewFast(input, WithIntegerOverflow(tt.policy)).Parse()
+			if err != nil {
+				t.Fatalf("fast parser: unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("fast parser: expected %v, got %v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestParser_WithIntegerOverflow_Error(t *testing.T) {
+	input := "{\n  \"id\": 18446744073709551615\n}"
+	for _, p := range []Parser{
+		NewWithInput(lexer.New(input), input, WithIntegerOverflow(OverflowError)),
+		NewFast(input, WithIntegerOverflow(OverflowError), WithBigNumbers()),
+	} {
+		_, err := p.Parse()
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Key != MsgIntegerOverflow {
+			t.Fatalf("expected an error with the key %q, got %v", MsgIntegerOverflow, err)
+		}
+		if parseErr.Position.Line != 2 || parseErr.Position.Column != 9 {
+			t.Errorf("expected the error at 2:9, got %d:%d", parseErr.Position.Line, parseErr.Position.Column)
+		}
+	}
+
+	if _, err := New(lexer.New(`[1.5e300, 9223372036854775807]`), WithIntegerOverflow(OverflowError)).Parse(); err != nil {
+		t.Errorf("unexpected error for numbers that don't overflow: %v", err)
+	}
+}
+
+func TestParser_WithIntegerOverflow_Extensions(t *testing.T) {
+	input := `0xFFFFFFFFFFFFFFFF`
+	value, err := New(lexer.New(input, lexer.WithNumberExtensions(lexer.HexNumbers)), WithIntegerOverflow(OverflowNumber)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != Number("18446744073709551615") {
+		t.Errorf("expected the integer in decimal, got %#v", value)
+	}
+}
+
+func TestParser_WithIntegerOverflow_Warning(t *testing.T) {
+	input := `{"id": 18446744073709551615}`
+	doc, err := NewWithInput(lexer.New(input), input, WithIntegerOverflow(OverflowSaturate)).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Warning{{Rule: RulePrecisionLoss, Path: "/id", Message: "18446744073709551615 is stored as 9223372036854775807"}}
+	if !reflect.DeepEqual(doc.Warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, doc.Warnings)
+	}
+}
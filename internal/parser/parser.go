@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/VuNe/json-parser/internal/lexer"
 )
@@ -10,54 +14,132 @@ import (
 type Parser interface {
 	Parse() (JSONValue, error)
 	ParseValue() (JSONValue, error)
+	ParseAll() ([]JSONValue, error)
+	// ParseDocument is like Parse but also returns the non-fatal warnings
+	// found while parsing and running the WithChecks checks, and metrics.
+	ParseDocument() (*Document, error)
+	// Reset prepares the parser to parse input with its lexer (see
+	// lexer.Lexer.Reset), keeping its options and internal buffers so that a
+	// single parser can serve many documents. Errors quote input.
+	Reset(input string)
 }
 
 // parser is the concrete implementation of the Parser interface.
 type parser struct {
+	config
 	lexer        lexer.Lexer
 	currentToken lexer.Token
 	peekToken    lexer.Token
-	sourceInput  string // Keep track of original input for enhanced error reporting
+	sourceInput  string            // Keep track of original input for enhanced error reporting
+	elements     []any             // pending array elements of all open arrays
+	objectSizes  []int             // members of the last object completed at each depth, see objectSize
+	keys         map[string]string // interned object keys, shared across documents by a Session
+	path         string            // JSON Pointer to the value being parsed, see tracksPath
+	depth        int               // number of open objects and arrays
+	memory       int               // approximate heap bytes of the values built so far, see WithMaxMemory
+	ctx          context.Context   // checked while parsing, see ParseContext
+	values       int               // values parsed, counted for checkContext
+	doc          *Document         // document being parsed by ParseDocument, nil otherwise
+	bom          bool              // the lexer skipped a byte order mark not yet checked, see WithBOM
 }
 
 // New creates a new parser instance with the given lexer.
-func New(l lexer.Lexer) Parser {
-	p := &parser{lexer: l}
-
-	// Read two tokens, so currentToken and peekToken are both set
-	p.nextToken()
-	p.nextToken()
-
+func New(l lexer.Lexer, opts ...Option) Parser {
+	p := &parser{config: newConfig(opts)}
+	p.reset(l, "")
 	return p
 }
 
 // NewWithInput creates a new parser instance with the given lexer and keeps track of source input for enhanced error reporting.
-func NewWithInput(l lexer.Lexer, sourceInput string) Parser {
-	p := &parser{
-		lexer:       l,
-		sourceInput: sourceInput,
-	}
+func NewWithInput(l lexer.Lexer, sourceInput string, opts ...Option) Parser {
+	p := &parser{config: newConfig(opts)}
+	p.reset(l, sourceInput)
+	return p
+}
+
+// NewFromReader creates a parser that reads the document incrementally from
+// r, so large inputs are never held in memory as a whole. Without the source
+// text, errors have no snippet; use New with lexer.NewReader to pass lexer
+// options.
+func NewFromReader(r io.Reader, opts ...Option) Parser {
+	return New(lexer.NewReader(r), opts...)
+}
+
+// reset prepares the parser to parse a new document from l, keeping its
+// configuration and internal buffers.
+func (p *parser) reset(l lexer.Lexer, sourceInput string) {
+	p.lexer = l
+	p.sourceInput = sourceInput
+	p.currentToken = lexer.Token{}
+	p.peekToken = lexer.Token{}
+	p.depth = 0
+	p.memory = 0
+	p.values = 0
+	p.path = ""
+	p.bom = l.BOM()
+	clear(p.elements)
+	p.elements = p.elements[:0]
 
 	// Read two tokens, so currentToken and peekToken are both set
 	p.nextToken()
 	p.nextToken()
+}
 
-	return p
+// Reset implements Parser.
+func (p *parser) Reset(input string) {
+	p.lexer.Reset(input)
+	p.reset(p.lexer, input)
+}
+
+// internKey returns a shared copy of key when interning is enabled, so that
+// documents parsed by the same Session don't each retain their own copies of
+// frequently repeated keys.
+func (p *parser) internKey(key string) string {
+	if p.keys == nil {
+		return key
+	}
+	if interned, ok := p.keys[key]; ok {
+		return interned
+	}
+	if len(p.keys) < maxInternedKeys {
+		if p.zeroCopy {
+			// Don't let the table keep the input of every document alive.
+			key = strings.Clone(key)
+		}
+		p.keys[key] = key
+	}
+	return key
 }
 
 // Enhanced error reporting helper methods
-func (p *parser) newSyntaxError(message string, expected []string, suggestion string) *ParseError {
-	if p.sourceInput != "" {
-		return NewSyntaxError(message, p.currentToken, expected, suggestion, p.sourceInput)
+func (p *parser) newSyntaxError(key MessageKey, expected []string, suggestion MessageKey) *ParseError {
+	text, _ := English.Format(suggestion)
+	if p.sourceInput == "" {
+		pe := newKeyedError(key, p.currentToken)
+		pe.Expected, pe.Suggestion, pe.SuggestionKey = expected, text, suggestion
+		return pe
 	}
-	return NewParseError(message, p.currentToken)
+	message, _ := English.Format(key)
+	pe := NewSyntaxError(message, p.currentToken, expected, text, p.sourceInput)
+	pe.Key, pe.SuggestionKey = key, suggestion
+	return pe
 }
 
-func (p *parser) newSemanticError(message string, suggestion string) *ParseError {
-	if p.sourceInput != "" {
-		return NewSemanticError(message, p.currentToken, suggestion, p.sourceInput)
+func (p *parser) newSemanticError(key MessageKey, suggestion MessageKey, args ...any) *ParseError {
+	return p.newSemanticErrorAt(p.currentToken, key, suggestion, args...)
+}
+
+// newSemanticErrorAt is newSemanticError for a token other than the current
+// one.
+func (p *parser) newSemanticErrorAt(token lexer.Token, key MessageKey, suggestion MessageKey, args ...any) *ParseError {
+	if p.sourceInput == "" {
+		return newKeyedError(key, token, args...)
 	}
-	return NewParseError(message, p.currentToken)
+	message, _ := English.Format(key, args...)
+	text, _ := English.Format(suggestion)
+	pe := NewSemanticError(message, token, text, p.sourceInput)
+	pe.Key, pe.Args, pe.SuggestionKey = key, args, suggestion
+	return pe
 }
 
 // nextToken advances both currentToken and peekToken.
@@ -84,32 +166,71 @@ func (p *parser) Parse() (JSONValue, error) {
 
 	// Ensure we're at the end of input after parsing a valid value
 	if p.currentToken.Type != lexer.EOF {
-		return nil, p.newSyntaxError("unexpected content after JSON value", []string{"EOF"}, "Remove any extra content after the JSON value")
+		return nil, p.newSyntaxError(MsgExtraContent, []string{"EOF"}, MsgSuggestRemoveExtraContent)
+	}
+	if err := p.checkSize(); err != nil {
+		return nil, err
 	}
 
 	return value, nil
 }
 
+// ParseAll parses a sequence of concatenated JSON values, such as
+// `{"id":1} {"id":2}` or newline-delimited JSON, and returns them in order.
+// Whitespace-only input yields no values.
+func (p *parser) ParseAll() ([]JSONValue, error) {
+	var values []JSONValue
+	for p.currentToken.Type != lexer.EOF {
+		value, err := p.ParseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
 // ParseValue parses a JSON value (supports objects, arrays, and all primitive types).
 func (p *parser) ParseValue() (JSONValue, error) {
-	return p.parseValue()
+	if p.bom {
+		if err := p.checkBOM(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.checkIJSONTopLevel(); err != nil {
+		return nil, err
+	}
+	value, err := p.parseValue()
+	if err != nil || p.reviver == nil {
+		return value, err
+	}
+	value, _, err = p.revive(value)
+	return value, err
 }
 
 // parseObject parses a JSON object with string key-value pairs.
 func (p *parser) parseObject() (JSONValue, error) {
 	if p.currentToken.Type != lexer.LEFT_BRACE {
-		return nil, NewParseError("expected '{'", p.currentToken)
+		return nil, newKeyedError(MsgExpectedObject, p.currentToken)
+	}
+	if err := p.enterContainer(); err != nil {
+		return nil, err
 	}
+	defer p.leaveContainer()
 
 	// Move past the opening brace
 	p.nextToken()
 
 	// Check if we hit EOF before finding the closing brace
 	if p.currentToken.Type == lexer.EOF {
-		return nil, p.newSyntaxError("unterminated object", []string{"'}'"}, SuggestionCloseObject)
+		return nil, p.newSyntaxError(MsgUnterminatedObject, []string{"'}'"}, MsgSuggestCloseObject)
 	}
 
-	obj := NewJSONObject()
+	if err := p.charge(mapHeaderSize); err != nil {
+		return nil, err
+	}
+	obj := make(JSONObject, p.objectSize())
+	var seen map[string]string // keys by matching form, see checkDuplicateKey
 
 	// Check if it's an empty object
 	if p.currentToken.Type == lexer.RIGHT_BRACE {
@@ -121,25 +242,35 @@ func (p *parser) parseObject() (JSONValue, error) {
 	for {
 		// Expect string key
 		if p.currentToken.Type != lexer.STRING {
-			return nil, NewParseError("expected string key", p.currentToken)
+			return nil, p.newSyntaxError(MsgExpectedStringKey, []string{"STRING"}, MsgSuggestStringKey)
 		}
 
-		key := p.currentToken.Value
+		if err := p.checkString(); err != nil {
+			return nil, err
+		}
+		key := p.internKey(p.normalized(p.currentToken.Value, NormalizeKeys))
+		if err := p.checkDuplicateKey(obj, key, &seen); err != nil {
+			return nil, err
+		}
 		p.nextToken()
 
 		// Expect colon
 		if p.currentToken.Type != lexer.COLON {
-			return nil, NewParseError("expected ':'", p.currentToken)
+			return nil, p.newSyntaxError(MsgExpectedColon, []string{"':'"}, MsgSuggestMissingColon)
 		}
 		p.nextToken()
 
 		// Parse value (supports all JSON types)
-		value, err := p.parseValue()
+		value, keep, err := p.parseMember(key)
 		if err != nil {
 			return nil, err
 		}
-
-		obj[key] = value
+		if keep {
+			if err := p.charge(stringHeaderSize + len(key) + interfaceSize + mapEntryOverhead); err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
 
 		// Check for comma or closing brace
 		if p.currentToken.Type == lexer.RIGHT_BRACE {
@@ -150,31 +281,43 @@ func (p *parser) parseObject() (JSONValue, error) {
 
 			// After comma, we must have another key-value pair or it's an error
 			if p.currentToken.Type == lexer.RIGHT_BRACE {
-				return nil, NewParseError("trailing comma not allowed", p.currentToken)
+				if !p.trailingCommas {
+					return nil, p.newSyntaxError(MsgTrailingComma, []string{"STRING"}, MsgSuggestRemoveTrailingComma)
+				}
+				p.nextToken() // consume the closing brace
+				break
 			}
 		} else {
-			return nil, NewParseError("expected ',' or '}'", p.currentToken)
+			return nil, p.newSyntaxError(MsgExpectedCommaOrBrace, []string{"','", "'}'"}, MsgSuggestMissingComma)
 		}
 	}
 
+	p.objectDone(len(obj))
 	return obj, nil
 }
 
 // parseArray parses a JSON array with comma-separated values.
 func (p *parser) parseArray() (JSONValue, error) {
 	if p.currentToken.Type != lexer.LEFT_BRACKET {
-		return nil, NewParseError("expected '['", p.currentToken)
+		return nil, newKeyedError(MsgExpectedArray, p.currentToken)
 	}
+	if err := p.enterContainer(); err != nil {
+		return nil, err
+	}
+	defer p.leaveContainer()
 
 	// Move past the opening bracket
 	p.nextToken()
 
 	// Check if we hit EOF before finding the closing bracket
 	if p.currentToken.Type == lexer.EOF {
-		return nil, NewParseError("expected ']'", p.currentToken)
+		return nil, p.newSyntaxError(MsgExpectedBracket, []string{"value", "']'"}, MsgSuggestCloseArray)
 	}
 
 	var arr []any
+	if err := p.charge(sliceHeaderSize); err != nil {
+		return nil, err
+	}
 
 	// Check if it's an empty array
 	if p.currentToken.Type == lexer.RIGHT_BRACKET {
@@ -182,15 +325,29 @@ func (p *parser) parseArray() (JSONValue, error) {
 		return arr, nil
 	}
 
+	// Elements are staged on a shared stack and copied into an exactly sized
+	// slice, from the arena if there is one, once the array is complete,
+	// avoiding the repeated growth of append.
+	start := len(p.elements)
+	defer func() {
+		clear(p.elements[start:])
+		p.elements = p.elements[:start]
+	}()
+
 	// Parse array elements
-	for {
+	for index := 0; ; index++ {
 		// Parse value
-		value, err := p.parseValue()
+		value, keep, err := p.parseElement(index)
 		if err != nil {
 			return nil, err
 		}
 
-		arr = append(arr, value)
+		if keep {
+			if err := p.charge(interfaceSize); err != nil {
+				return nil, err
+			}
+			p.elements = append(p.elements, value)
+		}
 
 		// Check for comma or closing bracket
 		if p.currentToken.Type == lexer.RIGHT_BRACKET {
@@ -201,25 +358,59 @@ func (p *parser) parseArray() (JSONValue, error) {
 
 			// After comma, we must have another value or it's an error
 			if p.currentToken.Type == lexer.RIGHT_BRACKET {
-				return nil, NewParseError("trailing comma not allowed", p.currentToken)
+				if !p.trailingCommas {
+					return nil, p.newSyntaxError(MsgTrailingComma, []string{"value"}, MsgSuggestRemoveTrailingComma)
+				}
+				p.nextToken() // consume the closing bracket
+				break
 			}
 		} else {
-			return nil, NewParseError("expected ',' or ']'", p.currentToken)
+			return nil, p.newSyntaxError(MsgExpectedCommaOrBracket, []string{"','", "']'"}, MsgSuggestMissingComma)
 		}
 	}
 
+	switch n := len(p.elements) - start; {
+	case p.arena != nil:
+		arr = p.arena.Values(n)
+	case n > 0:
+		arr = make([]any, n)
+	}
+	copy(arr, p.elements[start:])
+
 	return arr, nil
 }
 
+// parseElement is parseMember for array element index.
+func (p *parser) parseElement(index int) (JSONValue, bool, error) {
+	if !p.tracksPath() {
+		value, err := p.parseValue()
+		return value, err == nil, err
+	}
+	return p.parseMember(strconv.Itoa(index))
+}
+
 // parseValue parses a JSON value (supports objects, arrays, strings, numbers, booleans, and null).
 func (p *parser) parseValue() (JSONValue, error) {
+	p.countValue()
+	if err := p.checkSize(); err != nil {
+		return nil, err
+	}
+	if err := p.checkContext(); err != nil {
+		return nil, err
+	}
 	switch p.currentToken.Type {
 	case lexer.LEFT_BRACE:
 		return p.parseObject()
 	case lexer.LEFT_BRACKET:
 		return p.parseArray()
 	case lexer.STRING:
-		value := p.currentToken.Value
+		if err := p.checkString(); err != nil {
+			return nil, err
+		}
+		if err := p.charge(stringHeaderSize + len(p.currentToken.Value)); err != nil {
+			return nil, err
+		}
+		value := p.normalized(p.currentToken.Value, NormalizeValues)
 		p.nextToken()
 		return value, nil
 	case lexer.NUMBER:
@@ -229,31 +420,67 @@ func (p *parser) parseValue() (JSONValue, error) {
 	case lexer.NULL:
 		return p.parseNull()
 	case lexer.EOF:
-		return nil, NewParseError("unexpected end of input", p.currentToken)
-	case lexer.INVALID, lexer.RIGHT_BRACE, lexer.RIGHT_BRACKET, lexer.COLON, lexer.COMMA:
-		return nil, NewParseError("expected JSON value", p.currentToken)
+		return nil, p.newSyntaxError(MsgUnexpectedEOF, []string{"value"}, "")
 	default:
-		return nil, NewParseError("expected JSON value", p.currentToken)
+		return nil, p.newSyntaxError(MsgExpectedValue, []string{"value"}, "")
 	}
 }
 
 // parseNumber parses a JSON number token and returns the appropriate Go type.
 func (p *parser) parseNumber() (JSONValue, error) {
-	value := p.currentToken.Value
+	text := p.currentToken.Value
+	value, parsed, failure := p.convertNumber(text)
+	if failure != MsgInvalidNumber {
+		if err := p.checkIJSONNumber(parsed); err != nil {
+			return nil, err
+		}
+	}
+	switch failure {
+	case "":
+	case MsgInvalidNumber:
+		p.nextToken()
+		return nil, newKeyedError(MsgInvalidNumber, p.currentToken)
+	default:
+		return nil, p.newSemanticError(failure, MsgSuggestQuoteNumber, text)
+	}
+	if err := p.charge(EstimateSize(value)); err != nil {
+		return nil, err
+	}
+	if p.doc != nil {
+		switch v := value.(type) {
+		case float64:
+			p.checkPrecision(text, v)
+		case int64:
+			p.checkSaturation(text, v, parsed)
+		}
+	}
 	p.nextToken()
+	return value, nil
+}
 
-	// Try to parse as integer first
-	if intVal, err := strconv.ParseInt(value, 10, 64); err == nil {
-		return intVal, nil
+// ParseNumber converts the text of a NUMBER token to an int64 if it is an
+// integer that fits, and to a float64 otherwise.
+func ParseNumber(text string) (JSONValue, error) {
+	// Try to parse as integer first. Literals enabled by
+	// lexer.WithNumberExtensions follow Go syntax, which base 0 accepts.
+	base := 10
+	if strings.ContainsAny(text, "xXbB_") {
+		base = 0
+	}
+	// A failed strconv.ParseInt allocates its error, so only try integers
+	// when there is no fraction or exponent.
+	if base == 0 || !strings.ContainsAny(text, ".eE") {
+		if intVal, err := strconv.ParseInt(text, base, 64); err == nil {
+			return intVal, nil
+		}
 	}
 
 	// If integer parsing fails, try float64
-	if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-		return floatVal, nil
+	floatVal, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", text)
 	}
-
-	// If both fail, return error
-	return nil, NewParseError("invalid number format", p.currentToken)
+	return floatVal, nil
 }
 
 // parseBoolean parses a JSON boolean token.
@@ -267,7 +494,7 @@ func (p *parser) parseBoolean() (JSONValue, error) {
 	case "false":
 		return false, nil
 	default:
-		return nil, NewParseError("invalid boolean value", p.currentToken)
+		return nil, newKeyedError(MsgInvalidBoolean, p.currentToken)
 	}
 }
 
@@ -280,5 +507,5 @@ func (p *parser) parseNull() (JSONValue, error) {
 		return nil, nil
 	}
 
-	return nil, NewParseError("invalid null value", p.currentToken)
+	return nil, newKeyedError(MsgInvalidNull, p.currentToken)
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/VuNe/json-parser/internal/arena"
 	"github.com/VuNe/json-parser/internal/lexer"
 )
 
@@ -210,3 +211,160 @@ func generateNestedJSON(depth int) string {
 	}
 	return `{"level": ` + generateNestedJSON(depth-1) + `}`
 }
+
+// BenchmarkParser_WithArena compares heap allocation against arena allocation
+// for request-scoped parsing of the same document
+func BenchmarkParser_WithArena(b *testing.B) {
+	input := `{"users": [{"id": 1, "name": "Alice", "tags": ["admin", "dev"]}, {"id": 2, "name": "Bob", "tags": ["dev"]}], "total": 2}`
+
+	b.Run("Heap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := New(lexer.New(input)).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("Arena", func(b *testing.B) {
+		b.ReportAllocs()
+		a := arena.New()
+		for i := 0; i < b.N; i++ {
+			if _, err := New(lexer.New(input, lexer.WithArena(a)), WithArena(a)).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+			a.Reset()
+		}
+	})
+}
+
+// BenchmarkParser_Session compares a fresh parser per message against a
+// Session that reuses its lexer, buffers, and key table
+func BenchmarkParser_Session(b *testing.B) {
+	input := `{"id": 12345, "event": "order.created", "payload": {"sku": "A-1", "qty": 2}}`
+
+	b.Run("FreshParser", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewWithInput(lexer.New(input), input).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("Session", func(b *testing.B) {
+		b.ReportAllocs()
+		session := NewSession()
+		for i := 0; i < b.N; i++ {
+			if _, err := session.Parse(input); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkParser_WithZeroCopy compares copying string values out of the input
+// against sharing the input's memory
+func BenchmarkParser_WithZeroCopy(b *testing.B) {
+	input := `{"users": [{"name": "Alice", "email": "alice@example.com", "role": "admin"}, {"name": "Bob", "email": "bob@example.com", "role": "dev"}], "description": "` + strings.Repeat("text without escapes ", 50) + `"}`
+
+	b.Run("Copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := New(lexer.New(input)).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("ZeroCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := New(lexer.New(input, lexer.WithZeroCopy()), WithZeroCopy()).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkParser_Pool compares a fresh lexer and parser per payload against
+// Parse, which reuses pooled ones
+func BenchmarkParser_Pool(b *testing.B) {
+	input := `{"id": 12345, "event": "order.created", "payload": {"sku": "A-1", "qty": 2}}`
+
+	b.Run("FreshParser", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewWithInput(lexer.New(input), input).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("Pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := Parse(input); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkParser_Fast compares the standard parser against NewFast, which
+// indexes the input's structure before building values
+func BenchmarkParser_Fast(b *testing.B) {
+	input := `{"users": [` + strings.Repeat(`{"id": 1, "name": "Alice", "tags": ["a", "b"], "active": true, "score": 9.5}, `, 100) + `null]}`
+
+	b.Run("Standard", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			if _, err := NewWithInput(lexer.New(input), input).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("Fast", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			if _, err := NewFast(input).Parse(); err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkParser_Lazy compares building a whole document to read one value
+// against building only that value from a LazyDocument
+func BenchmarkParser_Lazy(b *testing.B) {
+	input := `{"users": [` + strings.Repeat(`{"id": 1, "name": "Alice", "tags": ["a", "b"], "active": true, "score": 9.5}, `, 100) + `null], "total": 100}`
+
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			value, err := NewWithInput(lexer.New(input), input).Parse()
+			if err != nil {
+				b.Fatalf("Parse failed: %v", err)
+			}
+			if _, found := lookup(value, []string{"total"}); !found {
+				b.Fatal("value not found")
+			}
+		}
+	})
+
+	b.Run("Lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			doc, err := ParseLazy(input)
+			if err != nil {
+				b.Fatalf("ParseLazy failed: %v", err)
+			}
+			if _, found, _ := doc.Get("/total"); !found {
+				b.Fatal("value not found")
+			}
+		}
+	})
+}
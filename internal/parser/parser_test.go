@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/VuNe/json-parser/internal/lexer"
 )
@@ -14,6 +18,32 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewFromReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "object", input: `{"a": [1, 2.5, "x"], "b": null}`, want: `map[a:[1 2.5 x] b:<nil>]`},
+		{name: "scalar", input: "  true\n", want: "true"},
+		{name: "syntax error", input: `{"a" 1}`, wantErr: true},
+		{name: "extra content", input: `[] []`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewFromReader(iotest.OneByteReader(strings.NewReader(tt.input))).Parse()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && fmt.Sprint(value) != tt.want {
+				t.Errorf("expected %s, got %v", tt.want, value)
+			}
+		})
+	}
+}
+
 func TestParser_Parse(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -161,6 +191,41 @@ func TestParser_ParseValue(t *testing.T) {
 	}
 }
 
+func TestParser_ParseAll(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedCount int
+		expectError   bool
+	}{
+		{name: "empty", input: "", expectedCount: 0},
+		{name: "whitespace only", input: " \n\t", expectedCount: 0},
+		{name: "single value", input: `{"id": 1}`, expectedCount: 1},
+		{name: "concatenated", input: `{"id": 1}{"id": 2} [3] "four" 5 true null`, expectedCount: 7},
+		{name: "newline delimited", input: "{\"id\": 1}\n{\"id\": 2}\n", expectedCount: 2},
+		{name: "invalid second value", input: `{"id": 1} {"id": }`, expectError: true},
+		{name: "stray separator", input: `{"id": 1}, {"id": 2}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := New(lexer.New(tt.input)).ParseAll()
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got values %v", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(values) != tt.expectedCount {
+				t.Errorf("expected %d values, got %d: %v", tt.expectedCount, len(values), values)
+			}
+		})
+	}
+}
+
 func TestParseError_Error(t *testing.T) {
 	token := lexer.Token{
 		Type:     lexer.INVALID,
@@ -1034,3 +1099,169 @@ func TestParser_TypeAssertions(t *testing.T) {
 		t.Errorf("Nil assertion failed: got %v (%T)", obj["null"], obj["null"])
 	}
 }
+
+func TestParser_NumberExtensions(t *testing.T) {
+	input := `{"hex": 0xFF, "neg": -0x10, "bin": 0b1010, "big": 1_000_000, "pi": 3.141_592}`
+	lex := lexer.New(input, lexer.WithNumberExtensions(lexer.HexNumbers|lexer.BinaryNumbers|lexer.DigitSeparators))
+	result, err := New(lex).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := JSONObject{"hex": int64(255), "neg": int64(-16), "bin": int64(10), "big": int64(1000000), "pi": 3.141592}
+	if !deepEqual(result.(JSONObject), expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	if _, err := New(lexer.New(`[0xFF]`)).Parse(); err == nil {
+		t.Error("expected hex literals to be rejected by default")
+	}
+}
+
+func TestParser_MaxDepth(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat(`[{"a":`, depth/2) + strings.Repeat("[", depth%2) + "1" +
+			strings.Repeat("]", depth%2) + strings.Repeat("}]", depth/2)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "at the default limit", input: nested(DefaultMaxDepth)},
+		{name: "beyond the default limit", input: nested(DefaultMaxDepth + 1), wantErr: true},
+		{name: "hostile input", input: strings.Repeat("[", 1_000_000), wantErr: true},
+		{name: "at a custom limit", input: nested(3), opts: []Option{WithMaxDepth(3)}},
+		{name: "beyond a custom limit", input: nested(4), opts: []Option{WithMaxDepth(3)}, wantErr: true},
+		{name: "scalar at limit 1", input: `"x"`, opts: []Option{WithMaxDepth(1)}},
+		{name: "no limit", input: nested(DefaultMaxDepth * 2), opts: []Option{WithMaxDepth(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...).Parse()
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Key != MsgMaxDepth {
+				t.Fatalf("expected a %s ParseError, got %v", MsgMaxDepth, err)
+			}
+		})
+	}
+}
+
+func TestParser_SizeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		wantKey  MessageKey // "" for success
+		fromFile bool       // parse from a reader, without the source text
+	}{
+		{name: "within size", input: `[1, 2]`, opts: []Option{WithMaxBytes(6)}},
+		{name: "beyond size", input: `[1, 2, 3]`, opts: []Option{WithMaxBytes(6)}, wantKey: MsgMaxBytes},
+		{name: "trailing whitespace beyond size", input: "[1, 2]\n\n", opts: []Option{WithMaxBytes(6)}, wantKey: MsgMaxBytes},
+		{name: "beyond size streamed", input: `{"a": "` + strings.Repeat("x", 100) + `"}`, opts: []Option{WithMaxBytes(50)}, wantKey: MsgMaxBytes, fromFile: true},
+		{name: "string at limit", input: `["abc", {"abc": 1}]`, opts: []Option{WithMaxStringLength(3)}},
+		{name: "long string", input: `["abcd"]`, opts: []Option{WithMaxStringLength(3)}, wantKey: MsgMaxStringLength},
+		{name: "long key", input: `{"abcd": 1}`, opts: []Option{WithMaxStringLength(3)}, wantKey: MsgMaxStringLength},
+		{name: "unescaped length", input: `"\u00e9\u00e9"`, opts: []Option{WithMaxStringLength(4)}},
+		{name: "within memory", input: `["abc"]`, opts: []Option{WithMaxMemory(100)}},
+		{name: "beyond memory", input: `["` + strings.Repeat("x", 100) + `"]`, opts: []Option{WithMaxMemory(100)}, wantKey: MsgMaxMemory},
+		{name: "many small values beyond memory", input: `[` + strings.Repeat(`[], `, 100) + `[]]`, opts: []Option{WithMaxMemory(1000)}, wantKey: MsgMaxMemory},
+		{name: "beyond memory streamed", input: `{"a": [` + strings.Repeat(`1.5, `, 100) + `0]}`, opts: []Option{WithMaxMemory(500)}, wantKey: MsgMaxMemory, fromFile: true},
+		{name: "no limits", input: `["` + strings.Repeat("x", 1000) + `"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...)
+			if tt.fromFile {
+				p = NewFromReader(strings.NewReader(tt.input), tt.opts...)
+			}
+			_, err := p.Parse()
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Key != tt.wantKey {
+				t.Fatalf("expected a %s ParseError, got %v", tt.wantKey, err)
+			}
+		})
+	}
+}
+
+func TestWithMaxMemory_EstimateSize(t *testing.T) {
+	input := `{"name": "test", "items": [1, -2.5, "three", true, null, [], {}], "nested": {"a": {"b": ["c"]}}, ` +
+		`"numbers": [1.50, 12345678901234567890, 3.141592653589793238462643383279502884197]}`
+	options := map[string][]Option{
+		"default":     nil,
+		"raw numbers": {WithRawNumbers()},
+		"big numbers": {WithBigNumbers()},
+	}
+
+	for name, opts := range options {
+		t.Run(name, func(t *testing.T) {
+			value, err := Parse(input, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The memory counted while parsing is the estimate of the result.
+			size := EstimateSize(value)
+			if _, err := Parse(input, append(opts, WithMaxMemory(size))...); err != nil {
+				t.Errorf("expected the document to fit in %d bytes, got %v", size, err)
+			}
+			limited := append(opts, WithMaxMemory(size-1))
+			for _, p := range []Parser{NewWithInput(lexer.New(input), input, limited...), NewFast(input, limited...)} {
+				if _, err := p.Parse(); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("memory limit of %d bytes", size-1)) {
+					t.Errorf("expected the memory limit to be exceeded, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestParser_TrailingCommas(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "object", input: `{"a": 1,}`, expected: "map[a:1]"},
+		{name: "array", input: `[1, 2,]`, expected: "[1 2]"},
+		{name: "nested", input: "{\n  \"a\": [true,],\n  \"b\": {},\n}", expected: "map[a:[true] b:map[]]"},
+		{name: "comma only", input: `[,]`, wantErr: true},
+		{name: "two commas", input: `[1,,]`, wantErr: true},
+		{name: "missing value", input: `{"a":,}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := New(lexer.New(tt.input), WithTrailingCommas()).Parse()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && fmt.Sprint(value) != tt.expected {
+				t.Errorf("expected %s, got %v", tt.expected, value)
+			}
+		})
+	}
+
+	t.Run("rejected by default", func(t *testing.T) {
+		var parseErr *ParseError
+		if _, err := New(lexer.New(`[1,]`)).Parse(); !errors.As(err, &parseErr) || parseErr.Key != MsgTrailingComma {
+			t.Errorf("expected a %s ParseError, got %v", MsgTrailingComma, err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// pools hold the parsers used by Parse, each with its lexer, indexed by
+// whether the lexer was created with lexer.WithZeroCopy.
+var pools = [2]sync.Pool{
+	{New: func() any { return &parser{lexer: lexer.New("")} }},
+	{New: func() any { return &parser{lexer: lexer.New("", lexer.WithZeroCopy())} }},
+}
+
+// poolFor returns the pool of parsers whose lexer suits c.
+func poolFor(c config) *sync.Pool {
+	if c.zeroCopy {
+		return &pools[1]
+	}
+	return &pools[0]
+}
+
+// Parse parses the single document in input with opts like
+//
+//	NewWithInput(lexer.New(input), input, opts...).Parse()
+//
+// but with a parser and lexer taken from an internal pool and returned to it
+// afterwards, so services parsing millions of small payloads don't allocate
+// them for every call. The lexer uses the default options, with
+// lexer.WithZeroCopy added for WithZeroCopy; use New for others. Parse is
+// safe for concurrent use.
+func Parse(input string, opts ...Option) (JSONValue, error) {
+	c := newConfig(opts)
+	pool := poolFor(c)
+	p := pool.Get().(*parser)
+	defer release(pool, p)
+
+	p.config = c
+	p.Reset(input)
+	return p.Parse()
+}
+
+// release returns p to pool, without keeping its input or options alive
+// while it is pooled.
+func release(pool *sync.Pool, p *parser) {
+	p.Reset("")
+	p.config = config{}
+	p.ctx = nil
+	pool.Put(p)
+}
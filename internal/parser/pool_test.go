@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{name: "object", input: `{"id": 1, "tags": ["a", "b"], "nested": {"ok": true}}`},
+		{name: "invalid", input: `{"id": }`},
+		{name: "trailing comma", input: `[1, 2,]`, opts: []Option{WithTrailingCommas()}},
+		{name: "too deep", input: `[[[1]]]`, opts: []Option{WithMaxDepth(2)}},
+		{name: "zero copy", input: `{"name": "value"}`, opts: []Option{WithZeroCopy()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, expectedErr := NewWithInput(lexer.New(tt.input), tt.input, tt.opts...).Parse()
+			// Twice, so that the second call may reuse the pooled parser.
+			for range 2 {
+				value, err := Parse(tt.input, tt.opts...)
+				if (err != nil) != (expectedErr != nil) || err != nil && err.Error() != expectedErr.Error() {
+					t.Fatalf("expected error %v, got %v", expectedErr, err)
+				}
+				if !reflect.DeepEqual(value, expected) {
+					t.Errorf("expected %v, got %v", expected, value)
+				}
+			}
+		})
+	}
+
+	t.Run("options don't leak", func(t *testing.T) {
+		if _, err := Parse(`[1,]`, WithTrailingCommas()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := Parse(`[1,]`); err == nil {
+			t.Error("expected the trailing comma to be rejected without the option")
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				for j := range 100 {
+					input := fmt.Sprintf(`{"worker": %d, "call": %d}`, i, j)
+					value, err := Parse(input)
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+						return
+					}
+					if obj := value.(JSONObject); obj["worker"] != int64(i) || obj["call"] != int64(j) {
+						t.Errorf("expected the document %s, got %v", input, obj)
+						return
+					}
+				}
+			})
+		}
+		wg.Wait()
+	})
+}
+
+func TestParser_Reset(t *testing.T) {
+	p := New(lexer.New(`{"first": true}`))
+	if _, err := p.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.Reset(`[1, 2`)
+	if _, err := p.Parse(); err == nil {
+		t.Fatal("expected an error for the second document")
+	}
+
+	p.Reset(`{"third": [3]}`)
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatalf("unexpected error after a failed document: %v", err)
+	}
+	if expected := (JSONObject{"third": []any{int64(3)}}); !reflect.DeepEqual(value, expected) {
+		t.Errorf("expected %v, got %v", expected, value)
+	}
+}
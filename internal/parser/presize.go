@@ -0,0 +1,31 @@
+package parser
+
+// WithSizeHint creates objects with room for n members when the parser has
+// no better estimate of their size, saving the map growth that adding their
+// members would otherwise cause. The parser estimates the size of an object
+// from the last object it completed at the same depth, as the objects of an
+// array tend to have the same members, so the hint matters most for large
+// objects that have no such neighbor. Arrays are always allocated with their
+// exact length. n <= 0, the default, creates objects empty.
+func WithSizeHint(n int) Option {
+	return func(c *config) {
+		c.sizeHint = max(n, 0)
+	}
+}
+
+// objectSize returns the capacity to create the object just entered with.
+func (p *parser) objectSize() int {
+	if p.depth < len(p.objectSizes) && p.objectSizes[p.depth] > 0 {
+		return p.objectSizes[p.depth]
+	}
+	return p.sizeHint
+}
+
+// objectDone records that an object of n members was completed at the
+// current depth, for objectSize.
+func (p *parser) objectDone(n int) {
+	for len(p.objectSizes) <= p.depth {
+		p.objectSizes = append(p.objectSizes, 0)
+	}
+	p.objectSizes[p.depth] = n
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestWithSizeHint(t *testing.T) {
+	input := `{"list": [1, 2, 3], "objects": [{"a": 1, "b": 2}, {"a": 3, "b": 4, "c": 5}, {}], "empty": []}`
+	expected := JSONObject{
+		"list":    []any{int64(1), int64(2), int64(3)},
+		"objects": []any{JSONObject{"a": int64(1), "b": int64(2)}, JSONObject{"a": int64(3), "b": int64(4), "c": int64(5)}, JSONObject{}},
+		"empty":   []any(nil),
+	}
+
+	tests := []struct {
+		name string
+		p    Parser
+	}{
+		{name: "default", p: NewWithInput(lexer.New(input), input)},
+		{name: "size hint", p: NewWithInput(lexer.New(input), input, WithSizeHint(64))},
+		{name: "negative size hint", p: NewWithInput(lexer.New(input), input, WithSizeHint(-1))},
+		{name: "fast", p: NewFast(input, WithSizeHint(64))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.p.Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, expected) {
+				t.Errorf("expected %v, got %v", expected, value)
+			}
+			if list := value.(JSONObject)["list"].([]any); cap(list) != len(list) {
+				t.Errorf("expected an exactly sized array, got capacity %d for %d elements", cap(list), len(list))
+			}
+		})
+	}
+}
+
+func TestParser_ObjectSize(t *testing.T) {
+	p := &parser{config: newConfig([]Option{WithSizeHint(4)})}
+	p.reset(lexer.New(`[{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6}]`), "")
+	p.depth = 2
+	if n := p.objectSize(); n != 4 {
+		t.Errorf("expected the size hint 4 before any object, got %d", n)
+	}
+	p.depth = 0
+	if _, err := p.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.depth = 2
+	if n := p.objectSize(); n != 6 {
+		t.Errorf("expected the size of the last object, 6, got %d", n)
+	}
+	p.depth = 1
+	if n := p.objectSize(); n != 4 {
+		t.Errorf("expected the size hint 4 at a depth without objects, got %d", n)
+	}
+}
+
+func BenchmarkParser_SizeHint(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`{`)
+	for i := range 1000 {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(`"key` + strings.Repeat("x", i%7) + string(rune('a'+i%26)) + `` + strings.Repeat("y", i/26) + `": 1`)
+	}
+	sb.WriteString(`}`)
+	input := sb.String()
+
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "NoHint"},
+		{name: "Hint", opts: []Option{WithSizeHint(1000)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := NewWithInput(lexer.New(input), input, bm.opts...).Parse(); err != nil {
+					b.Fatalf("Parse failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"slices"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// Profile names a coherent set of syntax and strictness settings, so that
+// callers pick one behavior instead of combining individual options. A
+// profile configures both the lexer and the parser: pass its LexerOptions to
+// the lexer and WithProfile to the parser.
+//
+//	l := lexer.New(input, parser.ProfilePermissive.LexerOptions()...)
+//	p := parser.New(l, parser.WithProfile(parser.ProfilePermissive))
+type Profile int
+
+const (
+	// ProfileDefault is the behavior without options: RFC 8259 syntax, the
+	// last of duplicate keys wins, invalid UTF-8 and lone surrogates decode
+	// to U+FFFD and a byte order mark is skipped with a warning.
+	ProfileDefault Profile = iota
+
+	// ProfileStrict8259 accepts only what RFC 8259 allows without
+	// exception: duplicate keys, invalid UTF-8, lone surrogates and byte
+	// order marks are errors.
+	ProfileStrict8259
+
+	// ProfilePermissive accepts what hand-written and legacy documents
+	// commonly contain: comments, trailing commas, unescaped control
+	// characters in strings and byte order marks, without warnings.
+	ProfilePermissive
+
+	// ProfileJSON5 accepts the parts of JSON5 the lexer supports: comments,
+	// trailing commas and hexadecimal numbers. Single-quoted strings,
+	// unquoted keys, Infinity, NaN and the other JSON5 number forms are
+	// still rejected.
+	ProfileJSON5
+
+	// ProfileIJSON accepts only I-JSON (RFC 7493), as WithIJSON with the
+	// strict lexer options does, and makes a byte order mark an error.
+	ProfileIJSON
+)
+
+// profileNames are the names of the profiles, indexed by Profile.
+var profileNames = [...]string{"default", "strict", "permissive", "json5", "i-json"}
+
+// String returns the name of the profile, as accepted by ParseProfile.
+func (pr Profile) String() string {
+	if pr < 0 || int(pr) >= len(profileNames) {
+		return "unknown"
+	}
+	return profileNames[pr]
+}
+
+// ParseProfile returns the profile with the given name, one of
+// ProfileNames, or false if there is none.
+func ParseProfile(name string) (Profile, bool) {
+	i := slices.Index(profileNames[:], name)
+	return Profile(max(i, 0)), i >= 0
+}
+
+// ProfileNames returns the names of the profiles in the order of their
+// values, for listing the choices in messages and flag usage.
+func ProfileNames() []string {
+	return slices.Clone(profileNames[:])
+}
+
+// LexerOptions returns the lexer options of the profile.
+func (pr Profile) LexerOptions() []lexer.Option {
+	switch pr {
+	case ProfileStrict8259, ProfileIJSON:
+		return []lexer.Option{lexer.WithStrictUTF8(), lexer.WithStrictSurrogates()}
+	case ProfilePermissive:
+		return []lexer.Option{lexer.WithComments(), lexer.WithControlCharacters()}
+	case ProfileJSON5:
+		return []lexer.Option{lexer.WithComments(), lexer.WithNumberExtensions(lexer.HexNumbers)}
+	}
+	return nil
+}
+
+// WithProfile applies the parser settings of the profile; see Profile. Later
+// options override them, e.g. WithDuplicateKeys after
+// WithProfile(ProfileStrict8259) to let the last of duplicate keys win.
+func WithProfile(pr Profile) Option {
+	return func(c *config) {
+		c.trailingCommas, c.ijson = false, false
+		c.duplicateKeys, c.keyMatching = DuplicateKeysLast, 0
+		c.bom = BOMWarn
+		switch pr {
+		case ProfileStrict8259:
+			c.duplicateKeys = DuplicateKeysError
+			c.bom = BOMError
+		case ProfilePermissive:
+			c.trailingCommas = true
+			c.bom = BOMIgnore
+		case ProfileJSON5:
+			c.trailingCommas = true
+		case ProfileIJSON:
+			c.ijson = true
+			c.duplicateKeys = DuplicateKeysError
+			c.bom = BOMError
+		}
+	}
+}
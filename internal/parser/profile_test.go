@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithProfile(t *testing.T) {
+	inputs := []struct {
+		name  string
+		input string
+		valid map[Profile]bool // profiles accepting the input
+	}{
+		{name: "plain", input: `{"a": [1, "b"]}`, valid: map[Profile]bool{ProfileDefault: true, ProfileStrict8259: true, ProfilePermissive: true, ProfileJSON5: true, ProfileIJSON: true}},
+		{name: "top-level scalar", input: `1`, valid: map[Profile]bool{ProfileDefault: true, ProfileStrict8259: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "unsafe integer", input: `[9007199254740993]`, valid: map[Profile]bool{ProfileDefault: true, ProfileStrict8259: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "comments", input: "{\"a\": 1 // one\n}", valid: map[Profile]bool{ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "trailing comma", input: `[1, 2,]`, valid: map[Profile]bool{ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "duplicate keys", input: `{"a": 1, "a": 2}`, valid: map[Profile]bool{ProfileDefault: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "control character", input: "[\"a\tb\"]", valid: map[Profile]bool{ProfilePermissive: true}},
+		{name: "hex number", input: `[0xFF]`, valid: map[Profile]bool{ProfileJSON5: true}},
+		{name: "lone surrogate", input: `["\uD83D"]`, valid: map[Profile]bool{ProfileDefault: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "invalid UTF-8", input: "[\"\xff\"]", valid: map[Profile]bool{ProfileDefault: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "byte order mark", input: "\uFEFF[1]", valid: map[Profile]bool{ProfileDefault: true, ProfilePermissive: true, ProfileJSON5: true}},
+		{name: "single quotes", input: `['a']`, valid: map[Profile]bool{}},
+	}
+	profiles := []Profile{ProfileDefault, ProfileStrict8259, ProfilePermissive, ProfileJSON5, ProfileIJSON}
+
+	for _, tt := range inputs {
+		for _, profile := range profiles {
+			l := lexer.New(tt.input, profile.LexerOptions()...)
+			_, err := NewWithInput(l, tt.input, WithProfile(profile)).Parse()
+			if valid := err == nil; valid != tt.valid[profile] {
+				t.Errorf("%s with profile %s: expected valid %v, got error %v", tt.name, profile, tt.valid[profile], err)
+			}
+		}
+	}
+}
+
+func TestParser_WithProfile_Overrides(t *testing.T) {
+	input := `{"a": 1, "a": 2}`
+
+	// A later option overrides the profile...
+	p := New(lexer.New(input), WithProfile(ProfileStrict8259), WithDuplicateKeys(DuplicateKeysLast, 0))
+	if _, err := p.Parse(); err != nil {
+		t.Errorf("expected WithDuplicateKeys to override the profile, got %v", err)
+	}
+
+	// ...and the profile overrides earlier options.
+	p = New(lexer.New(`[1,]`), WithTrailingCommas(), WithProfile(ProfileDefault))
+	if _, err := p.Parse(); err == nil {
+		t.Error("expected ProfileDefault to reject trailing commas")
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	for i, name := range ProfileNames() {
+		pr, ok := ParseProfile(name)
+		if !ok || pr != Profile(i) || pr.String() != name {
+			t.Errorf("expected %q to name profile %d, got %d (%s), %v", name, i, pr, pr, ok)
+		}
+	}
+	if _, ok := ParseProfile("relaxed"); ok {
+		t.Error("expected an unknown name to be rejected")
+	}
+	if got := Profile(-1).String(); got != "unknown" {
+		t.Errorf("expected an invalid profile to be unknown, got %q", got)
+	}
+}
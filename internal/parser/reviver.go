@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Reviver transforms a parsed value, like the reviver argument of
+// JavaScript's JSON.parse. path is the JSON Pointer (RFC 6901) to v in the
+// input document, "" for the root. Return SkipValue to drop v from its object
+// or array; a dropped root parses as null.
+type Reviver func(path string, v JSONValue) (JSONValue, error)
+
+// SkipValue is returned by a Reviver to drop the value it was given.
+var SkipValue = errors.New("skip this value")
+
+// ReviverError reports an error returned by a Reviver.
+type ReviverError struct {
+	Path string // JSON Pointer (RFC 6901) to the value, "" for the root
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ReviverError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("reviver failed at %s: %v", path, e.Err)
+}
+
+// Unwrap returns the reviver's error.
+func (e *ReviverError) Unwrap() error {
+	return e.Err
+}
+
+// WithReviver calls revive on every parsed value as it is completed, so a
+// container's members are revived before the container itself. This allows
+// converting values, such as ISO 8601 strings to time.Time, or dropping
+// members while parsing, without a second traversal of the document.
+func WithReviver(revive Reviver) Option {
+	return func(c *config) {
+		c.reviver = revive
+	}
+}
+
+// parseMember parses the value of the member or element token of the
+// container at p.path and passes it to the reviver, if there is one. keep is
+// false if the reviver dropped the value.
+func (p *parser) parseMember(token string) (value JSONValue, keep bool, err error) {
+	if !p.tracksPath() {
+		value, err = p.parseValue()
+		return value, err == nil, err
+	}
+
+	parent := p.path
+	p.path += "/" + pointer.Escape(token)
+	defer func() { p.path = parent }()
+
+	if value, err = p.parseValue(); err != nil {
+		return nil, false, err
+	}
+	if p.reviver == nil {
+		return value, true, nil
+	}
+	return p.revive(value)
+}
+
+// revive passes the value at p.path to the reviver.
+func (p *parser) revive(value JSONValue) (JSONValue, bool, error) {
+	revived, err := p.reviver(p.path, value)
+	if errors.Is(err, SkipValue) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, &ReviverError{Path: p.path, Err: err}
+	}
+	return revived, true, nil
+}
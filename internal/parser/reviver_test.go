@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/arena"
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithReviver_Order(t *testing.T) {
+	var paths []string
+	revive := func(path string, v JSONValue) (JSONValue, error) {
+		paths = append(paths, path)
+		return v, nil
+	}
+
+	_, err := New(lexer.New(`{"a": [1, {"b/c": true}], "d": null}`), WithReviver(revive)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Members in document order, each container after its members.
+	expected := []string{"/a/0", "/a/1/b~1c", "/a/1", "/a", "/d", ""}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %q, got %q", expected, paths)
+	}
+}
+
+func TestParser_WithReviver(t *testing.T) {
+	parseDates := func(path string, v JSONValue) (JSONValue, error) {
+		s, ok := v.(string)
+		if !ok || !strings.HasSuffix(path, "At") {
+			return v, nil
+		}
+		return time.Parse(time.RFC3339, s)
+	}
+	dropInternal := func(path string, v JSONValue) (JSONValue, error) {
+		if strings.Contains(path, "/_") {
+			return nil, SkipValue
+		}
+		return v, nil
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		revive   Reviver
+		expected JSONValue
+	}{
+		{
+			name:     "converts values",
+			input:    `{"createdAt": "2024-05-01T12:00:00Z", "name": "x"}`,
+			revive:   parseDates,
+			expected: JSONObject{"createdAt": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "name": "x"},
+		},
+		{
+			name:     "drops members",
+			input:    `{"_id": 1, "name": "x", "nested": {"_rev": 2, "ok": true}}`,
+			revive:   dropInternal,
+			expected: JSONObject{"name": "x", "nested": JSONObject{"ok": true}},
+		},
+		{
+			name:  "drops array elements",
+			input: `[1, 2, 3, 4]`,
+			revive: func(path string, v JSONValue) (JSONValue, error) {
+				if n, ok := v.(int64); ok && n%2 == 0 {
+					return nil, SkipValue
+				}
+				return v, nil
+			},
+			expected: []any{int64(1), int64(3)},
+		},
+		{
+			name:  "sees revived members",
+			input: `{"items": [1, 2]}`,
+			revive: func(path string, v JSONValue) (JSONValue, error) {
+				switch v := v.(type) {
+				case int64:
+					return v * 10, nil
+				case []any:
+					return len(v), nil
+				}
+				return v, nil
+			},
+			expected: JSONObject{"items": 2},
+		},
+		{
+			name:  "drops the root",
+			input: `{"a": 1}`,
+			revive: func(path string, v JSONValue) (JSONValue, error) {
+				if path == "" {
+					return nil, SkipValue
+				}
+				return v, nil
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := New(lexer.New(tt.input), WithReviver(tt.revive)).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParser_WithReviver_Arena(t *testing.T) {
+	revive := func(path string, v JSONValue) (JSONValue, error) {
+		if v == "skip" {
+			return nil, SkipValue
+		}
+		return v, nil
+	}
+
+	result, err := New(lexer.New(`[["a", "skip", "b"], "skip", "c"]`), WithArena(arena.New()), WithReviver(revive)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []any{[]any{"a", "b"}, "c"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestParser_WithReviver_Error(t *testing.T) {
+	errBad := errors.New("bad value")
+	revive := func(path string, v JSONValue) (JSONValue, error) {
+		if v == "bad" {
+			return nil, errBad
+		}
+		return v, nil
+	}
+
+	_, err := New(lexer.New(`{"list": ["ok", "bad"]}`), WithReviver(revive)).Parse()
+	var reviverErr *ReviverError
+	if !errors.As(err, &reviverErr) || !errors.Is(err, errBad) {
+		t.Fatalf("expected a ReviverError wrapping %v, got %v", errBad, err)
+	}
+	if reviverErr.Path != "/list/1" {
+		t.Errorf("expected path /list/1, got %s", reviverErr.Path)
+	}
+}
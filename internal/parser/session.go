@@ -0,0 +1,83 @@
+package parser
+
+import "github.com/VuNe/json-parser/internal/lexer"
+
+// maxInternedKeys bounds the key table of a Session so that documents with
+// unbounded key sets (e.g. IDs used as keys) can't grow it without limit.
+const maxInternedKeys = 4096
+
+// Result is the outcome of parsing a single document in a batch.
+type Result struct {
+	Value JSONValue
+	Err   error
+}
+
+// SessionStats aggregates statistics over all documents parsed by a Session.
+type SessionStats struct {
+	Documents    int // documents parsed, successfully or not
+	Failed       int // documents that failed to parse
+	Bytes        int // total input size in bytes
+	InternedKeys int // distinct object keys shared across documents
+}
+
+// Session parses many small documents, such as messages from a queue, while
+// sharing state between them: the options are resolved once, the lexer and
+// parser (with their buffers) are reused, and object keys are interned so
+// repeated keys are stored only once across all documents.
+//
+// A Session is not safe for concurrent use; use one Session per goroutine.
+type Session struct {
+	lexer  lexer.Lexer
+	parser *parser
+	stats  SessionStats
+}
+
+// NewSession creates a Session whose documents are parsed with opts.
+func NewSession(opts ...Option) *Session {
+	return &Session{
+		parser: &parser{
+			config: newConfig(opts),
+			keys:   make(map[string]string),
+		},
+	}
+}
+
+// Parse parses a single document. Errors include source context, as with NewWithInput.
+func (s *Session) Parse(input string) (JSONValue, error) {
+	if s.lexer == nil {
+		var opts []lexer.Option
+		if s.parser.zeroCopy {
+			opts = append(opts, lexer.WithZeroCopy())
+		}
+		s.lexer = lexer.New(input, opts...)
+	} else {
+		s.lexer.Reset(input)
+	}
+	s.parser.reset(s.lexer, input)
+
+	value, err := s.parser.Parse()
+
+	s.stats.Documents++
+	s.stats.Bytes += len(input)
+	s.stats.InternedKeys = len(s.parser.keys)
+	if err != nil {
+		s.stats.Failed++
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseBatch parses each input and returns one Result per input, in order.
+// A failing document doesn't stop the batch.
+func (s *Session) ParseBatch(inputs []string) []Result {
+	results := make([]Result, len(inputs))
+	for i, input := range inputs {
+		results[i].Value, results[i].Err = s.Parse(input)
+	}
+	return results
+}
+
+// Stats returns the aggregate statistics for all documents parsed so far.
+func (s *Session) Stats() SessionStats {
+	return s.stats
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestSession_ParseBatch(t *testing.T) {
+	inputs := []string{
+		`{"id": 1, "event": "created", "tags": ["a", "b"]}`,
+		`{"id": 2, "event": }`,
+		`{"id": 3, "event": "deleted", "tags": []}`,
+		`[1, 2, 3]`,
+	}
+
+	session := NewSession()
+	results := session.ParseBatch(inputs)
+
+	if len(results) != len(inputs) {
+		t.Fatalf("expected %d results, got %d", len(inputs), len(results))
+	}
+
+	for i, input := range inputs {
+		expected, expectedErr := NewWithInput(lexer.New(input), input).Parse()
+
+		if (results[i].Err != nil) != (expectedErr != nil) {
+			t.Errorf("document %d: expected error %v, got %v", i, expectedErr, results[i].Err)
+			continue
+		}
+		if expectedErr != nil && results[i].Err.Error() != expectedErr.Error() {
+			t.Errorf("document %d: expected error %q, got %q", i, expectedErr.Error(), results[i].Err.Error())
+		}
+		if !reflect.DeepEqual(results[i].Value, expected) {
+			t.Errorf("document %d: expected %v, got %v", i, expected, results[i].Value)
+		}
+	}
+
+	stats := session.Stats()
+	expectedBytes := 0
+	for _, input := range inputs {
+		expectedBytes += len(input)
+	}
+	if stats.Documents != 4 || stats.Failed != 1 || stats.Bytes != expectedBytes {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.InternedKeys != 3 {
+		t.Errorf("expected 3 interned keys (id, event, tags), got %d", stats.InternedKeys)
+	}
+}
+
+func TestSession_InternsKeys(t *testing.T) {
+	session := NewSession()
+
+	first, err := session.Parse(`{"name": "a"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := session.Parse(`{"name": "b"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	firstKey := keyOf(t, first)
+	secondKey := keyOf(t, second)
+	if unsafe.StringData(firstKey) != unsafe.StringData(secondKey) {
+		t.Error("expected both documents to share the interned key")
+	}
+}
+
+func keyOf(t *testing.T, value JSONValue) string {
+	t.Helper()
+
+	obj, ok := value.(JSONObject)
+	if !ok {
+		t.Fatalf("expected JSONObject, got %T", value)
+	}
+	for key := range obj {
+		return key
+	}
+	t.Fatal("expected a key")
+	return ""
+}
@@ -0,0 +1,62 @@
+package parser
+
+import "math/big"
+
+// Approximate heap costs on a 64-bit platform, used by EstimateSize.
+const (
+	interfaceSize    = 16 // type word + data word stored for every element
+	stringHeaderSize = 16 // pointer + length
+	sliceHeaderSize  = 24 // pointer + length + capacity
+	boxedScalarSize  = 8  // int64/float64 moved to the heap when stored in an interface
+	mapHeaderSize    = 48 // runtime map header
+	mapEntryOverhead = 8  // per-entry bookkeeping (tophash bytes, load factor slack)
+	bigIntSize       = 32 // sign + slice header of big.Int, see WithBigNumbers
+	bigFloatSize     = 48 // precision, exponent, flags + slice header of big.Float
+	wordSize         = 8  // one word of a big.Int or big.Float mantissa
+)
+
+// EstimateSize returns the approximate number of heap bytes retained by a
+// parsed value, including all nested maps, slices, and strings. The figure is
+// meant for reasoning about caching parsed documents, not exact accounting:
+// the Go runtime's size classes and map growth make the real number differ.
+func EstimateSize(value JSONValue) int {
+	switch v := value.(type) {
+	case nil, bool:
+		// nil and booleans don't allocate when boxed in an interface.
+		return 0
+	case int64, float64:
+		return boxedScalarSize
+	case string:
+		return stringHeaderSize + len(v)
+	case Number:
+		return stringHeaderSize + len(v)
+	case *big.Int:
+		return bigIntSize + len(v.Bits())*wordSize
+	case *big.Float:
+		return bigFloatSize + int(v.Prec()+63)/64*wordSize
+	case []any:
+		size := sliceHeaderSize + cap(v)*interfaceSize
+		for _, elem := range v {
+			size += EstimateSize(elem)
+		}
+		return size
+	case JSONObject:
+		return estimateMapSize(v)
+	case EmptyObject:
+		return estimateMapSize(v)
+	case map[string]any:
+		return estimateMapSize(v)
+	default:
+		return 0
+	}
+}
+
+// estimateMapSize estimates the heap bytes of an object and its contents.
+func estimateMapSize(obj map[string]any) int {
+	size := mapHeaderSize
+	for key, elem := range obj {
+		size += stringHeaderSize + len(key) + interfaceSize + mapEntryOverhead
+		size += EstimateSize(elem)
+	}
+	return size
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestEstimateSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    JSONValue
+		expected int
+	}{
+		{name: "null", value: nil, expected: 0},
+		{name: "boolean", value: true, expected: 0},
+		{name: "integer", value: int64(1), expected: boxedScalarSize},
+		{name: "float", value: 1.5, expected: boxedScalarSize},
+		{name: "string", value: "hello", expected: stringHeaderSize + 5},
+		{name: "empty array", value: []any{}, expected: sliceHeaderSize},
+		{
+			name:     "array of scalars",
+			value:    []any{int64(1), "ab"},
+			expected: sliceHeaderSize + 2*interfaceSize + boxedScalarSize + stringHeaderSize + 2,
+		},
+		{name: "empty object", value: NewJSONObject(), expected: mapHeaderSize},
+		{
+			name:     "object",
+			value:    JSONObject{"key": "value"},
+			expected: mapHeaderSize + stringHeaderSize + 3 + interfaceSize + mapEntryOverhead + stringHeaderSize + 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if size := EstimateSize(tt.value); size != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestEstimateSize_GrowsWithContent(t *testing.T) {
+	small, err := New(lexer.New(`{"items": [1, 2, 3]}`)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	large, err := New(lexer.New(`{"items": [1, 2, 3], "nested": {"name": "a much longer string value", "list": [true, false]}}`)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if EstimateSize(large) <= EstimateSize(small) {
+		t.Errorf("expected larger document to have a larger estimate: %d <= %d", EstimateSize(large), EstimateSize(small))
+	}
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortArrays returns a copy of value where every array consisting only of
+// scalars (strings, numbers, booleans, and null) is sorted. Arrays that
+// contain objects or arrays keep their order because there is no natural
+// ordering for them, but their elements are still processed recursively.
+func SortArrays(value JSONValue) JSONValue {
+	switch v := value.(type) {
+	case JSONObject:
+		sorted := make(JSONObject, len(v))
+		for key, elem := range v {
+			sorted[key] = SortArrays(elem)
+		}
+		return sorted
+	case map[string]any:
+		sorted := make(map[string]any, len(v))
+		for key, elem := range v {
+			sorted[key] = SortArrays(elem)
+		}
+		return sorted
+	case []any:
+		sorted := make([]any, len(v))
+		scalarsOnly := true
+		for i, elem := range v {
+			sorted[i] = SortArrays(elem)
+			if !isScalar(elem) {
+				scalarsOnly = false
+			}
+		}
+		if scalarsOnly {
+			slices.SortStableFunc(sorted, compareScalars)
+		}
+		return sorted
+	default:
+		return value
+	}
+}
+
+// isScalar reports whether v is a JSON primitive rather than a container.
+func isScalar(v JSONValue) bool {
+	switch v.(type) {
+	case nil, bool, string, int64, float64, Number:
+		return true
+	default:
+		return false
+	}
+}
+
+// scalarRank orders values of different JSON types: null, booleans, numbers, strings.
+func scalarRank(v JSONValue) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64, Number:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// compareScalars orders two scalars first by type rank and then by value.
+func compareScalars(a, b any) int {
+	if rankA, rankB := scalarRank(a), scalarRank(b); rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		if av == bv {
+			return 0
+		}
+		if !av {
+			return -1
+		}
+		return 1
+	case string:
+		return cmp.Compare(av, b.(string))
+	case int64, float64, Number:
+		return compareNumbers(av, b)
+	default:
+		return 0
+	}
+}
+
+// compareNumbers compares two numeric values regardless of whether they were
+// parsed as int64, float64 or Number.
+func compareNumbers(a, b any) int {
+	if n, ok := a.(Number); ok {
+		a, _ = n.Value()
+	}
+	if n, ok := b.(Number); ok {
+		b, _ = n.Value()
+	}
+	ai, aIsInt := a.(int64)
+	bi, bIsInt := b.(int64)
+	if aIsInt && bIsInt {
+		return cmp.Compare(ai, bi)
+	}
+	return cmp.Compare(toFloat(a), toFloat(b))
+}
+
+// toFloat converts a parsed number to float64.
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortArrays(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    JSONValue
+		expected JSONValue
+	}{
+		{
+			name:     "scalar value is unchanged",
+			input:    "text",
+			expected: "text",
+		},
+		{
+			name:     "numbers sorted",
+			input:    []any{int64(3), 1.5, int64(-2)},
+			expected: []any{int64(-2), 1.5, int64(3)},
+		},
+		{
+			name:     "mixed scalars ordered by type then value",
+			input:    []any{"b", int64(1), true, nil, "a", false},
+			expected: []any{nil, false, true, int64(1), "a", "b"},
+		},
+		{
+			name:     "arrays containing containers keep their order",
+			input:    []any{int64(2), []any{"z", "y"}, int64(1)},
+			expected: []any{int64(2), []any{"y", "z"}, int64(1)},
+		},
+		{
+			name:     "nested in objects",
+			input:    JSONObject{"tags": []any{"c", "a", "b"}},
+			expected: JSONObject{"tags": []any{"a", "b", "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SortArrays(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSortArrays_DoesNotModifyInput(t *testing.T) {
+	input := []any{int64(2), int64(1)}
+	SortArrays(input)
+
+	if input[0] != int64(2) || input[1] != int64(1) {
+		t.Errorf("input was modified: %v", input)
+	}
+}
@@ -19,3 +19,18 @@ type EmptyObject map[string]any
 func NewEmptyObject() EmptyObject {
 	return make(EmptyObject)
 }
+
+// AsObject returns v as a map if it is any of the object types produced by
+// the parser (JSONObject, EmptyObject) or a plain map[string]any.
+func AsObject(v JSONValue) (map[string]any, bool) {
+	switch obj := v.(type) {
+	case JSONObject:
+		return obj, true
+	case EmptyObject:
+		return obj, true
+	case map[string]any:
+		return obj, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+func TestParser_WithZeroCopy(t *testing.T) {
+	inputs := []string{
+		`{"name": "test", "items": [1, "two", ["three"]], "escaped": "a\"bé", "": ""}`,
+		`"top-level string"`,
+		"[\"multi\nline\", 2]",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			// The raw newline needs WithControlCharacters.
+			expected, err := New(lexer.New(input, lexer.WithControlCharacters())).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result, err := New(lexer.New(input, lexer.WithControlCharacters(), lexer.WithZeroCopy()), WithZeroCopy()).Parse()
+			if err != nil {
+				t.Fatalf("unexpected error with zero copy: %v", err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %v, got %v", expected, result)
+			}
+		})
+	}
+}
+
+func TestParser_WithZeroCopyShares(t *testing.T) {
+	input := `{"key": "value"}`
+	inInput := func(s string) bool {
+		start := uintptr(unsafe.Pointer(unsafe.StringData(input)))
+		p := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+		return p >= start && p < start+uintptr(len(input))
+	}
+
+	copied, err := New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inInput(copied.(JSONObject)["key"].(string)) {
+		t.Error("expected the value to be copied by default")
+	}
+
+	shared, err := New(lexer.New(input, lexer.WithZeroCopy()), WithZeroCopy()).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inInput(shared.(JSONObject)["key"].(string)) {
+		t.Error("expected the value to share the input's memory")
+	}
+
+	session := NewSession(WithZeroCopy())
+	value, err := session.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inInput(value.(JSONObject)["key"].(string)) {
+		t.Error("expected the session's value to share the input's memory")
+	}
+	for key := range session.parser.keys {
+		if inInput(key) {
+			t.Errorf("expected the interned key %q to be copied", key)
+		}
+	}
+}
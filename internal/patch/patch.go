@@ -0,0 +1,346 @@
+// Package patch applies JSON Patch documents (RFC 6902): sequences of add,
+// remove, replace, move, copy and test operations addressed by JSON Pointers.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/diff"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// ErrTestFailed reports a test operation whose value differs from the one in
+// the document.
+var ErrTestFailed = errors.New("test failed")
+
+// Operation is a single patch operation.
+type Operation struct {
+	Op    string           // add, remove, replace, move, copy or test
+	Path  string           // JSON Pointer to the target location
+	From  string           // JSON Pointer to the source, for move and copy
+	Value parser.JSONValue // the value, for add, replace and test
+}
+
+// OperationError reports an operation that is malformed or can't be applied.
+type OperationError struct {
+	Index int // 0-based index of the operation in the patch
+	Op    string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *OperationError) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Parse returns the operations of a parsed patch document, which must be an
+// array of operation objects. Members other than op, path, from and value are
+// ignored, as RFC 6902 requires.
+func Parse(patch parser.JSONValue) ([]Operation, error) {
+	elements, ok := patch.([]any)
+	if !ok {
+		return nil, errors.New("a patch must be an array of operations")
+	}
+
+	ops := make([]Operation, 0, len(elements))
+	for i, element := range elements {
+		op, err := parseOperation(element)
+		if err != nil {
+			return nil, &OperationError{Index: i, Op: op.Op, Err: err}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// parseOperation returns the operation described by element.
+func parseOperation(element parser.JSONValue) (Operation, error) {
+	obj, ok := parser.AsObject(element)
+	if !ok {
+		return Operation{}, errors.New("an operation must be an object")
+	}
+
+	var op Operation
+	var err error
+	if op.Op, err = stringMember(obj, "op"); err != nil {
+		return op, err
+	}
+	if op.Path, err = stringMember(obj, "path"); err != nil {
+		return op, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, ok := obj["value"]
+		if !ok {
+			return op, errors.New(`missing "value" member`)
+		}
+		op.Value = value
+	case "move", "copy":
+		if op.From, err = stringMember(obj, "from"); err != nil {
+			return op, err
+		}
+	case "remove":
+	default:
+		return op, fmt.Errorf("unknown operation %q", op.Op)
+	}
+	return op, nil
+}
+
+// stringMember returns the string member name of obj.
+func stringMember(obj map[string]any, name string) (string, error) {
+	value, ok := obj[name]
+	if !ok {
+		return "", fmt.Errorf("missing %q member", name)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%q must be a string", name)
+	}
+	return s, nil
+}
+
+// Apply applies ops to doc in order and returns the result. Patches are
+// atomic: doc itself is never modified, and if any operation fails the
+// result is discarded and an *OperationError is returned.
+func Apply(doc parser.JSONValue, ops []Operation) (parser.JSONValue, error) {
+	result := clone(doc)
+	for i, op := range ops {
+		var err error
+		if result, err = apply(result, op); err != nil {
+			return nil, &OperationError{Index: i, Op: op.Op, Err: err}
+		}
+	}
+	return result, nil
+}
+
+// apply applies a single operation to doc.
+func apply(doc parser.JSONValue, op Operation) (parser.JSONValue, error) {
+	path, err := pointer.Split(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, path, clone(op.Value))
+	case "remove":
+		return remove(doc, path)
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return clone(op.Value), nil
+		}
+		return update(doc, path, func(parent parser.JSONValue, token string) (parser.JSONValue, error) {
+			return set(parent, token, clone(op.Value))
+		})
+	case "move", "copy":
+		from, err := pointer.Split(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, clone(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into its own child %s", op.From, op.Path)
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "test":
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !diff.Equal(value, op.Value) {
+			return nil, fmt.Errorf("%w: value at %q differs", ErrTestFailed, op.Path)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// add adds value at path: it sets an object member, inserts into an array
+// ("-" appends) or, for the root, replaces the whole document.
+func add(doc parser.JSONValue, path []string, value parser.JSONValue) (parser.JSONValue, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent parser.JSONValue, token string) (parser.JSONValue, error) {
+		if obj, ok := parser.AsObject(parent); ok {
+			obj[token] = value
+			return parent, nil
+		}
+		arr, ok := parent.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot add to a %s", kind(parent))
+		}
+		if token == "-" {
+			return append(arr, value), nil
+		}
+		i, err := index(token, len(arr)+1)
+		if err != nil {
+			return nil, err
+		}
+		return append(arr[:i], append([]any{value}, arr[i:]...)...), nil
+	})
+}
+
+// remove removes the value at path, which must exist.
+func remove(doc parser.JSONValue, path []string) (parser.JSONValue, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the root")
+	}
+	return update(doc, path, func(parent parser.JSONValue, token string) (parser.JSONValue, error) {
+		if obj, ok := parser.AsObject(parent); ok {
+			if _, ok := obj[token]; !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			delete(obj, token)
+			return parent, nil
+		}
+		arr, ok := parent.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot remove from a %s", kind(parent))
+		}
+		i, err := index(token, len(arr))
+		if err != nil {
+			return nil, err
+		}
+		return append(arr[:i], arr[i+1:]...), nil
+	})
+}
+
+// update replaces the container holding the last token of path with the
+// result of fn, which is given the container and that token, and returns the
+// updated document. Every container on the way must exist.
+func update(doc parser.JSONValue, path []string, fn func(parent parser.JSONValue, token string) (parser.JSONValue, error)) (parser.JSONValue, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = update(child, path[1:], fn); err != nil {
+		return nil, err
+	}
+	return set(doc, path[0], child)
+}
+
+// set replaces the existing member or element token of container.
+func set(container parser.JSONValue, token string, value parser.JSONValue) (parser.JSONValue, error) {
+	if obj, ok := parser.AsObject(container); ok {
+		obj[token] = value
+		return container, nil
+	}
+	arr, ok := container.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot index into a %s", kind(container))
+	}
+	i, err := index(token, len(arr))
+	if err != nil {
+		return nil, err
+	}
+	arr[i] = value
+	return arr, nil
+}
+
+// get returns the value at path, which must exist.
+func get(doc parser.JSONValue, path []string) (parser.JSONValue, error) {
+	value := doc
+	for _, token := range path {
+		if obj, ok := parser.AsObject(value); ok {
+			member, ok := obj[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			value = member
+			continue
+		}
+		arr, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot index into a %s", kind(value))
+		}
+		i, err := index(token, len(arr))
+		if err != nil {
+			return nil, err
+		}
+		value = arr[i]
+	}
+	return value, nil
+}
+
+// index parses an array index token, which must be below limit. Leading
+// zeros are not allowed (RFC 6901, section 4).
+func index(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.Trim(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// kind names the type of a scalar value for errors.
+func kind(v parser.JSONValue) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+// clone returns a deep copy of v, so that patching never modifies shared
+// objects or arrays.
+func clone(v parser.JSONValue) parser.JSONValue {
+	if obj, ok := parser.AsObject(v); ok {
+		copied := make(parser.JSONObject, len(obj))
+		for key, member := range obj {
+			copied[key] = clone(member)
+		}
+		return copied
+	}
+	if arr, ok := v.([]any); ok {
+		copied := make([]any, len(arr))
+		for i, element := range arr {
+			copied[i] = clone(element)
+		}
+		return copied
+	}
+	return v
+}
@@ -0,0 +1,110 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/diff"
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string // empty if the patch fails
+	}{
+		// Examples from RFC 6902, appendix A.
+		{name: "add member", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, expected: `{"baz": "qux", "foo": "bar"}`},
+		{name: "insert element", doc: `{"foo": ["bar", "baz"]}`, patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, expected: `{"foo": ["bar", "qux", "baz"]}`},
+		{name: "append element", doc: `[1, 2]`, patch: `[{"op": "add", "path": "/-", "value": [3]}]`, expected: `[1, 2, [3]]`},
+		{name: "remove member", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "remove", "path": "/baz"}]`, expected: `{"foo": "bar"}`},
+		{name: "remove element", doc: `{"foo": ["bar", "qux", "baz"]}`, patch: `[{"op": "remove", "path": "/foo/1"}]`, expected: `{"foo": ["bar", "baz"]}`},
+		{name: "replace", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, expected: `{"baz": "boo", "foo": "bar"}`},
+		{
+			name:     "move member",
+			doc:      `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{name: "move element", doc: `{"foo": ["all", "grass", "cows", "eat"]}`, patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, expected: `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{name: "copy", doc: `{"a": {"b": 1}}`, patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b", "value": 2}]`, expected: `{"a": {"b": 1}, "c": {"b": 2}}`},
+		{name: "test", doc: `{"baz": "qux", "foo": ["a", 2, "c"]}`, patch: `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2.0}]`, expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`},
+		{name: "escaped pointer", doc: `{"a/b": {"~": 1}}`, patch: `[{"op": "replace", "path": "/a~1b/~0", "value": 2}]`, expected: `{"a/b": {"~": 2}}`},
+		{name: "replace root", doc: `{"a": 1}`, patch: `[{"op": "replace", "path": "", "value": [1]}]`, expected: `[1]`},
+		{name: "extra members ignored", doc: `{}`, patch: `[{"op": "add", "path": "/a", "value": 1, "note": "x"}]`, expected: `{"a": 1}`},
+		{name: "failed test", doc: `{"baz": "qux"}`, patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`},
+		{name: "missing parent", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`},
+		{name: "remove missing member", doc: `{}`, patch: `[{"op": "remove", "path": "/a"}]`},
+		{name: "replace missing member", doc: `{}`, patch: `[{"op": "replace", "path": "/a", "value": 1}]`},
+		{name: "index out of range", doc: `[1]`, patch: `[{"op": "add", "path": "/2", "value": 2}]`},
+		{name: "leading zero index", doc: `[1, 2]`, patch: `[{"op": "remove", "path": "/01"}]`},
+		{name: "move into own child", doc: `{"a": {"b": {}}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`},
+		{name: "remove root", doc: `{}`, patch: `[{"op": "remove", "path": ""}]`},
+		{name: "unknown operation", doc: `{}`, patch: `[{"op": "merge", "path": "/a"}]`},
+		{name: "missing value", doc: `{}`, patch: `[{"op": "add", "path": "/a"}]`},
+		{name: "missing from", doc: `{}`, patch: `[{"op": "copy", "path": "/a"}]`},
+		{name: "not an array", doc: `{}`, patch: `{"op": "remove", "path": "/a"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.doc)
+			ops, err := Parse(mustParse(t, tt.patch))
+			var result parser.JSONValue
+			if err == nil {
+				result, err = Apply(doc, ops)
+			}
+
+			if tt.expected == "" {
+				if err == nil {
+					encoded, _ := encoder.Marshal(result)
+					t.Fatalf("expected an error, got %s", encoded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changes := diff.Compare(mustParse(t, tt.expected), result); len(changes) > 0 {
+				t.Errorf("unexpected result: %v", changes)
+			}
+		})
+	}
+}
+
+func TestApply_Atomic(t *testing.T) {
+	doc := mustParse(t, `{"a": [1, 2], "b": {"c": 3}}`)
+	ops, err := Parse(mustParse(t, `[
+		{"op": "remove", "path": "/a/0"},
+		{"op": "add", "path": "/b/d", "value": 4},
+		{"op": "test", "path": "/b/c", "value": 5}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = Apply(doc, ops)
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || opErr.Index != 2 || opErr.Op != "test" {
+		t.Fatalf("expected an *OperationError for operation 2, got %v", err)
+	}
+	if !errors.Is(err, ErrTestFailed) {
+		t.Errorf("expected ErrTestFailed, got %v", err)
+	}
+	if !diff.Equal(doc, mustParse(t, `{"a": [1, 2], "b": {"c": 3}}`)) {
+		t.Errorf("the document was modified: %v", doc)
+	}
+}
@@ -0,0 +1,42 @@
+package pointer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Escape escapes a single reference token for use in a JSON Pointer
+// (RFC 6901): "~" becomes "~0" and "/" becomes "~1".
+func Escape(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// Split returns the unescaped reference tokens of a JSON Pointer, none for
+// the root pointer "". Pointers other than "" must start with '/', and '~'
+// may only appear in the escapes "~0" and "~1".
+func Split(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must start with '/'", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("invalid JSON Pointer %q: '~' must be followed by 0 or 1", ptr)
+			}
+			j++
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
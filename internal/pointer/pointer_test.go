@@ -0,0 +1,57 @@
+package pointer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		token    string
+		expected string
+	}{
+		{token: "plain", expected: "plain"},
+		{token: "", expected: ""},
+		{token: "a/b", expected: "a~1b"},
+		{token: "c~d", expected: "c~0d"},
+		{token: "~/", expected: "~0~1"},
+		{token: "~1", expected: "~01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			if got := Escape(tt.token); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		ptr      string
+		expected []string
+		wantErr  bool
+	}{
+		{ptr: "", expected: nil},
+		{ptr: "/", expected: []string{""}},
+		{ptr: "/a/0", expected: []string{"a", "0"}},
+		{ptr: "/a~1b/c~0d", expected: []string{"a/b", "c~d"}},
+		{ptr: "/~01", expected: []string{"~1"}},
+		{ptr: "a", wantErr: true},
+		{ptr: "/a~", wantErr: true},
+		{ptr: "/a~2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ptr, func(t *testing.T) {
+			tokens, err := Split(tt.ptr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(tokens, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Filter returns a pruned copy of value holding only the subtrees for which
+// pred returns true, together with the objects and arrays that lead to them.
+// pred is called with the JSON Pointer of each value, "" for the root, and is
+// not called below a value it accepted. Pruned arrays are compacted, so
+// element indices in the result may differ from the paths pred saw.
+//
+// Filter returns nil if nothing matches. The containers on the way to a
+// match are new; the matching subtrees themselves are shared with value, not
+// copied.
+func Filter(value parser.JSONValue, pred func(path string, v parser.JSONValue) bool) parser.JSONValue {
+	result, _ := filter("", value, pred)
+	return result
+}
+
+// filter returns the pruned copy of value at path and whether anything in it
+// matched.
+func filter(path string, value parser.JSONValue, pred func(string, parser.JSONValue) bool) (parser.JSONValue, bool) {
+	if pred(path, value) {
+		return value, true
+	}
+
+	if arr, ok := value.([]any); ok {
+		var kept []any
+		for i, elem := range arr {
+			if child, ok := filter(path+"/"+strconv.Itoa(i), elem, pred); ok {
+				kept = append(kept, child)
+			}
+		}
+		if kept == nil {
+			return nil, false
+		}
+		return kept, true
+	}
+
+	obj, ok := parser.AsObject(value)
+	if !ok {
+		return nil, false
+	}
+	var kept parser.JSONObject
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		if child, ok := filter(path+"/"+pointer.Escape(key), obj[key], pred); ok {
+			if kept == nil {
+				kept = parser.JSONObject{}
+			}
+			kept[key] = child
+		}
+	}
+	if kept == nil {
+		return nil, false
+	}
+	return kept, true
+}
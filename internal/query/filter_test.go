@@ -0,0 +1,100 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestFilter(t *testing.T) {
+	doc := `{
+		"name": "app",
+		"services": [
+			{"name": "web", "port": 80, "tags": ["public"]},
+			{"name": "db", "port": 5432}
+		],
+		"owner": {"email": "ops@example.com"}
+	}`
+
+	tests := []struct {
+		name     string
+		pred     func(path string, v parser.JSONValue) bool
+		expected string // JSON, or "" for no match
+	}{
+		{
+			name:     "keeps ancestors of matches",
+			pred:     func(path string, v parser.JSONValue) bool { return strings.HasSuffix(path, "/port") },
+			expected: `{"services": [{"port": 80}, {"port": 5432}]}`,
+		},
+		{
+			name:     "compacts arrays",
+			pred:     func(path string, v parser.JSONValue) bool { return v == "db" },
+			expected: `{"services": [{"name": "db"}]}`,
+		},
+		{
+			name:     "keeps matched subtrees whole",
+			pred:     func(path string, v parser.JSONValue) bool { return path == "/owner" },
+			expected: `{"owner": {"email": "ops@example.com"}}`,
+		},
+		{
+			name: "matches by value",
+			pred: func(path string, v parser.JSONValue) bool {
+				s, ok := v.(string)
+				return ok && strings.Contains(s, "@")
+			},
+			expected: `{"owner": {"email": "ops@example.com"}}`,
+		},
+		{
+			name:     "root match",
+			pred:     func(path string, v parser.JSONValue) bool { return path == "" },
+			expected: doc,
+		},
+		{
+			name: "no match",
+			pred: func(path string, v parser.JSONValue) bool { return false },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := mustParse(t, doc)
+			result := Filter(value, tt.pred)
+
+			var expected parser.JSONValue
+			if tt.expected != "" {
+				expected = mustParse(t, tt.expected)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %v, got %v", expected, result)
+			}
+			if !reflect.DeepEqual(value, mustParse(t, doc)) {
+				t.Error("input was modified")
+			}
+		})
+	}
+}
+
+func TestFilter_EscapedPaths(t *testing.T) {
+	var paths []string
+	Filter(mustParse(t, `{"a/b": {"c~d": 1}}`), func(path string, v parser.JSONValue) bool {
+		paths = append(paths, path)
+		return false
+	})
+
+	expected := []string{"", "/a~1b", "/a~1b/c~0d"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %q, got %q", expected, paths)
+	}
+}
@@ -0,0 +1,306 @@
+package query
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Result is a value selected by a path, together with its location.
+type Result struct {
+	Path  string // JSON Pointer (RFC 6901) to the value, "" for the root
+	Value parser.JSONValue
+}
+
+// segmentKind identifies the kind of a path segment.
+type segmentKind int
+
+const (
+	keySegment        segmentKind = iota // a member name, or an index when applied to an array
+	indexSegment                         // [n], counting from the end if negative
+	sliceSegment                         // [start:end]
+	wildcardSegment                      // * : every member or element
+	descendantSegment                    // ** : the value itself and everything below it
+)
+
+// segment is one step of a compiled path.
+type segment struct {
+	kind  segmentKind
+	key   string
+	index int // the index, or the start of a slice
+	end   int // the end of a slice, exclusive
+}
+
+// Path is a compiled path expression.
+//
+// Expressions are dot-separated member names with bracketed array indices,
+// e.g. `users[0].name`. As in Python and jq, negative indices count from the
+// end of the array, so `items[-1]` is the last element, and `items[2:5]`
+// selects elements 2 through 4; either bound of a slice may be omitted or
+// negative, and bounds past the ends are clamped. Names containing '.', '[' or ']' are written as
+// quoted strings in brackets: `["a.b"]`. Two wildcards select several values
+// at once: `*` matches every member of an object or element of an array, and
+// `**` matches the current value and all values nested below it, so
+// `**.id` finds every member named id anywhere. The empty expression selects
+// the root.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// Compile parses a path expression.
+func Compile(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	rest := expr
+	for i := 0; rest != ""; i++ {
+		switch {
+		case rest[0] == '[':
+			seg, n, err := parseBracket(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+			p.segments = append(p.segments, seg)
+			rest = rest[n:]
+		case i > 0 && rest[0] != '.':
+			return nil, fmt.Errorf("invalid path %q: expected '.' or '[' before %q", expr, rest)
+		default:
+			if i > 0 {
+				rest = rest[1:]
+			}
+			end := strings.IndexAny(rest, ".[]")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid path %q: empty member name", expr)
+			}
+			p.segments = append(p.segments, nameSegment(name))
+			rest = rest[end:]
+		}
+	}
+	return p, nil
+}
+
+// nameSegment returns the segment for an unbracketed name.
+func nameSegment(name string) segment {
+	switch name {
+	case "*":
+		return segment{kind: wildcardSegment}
+	case "**":
+		return segment{kind: descendantSegment}
+	default:
+		return segment{kind: keySegment, key: name}
+	}
+}
+
+// parseBracket parses the bracketed segment at the start of s and returns it
+// with the number of bytes consumed.
+func parseBracket(s string) (segment, int, error) {
+	end := strings.IndexByte(s, ']')
+	if len(s) > 1 && s[1] == '"' {
+		// The closing bracket follows the closing quote, which may be
+		// preceded by escaped quotes and brackets.
+		end = closingQuote(s, 1)
+		if end < 0 || end+1 >= len(s) || s[end+1] != ']' {
+			return segment{}, 0, fmt.Errorf("unterminated quoted name in %q", s)
+		}
+		key, err := strconv.Unquote(s[1 : end+1])
+		if err != nil {
+			return segment{}, 0, fmt.Errorf("invalid quoted name %s", s[1:end+1])
+		}
+		return segment{kind: keySegment, key: key}, end + 2, nil
+	}
+	if end < 0 {
+		return segment{}, 0, fmt.Errorf("missing ']' in %q", s)
+	}
+
+	inner := s[1:end]
+	if inner == "*" {
+		return segment{kind: wildcardSegment}, end + 1, nil
+	}
+	if from, to, ok := strings.Cut(inner, ":"); ok {
+		seg, err := parseSlice(from, to)
+		if err != nil {
+			return segment{}, 0, fmt.Errorf("invalid array slice %q", inner)
+		}
+		return seg, end + 1, nil
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, 0, fmt.Errorf("invalid array index %q", inner)
+	}
+	return segment{kind: indexSegment, index: index}, end + 1, nil
+}
+
+// parseSlice returns the slice segment for the bounds of `[from:to]`. An
+// omitted start is 0 and an omitted end is past the last element.
+func parseSlice(from, to string) (segment, error) {
+	seg := segment{kind: sliceSegment, end: math.MaxInt}
+	var err error
+	if from != "" {
+		if seg.index, err = strconv.Atoi(from); err != nil {
+			return segment{}, err
+		}
+	}
+	if to != "" {
+		if seg.end, err = strconv.Atoi(to); err != nil {
+			return segment{}, err
+		}
+	}
+	return seg, nil
+}
+
+// closingQuote returns the index of the quote closing the string that starts
+// at s[start], or -1.
+func closingQuote(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// String returns the source expression.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Get returns the values selected by the path, in document order with object
+// members in sorted key order. Each value is returned at most once. Segments
+// that don't apply, such as a member name on an array or an index past the
+// end, select nothing.
+func (p *Path) Get(value parser.JSONValue) []Result {
+	results := []Result{{Path: "", Value: value}}
+	for _, seg := range p.segments {
+		var next []Result
+		for _, r := range results {
+			next = seg.apply(r, next)
+		}
+		if seg.kind == descendantSegment {
+			// Nested values are reached once per ancestor in results.
+			next = unique(next)
+		}
+		results = next
+	}
+	return results
+}
+
+// unique removes results with the same path, keeping the first.
+func unique(results []Result) []Result {
+	seen := make(map[string]bool, len(results))
+	return slices.DeleteFunc(results, func(r Result) bool {
+		if seen[r.Path] {
+			return true
+		}
+		seen[r.Path] = true
+		return false
+	})
+}
+
+// Get compiles expr and returns the values it selects in value.
+func Get(value parser.JSONValue, expr string) ([]Result, error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(value), nil
+}
+
+// apply appends the values selected by the segment below r to out.
+func (s segment) apply(r Result, out []Result) []Result {
+	switch s.kind {
+	case keySegment:
+		if obj, ok := parser.AsObject(r.Value); ok {
+			if v, ok := obj[s.key]; ok {
+				out = append(out, child(r, s.key, v))
+			}
+			return out
+		}
+		if index, err := strconv.Atoi(s.key); err == nil {
+			return appendElement(r, index, out)
+		}
+	case indexSegment:
+		return appendElement(r, s.index, out)
+	case sliceSegment:
+		return appendSlice(r, s.index, s.end, out)
+	case wildcardSegment:
+		return appendChildren(r, out)
+	case descendantSegment:
+		out = append(out, r)
+		for _, c := range appendChildren(r, nil) {
+			out = s.apply(c, out)
+		}
+	}
+	return out
+}
+
+// appendElement appends element index of the array in r, if it exists.
+// Negative indices count from the end.
+func appendElement(r Result, index int, out []Result) []Result {
+	arr, ok := r.Value.([]any)
+	if !ok {
+		return out
+	}
+	if index < 0 {
+		index += len(arr)
+	}
+	if index < 0 || index >= len(arr) {
+		return out
+	}
+	return append(out, child(r, strconv.Itoa(index), arr[index]))
+}
+
+// appendSlice appends elements start through end-1 of the array in r.
+// Negative bounds count from the end, and bounds outside the array are
+// clamped to it.
+func appendSlice(r Result, start, end int, out []Result) []Result {
+	arr, ok := r.Value.([]any)
+	if !ok {
+		return out
+	}
+	start, end = clampBound(start, len(arr)), clampBound(end, len(arr))
+	for i := start; i < end; i++ {
+		out = append(out, child(r, strconv.Itoa(i), arr[i]))
+	}
+	return out
+}
+
+// clampBound resolves a slice bound against an array of length n.
+func clampBound(bound, n int) int {
+	if bound < 0 {
+		bound += n
+	}
+	return max(0, min(bound, n))
+}
+
+// appendChildren appends the members or elements of the value in r.
+func appendChildren(r Result, out []Result) []Result {
+	if arr, ok := r.Value.([]any); ok {
+		for i, v := range arr {
+			out = append(out, child(r, strconv.Itoa(i), v))
+		}
+		return out
+	}
+	if obj, ok := parser.AsObject(r.Value); ok {
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			out = append(out, child(r, key, obj[key]))
+		}
+	}
+	return out
+}
+
+// child returns the result for the member or element token of r.
+func child(r Result, token string, v parser.JSONValue) Result {
+	return Result{Path: r.Path + "/" + pointer.Escape(token), Value: v}
+}
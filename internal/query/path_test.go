@@ -0,0 +1,140 @@
+package query
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	doc := `{
+		"id": 1,
+		"users": [
+			{"id": 2, "name": "ann", "tags": ["admin"]},
+			{"id": 3, "name": "bob"}
+		],
+		"meta": {"a.b": true, "x/y": {"id": 4}}
+	}`
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected []string // paths of the results, in order
+	}{
+		{name: "root", expr: "", expected: []string{""}},
+		{name: "member", expr: "id", expected: []string{"/id"}},
+		{name: "index", expr: "users[1].name", expected: []string{"/users/1/name"}},
+		{name: "numeric name indexes arrays", expr: "users.0.id", expected: []string{"/users/0/id"}},
+		{name: "quoted name", expr: `meta["a.b"]`, expected: []string{"/meta/a.b"}},
+		{name: "escaped pointer", expr: `meta["x/y"].id`, expected: []string{"/meta/x~1y/id"}},
+		{name: "wildcard over array", expr: "users.*.name", expected: []string{"/users/0/name", "/users/1/name"}},
+		{name: "bracketed wildcard", expr: "users[*].id", expected: []string{"/users/0/id", "/users/1/id"}},
+		{name: "wildcard over object", expr: "meta.*", expected: []string{"/meta/a.b", "/meta/x~1y"}},
+		{
+			name:     "recursive descent",
+			expr:     "**.id",
+			expected: []string{"/id", "/meta/x~1y/id", "/users/0/id", "/users/1/id"},
+		},
+		{name: "recursive descent below a member", expr: "users.**.tags[0]", expected: []string{"/users/0/tags/0"}},
+		{name: "repeated recursive descent", expr: "meta.**.**.id", expected: []string{"/meta/x~1y/id"}},
+		{name: "missing member", expr: "users.*.email", expected: nil},
+		{name: "index out of range", expr: "users[5]", expected: nil},
+		{name: "negative index", expr: "users[-1].name", expected: []string{"/users/1/name"}},
+		{name: "negative numeric name", expr: "users.-2.id", expected: []string{"/users/0/id"}},
+		{name: "negative index out of range", expr: "users[-3]", expected: nil},
+		{name: "member of scalar", expr: "id.x", expected: nil},
+	}
+
+	value := mustParse(t, doc)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Get(value, tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var paths []string
+			for _, r := range results {
+				paths = append(paths, r.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Errorf("expected paths %q, got %q", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestGet_Values(t *testing.T) {
+	results, err := Get(mustParse(t, `{"a": [{"b": 1}, {"b": "two"}]}`), "a[*].b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Result{{Path: "/a/0/b", Value: int64(1)}, {Path: "/a/1/b", Value: "two"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %v, got %v", expected, results)
+	}
+}
+
+func TestGet_Slices(t *testing.T) {
+	value := mustParse(t, `{"items": [0, 1, 2, 3, 4, 5, 6]}`)
+
+	tests := []struct {
+		expr     string
+		expected []int64
+	}{
+		{expr: "items[2:5]", expected: []int64{2, 3, 4}},
+		{expr: "items[:2]", expected: []int64{0, 1}},
+		{expr: "items[5:]", expected: []int64{5, 6}},
+		{expr: "items[:]", expected: []int64{0, 1, 2, 3, 4, 5, 6}},
+		{expr: "items[-2:]", expected: []int64{5, 6}},
+		{expr: "items[1:-4]", expected: []int64{1, 2}},
+		{expr: "items[-100:1]", expected: []int64{0}},
+		{expr: "items[4:100]", expected: []int64{4, 5, 6}},
+		{expr: "items[4:2]", expected: nil},
+		{expr: "items[*][0:1]", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			results, err := Get(value, tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var values []int64
+			for _, r := range results {
+				values = append(values, r.Value.(int64))
+				if expected := "/items/" + strconv.FormatInt(r.Value.(int64), 10); r.Path != expected {
+					t.Errorf("expected path %s, got %s", expected, r.Path)
+				}
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []string{
+		".a",
+		"a.",
+		"a..b",
+		"a]",
+		"[0]a",
+		"[",
+		"a[",
+		"a[x]",
+		"a[1:x]",
+		"a[1:2:3]",
+		`a["b]`,
+		`a["b"`,
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Compile(expr); err == nil {
+				t.Errorf("expected an error for %q", expr)
+			}
+		})
+	}
+}
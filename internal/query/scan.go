@@ -0,0 +1,474 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Scan returns the single value selected by the path expression expr in the
+// JSON document input, without building the rest of the document: it skips
+// over the members and elements that aren't on the path, so reading one field
+// of a large document costs little more than finding it. The expression may
+// only use member names and indices, not wildcards or slices; found is false
+// if the path doesn't exist. As with Get, a member name selects the last
+// member with that name and negative indices count from the end.
+//
+// Only the selected value is parsed and validated. The rest of the input is
+// merely scanned for its structure, so some errors elsewhere, such as an
+// invalid number in a skipped member, go unreported; check the document with
+// a parser first if that matters.
+func Scan(input, expr string) (result Result, found bool, err error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return Result{}, false, err
+	}
+	for _, seg := range p.segments {
+		if seg.kind != keySegment && seg.kind != indexSegment {
+			return Result{}, false, fmt.Errorf("path %q may select several values", expr)
+		}
+	}
+
+	sc := &scanner{input: input}
+	sc.skipSpace()
+	for _, seg := range p.segments {
+		token, ok, err := sc.enter(seg)
+		if err != nil || !ok {
+			return Result{}, false, err
+		}
+		result.Path += "/" + pointer.Escape(token)
+	}
+
+	start := sc.pos
+	if err := sc.skipValue(); err != nil {
+		return Result{}, false, err
+	}
+	text := input[start:sc.pos]
+	if result.Value, err = parser.New(lexer.New(text)).Parse(); err != nil {
+		return Result{}, false, fmt.Errorf("offset %d: invalid value: %w", start, err)
+	}
+	return result, true, nil
+}
+
+// scanner moves through a JSON document without decoding it.
+type scanner struct {
+	input string
+	pos   int
+}
+
+// enter moves from the start of the current value to the start of its member
+// or element selected by seg, and returns the reference token of the value
+// found, if any.
+func (sc *scanner) enter(seg segment) (string, bool, error) {
+	if sc.pos >= len(sc.input) {
+		return "", false, sc.unexpected("a JSON value")
+	}
+	switch sc.input[sc.pos] {
+	case '{':
+		if seg.kind != keySegment {
+			return "", false, nil
+		}
+		ok, err := sc.member(seg.key)
+		return seg.key, ok, err
+	case '[':
+		index, ok := seg.arrayIndex()
+		if !ok {
+			return "", false, nil
+		}
+		index, ok, err := sc.element(index)
+		return strconv.Itoa(index), ok, err
+	default:
+		return "", false, nil
+	}
+}
+
+// arrayIndex returns the index selected by an index segment, or by a key
+// segment whose name is a number.
+func (seg segment) arrayIndex() (int, bool) {
+	if seg.kind == indexSegment {
+		return seg.index, true
+	}
+	index, err := strconv.Atoi(seg.key)
+	return index, err == nil
+}
+
+// member moves from the start of an object to the value of its last member
+// named key.
+func (sc *scanner) member(key string) (bool, error) {
+	found := -1
+	sc.pos++ // {
+	sc.skipSpace()
+	if sc.peek() == '}' {
+		return false, nil
+	}
+	for {
+		if sc.peek() != '"' {
+			return false, sc.unexpected("a member name")
+		}
+		start := sc.pos
+		if err := sc.skipString(); err != nil {
+			return false, err
+		}
+		name, err := sc.name(start)
+		if err != nil {
+			return false, err
+		}
+
+		sc.skipSpace()
+		if sc.peek() != ':' {
+			return false, sc.unexpected("':'")
+		}
+		sc.pos++
+		sc.skipSpace()
+		if name == key {
+			found = sc.pos
+		}
+		if err := sc.skipValue(); err != nil {
+			return false, err
+		}
+
+		sc.skipSpace()
+		switch sc.peek() {
+		case ',':
+			sc.pos++
+			sc.skipSpace()
+		case '}':
+			sc.pos = found
+			return found >= 0, nil
+		default:
+			return false, sc.unexpected("',' or '}'")
+		}
+	}
+}
+
+// name returns the decoded member name whose quoted text starts at start and
+// ends at the current position.
+func (sc *scanner) name(start int) (string, error) {
+	raw := sc.input[start:sc.pos]
+	if !strings.ContainsRune(raw, '\\') {
+		return raw[1 : len(raw)-1], nil
+	}
+	value, err := parser.New(lexer.New(raw)).Parse()
+	if err != nil {
+		return "", fmt.Errorf("offset %d: invalid member name %s: %w", start, raw, err)
+	}
+	return value.(string), nil
+}
+
+// element moves from the start of an array to its element index, counting
+// from the end if negative, and returns the non-negative index.
+func (sc *scanner) element(index int) (int, bool, error) {
+	var starts []int // element offsets, kept only for negative indices
+	sc.pos++         // [
+	sc.skipSpace()
+	if sc.peek() == ']' {
+		return 0, false, nil
+	}
+	for i := 0; ; i++ {
+		if i == index {
+			return i, true, nil
+		}
+		if index < 0 {
+			starts = append(starts, sc.pos)
+		}
+		if err := sc.skipValue(); err != nil {
+			return 0, false, err
+		}
+
+		sc.skipSpace()
+		switch sc.peek() {
+		case ',':
+			sc.pos++
+			sc.skipSpace()
+		case ']':
+			if index < 0 && -index <= len(starts) {
+				index += len(starts)
+				sc.pos = starts[index]
+				return index, true, nil
+			}
+			return 0, false, nil
+		default:
+			return 0, false, sc.unexpected("',' or ']'")
+		}
+	}
+}
+
+// skipValue moves past the value starting at the current position, checking
+// only that strings are terminated and brackets balanced.
+func (sc *scanner) skipValue() error {
+	switch sc.peek() {
+	case '"':
+		return sc.skipString()
+	case '{', '[':
+		depth := 0
+		for sc.pos < len(sc.input) {
+			switch sc.input[sc.pos] {
+			case '"':
+				if err := sc.skipString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			sc.pos++
+			if depth == 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("offset %d: unexpected end of input", sc.pos)
+	}
+
+	start := sc.pos
+	for sc.pos < len(sc.input) && !strings.ContainsRune(" \t\n\r,:[]{}\"", rune(sc.input[sc.pos])) {
+		sc.pos++
+	}
+	if sc.pos == start {
+		return sc.unexpected("a JSON value")
+	}
+	return nil
+}
+
+// skipString moves past the string whose opening quote is at the current
+// position.
+func (sc *scanner) skipString() error {
+	for i := sc.pos + 1; i < len(sc.input); i++ {
+		switch sc.input[i] {
+		case '\\':
+			i++
+		case '"':
+			sc.pos = i + 1
+			return nil
+		}
+	}
+	return fmt.Errorf("offset %d: unterminated string", sc.pos)
+}
+
+// skipSpace moves past whitespace.
+func (sc *scanner) skipSpace() {
+	for sc.pos < len(sc.input) && strings.IndexByte(" \t\n\r", sc.input[sc.pos]) >= 0 {
+		sc.pos++
+	}
+}
+
+// peek returns the byte at the current position, or 0 at the end of input.
+func (sc *scanner) peek() byte {
+	if sc.pos >= len(sc.input) {
+		return 0
+	}
+	return sc.input[sc.pos]
+}
+
+// unexpected reports that something other than expected is at the current
+// position.
+func (sc *scanner) unexpected(expected string) error {
+	if sc.pos >= len(sc.input) {
+		return fmt.Errorf("offset %d: expected %s, got end of input", sc.pos, expected)
+	}
+	return fmt.Errorf("offset %d: expected %s, got %q", sc.pos, expected, sc.input[sc.pos])
+}
+
+// SetText returns the JSON document input with the value selected by expr, a
+// path as for Scan, replaced by the compact encoding of value, which may be a
+// parsed value or a Go value. If the value doesn't exist, but the object or
+// array holding it does, it is added: as a new last member, or appended if
+// the index is the length of the array. The rest of the document, including
+// its formatting, is kept byte for byte.
+func SetText(input, expr string, value any) (string, error) {
+	encoded, err := encoder.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if expr == "" {
+		return string(encoded), nil
+	}
+
+	last, entries, closing, err := scanParent(input, expr)
+	if err != nil {
+		return "", err
+	}
+	if matches := matching(entries, last, input[closing] == '}'); len(matches) > 0 {
+		e := entries[matches[len(matches)-1]]
+		return input[:e.value] + string(encoded) + input[e.end:], nil
+	}
+
+	insert := string(encoded)
+	if input[closing] == '}' {
+		if last.kind != keySegment {
+			return "", fmt.Errorf("path %q: cannot index into an object", expr)
+		}
+		name, _ := encoder.Marshal(last.key)
+		insert = string(name) + ":" + insert
+	} else if index, ok := last.arrayIndex(); !ok || index != len(entries) {
+		return "", fmt.Errorf("path %q: array index out of range", expr)
+	}
+	if len(entries) > 0 {
+		end := entries[len(entries)-1].end
+		return input[:end] + "," + insert + input[end:], nil
+	}
+	return input[:closing] + insert + input[closing:], nil
+}
+
+// DeleteText returns the JSON document input without the member or element
+// selected by expr, a path as for Scan, together with the comma separating
+// it from its neighbours; all the members with the name are removed. The
+// document is returned unchanged if the value doesn't exist. The rest of the
+// document, including its formatting, is kept byte for byte.
+func DeleteText(input, expr string) (string, error) {
+	if expr == "" {
+		return "", fmt.Errorf("cannot delete the root")
+	}
+	for {
+		last, entries, closing, err := scanParent(input, expr)
+		if err != nil {
+			return "", err
+		}
+		matches := matching(entries, last, input[closing] == '}')
+		if len(matches) == 0 {
+			return input, nil
+		}
+
+		// Remove the last match; earlier duplicates are found again on the
+		// next pass.
+		n := matches[len(matches)-1]
+		start, end := entries[n].start, entries[n].end
+		switch {
+		case n > 0:
+			start = entries[n-1].end // with the comma before
+		case len(entries) > 1:
+			end = entries[1].start // with the comma after
+		}
+		input = input[:start] + input[end:]
+		if len(matches) == 1 {
+			return input, nil
+		}
+	}
+}
+
+// scanParent finds the object or array holding the value at the non-empty
+// path expr and returns the last segment of the path with the entries and
+// the offset of the closing bracket of the container.
+func scanParent(input, expr string) (segment, []entry, int, error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return segment{}, nil, 0, err
+	}
+	for _, seg := range p.segments {
+		if seg.kind != keySegment && seg.kind != indexSegment {
+			return segment{}, nil, 0, fmt.Errorf("path %q may select several values", expr)
+		}
+	}
+
+	sc := &scanner{input: input}
+	sc.skipSpace()
+	parent, last := p.segments[:len(p.segments)-1], p.segments[len(p.segments)-1]
+	for _, seg := range parent {
+		_, ok, err := sc.enter(seg)
+		if err != nil {
+			return segment{}, nil, 0, err
+		}
+		if !ok {
+			return segment{}, nil, 0, fmt.Errorf("path %q: parent not found", expr)
+		}
+	}
+	if c := sc.peek(); c != '{' && c != '[' {
+		return segment{}, nil, 0, fmt.Errorf("path %q: parent is not an object or array", expr)
+	}
+	entries, closing, err := sc.entries()
+	return last, entries, closing, err
+}
+
+// entry is the location of a member or element in the input.
+type entry struct {
+	name       string // member name, "" for elements
+	start, end int    // from the member name or element to the end of its value
+	value      int    // start of the value
+}
+
+// entries moves from the start of an object or array past its end and
+// returns its members or elements and the offset of its closing bracket.
+func (sc *scanner) entries() ([]entry, int, error) {
+	object := sc.peek() == '{'
+	closing := byte(']')
+	if object {
+		closing = '}'
+	}
+
+	var entries []entry
+	sc.pos++
+	sc.skipSpace()
+	if sc.peek() == closing {
+		return nil, sc.pos, nil
+	}
+	for {
+		e := entry{start: sc.pos, value: sc.pos}
+		if object {
+			if sc.peek() != '"' {
+				return nil, 0, sc.unexpected("a member name")
+			}
+			if err := sc.skipString(); err != nil {
+				return nil, 0, err
+			}
+			var err error
+			if e.name, err = sc.name(e.start); err != nil {
+				return nil, 0, err
+			}
+			sc.skipSpace()
+			if sc.peek() != ':' {
+				return nil, 0, sc.unexpected("':'")
+			}
+			sc.pos++
+			sc.skipSpace()
+			e.value = sc.pos
+		}
+		if err := sc.skipValue(); err != nil {
+			return nil, 0, err
+		}
+		e.end = sc.pos
+		entries = append(entries, e)
+
+		sc.skipSpace()
+		switch sc.peek() {
+		case ',':
+			sc.pos++
+			sc.skipSpace()
+		case closing:
+			return entries, sc.pos, nil
+		default:
+			return nil, 0, sc.unexpected(fmt.Sprintf("',' or '%c'", closing))
+		}
+	}
+}
+
+// matching returns the indices of the entries of an object or array selected
+// by seg, in order.
+func matching(entries []entry, seg segment, object bool) []int {
+	if object {
+		var matches []int
+		for i, e := range entries {
+			if seg.kind == keySegment && e.name == seg.key {
+				matches = append(matches, i)
+			}
+		}
+		return matches
+	}
+
+	index, ok := seg.arrayIndex()
+	if !ok {
+		return nil
+	}
+	if index < 0 {
+		index += len(entries)
+	}
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	return []int{index}
+}
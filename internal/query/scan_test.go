@@ -0,0 +1,165 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestScan(t *testing.T) {
+	doc := `{
+		"id": 1,
+		"users": [
+			{"id": 2, "name": "ann", "tags": ["admin", "x"]},
+			{"id": 3, "name": "bob", "bio": "likes [brackets] and \"quotes\" {}"}
+		],
+		"meta": {"a.b": true, "x/y": {"id": 4}, "caf\u00e9": "esc", "dup": 1, "dup": 2},
+		"empty": {"a": [], "o": {}}
+	}`
+	parsed, err := parser.New(lexer.New(doc)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse the document: %v", err)
+	}
+
+	exprs := []string{
+		"", "id", "users", "users[1].name", "users.0.tags[1]", "users[-1].bio", "users[-2].id",
+		`meta["a.b"]`, `meta["x/y"].id`, `meta["caf\u00e9"]`, "meta.dup", "empty.a", "empty.o",
+		// Paths that don't exist.
+		"email", "users[2]", "users[-3]", "users.x", "id.x", "id[0]", "empty.a[0]", "empty.o.a", "meta[0]",
+	}
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			result, found, err := Scan(doc, expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected, err := Get(parsed, expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !found {
+				if len(expected) > 0 {
+					t.Errorf("expected %v, found nothing", expected[0])
+				}
+				return
+			}
+			if len(expected) != 1 || !reflect.DeepEqual(result, expected[0]) {
+				t.Errorf("expected %v, got %v", expected, result)
+			}
+		})
+	}
+}
+
+func TestScan_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		expr  string
+	}{
+		{name: "wildcard", input: `{"a": [1]}`, expr: "a.*"},
+		{name: "slice", input: `[1, 2]`, expr: "[0:1]"},
+		{name: "invalid path", input: `{}`, expr: "a..b"},
+		{name: "invalid selected value", input: `{"a": tru}`, expr: "a"},
+		{name: "unterminated string", input: `{"a": "x`, expr: "b"},
+		{name: "unbalanced brackets", input: `{"a": [1, {"b": 2}`, expr: "c"},
+		{name: "missing colon", input: `{"a" 1}`, expr: "a"},
+		{name: "empty input", input: ``, expr: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Scan(tt.input, tt.expr); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestSetText(t *testing.T) {
+	doc := "{\n  \"a\": {\"b\": [1, 2]},\n  \"c\": \"x\"\n}"
+	tests := []struct {
+		name     string
+		expr     string
+		value    any
+		expected string // empty for an error
+	}{
+		{name: "replace member", expr: "c", value: map[string]any{"d": true}, expected: "{\n  \"a\": {\"b\": [1, 2]},\n  \"c\": {\"d\":true}\n}"},
+		{name: "replace element", expr: "a.b[-1]", value: "y", expected: "{\n  \"a\": {\"b\": [1, \"y\"]},\n  \"c\": \"x\"\n}"},
+		{name: "add member", expr: `a["new key"]`, value: nil, expected: "{\n  \"a\": {\"b\": [1, 2],\"new key\":null},\n  \"c\": \"x\"\n}"},
+		{name: "append element", expr: "a.b[2]", value: 3, expected: "{\n  \"a\": {\"b\": [1, 2,3]},\n  \"c\": \"x\"\n}"},
+		{name: "append by name", expr: "a.b.2", value: 3, expected: "{\n  \"a\": {\"b\": [1, 2,3]},\n  \"c\": \"x\"\n}"},
+		{name: "root", expr: "", value: []int{1}, expected: "[1]"},
+		{name: "index past the end", expr: "a.b[3]", value: 3},
+		{name: "missing parent", expr: "x.y", value: 1},
+		{name: "scalar parent", expr: "c.d", value: 1},
+		{name: "wildcard", expr: "a.*", value: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetText(doc, tt.expr, tt.value)
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("empty containers", func(t *testing.T) {
+		got, err := SetText(`{"o": {}, "a": [ ]}`, "o.k", 1)
+		if err == nil {
+			got, err = SetText(got, "a[0]", 2)
+		}
+		if expected := `{"o": {"k":1}, "a": [ 2]}`; err != nil || got != expected {
+			t.Errorf("expected %q, got %q (%v)", expected, got, err)
+		}
+	})
+}
+
+func TestDeleteText(t *testing.T) {
+	doc := `{"a": 1, "b": [1, 2, 3], "c": {"d": 1}}`
+	tests := []struct {
+		name     string
+		input    string
+		expr     string
+		expected string // empty for an error
+	}{
+		{name: "first member", input: doc, expr: "a", expected: `{"b": [1, 2, 3], "c": {"d": 1}}`},
+		{name: "middle member", input: doc, expr: "b", expected: `{"a": 1, "c": {"d": 1}}`},
+		{name: "last member", input: doc, expr: "c", expected: `{"a": 1, "b": [1, 2, 3]}`},
+		{name: "only member", input: doc, expr: "c.d", expected: `{"a": 1, "b": [1, 2, 3], "c": {}}`},
+		{name: "element", input: doc, expr: "b[-2]", expected: `{"a": 1, "b": [1, 3], "c": {"d": 1}}`},
+		{name: "missing member", input: doc, expr: "x", expected: doc},
+		{name: "duplicate members", input: `{"k": 1, "k": 2, "j": 3, "k": 4}`, expr: "k", expected: `{"j": 3}`},
+		{name: "missing parent", input: doc, expr: "x.y"},
+		{name: "root", input: doc, expr: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeleteText(tt.input, tt.expr)
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"errors"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Order selects the order in which Walk visits values. Within a container,
+// array elements are visited by index and object members by sorted key.
+type Order int
+
+const (
+	PreOrder     Order = iota // depth first, each value before its members (default)
+	PostOrder                 // depth first, each value after its members
+	BreadthFirst              // level by level, the root first
+)
+
+// Node is a value visited by Walk, together with its location.
+type Node struct {
+	Path  string // JSON Pointer (RFC 6901) to the value, "" for the root
+	Depth int    // nesting depth, 1 for the root value
+	Value parser.JSONValue
+}
+
+// WalkFunc is called by Walk for each visited value. Returning SkipChildren
+// skips the members of the value, SkipAll stops the walk, and any other
+// error stops the walk and is returned by Walk.
+type WalkFunc func(node Node) error
+
+var (
+	// SkipChildren is returned by a WalkFunc to skip the members of the value
+	// it was given. In PostOrder the members have already been visited, so it
+	// has no effect.
+	SkipChildren = errors.New("skip children")
+
+	// SkipAll is returned by a WalkFunc to stop the walk without an error.
+	SkipAll = errors.New("skip all")
+)
+
+// walkConfig holds the settings applied by WalkOptions.
+type walkConfig struct {
+	order    Order
+	maxDepth int
+}
+
+// WalkOption configures Walk.
+type WalkOption func(*walkConfig)
+
+// WithOrder sets the traversal order, PreOrder by default.
+func WithOrder(order Order) WalkOption {
+	return func(c *walkConfig) {
+		c.order = order
+	}
+}
+
+// WithMaxDepth limits the walk to values nested at most depth levels deep,
+// so WithMaxDepth(1) visits only the root and WithMaxDepth(2) the root and
+// its members. Values below the limit are never traversed, which keeps
+// inspecting the top levels of a very large document cheap. Zero, the
+// default, means no limit.
+func WithMaxDepth(depth int) WalkOption {
+	return func(c *walkConfig) {
+		c.maxDepth = depth
+	}
+}
+
+// Walk calls fn for value and every value nested in it, in the order chosen
+// by WithOrder.
+func Walk(value parser.JSONValue, fn WalkFunc, opts ...WalkOption) error {
+	w := walker{fn: fn}
+	for _, opt := range opts {
+		opt(&w.walkConfig)
+	}
+
+	root := Node{Path: "", Depth: 1, Value: value}
+	var err error
+	if w.order == BreadthFirst {
+		err = w.breadthFirst(root)
+	} else {
+		err = w.depthFirst(root)
+	}
+	if errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// walker holds the state of a Walk.
+type walker struct {
+	walkConfig
+	fn WalkFunc
+}
+
+// depthFirst visits node and its members in pre- or post-order.
+func (w *walker) depthFirst(node Node) error {
+	if w.order == PreOrder {
+		if err := w.fn(node); err != nil {
+			if errors.Is(err, SkipChildren) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	for _, child := range w.children(node) {
+		if err := w.depthFirst(child); err != nil {
+			return err
+		}
+	}
+
+	if w.order == PostOrder {
+		if err := w.fn(node); err != nil && !errors.Is(err, SkipChildren) {
+			return err
+		}
+	}
+	return nil
+}
+
+// breadthFirst visits root and its members level by level.
+func (w *walker) breadthFirst(root Node) error {
+	queue := []Node{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if err := w.fn(node); err != nil {
+			if errors.Is(err, SkipChildren) {
+				continue
+			}
+			return err
+		}
+		queue = append(queue, w.children(node)...)
+	}
+	return nil
+}
+
+// children returns the members or elements of node, or nil if they are
+// beyond the maximum depth.
+func (w *walker) children(node Node) []Node {
+	if w.maxDepth > 0 && node.Depth >= w.maxDepth {
+		return nil
+	}
+	results := appendChildren(Result{Path: node.Path, Value: node.Value}, nil)
+	if len(results) == 0 {
+		return nil
+	}
+	nodes := make([]Node, len(results))
+	for i, r := range results {
+		nodes[i] = Node{Path: r.Path, Depth: node.Depth + 1, Value: r.Value}
+	}
+	return nodes
+}
@@ -0,0 +1,146 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	doc := `{"a": [1, {"b": 2}], "c": {"d": 3}}`
+
+	tests := []struct {
+		name     string
+		opts     []WalkOption
+		expected []string
+	}{
+		{
+			name:     "pre-order by default",
+			expected: []string{"", "/a", "/a/0", "/a/1", "/a/1/b", "/c", "/c/d"},
+		},
+		{
+			name:     "post-order",
+			opts:     []WalkOption{WithOrder(PostOrder)},
+			expected: []string{"/a/0", "/a/1/b", "/a/1", "/a", "/c/d", "/c", ""},
+		},
+		{
+			name:     "breadth first",
+			opts:     []WalkOption{WithOrder(BreadthFirst)},
+			expected: []string{"", "/a", "/c", "/a/0", "/a/1", "/c/d", "/a/1/b"},
+		},
+		{
+			name:     "max depth",
+			opts:     []WalkOption{WithMaxDepth(2)},
+			expected: []string{"", "/a", "/c"},
+		},
+		{
+			name:     "max depth post-order",
+			opts:     []WalkOption{WithOrder(PostOrder), WithMaxDepth(2)},
+			expected: []string{"/a", "/c", ""},
+		},
+		{
+			name:     "max depth breadth first",
+			opts:     []WalkOption{WithOrder(BreadthFirst), WithMaxDepth(3)},
+			expected: []string{"", "/a", "/c", "/a/0", "/a/1", "/c/d"},
+		},
+		{
+			name:     "root only",
+			opts:     []WalkOption{WithMaxDepth(1)},
+			expected: []string{""},
+		},
+	}
+
+	value := mustParse(t, doc)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			err := Walk(value, func(node Node) error {
+				paths = append(paths, node.Path)
+				return nil
+			}, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Errorf("expected paths %q, got %q", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestWalk_Depth(t *testing.T) {
+	depths := map[string]int{}
+	Walk(mustParse(t, `{"a": [{"b": 1}]}`), func(node Node) error {
+		depths[node.Path] = node.Depth
+		return nil
+	})
+
+	expected := map[string]int{"": 1, "/a": 2, "/a/0": 3, "/a/0/b": 4}
+	if !reflect.DeepEqual(depths, expected) {
+		t.Errorf("expected depths %v, got %v", expected, depths)
+	}
+}
+
+func TestWalk_Skip(t *testing.T) {
+	value := mustParse(t, `{"a": {"x": 1}, "b": {"y": 2}, "c": 3}`)
+	skipA := func(node Node) error {
+		if node.Path == "/a" {
+			return SkipChildren
+		}
+		return nil
+	}
+	stopAtB := func(node Node) error {
+		if node.Path == "/b" {
+			return SkipAll
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		skip     func(Node) error
+		order    Order
+		expected []string
+	}{
+		{name: "skip children", skip: skipA, order: PreOrder, expected: []string{"", "/a", "/b", "/b/y", "/c"}},
+		{name: "skip children breadth first", skip: skipA, order: BreadthFirst, expected: []string{"", "/a", "/b", "/c", "/b/y"}},
+		{name: "skip children post-order", skip: skipA, order: PostOrder, expected: []string{"/a/x", "/a", "/b/y", "/b", "/c", ""}},
+		{name: "skip all", skip: stopAtB, order: PreOrder, expected: []string{"", "/a", "/a/x", "/b"}},
+		{name: "skip all breadth first", skip: stopAtB, order: BreadthFirst, expected: []string{"", "/a", "/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			err := Walk(value, func(node Node) error {
+				paths = append(paths, node.Path)
+				return tt.skip(node)
+			}, WithOrder(tt.order))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Errorf("expected paths %q, got %q", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestWalk_Error(t *testing.T) {
+	errStop := errors.New("stop")
+	visited := 0
+	err := Walk(mustParse(t, `[1, 2, 3]`), func(node Node) error {
+		visited++
+		if node.Path == "/1" {
+			return errStop
+		}
+		return nil
+	})
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected %v, got %v", errStop, err)
+	}
+	if visited != 3 {
+		t.Errorf("expected the walk to stop after 3 values, visited %d", visited)
+	}
+}
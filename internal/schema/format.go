@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// FormatFunc reports whether s is a valid instance of a format.
+type FormatFunc func(s string) bool
+
+// FormatRegistry maps names used by the "format" keyword to validators. It is
+// safe for concurrent use.
+type FormatRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]FormatFunc
+}
+
+// NewFormatRegistry creates a registry containing the built-in formats:
+// date-time, date, email, uuid, uri, ipv4 and ipv6.
+func NewFormatRegistry() *FormatRegistry {
+	return &FormatRegistry{formats: map[string]FormatFunc{
+		"date-time": isDateTime,
+		"date":      isDate,
+		"email":     isEmail,
+		"uuid":      isUUID,
+		"uri":       isURI,
+		"ipv4":      isIPv4,
+		"ipv6":      isIPv6,
+	}}
+}
+
+// Register adds a custom format, replacing any existing format with the same
+// name, including built-in ones.
+func (r *FormatRegistry) Register(name string, fn FormatFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.formats[name] = fn
+}
+
+// Lookup returns the validator for the named format, if one is registered.
+func (r *FormatRegistry) Lookup(name string) (FormatFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.formats[name]
+	return fn, ok
+}
+
+// defaultFormats backs schemas compiled without WithFormats.
+var defaultFormats = NewFormatRegistry()
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isDateTime validates an RFC 3339 date-time such as 2024-01-02T15:04:05Z.
+func isDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+// isDate validates an RFC 3339 full-date such as 2024-01-02.
+func isDate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
+
+// isEmail validates a bare address (no display name) such as user@example.com.
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Name == "" && addr.Address == s
+}
+
+// isUUID validates the hyphenated 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// isURI validates an absolute URI, i.e. one with a scheme.
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
+
+// isIPv4 validates a dotted-quad address without leading zeros.
+func isIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// isIPv6 validates an IPv6 address without a zone.
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			format:  "date-time",
+			valid:   []string{"2024-01-02T15:04:05Z", "2024-01-02T15:04:05.123+02:00"},
+			invalid: []string{"2024-01-02", "2024-13-02T15:04:05Z", "yesterday"},
+		},
+		{format: "date", valid: []string{"2024-02-29"}, invalid: []string{"2023-02-29", "2024-1-2"}},
+		{format: "email", valid: []string{"user@example.com"}, invalid: []string{"user", "User <user@example.com>", "@example.com"}},
+		{
+			format:  "uuid",
+			valid:   []string{"123e4567-e89b-12d3-a456-426614174000"},
+			invalid: []string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"},
+		},
+		{format: "uri", valid: []string{"https://example.com/a?b=c", "urn:isbn:0451450523"}, invalid: []string{"/relative/path", "example.com"}},
+		{format: "ipv4", valid: []string{"192.168.0.1"}, invalid: []string{"256.0.0.1", "01.2.3.4", "::1"}},
+		{format: "ipv6", valid: []string{"::1", "2001:db8::1", "::ffff:1.2.3.4"}, invalid: []string{"192.168.0.1", "fe80::1%eth0", "2001:db8:::1"}},
+	}
+
+	registry := NewFormatRegistry()
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			check, ok := registry.Lookup(tt.format)
+			if !ok {
+				t.Fatalf("format %q is not registered", tt.format)
+			}
+			for _, s := range tt.valid {
+				if !check(s) {
+					t.Errorf("expected %q to be a valid %s", s, tt.format)
+				}
+			}
+			for _, s := range tt.invalid {
+				if check(s) {
+					t.Errorf("expected %q to be an invalid %s", s, tt.format)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatModes(t *testing.T) {
+	const schemaDoc = `{"properties": {"id": {"format": "uuid"}, "note": {"format": "unknown-format"}}}`
+	const input = `{"id": "not-a-uuid", "note": "anything"}`
+
+	annotated := mustCompile(t, schemaDoc).Validate(mustParse(t, input))
+	if !annotated.Valid() {
+		t.Errorf("annotate mode should not fail validation: %v", annotated.Errors)
+	}
+	if len(annotated.Annotations) != 1 || annotated.Annotations[0].String() != "/id: value is not a valid uuid [format]" {
+		t.Errorf("unexpected annotations: %v", annotated.Annotations)
+	}
+
+	asserted := mustCompile(t, schemaDoc, WithFormatMode(FormatAssert)).Validate(mustParse(t, input))
+	if asserted.Valid() || len(asserted.Errors) != 1 || len(asserted.Annotations) != 0 {
+		t.Errorf("assert mode should report one error: %+v", asserted)
+	}
+}
+
+func TestFormatRegistry_Register(t *testing.T) {
+	registry := NewFormatRegistry()
+	registry.Register("lowercase", func(s string) bool { return s == strings.ToLower(s) })
+
+	s := mustCompile(t, `{"items": {"format": "lowercase"}}`, WithFormats(registry), WithFormatMode(FormatAssert))
+	result := s.Validate(mustParse(t, `["ok", "NOT OK"]`))
+	if len(result.Errors) != 1 || result.Errors[0].Path != "/1" {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+
+	if _, ok := NewFormatRegistry().Lookup("lowercase"); ok {
+		t.Error("custom format leaked into a new registry")
+	}
+}
@@ -0,0 +1,45 @@
+package schema
+
+// FormatMode controls how the "format" keyword affects validation.
+type FormatMode int
+
+const (
+	// FormatAnnotate reports format mismatches as annotations without failing
+	// validation, which is the JSON Schema default.
+	FormatAnnotate FormatMode = iota
+	// FormatAssert reports format mismatches as validation errors.
+	FormatAssert
+)
+
+// config holds the validation settings shared by a schema and its subschemas.
+type config struct {
+	formats    *FormatRegistry
+	formatMode FormatMode
+}
+
+// Option configures a compiled schema.
+type Option func(*config)
+
+// newConfig returns the default configuration with opts applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{formats: defaultFormats, formatMode: FormatAnnotate}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithFormats validates the "format" keyword using r instead of the built-in
+// formats. Start from NewFormatRegistry to keep the built-ins.
+func WithFormats(r *FormatRegistry) Option {
+	return func(c *config) {
+		c.formats = r
+	}
+}
+
+// WithFormatMode selects whether format mismatches are annotations or errors.
+func WithFormatMode(mode FormatMode) Option {
+	return func(c *config) {
+		c.formatMode = mode
+	}
+}
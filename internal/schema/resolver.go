@@ -0,0 +1,251 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// maxSchemaSize bounds the size of fetched schema documents.
+const maxSchemaSize = 10 << 20
+
+// ErrUnsupportedURI is returned for "$schema" URIs that are neither mapped
+// to a file nor file, http or https URIs.
+var ErrUnsupportedURI = errors.New("unsupported schema URI")
+
+// ErrNotCached is returned in offline mode for remote schemas missing from
+// the cache directory.
+var ErrNotCached = errors.New("schema not cached")
+
+// DeclaredURI returns the URI of the schema a document declares in its
+// top-level "$schema" member, if any.
+func DeclaredURI(doc parser.JSONValue) (string, bool) {
+	obj, ok := parser.AsObject(doc)
+	if !ok {
+		return "", false
+	}
+	uri, ok := obj["$schema"].(string)
+	return uri, ok && uri != ""
+}
+
+// Resolver loads the schema documents that "$schema" URIs refer to.
+type Resolver interface {
+	// Resolve returns the parsed schema document uri refers to. Relative
+	// URIs are file paths relative to base, the file declaring the schema.
+	Resolve(uri, base string) (parser.JSONValue, error)
+}
+
+// resolver implements Resolver, loading each location at most once.
+type resolver struct {
+	locations map[string]string // URI -> local file
+	client    *http.Client
+	cacheDir  string        // on-disk cache of fetched schemas, "" for none
+	ttl       time.Duration // age after which cached schemas are fetched again, 0 for never
+	offline   bool          // never fetch
+
+	mu    sync.Mutex
+	cache map[string]parser.JSONValue // by file path or URL
+}
+
+// ResolverOption configures a Resolver.
+type ResolverOption func(*resolver)
+
+// WithLocations maps schema URIs to local files, which are read instead of
+// fetching the URIs, e.g. to validate offline against vendored schemas.
+func WithLocations(locations map[string]string) ResolverOption {
+	return func(r *resolver) {
+		r.locations = locations
+	}
+}
+
+// WithHTTPClient fetches http and https URIs with client instead of a client
+// with a 30 second timeout.
+func WithHTTPClient(client *http.Client) ResolverOption {
+	return func(r *resolver) {
+		r.client = client
+	}
+}
+
+// WithCacheDir keeps fetched schemas in dir, which is created if needed, and
+// reuses them until they are older than ttl, or indefinitely if ttl is 0.
+// This makes repeated runs fast and independent of the network.
+func WithCacheDir(dir string, ttl time.Duration) ResolverOption {
+	return func(r *resolver) {
+		r.cacheDir, r.ttl = dir, ttl
+	}
+}
+
+// WithOffline never fetches remote schemas: they must be mapped with
+// WithLocations or present in the cache directory, however old, or Resolve
+// fails with ErrNotCached. It makes validation reproducible in CI.
+func WithOffline() ResolverOption {
+	return func(r *resolver) {
+		r.offline = true
+	}
+}
+
+// NewResolver returns a Resolver that reads mapped URIs, file paths and file
+// URIs from disk and fetches http and https URIs.
+func NewResolver(opts ...ResolverOption) Resolver {
+	r := &resolver{
+		client: &http.Client{Timeout: 30 * time.Second},
+		cache:  make(map[string]parser.JSONValue),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Resolve implements Resolver.
+func (r *resolver) Resolve(uri, base string) (parser.JSONValue, error) {
+	if path, ok := r.locations[uri]; ok {
+		return r.load(path, readFile)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrUnsupportedURI, uri, err)
+	}
+	switch u.Scheme {
+	case "":
+		path := filepath.FromSlash(u.Path)
+		if !filepath.IsAbs(path) && base != "" {
+			path = filepath.Join(filepath.Dir(base), path)
+		}
+		return r.load(path, readFile)
+	case "file":
+		return r.load(filepath.FromSlash(u.Path), readFile)
+	case "http", "https":
+		return r.load(uri, r.fetchCached)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedURI, uri)
+	}
+}
+
+// load returns the document at location, reading it with read unless it was
+// loaded before.
+func (r *resolver) load(location string, read func(string) (parser.JSONValue, error)) (parser.JSONValue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if doc, ok := r.cache[location]; ok {
+		return doc, nil
+	}
+
+	doc, err := read(location)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[location] = doc
+	return doc, nil
+}
+
+// readFile reads and parses a local schema file.
+func readFile(path string) (parser.JSONValue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(path, content)
+}
+
+// parse parses the schema document read from location.
+func parse(location string, content []byte) (parser.JSONValue, error) {
+	doc, err := parser.New(lexer.New(string(content))).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+	return doc, nil
+}
+
+// fetchCached returns the remote document at uri from the cache directory,
+// fetching and caching it if it is missing or expired.
+func (r *resolver) fetchCached(uri string) (parser.JSONValue, error) {
+	if r.cacheDir == "" {
+		if r.offline {
+			return nil, fmt.Errorf("%w: %s", ErrNotCached, uri)
+		}
+		return r.fetch(uri)
+	}
+
+	path := filepath.Join(r.cacheDir, cacheFile(uri))
+	if info, err := os.Stat(path); err == nil && (r.offline || r.ttl == 0 || time.Since(info.ModTime()) < r.ttl) {
+		return readFile(path)
+	}
+	if r.offline {
+		return nil, fmt.Errorf("%w: %s", ErrNotCached, uri)
+	}
+
+	content, err := r.download(uri)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parse(uri, content)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCacheFile(path, content); err != nil {
+		return nil, fmt.Errorf("caching %s: %w", uri, err)
+	}
+	return doc, nil
+}
+
+// cacheFile returns the name of the cache file for uri.
+func cacheFile(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// writeCacheFile atomically replaces the cache file at path, so concurrent
+// runs never read a partial document.
+func writeCacheFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schema-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fetch downloads and parses a schema document.
+func (r *resolver) fetch(uri string) (parser.JSONValue, error) {
+	content, err := r.download(uri)
+	if err != nil {
+		return nil, err
+	}
+	return parse(uri, content)
+}
+
+// download returns the content of a remote schema document.
+func (r *resolver) download(uri string) ([]byte, error) {
+	resp, err := r.client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", uri, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSchemaSize))
+}
@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestDeclaredURI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		found    bool
+	}{
+		{input: `{"$schema": "https://example.com/s.json", "a": 1}`, expected: "https://example.com/s.json", found: true},
+		{input: `{"a": {"$schema": "nested.json"}}`},
+		{input: `{"$schema": 1}`},
+		{input: `{"$schema": ""}`},
+		{input: `["$schema"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			uri, found := DeclaredURI(mustParse(t, tt.input))
+			if uri != tt.expected || found != tt.found {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.found, uri, found)
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	document := writeFile("configs/app.json", `{}`)
+	writeFile("configs/schema.json", `{"type": "object"}`)
+	vendored := writeFile("vendored.json", `{"type": "array"}`)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schema.json" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"type": "string"}`)
+	}))
+	defer server.Close()
+
+	r := NewResolver(
+		WithHTTPClient(server.Client()),
+		WithLocations(map[string]string{"https://example.com/vendored.json": vendored}),
+	)
+
+	tests := []struct {
+		name     string
+		uri      string
+		expected string
+	}{
+		{name: "relative path", uri: "schema.json", expected: `{"type": "object"}`},
+		{name: "file URI", uri: "file://" + filepath.ToSlash(vendored), expected: `{"type": "array"}`},
+		{name: "mapped URI", uri: "https://example.com/vendored.json", expected: `{"type": "array"}`},
+		{name: "fetched", uri: server.URL + "/schema.json", expected: `{"type": "string"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := r.Resolve(tt.uri, document)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := mustParse(t, tt.expected); !reflect.DeepEqual(doc, expected) {
+				t.Errorf("expected %v, got %v", expected, doc)
+			}
+		})
+	}
+
+	t.Run("fetches once", func(t *testing.T) {
+		if _, err := r.Resolve(server.URL+"/schema.json", document); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requests != 1 {
+			t.Errorf("expected 1 request, got %d", requests)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		uri  string
+	}{
+		{name: "missing file", uri: "missing.json"},
+		{name: "not found", uri: server.URL + "/missing.json"},
+		{name: "unsupported scheme", uri: "urn:example:schema"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Resolve(tt.uri, document); err == nil {
+				t.Errorf("expected an error for %q", tt.uri)
+			}
+		})
+	}
+
+	if _, err := r.Resolve("urn:example:schema", document); !errors.Is(err, ErrUnsupportedURI) {
+		t.Errorf("expected ErrUnsupportedURI, got %v", err)
+	}
+}
+
+func TestResolver_CacheDir(t *testing.T) {
+	var requests int
+	body := `{"type": "string"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	cacheDir := filepath.Join(t.TempDir(), "schemas")
+	uri := server.URL + "/schema.json"
+	resolve := func(opts ...ResolverOption) (parser.JSONValue, error) {
+		opts = append([]ResolverOption{WithHTTPClient(server.Client())}, opts...)
+		return NewResolver(opts...).Resolve(uri, "")
+	}
+
+	if _, err := resolve(WithCacheDir(cacheDir, time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := resolve(WithCacheDir(cacheDir, time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the second resolver to use the cache, got %d requests", requests)
+	}
+
+	// Expire the cached copy.
+	path := filepath.Join(cacheDir, cacheFile(uri))
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("failed to age cache file: %v", err)
+	}
+	if _, err := resolve(WithCacheDir(cacheDir, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a TTL of 0 to never expire, got %d requests", requests)
+	}
+	if _, err := resolve(WithCacheDir(cacheDir, time.Hour), WithOffline()); err != nil {
+		t.Fatalf("expected offline mode to use the expired copy, got %v", err)
+	}
+	body = `{"type": "integer"}`
+	doc, err := resolve(WithCacheDir(cacheDir, time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 || !reflect.DeepEqual(doc, mustParse(t, body)) {
+		t.Errorf("expected the expired copy to be fetched again, got %v after %d requests", doc, requests)
+	}
+
+	// Invalid documents are not cached.
+	body = `{"type": `
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("failed to age cache file: %v", err)
+	}
+	if _, err := resolve(WithCacheDir(cacheDir, time.Hour)); err == nil {
+		t.Error("expected an error for an invalid document")
+	}
+	if _, err := resolve(WithCacheDir(cacheDir, time.Hour), WithOffline()); err != nil {
+		t.Errorf("expected the cached copy to survive an invalid download, got %v", err)
+	}
+
+	if _, err := resolve(WithCacheDir(t.TempDir(), time.Hour), WithOffline()); !errors.Is(err, ErrNotCached) {
+		t.Errorf("expected ErrNotCached, got %v", err)
+	}
+	if _, err := resolve(WithOffline()); !errors.Is(err, ErrNotCached) {
+		t.Errorf("expected ErrNotCached without a cache directory, got %v", err)
+	}
+}
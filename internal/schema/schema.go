@@ -0,0 +1,414 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/VuNe/json-parser/internal/diff"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Violation is a single validation finding.
+type Violation struct {
+	Path    string // JSON Pointer (RFC 6901) to the instance value, "" for the root
+	Keyword string // schema keyword that produced the finding
+	Message string
+}
+
+// String renders the violation on a single line, e.g. `/age: ... [minimum]`.
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s [%s]", path, v.Message, v.Keyword)
+}
+
+// Result holds the outcome of validating a document against a schema.
+type Result struct {
+	Errors      []Violation // failed assertions; the document is valid if empty
+	Annotations []Violation // informational findings, e.g. format mismatches in annotate mode
+}
+
+// Valid reports whether the document satisfied the schema.
+func (r Result) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Schema is a compiled JSON Schema. It supports the boolean schemas and the
+// keywords type, enum, const, properties, required, additionalProperties,
+// items, minItems, maxItems, minLength, maxLength, pattern, minimum, maximum
+// and format. Other keywords are ignored.
+type Schema struct {
+	config *config
+
+	always               *bool // set for the boolean schemas true and false
+	types                []string
+	enum                 []any
+	hasEnum              bool
+	constValue           any
+	hasConst             bool
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	items                *Schema
+	minItems, maxItems   *int
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	minimum, maximum     *float64
+	format               string
+}
+
+// Compile compiles a parsed schema document.
+func Compile(doc parser.JSONValue, opts ...Option) (*Schema, error) {
+	return compile(doc, "", newConfig(opts))
+}
+
+// compile compiles the schema at path within the schema document.
+func compile(doc parser.JSONValue, path string, cfg *config) (*Schema, error) {
+	s := &Schema{config: cfg}
+
+	if b, ok := doc.(bool); ok {
+		s.always = &b
+		return s, nil
+	}
+	obj, ok := doc.(parser.JSONObject)
+	if !ok {
+		return nil, schemaError(path, "schema must be an object or a boolean")
+	}
+
+	var err error
+	if t, ok := obj["type"]; ok {
+		if s.types, err = compileTypes(t, path+"/type"); err != nil {
+			return nil, err
+		}
+	}
+	if enum, ok := obj["enum"]; ok {
+		values, isArray := enum.([]any)
+		if !isArray {
+			return nil, schemaError(path+"/enum", "must be an array")
+		}
+		s.enum, s.hasEnum = values, true
+	}
+	if c, ok := obj["const"]; ok {
+		s.constValue, s.hasConst = c, true
+	}
+	if props, ok := obj["properties"]; ok {
+		propsObj, isObject := props.(parser.JSONObject)
+		if !isObject {
+			return nil, schemaError(path+"/properties", "must be an object")
+		}
+		s.properties = make(map[string]*Schema, len(propsObj))
+		for _, name := range sortedKeys(propsObj) {
+			if s.properties[name], err = compile(propsObj[name], path+"/properties/"+pointer.Escape(name), cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req, ok := obj["required"]; ok {
+		if s.required, err = compileStrings(req, path+"/required"); err != nil {
+			return nil, err
+		}
+	}
+	if additional, ok := obj["additionalProperties"]; ok {
+		if s.additionalProperties, err = compile(additional, path+"/additionalProperties", cfg); err != nil {
+			return nil, err
+		}
+	}
+	if items, ok := obj["items"]; ok {
+		if s.items, err = compile(items, path+"/items", cfg); err != nil {
+			return nil, err
+		}
+	}
+	counts := []struct {
+		keyword string
+		target  **int
+	}{
+		{"minItems", &s.minItems}, {"maxItems", &s.maxItems},
+		{"minLength", &s.minLength}, {"maxLength", &s.maxLength},
+	}
+	for _, c := range counts {
+		if value, ok := obj[c.keyword]; ok {
+			if *c.target, err = compileCount(value, path+"/"+c.keyword); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pattern, ok := obj["pattern"]; ok {
+		str, isString := pattern.(string)
+		if !isString {
+			return nil, schemaError(path+"/pattern", "must be a string")
+		}
+		if s.pattern, err = regexp.Compile(str); err != nil {
+			return nil, schemaError(path+"/pattern", fmt.Sprintf("invalid regular expression: %v", err))
+		}
+	}
+	bounds := []struct {
+		keyword string
+		target  **float64
+	}{
+		{"minimum", &s.minimum}, {"maximum", &s.maximum},
+	}
+	for _, b := range bounds {
+		if value, ok := obj[b.keyword]; ok {
+			n, isNumber := toFloat(value)
+			if !isNumber {
+				return nil, schemaError(path+"/"+b.keyword, "must be a number")
+			}
+			*b.target = &n
+		}
+	}
+	if format, ok := obj["format"]; ok {
+		str, isString := format.(string)
+		if !isString {
+			return nil, schemaError(path+"/format", "must be a string")
+		}
+		s.format = str
+	}
+
+	return s, nil
+}
+
+// compileTypes compiles the "type" keyword, which is a type name or an array
+// of type names.
+func compileTypes(value parser.JSONValue, path string) ([]string, error) {
+	var types []string
+	if name, ok := value.(string); ok {
+		types = []string{name}
+	} else {
+		var err error
+		if types, err = compileStrings(value, path); err != nil {
+			return nil, schemaError(path, "must be a string or an array of strings")
+		}
+	}
+
+	for _, name := range types {
+		switch name {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, schemaError(path, fmt.Sprintf("unknown type %q", name))
+		}
+	}
+	return types, nil
+}
+
+// compileStrings compiles a keyword whose value is an array of strings.
+func compileStrings(value parser.JSONValue, path string) ([]string, error) {
+	arr, ok := value.([]any)
+	if !ok {
+		return nil, schemaError(path, "must be an array of strings")
+	}
+	strs := make([]string, 0, len(arr))
+	for _, elem := range arr {
+		str, isString := elem.(string)
+		if !isString {
+			return nil, schemaError(path, "must be an array of strings")
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+// compileCount compiles a keyword whose value is a non-negative integer.
+func compileCount(value parser.JSONValue, path string) (*int, error) {
+	n, ok := toFloat(value)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, schemaError(path, "must be a non-negative integer")
+	}
+	count := int(n)
+	return &count, nil
+}
+
+// schemaError reports an invalid schema document.
+func schemaError(path, message string) error {
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Errorf("invalid schema at %s: %s", path, message)
+}
+
+// Validate validates a parsed document against the schema.
+func (s *Schema) Validate(value parser.JSONValue) Result {
+	var result Result
+	s.validate(value, "", &result)
+	return result
+}
+
+// validate checks value at path against s, recording findings in result.
+func (s *Schema) validate(value parser.JSONValue, path string, result *Result) {
+	fail := func(keyword, format string, args ...any) {
+		result.Errors = append(result.Errors, Violation{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.always != nil {
+		if !*s.always {
+			fail("false", "no value is allowed here")
+		}
+		return
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(value, t) }) {
+		fail("type", "expected %s, got %s", joinTypes(s.types), typeOf(value))
+	}
+	if s.hasEnum && !slices.ContainsFunc(s.enum, func(e any) bool { return diff.Equal(e, value) }) {
+		fail("enum", "value is not one of the allowed values")
+	}
+	if s.hasConst && !diff.Equal(s.constValue, value) {
+		fail("const", "value does not match the required constant")
+	}
+
+	switch v := value.(type) {
+	case parser.JSONObject:
+		s.validateObject(v, path, result)
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("minItems", "expected at least %d items, got %d", *s.minItems, len(v))
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("maxItems", "expected at most %d items, got %d", *s.maxItems, len(v))
+		}
+		if s.items != nil {
+			for i, elem := range v {
+				s.items.validate(elem, path+"/"+strconv.Itoa(i), result)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			fail("minLength", "expected at least %d characters, got %d", *s.minLength, length)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("maxLength", "expected at most %d characters, got %d", *s.maxLength, length)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("pattern", "value does not match pattern %q", s.pattern.String())
+		}
+		s.validateFormat(v, path, result)
+	case int64, float64:
+		n, _ := toFloat(v)
+		if s.minimum != nil && n < *s.minimum {
+			fail("minimum", "value %v is less than the minimum %v", v, *s.minimum)
+		}
+		if s.maximum != nil && n > *s.maximum {
+			fail("maximum", "value %v is greater than the maximum %v", v, *s.maximum)
+		}
+	}
+}
+
+// validateObject applies the object keywords to obj.
+func (s *Schema) validateObject(obj parser.JSONObject, path string, result *Result) {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			result.Errors = append(result.Errors, Violation{
+				Path:    path,
+				Keyword: "required",
+				Message: fmt.Sprintf("missing required property %q", name),
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(obj) {
+		childPath := path + "/" + pointer.Escape(key)
+		if sub, ok := s.properties[key]; ok {
+			sub.validate(obj[key], childPath, result)
+		} else if s.additionalProperties != nil {
+			s.additionalProperties.validate(obj[key], childPath, result)
+		}
+	}
+}
+
+// validateFormat checks str against the "format" keyword. Unknown formats are
+// ignored, as the JSON Schema specification recommends.
+func (s *Schema) validateFormat(str, path string, result *Result) {
+	if s.format == "" {
+		return
+	}
+	check, ok := s.config.formats.Lookup(s.format)
+	if !ok || check(str) {
+		return
+	}
+
+	violation := Violation{Path: path, Keyword: "format", Message: fmt.Sprintf("value is not a valid %s", s.format)}
+	if s.config.formatMode == FormatAssert {
+		result.Errors = append(result.Errors, violation)
+	} else {
+		result.Annotations = append(result.Annotations, violation)
+	}
+}
+
+// sortedKeys returns the keys of obj in sorted order, so findings are
+// reported deterministically.
+func sortedKeys(obj parser.JSONObject) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// hasType reports whether value is an instance of the JSON Schema type t.
+func hasType(value parser.JSONValue, t string) bool {
+	if t == "integer" {
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	}
+	actual := typeOf(value)
+	return actual == t || (t == "number" && actual == "integer")
+}
+
+// typeOf returns the JSON Schema type name of a parsed value.
+func typeOf(value parser.JSONValue) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// joinTypes renders a list of type names, e.g. "string or null".
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	result := ""
+	for i, t := range types {
+		switch {
+		case i == 0:
+		case i == len(types)-1:
+			result += " or "
+		default:
+			result += ", "
+		}
+		result += t
+	}
+	return result
+}
+
+// toFloat converts numeric values to float64.
+func toFloat(value parser.JSONValue) (float64, bool) {
+	switch n := value.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func mustCompile(t *testing.T, input string, opts ...Option) *Schema {
+	t.Helper()
+	s, err := Compile(mustParse(t, input), opts...)
+	if err != nil {
+		t.Fatalf("failed to compile schema %q: %v", input, err)
+	}
+	return s
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		input    string
+		expected []string
+	}{
+		{name: "true schema", schema: `true`, input: `{"anything": [1]}`},
+		{name: "false schema", schema: `false`, input: `1`, expected: []string{"(root): no value is allowed here [false]"}},
+		{name: "type match", schema: `{"type": "string"}`, input: `"a"`},
+		{name: "type mismatch", schema: `{"type": "string"}`, input: `1`, expected: []string{"(root): expected string, got integer [type]"}},
+		{name: "integer accepts integral float", schema: `{"type": "integer"}`, input: `1.0`},
+		{name: "integer rejects fraction", schema: `{"type": "integer"}`, input: `1.5`, expected: []string{"(root): expected integer, got number [type]"}},
+		{name: "number accepts integer", schema: `{"type": "number"}`, input: `1`},
+		{name: "type list", schema: `{"type": ["string", "null"]}`, input: `true`, expected: []string{"(root): expected string or null, got boolean [type]"}},
+		{name: "enum", schema: `{"enum": ["a", 1]}`, input: `1.0`},
+		{name: "enum mismatch", schema: `{"enum": ["a", 1]}`, input: `"b"`, expected: []string{"(root): value is not one of the allowed values [enum]"}},
+		{name: "const", schema: `{"const": {"a": [1]}}`, input: `{"a": [2]}`, expected: []string{"(root): value does not match the required constant [const]"}},
+		{
+			name:     "object keywords",
+			schema:   `{"properties": {"name": {"type": "string"}}, "required": ["name", "id"], "additionalProperties": false}`,
+			input:    `{"name": 1, "extra": true}`,
+			expected: []string{`(root): missing required property "id" [required]`, "/extra: no value is allowed here [false]", "/name: expected string, got integer [type]"},
+		},
+		{
+			name:     "items and counts",
+			schema:   `{"items": {"type": "integer", "minimum": 0}, "minItems": 1, "maxItems": 2}`,
+			input:    `[1, -1, 2]`,
+			expected: []string{"(root): expected at most 2 items, got 3 [maxItems]", "/1: value -1 is less than the minimum 0 [minimum]"},
+		},
+		{
+			name:     "string keywords",
+			schema:   `{"minLength": 2, "maxLength": 3, "pattern": "^[a-z]+$"}`,
+			input:    `"é"`,
+			expected: []string{"(root): expected at least 2 characters, got 1 [minLength]", `(root): value does not match pattern "^[a-z]+$" [pattern]`},
+		},
+		{name: "maximum", schema: `{"maximum": 1.5}`, input: `2`, expected: []string{"(root): value 2 is greater than the maximum 1.5 [maximum]"}},
+		{name: "keywords ignored for other types", schema: `{"minLength": 5, "minimum": 3, "required": ["a"]}`, input: `[1]`},
+		{name: "unknown keywords ignored", schema: `{"title": "x", "$comment": "y"}`, input: `1`},
+		{name: "escaped path", schema: `{"additionalProperties": {"type": "null"}}`, input: `{"a/b": 1}`, expected: []string{"/a~1b: expected null, got integer [type]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mustCompile(t, tt.schema).Validate(mustParse(t, tt.input))
+
+			var got []string
+			for _, v := range result.Errors {
+				got = append(got, v.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if result.Valid() != (len(tt.expected) == 0) {
+				t.Errorf("Valid() = %v with errors %q", result.Valid(), got)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{name: "not a schema", schema: `1`, expected: "invalid schema at (root): schema must be an object or a boolean"},
+		{name: "unknown type", schema: `{"type": "text"}`, expected: `invalid schema at /type: unknown type "text"`},
+		{name: "bad type list", schema: `{"type": [1]}`, expected: "invalid schema at /type: must be a string or an array of strings"},
+		{name: "bad enum", schema: `{"enum": 1}`, expected: "invalid schema at /enum: must be an array"},
+		{name: "bad required", schema: `{"required": "a"}`, expected: "invalid schema at /required: must be an array of strings"},
+		{name: "negative count", schema: `{"minLength": -1}`, expected: "invalid schema at /minLength: must be a non-negative integer"},
+		{name: "bad pattern", schema: `{"pattern": "("}`, expected: "invalid schema at /pattern: invalid regular expression"},
+		{name: "bad minimum", schema: `{"minimum": "1"}`, expected: "invalid schema at /minimum: must be a number"},
+		{name: "nested", schema: `{"properties": {"a": {"items": 1}}}`, expected: "invalid schema at /properties/a/items: schema must be an object or a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(mustParse(t, tt.schema))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
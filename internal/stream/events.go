@@ -0,0 +1,217 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// EventType identifies the kind of an Event.
+type EventType int
+
+const (
+	StartObject EventType = iota
+	EndObject
+	StartArray
+	EndArray
+	Key
+	String
+	Number
+	Boolean
+	Null
+)
+
+// String returns the name of the event type, e.g. "StartObject".
+func (t EventType) String() string {
+	switch t {
+	case StartObject:
+		return "StartObject"
+	case EndObject:
+		return "EndObject"
+	case StartArray:
+		return "StartArray"
+	case EndArray:
+		return "EndArray"
+	case Key:
+		return "Key"
+	case String:
+		return "String"
+	case Number:
+		return "Number"
+	case Boolean:
+		return "Boolean"
+	case Null:
+		return "Null"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Event is a step in the SAX-style event stream of a document.
+type Event struct {
+	Type     EventType
+	Value    string         // decoded text of a Key or String, literal text of a Number or Boolean
+	Position lexer.Position // where the token starts
+	End      lexer.Position // just past the token
+}
+
+// String renders the event as its type followed by its value, if any, e.g.
+// `Key "name"` or `Number 1.5`. Keys and strings are quoted as JSON strings.
+func (e Event) String() string {
+	switch e.Type {
+	case Key, String:
+		quoted, _ := encoder.Marshal(e.Value)
+		return fmt.Sprintf("%s %s", e.Type, quoted)
+	case Number, Boolean:
+		return fmt.Sprintf("%s %s", e.Type, e.Value)
+	default:
+		return e.Type.String()
+	}
+}
+
+// EventReader reads the event stream of a single JSON document, for
+// processing documents without building them in memory.
+type EventReader interface {
+	// Next returns the next event, or io.EOF after the end of the document.
+	// Syntax errors are reported when they are reached, so the events before
+	// them are still returned.
+	Next() (Event, error)
+}
+
+// readState is what an eventReader expects next.
+type readState int
+
+const (
+	expectValue      readState = iota // a value
+	expectFirstValue                  // a value or ']'
+	expectKey                         // a key
+	expectFirstKey                    // a key or '}'
+	expectSeparator                   // ',' or the end of the enclosing container or document
+	expectNothing                     // the document ended
+)
+
+// eventReader is the concrete implementation of EventReader.
+type eventReader struct {
+	lexer lexer.Lexer
+	stack []lexer.TokenType // open containers, LEFT_BRACE or LEFT_BRACKET
+	state readState
+}
+
+// NewEventReader returns an EventReader for the document tokenized by l.
+func NewEventReader(l lexer.Lexer) EventReader {
+	return &eventReader{lexer: l}
+}
+
+// Next implements EventReader.
+func (r *eventReader) Next() (Event, error) {
+	for {
+		if r.state == expectNothing {
+			return Event{}, io.EOF
+		}
+		tok, err := r.lexer.NextToken()
+		if err != nil {
+			return Event{}, err
+		}
+		end := r.lexer.Position()
+
+		switch r.state {
+		case expectFirstKey:
+			if tok.Type == lexer.RIGHT_BRACE {
+				return r.close(EndObject, tok, end), nil
+			}
+			fallthrough
+		case expectKey:
+			return r.key(tok, end)
+		case expectFirstValue:
+			if tok.Type == lexer.RIGHT_BRACKET {
+				return r.close(EndArray, tok, end), nil
+			}
+			fallthrough
+		case expectValue:
+			return r.value(tok, end)
+		default: // expectSeparator
+			if len(r.stack) == 0 {
+				if tok.Type != lexer.EOF {
+					return Event{}, unexpected(tok, "end of input")
+				}
+				r.state = expectNothing
+				continue
+			}
+			switch open := r.stack[len(r.stack)-1]; {
+			case tok.Type == lexer.COMMA && open == lexer.LEFT_BRACE:
+				r.state = expectKey
+			case tok.Type == lexer.COMMA:
+				r.state = expectValue
+			case tok.Type == lexer.RIGHT_BRACE && open == lexer.LEFT_BRACE:
+				return r.close(EndObject, tok, end), nil
+			case tok.Type == lexer.RIGHT_BRACKET && open == lexer.LEFT_BRACKET:
+				return r.close(EndArray, tok, end), nil
+			case open == lexer.LEFT_BRACE:
+				return Event{}, unexpected(tok, "',' or '}'")
+			default:
+				return Event{}, unexpected(tok, "',' or ']'")
+			}
+		}
+	}
+}
+
+// key returns the Key event for tok, which ends at end, and consumes the
+// colon after it.
+func (r *eventReader) key(tok lexer.Token, end lexer.Position) (Event, error) {
+	if tok.Type != lexer.STRING {
+		return Event{}, unexpected(tok, "string key")
+	}
+	colon, err := r.lexer.NextToken()
+	if err != nil {
+		return Event{}, err
+	}
+	if colon.Type != lexer.COLON {
+		return Event{}, unexpected(colon, "':'")
+	}
+	r.state = expectValue
+	return Event{Type: Key, Value: tok.Value, Position: tok.Position, End: end}, nil
+}
+
+// value returns the event starting the value at tok, which ends at end.
+func (r *eventReader) value(tok lexer.Token, end lexer.Position) (Event, error) {
+	event := Event{Value: tok.Value, Position: tok.Position, End: end}
+	r.state = expectSeparator
+	switch tok.Type {
+	case lexer.LEFT_BRACE:
+		event.Type, event.Value = StartObject, ""
+		r.stack = append(r.stack, tok.Type)
+		r.state = expectFirstKey
+	case lexer.LEFT_BRACKET:
+		event.Type, event.Value = StartArray, ""
+		r.stack = append(r.stack, tok.Type)
+		r.state = expectFirstValue
+	case lexer.STRING:
+		event.Type = String
+	case lexer.NUMBER:
+		event.Type = Number
+	case lexer.BOOLEAN:
+		event.Type = Boolean
+	case lexer.NULL:
+		event.Type, event.Value = Null, ""
+	default:
+		return Event{}, unexpected(tok, "JSON value")
+	}
+	return event, nil
+}
+
+// close pops the innermost container and returns its end event.
+func (r *eventReader) close(t EventType, tok lexer.Token, end lexer.Position) Event {
+	r.stack = r.stack[:len(r.stack)-1]
+	r.state = expectSeparator
+	return Event{Type: t, Position: tok.Position, End: end}
+}
+
+// unexpected returns the error for tok where expected was required.
+func unexpected(tok lexer.Token, expected string) error {
+	if tok.Type == lexer.EOF {
+		return fmt.Errorf("unexpected end of input at %s, expected %s", tok.Position, expected)
+	}
+	return fmt.Errorf("unexpected %s at %s, expected %s", tok.Type, tok.Position, expected)
+}
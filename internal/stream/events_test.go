@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// readEvents returns the rendered events of input and the error that ended
+// the stream, nil for io.EOF.
+func readEvents(input string) ([]string, error) {
+	r := NewEventReader(lexer.New(input))
+	var events []string
+	for {
+		event, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event.String())
+	}
+}
+
+func TestEventReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:  "object",
+			input: `{"name": "x", "n": 1.5, "ok": true, "none": null}`,
+			expected: []string{
+				"StartObject", `Key "name"`, `String "x"`, `Key "n"`, "Number 1.5",
+				`Key "ok"`, "Boolean true", `Key "none"`, "Null", "EndObject",
+			},
+		},
+		{
+			name:     "nested",
+			input:    `[{"a": []}, [{}], -2]`,
+			expected: []string{"StartArray", "StartObject", `Key "a"`, "StartArray", "EndArray", "EndObject", "StartArray", "StartObject", "EndObject", "EndArray", "Number -2", "EndArray"},
+		},
+		{name: "scalar", input: `"a\nb"`, expected: []string{`String "a\nb"`}},
+		{name: "empty object", input: ` {} `, expected: []string{"StartObject", "EndObject"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := readEvents(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(events, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, events)
+			}
+		})
+	}
+}
+
+func TestEventReader_Positions(t *testing.T) {
+	r := NewEventReader(lexer.New("{\n  \"a\": [1]\n}"))
+	var positions []string
+	for {
+		event, err := r.Next()
+		if err != nil {
+			break
+		}
+		positions = append(positions, event.Position.String())
+	}
+
+	expected := []string{"line 1, column 1", "line 2, column 3", "line 2, column 8", "line 2, column 9", "line 2, column 10", "line 3, column 1"}
+	if !reflect.DeepEqual(positions, expected) {
+		t.Errorf("expected %q, got %q", expected, positions)
+	}
+}
+
+func TestEventReader_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string // events before the error
+		errorMsg string
+	}{
+		{name: "empty input", input: "", errorMsg: "unexpected end of input"},
+		{name: "trailing comma", input: `[1,]`, expected: []string{"StartArray", "Number 1"}, errorMsg: "expected JSON value"},
+		{name: "missing colon", input: `{"a" 1}`, expected: []string{"StartObject"}, errorMsg: "expected ':'"},
+		{name: "non-string key", input: `{1: 2}`, expected: []string{"StartObject"}, errorMsg: "expected string key"},
+		{name: "mismatched bracket", input: `[1}`, expected: []string{"StartArray", "Number 1"}, errorMsg: "expected ',' or ']'"},
+		{name: "unterminated", input: `{"a": [`, expected: []string{"StartObject", `Key "a"`, "StartArray"}, errorMsg: "unexpected end of input"},
+		{name: "content after the document", input: `{} 1`, expected: []string{"StartObject", "EndObject"}, errorMsg: "expected end of input"},
+		{name: "lexical error", input: `[tru]`, expected: []string{"StartArray"}, errorMsg: "invalid keyword"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := readEvents(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+			if !reflect.DeepEqual(events, tt.expected) {
+				t.Errorf("expected events %q, got %q", tt.expected, events)
+			}
+		})
+	}
+}
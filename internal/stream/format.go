@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+)
+
+// Format re-emits the document read from r to w with each array element and
+// object member on its own line, indented with one copy of indent per level,
+// or compactly if indent is empty. Unlike encoder.MarshalIndent it works on
+// the event stream, so the input is never held in memory, object members
+// keep their order and numbers are written exactly as in the input. The
+// output has no trailing newline. If the input is invalid, what was written
+// before the error was found stays written.
+func Format(r io.Reader, w io.Writer, indent string) error {
+	f := formatter{w: bufio.NewWriter(w), indent: indent}
+	events := NewEventReader(lexer.NewReader(r))
+	for {
+		event, err := events.Next()
+		if errors.Is(err, io.EOF) {
+			return f.w.Flush()
+		}
+		if err != nil {
+			f.w.Flush()
+			return err
+		}
+		if err := f.write(event); err != nil {
+			return err
+		}
+	}
+}
+
+// Compact copies the document read from r to w without insignificant
+// whitespace, validating it on the way. Unlike Format with an empty indent,
+// strings are copied byte for byte, so escapes such as \u00e9 or \/ are kept
+// as written.
+func Compact(r io.Reader, w io.Writer) error {
+	return Rewrite(r, w)
+}
+
+// formatter writes the events of a document as indented JSON text.
+type formatter struct {
+	w        *bufio.Writer
+	indent   string
+	depth    int
+	first    bool // the next value or key is the first of its container
+	afterKey bool // the next value is that of a member whose key was written
+}
+
+// write writes event along with the separator and line break before it.
+func (f *formatter) write(event Event) error {
+	switch event.Type {
+	case EndObject, EndArray:
+		f.depth--
+		if !f.first {
+			f.newline()
+		}
+		f.first = false
+		if event.Type == EndObject {
+			return f.w.WriteByte('}')
+		}
+		return f.w.WriteByte(']')
+	}
+
+	switch {
+	case f.afterKey:
+		f.afterKey = false
+	case f.depth > 0:
+		if !f.first {
+			f.w.WriteByte(',')
+		}
+		f.newline()
+	}
+	f.first = false
+
+	switch event.Type {
+	case StartObject, StartArray:
+		f.depth++
+		f.first = true
+		if event.Type == StartObject {
+			return f.w.WriteByte('{')
+		}
+		return f.w.WriteByte('[')
+	case Key:
+		if err := f.writeString(event.Value); err != nil {
+			return err
+		}
+		f.afterKey = true
+		if f.indent == "" {
+			return f.w.WriteByte(':')
+		}
+		_, err := f.w.WriteString(": ")
+		return err
+	case String:
+		return f.writeString(event.Value)
+	case Null:
+		_, err := f.w.WriteString("null")
+		return err
+	default: // Number, Boolean
+		_, err := f.w.WriteString(event.Value)
+		return err
+	}
+}
+
+// writeString writes s as a JSON string.
+func (f *formatter) writeString(s string) error {
+	data, err := encoder.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = f.w.Write(data)
+	return err
+}
+
+// newline starts a new line indented to the current depth, unless the output
+// is compact.
+func (f *formatter) newline() {
+	if f.indent == "" {
+		return
+	}
+	f.w.WriteByte('\n')
+	f.w.WriteString(strings.Repeat(f.indent, f.depth))
+}
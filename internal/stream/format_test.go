@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		indent   string
+		expected string
+	}{
+		{
+			name:     "indented",
+			input:    `{"b": [1, 1.50, {"c": null}], "a": true}`,
+			indent:   "  ",
+			expected: "{\n  \"b\": [\n    1,\n    1.50,\n    {\n      \"c\": null\n    }\n  ],\n  \"a\": true\n}",
+		},
+		{
+			name:     "compact",
+			input:    "{\n  \"b\": [1e3, \"x\"],\n  \"a\": {}\n}",
+			expected: `{"b":[1e3,"x"],"a":{}}`,
+		},
+		{name: "empty containers", input: `[{}, []]`, indent: "\t", expected: "[\n\t{},\n\t[]\n]"},
+		{name: "scalar", input: " \"a\u00e9\\n\" ", indent: "  ", expected: "\"a\u00e9\\n\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := Format(strings.NewReader(tt.input), &out, tt.indent); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestFormat_Errors(t *testing.T) {
+	for _, input := range []string{``, `{"a" 1}`, `[1, 2`, `[] []`} {
+		t.Run(input, func(t *testing.T) {
+			var out strings.Builder
+			if err := Format(strings.NewReader(input), &out, "  "); err == nil {
+				t.Errorf("expected an error, got output %q", out.String())
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "whitespace", input: "{\n\t\"a\" : [ 1 , 2.50 ] ,\r\n \"b\" : { } }\n", expected: `{"a":[1,2.50],"b":{}}`},
+		{name: "strings kept as written", input: `[ "a b", "\u00e9\/\n" ]`, expected: `["a b","\u00e9\/\n"]`},
+		{name: "invalid", input: `[1 2]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := Compact(strings.NewReader(tt.input), &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && out.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, out.String())
+			}
+		})
+	}
+}
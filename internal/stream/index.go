@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// Range locates a value in a document by its byte offsets, so that it can be
+// read directly, e.g. with io.NewSectionReader, without parsing what
+// precedes it.
+type Range struct {
+	Path  string // JSON Pointer to the value
+	Start int64  // offset of the first byte of the value
+	End   int64  // offset just past the last byte of the value
+}
+
+// errStopped ends indexing when the consumer stops iterating.
+var errStopped = errors.New("indexing stopped")
+
+// indexer walks a document token by token, yielding the ranges of the
+// values at one depth.
+type indexer struct {
+	tokenizer
+	depth int
+	path  []string // reference tokens of the current value
+	yield func(Range, error) bool
+}
+
+// Index returns an iterator over the byte ranges of the values at depth in
+// the document read from r, in document order: with depth 1 the members of
+// the top-level object or the elements of the top-level array, with depth 2
+// theirs, and so on. Depth 0 yields the whole document. The document is
+// scanned without being built in memory, and validated, so the iterator
+// stops after yielding the first error with a zero Range.
+func Index(r io.Reader, depth int) iter.Seq2[Range, error] {
+	return func(yield func(Range, error) bool) {
+		ix := &indexer{tokenizer: tokenizer{r: bufio.NewReader(r)}, depth: depth, yield: yield}
+		err := ix.index()
+		if err != nil && !errors.Is(err, errStopped) {
+			yield(Range{}, err)
+		}
+	}
+}
+
+// index walks the whole document.
+func (ix *indexer) index() error {
+	tok, err := ix.first()
+	if err != nil {
+		return err
+	}
+	if err := ix.walk(tok); err != nil {
+		return err
+	}
+	return ix.last()
+}
+
+// walk walks the value starting with tok, yielding its range if it is at
+// the indexed depth.
+func (ix *indexer) walk(tok byte) error {
+	start := ix.start(tok)
+	var err error
+	switch tok {
+	case '{':
+		err = ix.object()
+	case '[':
+		err = ix.array()
+	case '"', 'v':
+	default:
+		err = ix.unexpected(tok, "a JSON value")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(ix.path) == ix.depth {
+		path := ""
+		for _, token := range ix.path {
+			path += "/" + pointer.Escape(token)
+		}
+		if !ix.yield(Range{Path: path, Start: int64(start), End: int64(ix.offset)}, nil) {
+			return errStopped
+		}
+	}
+	return nil
+}
+
+// object walks the object whose '{' was just read.
+func (ix *indexer) object() error {
+	tok, err := ix.next()
+	if err != nil {
+		return ix.endOfInput(err)
+	}
+	if tok == '}' {
+		return nil
+	}
+
+	for {
+		if tok != '"' {
+			return ix.unexpected(tok, "a string key")
+		}
+		key := ix.value.(string)
+		if tok, err = ix.next(); err != nil {
+			return ix.endOfInput(err)
+		}
+		if tok != ':' {
+			return ix.unexpected(tok, "':'")
+		}
+		if tok, err = ix.next(); err != nil {
+			return ix.endOfInput(err)
+		}
+		if err := ix.entry(key, tok); err != nil {
+			return err
+		}
+
+		if tok, err = ix.next(); err != nil {
+			return ix.endOfInput(err)
+		}
+		switch tok {
+		case '}':
+			return nil
+		case ',':
+			if tok, err = ix.next(); err != nil {
+				return ix.endOfInput(err)
+			}
+		default:
+			return ix.unexpected(tok, "',' or '}'")
+		}
+	}
+}
+
+// array walks the array whose '[' was just read.
+func (ix *indexer) array() error {
+	tok, err := ix.next()
+	if err != nil {
+		return ix.endOfInput(err)
+	}
+	if tok == ']' {
+		return nil
+	}
+
+	for i := 0; ; i++ {
+		if err := ix.entry(strconv.Itoa(i), tok); err != nil {
+			return err
+		}
+
+		if tok, err = ix.next(); err != nil {
+			return ix.endOfInput(err)
+		}
+		switch tok {
+		case ']':
+			return nil
+		case ',':
+			if tok, err = ix.next(); err != nil {
+				return ix.endOfInput(err)
+			}
+		default:
+			return ix.unexpected(tok, "',' or ']'")
+		}
+	}
+}
+
+// entry walks the member or element with the given reference token whose
+// value starts with tok.
+func (ix *indexer) entry(token string, tok byte) error {
+	ix.path = append(ix.path, token)
+	defer func() { ix.path = ix.path[:len(ix.path)-1] }()
+	return ix.walk(tok)
+}
@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	doc := `{"users": [{"id": 1}, {"id": 2}], "a/b": "x" , "n": -1.5e3}`
+
+	tests := []struct {
+		name     string
+		input    string
+		depth    int
+		expected []string // path=text of each range
+	}{
+		{
+			name:     "top-level members",
+			input:    doc,
+			depth:    1,
+			expected: []string{`/users=[{"id": 1}, {"id": 2}]`, `/a~1b="x"`, `/n=-1.5e3`},
+		},
+		{
+			name:     "second level",
+			input:    doc,
+			depth:    2,
+			expected: []string{`/users/0={"id": 1}`, `/users/1={"id": 2}`},
+		},
+		{name: "whole document", input: "  [1, 2]\n", depth: 0, expected: []string{`=[1, 2]`}},
+		{name: "top-level array", input: `[true, null]`, depth: 1, expected: []string{`/0=true`, `/1=null`}},
+		{name: "too deep", input: doc, depth: 5, expected: nil},
+		{name: "empty containers", input: `{"a": {}, "b": []}`, depth: 2, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for r, err := range Index(strings.NewReader(tt.input), tt.depth) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got = append(got, r.Path+"="+tt.input[r.Start:r.End])
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIndex_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "empty", input: ` `, expected: "empty input"},
+		{name: "truncated", input: `{"a": [1`, expected: "offset 8: unexpected end of input"},
+		{name: "missing colon", input: `{"a" 1}`, expected: "offset 5: expected ':', got 1"},
+		{name: "trailing content", input: `{} {}`, expected: "offset 3: unexpected content after the document"},
+		{name: "invalid scalar", input: `[tru]`, expected: "offset 1: invalid value tru"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Range
+			var err error
+			for r, e := range Index(strings.NewReader(tt.input), 1) {
+				if e != nil {
+					err = e
+					break
+				}
+				got = append(got, r)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v (ranges %v)", tt.expected, err, got)
+			}
+		})
+	}
+}
+
+func TestIndex_Stop(t *testing.T) {
+	var paths []string
+	for r, err := range Index(strings.NewReader(`[1, 2, 3`), 1) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		paths = append(paths, r.Path)
+		break
+	}
+	if !slices.Equal(paths, []string{"/0"}) {
+		t.Errorf("expected only /0, got %q", paths)
+	}
+}
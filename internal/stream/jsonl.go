@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// ArrayToLines converts the top-level JSON array read from r into
+// newline-delimited JSON (JSONL/NDJSON) written to w: one compact element per
+// line. The array is streamed, so it may be larger than memory.
+func ArrayToLines(r io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for value, err := range NewElementReader(r).Elements() {
+		if err != nil {
+			return err
+		}
+
+		data, err := encoder.Marshal(value)
+		if err != nil {
+			return err
+		}
+		bw.Write(data)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// LinesToArray converts newline-delimited JSON read from r into a single JSON
+// array written to w, one line at a time. Blank lines are skipped; errors
+// name the offending line.
+func LinesToArray(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	out := NewArrayWriter(w)
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if text := string(bytes.TrimSpace(data)); text != "" {
+			value, parseErr := parser.NewWithInput(lexer.New(text), text).Parse()
+			if parseErr != nil {
+				return fmt.Errorf("line %d: %w", line, parseErr)
+			}
+			if writeErr := out.Write(value); writeErr != nil {
+				return writeErr
+			}
+		}
+
+		if err != nil {
+			return out.Close()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestArrayToLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{name: "empty array", input: `[]`, expected: ""},
+		{name: "elements", input: "[\n  {\"id\": 1, \"tags\": [\"a\"]},\n  \"two\",\n  3\n]", expected: "{\"id\":1,\"tags\":[\"a\"]}\n\"two\"\n3\n"},
+		{name: "not an array", input: `{"id": 1}`, expectError: true},
+		{name: "invalid element", input: `[1, tru]`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := ArrayToLines(strings.NewReader(tt.input), &out)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got output %q", out.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestLinesToArray(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError string
+	}{
+		{name: "empty input", input: "", expected: "[]\n"},
+		{name: "lines", input: "{\"id\": 1}\n{\"id\": 2}\n", expected: "[\n  {\"id\":1},\n  {\"id\":2}\n]\n"},
+		{name: "blank lines and no final newline", input: "\n1\r\n\n  \"two\"  ", expected: "[\n  1,\n  \"two\"\n]\n"},
+		{name: "invalid line", input: "{\"id\": 1}\n{\"id\": }\n", expectError: "line 2"},
+		{name: "two values on a line", input: "1 2\n", expectError: "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := LinesToArray(strings.NewReader(tt.input), &out)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
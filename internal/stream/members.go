@@ -0,0 +1,140 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Member is a member of a streamed object.
+type Member struct {
+	Key   string
+	Value parser.JSONValue
+}
+
+// MemberReader reads the members of a top-level JSON object one at a time,
+// in document order, so objects larger than memory (e.g. maps keyed by ID)
+// can be processed member by member. Duplicate keys are all returned.
+type MemberReader interface {
+	// Next returns the next member, or io.EOF after the last one.
+	Next() (Member, error)
+	// Members returns an iterator over the remaining members, for use with
+	// range. It stops after the last member, or after yielding the first
+	// error with a zero Member.
+	Members() iter.Seq2[Member, error]
+}
+
+// memberReader is the concrete implementation of MemberReader. Like
+// elementReader, it scans the raw text of each value and parses only that.
+type memberReader struct {
+	r       *bufio.Reader
+	index   int  // index of the next member
+	started bool // the opening '{' has been read
+	done    bool // the closing '}' has been read
+	raw     []byte
+}
+
+// NewMemberReader returns a MemberReader for the object read from r.
+func NewMemberReader(r io.Reader) MemberReader {
+	return &memberReader{r: bufio.NewReader(r)}
+}
+
+// Next implements MemberReader.
+func (m *memberReader) Next() (Member, error) {
+	if !m.started {
+		m.started = true
+		if err := openContainer(m.r, '{', "object"); err != nil {
+			return Member{}, err
+		}
+	}
+	if m.done {
+		return Member{}, io.EOF
+	}
+
+	c, err := skipWhitespace(m.r)
+	if err != nil {
+		return Member{}, m.endOfInput(err)
+	}
+	if c == '}' && m.index == 0 {
+		// "{}"
+		m.done = true
+		return Member{}, closeContainer(m.r, "object")
+	}
+	if c != '"' {
+		return Member{}, fmt.Errorf("member %d: expected a string key, got %q", m.index, c)
+	}
+	key, err := m.readKey()
+	if err != nil {
+		return Member{}, err
+	}
+
+	if c, err = skipWhitespace(m.r); err != nil {
+		return Member{}, m.endOfInput(err)
+	}
+	if c != ':' {
+		return Member{}, fmt.Errorf("member %q: expected ':' after the key, got %q", key, c)
+	}
+	m.raw, m.done, err = scanValue(m.r, m.raw[:0], '}')
+	if err != nil {
+		return Member{}, fmt.Errorf("member %q: %w", key, err)
+	}
+	if isBlank(m.raw) {
+		return Member{}, fmt.Errorf("member %q: expected JSON value", key)
+	}
+
+	input := string(m.raw)
+	value, err := parser.NewWithInput(lexer.New(input), input).Parse()
+	if err != nil {
+		return Member{}, fmt.Errorf("member %q: %w", key, err)
+	}
+	m.index++
+	if m.done {
+		if err := closeContainer(m.r, "object"); err != io.EOF {
+			return Member{}, err
+		}
+	}
+	return Member{Key: key, Value: value}, nil
+}
+
+// Members implements MemberReader.
+func (m *memberReader) Members() iter.Seq2[Member, error] {
+	return values(m.Next)
+}
+
+// readKey reads and decodes the key string whose opening quote was just read.
+func (m *memberReader) readKey() (string, error) {
+	m.raw = append(m.raw[:0], '"')
+	escaped := false
+	for {
+		c, err := m.r.ReadByte()
+		if err != nil {
+			return "", m.endOfInput(err)
+		}
+		m.raw = append(m.raw, c)
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			tok, err := lexer.New(string(m.raw)).NextToken()
+			if err != nil {
+				return "", fmt.Errorf("member %d: invalid key: %w", m.index, err)
+			}
+			return tok.Value, nil
+		}
+	}
+}
+
+// endOfInput describes a read error in the middle of member m.index.
+func (m *memberReader) endOfInput(err error) error {
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("member %d: unexpected end of input", m.index)
+	}
+	return err
+}
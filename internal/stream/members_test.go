@@ -0,0 +1,93 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestMemberReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []Member
+		expectError string
+	}{
+		{name: "empty object", input: " { } ", expected: nil},
+		{
+			name:     "document order",
+			input:    `{"b": 1, "a": "x", "c": null}`,
+			expected: []Member{{Key: "b", Value: int64(1)}, {Key: "a", Value: "x"}, {Key: "c", Value: nil}},
+		},
+		{
+			name:     "nested values",
+			input:    "{\n  \"a\": {\"b\": [1, \"}\"]},\n  \"c\": []\n}\n",
+			expected: []Member{{Key: "a", Value: parser.JSONObject{"b": []any{int64(1), "}"}}}, {Key: "c", Value: []any(nil)}},
+		},
+		{name: "escaped key", input: `{"a\"bA": true}`, expected: []Member{{Key: `a"bA`, Value: true}}},
+		{name: "duplicate keys", input: `{"a": 1, "a": 2}`, expected: []Member{{Key: "a", Value: int64(1)}, {Key: "a", Value: int64(2)}}},
+		{name: "not an object", input: `[1]`, expectError: "expected a JSON object"},
+		{name: "empty input", input: "  ", expectError: "empty input"},
+		{name: "unquoted key", input: `{a: 1}`, expectError: "member 0: expected a string key"},
+		{name: "trailing comma", input: `{"a": 1,}`, expectError: "member 1: expected a string key"},
+		{name: "missing colon", input: `{"a" 1}`, expectError: `member "a": expected ':'`},
+		{name: "missing value", input: `{"a": }`, expectError: `member "a": expected JSON value`},
+		{name: "invalid value", input: `{"a": tru}`, expectError: `member "a"`},
+		{name: "mismatched bracket", input: `{"a": 1]`, expectError: "unexpected ']'"},
+		{name: "unterminated", input: `{"a": 1`, expectError: "unexpected end of input"},
+		{name: "unterminated key", input: `{"a`, expectError: "member 0: unexpected end of input"},
+		{name: "content after object", input: `{} {}`, expectError: "after the object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMemberReader(strings.NewReader(tt.input))
+			var members []Member
+			var err error
+			for {
+				var member Member
+				member, err = r.Next()
+				if err != nil {
+					break
+				}
+				members = append(members, member)
+			}
+
+			if tt.expectError != "" {
+				if err == io.EOF || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(members, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, members)
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("expected io.EOF after the last member, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMemberReader_Members(t *testing.T) {
+	var keys []string
+	for member, err := range NewMemberReader(strings.NewReader(`{"x": 1, "y": 2, "z": }`)).Members() {
+		if err != nil {
+			if !strings.Contains(err.Error(), `member "z"`) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			continue
+		}
+		keys = append(keys, member.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"x", "y"}) {
+		t.Errorf("expected keys [x y], got %v", keys)
+	}
+}
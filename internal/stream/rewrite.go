@@ -0,0 +1,269 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/pointer"
+)
+
+// editOp is the kind of an Edit.
+type editOp int
+
+const (
+	setOp    editOp = iota // replace the value
+	deleteOp               // remove the member or element
+	renameOp               // change the key of the member
+)
+
+// Edit is a change applied by Rewrite to the values at a path. Paths are
+// JSON Pointers in which a "*" token matches any member or array index, e.g.
+// "/users/*/password". Indices are those of the input, so deleting an
+// element doesn't shift the indices matched by other edits.
+type Edit struct {
+	op    editOp
+	path  string
+	value parser.JSONValue // for setOp
+	name  string           // for renameOp
+}
+
+// Set returns an Edit replacing the values at path with value.
+func Set(path string, value parser.JSONValue) Edit {
+	return Edit{op: setOp, path: path, value: value}
+}
+
+// Delete returns an Edit removing the members or elements at path.
+func Delete(path string) Edit {
+	return Edit{op: deleteOp, path: path}
+}
+
+// Rename returns an Edit changing the key of the members at path to name.
+// Their values are kept, and edited if other edits match them.
+func Rename(path, name string) Edit {
+	return Edit{op: renameOp, path: path, name: name}
+}
+
+// compiledEdit is an Edit with its path split into tokens.
+type compiledEdit struct {
+	Edit
+	tokens []string
+	value  []byte // encoded value, for setOp
+}
+
+// matches reports whether the edit applies to the value at path.
+func (e *compiledEdit) matches(path []string) bool {
+	return slices.EqualFunc(e.tokens, path, func(pattern, token string) bool {
+		return pattern == "*" || pattern == token
+	})
+}
+
+// rewriter copies a document token by token, applying edits.
+type rewriter struct {
+	tokenizer
+	w     *bufio.Writer
+	edits []compiledEdit
+	path  []string // reference tokens of the current value
+	skip  int      // > 0 while the current value is being dropped
+}
+
+// Rewrite copies the JSON document read from r to w, applying the edits to
+// the values that exist at their paths, without building the document in
+// memory, so files of any size can be transformed; memory use only grows
+// with nesting depth and the size of single strings. Edits are matched
+// against the input as read: edits inside a replaced or deleted value have
+// no effect, and when several Set or Rename edits match the first one wins.
+// The output is compact, with strings and numbers copied verbatim; the input
+// is validated as it is copied.
+func Rewrite(r io.Reader, w io.Writer, edits ...Edit) error {
+	rw := &rewriter{tokenizer: tokenizer{r: bufio.NewReader(r)}, w: bufio.NewWriter(w)}
+	for _, edit := range edits {
+		tokens, err := pointer.Split(edit.path)
+		if err != nil {
+			return err
+		}
+		if len(tokens) == 0 && edit.op != setOp {
+			return fmt.Errorf("cannot delete or rename the root")
+		}
+		compiled := compiledEdit{Edit: edit, tokens: tokens}
+		if edit.op == setOp {
+			if compiled.value, err = encoder.Marshal(edit.value); err != nil {
+				return fmt.Errorf("value for %s: %w", edit.path, err)
+			}
+		}
+		rw.edits = append(rw.edits, compiled)
+	}
+
+	tok, err := rw.first()
+	if err != nil {
+		return err
+	}
+	if err := rw.copyValue(tok); err != nil {
+		return err
+	}
+	if err := rw.last(); err != nil {
+		return err
+	}
+	return rw.w.Flush()
+}
+
+// find returns the first edit of kind op matching the current path.
+func (rw *rewriter) find(op editOp) *compiledEdit {
+	for i := range rw.edits {
+		if rw.edits[i].op == op && rw.edits[i].matches(rw.path) {
+			return &rw.edits[i]
+		}
+	}
+	return nil
+}
+
+// write writes b unless the current value is being dropped.
+func (rw *rewriter) write(b ...byte) {
+	if rw.skip == 0 {
+		rw.w.Write(b)
+	}
+}
+
+// copyValue copies the value starting with tok, applying edits within it.
+func (rw *rewriter) copyValue(tok byte) error {
+	if rw.skip == 0 {
+		if edit := rw.find(setOp); edit != nil {
+			rw.write(edit.value...)
+			rw.skip++
+			defer func() { rw.skip-- }()
+		}
+	}
+
+	switch tok {
+	case '{':
+		return rw.copyObject()
+	case '[':
+		return rw.copyArray()
+	case '"', 'v':
+		rw.write(rw.raw...)
+		return nil
+	default:
+		return rw.unexpected(tok, "a JSON value")
+	}
+}
+
+// copyObject copies the object whose '{' was just read.
+func (rw *rewriter) copyObject() error {
+	rw.write('{')
+	tok, err := rw.next()
+	if err != nil {
+		return rw.endOfInput(err)
+	}
+	if tok == '}' {
+		rw.write('}')
+		return nil
+	}
+
+	written := false
+	for {
+		if tok != '"' {
+			return rw.unexpected(tok, "a string key")
+		}
+		key, rawKey := rw.value.(string), slices.Clone(rw.raw)
+		if tok, err = rw.next(); err != nil {
+			return rw.endOfInput(err)
+		}
+		if tok != ':' {
+			return rw.unexpected(tok, "':'")
+		}
+		if tok, err = rw.next(); err != nil {
+			return rw.endOfInput(err)
+		}
+
+		rw.path = append(rw.path, key)
+		if err := rw.copyEntry(rawKey, tok, &written); err != nil {
+			return err
+		}
+		rw.path = rw.path[:len(rw.path)-1]
+
+		if tok, err = rw.next(); err != nil {
+			return rw.endOfInput(err)
+		}
+		switch tok {
+		case '}':
+			rw.write('}')
+			return nil
+		case ',':
+			if tok, err = rw.next(); err != nil {
+				return rw.endOfInput(err)
+			}
+		default:
+			return rw.unexpected(tok, "',' or '}'")
+		}
+	}
+}
+
+// copyEntry copies the member with the given key, or the array element if
+// rawKey is nil, at the current path whose value starts with tok, unless it
+// is deleted. written tracks whether the container has entries in the
+// output, which must then be separated by commas.
+func (rw *rewriter) copyEntry(rawKey []byte, tok byte, written *bool) error {
+	if rw.skip == 0 && rw.find(deleteOp) != nil {
+		rw.skip++
+		defer func() { rw.skip-- }()
+		return rw.copyValue(tok)
+	}
+
+	if *written {
+		rw.write(',')
+	}
+	*written = true
+	if rawKey != nil {
+		if edit := rw.find(renameOp); edit != nil && rw.skip == 0 {
+			quoted, err := encoder.Marshal(edit.name)
+			if err != nil {
+				return err
+			}
+			rawKey = quoted
+		}
+		rw.write(rawKey...)
+		rw.write(':')
+	}
+	return rw.copyValue(tok)
+}
+
+// copyArray copies the array whose '[' was just read.
+func (rw *rewriter) copyArray() error {
+	rw.write('[')
+	tok, err := rw.next()
+	if err != nil {
+		return rw.endOfInput(err)
+	}
+	if tok == ']' {
+		rw.write(']')
+		return nil
+	}
+
+	written := false
+	for i := 0; ; i++ {
+		rw.path = append(rw.path, strconv.Itoa(i))
+		if err := rw.copyEntry(nil, tok, &written); err != nil {
+			return err
+		}
+		rw.path = rw.path[:len(rw.path)-1]
+
+		if tok, err = rw.next(); err != nil {
+			return rw.endOfInput(err)
+		}
+		switch tok {
+		case ']':
+			rw.write(']')
+			return nil
+		case ',':
+			if tok, err = rw.next(); err != nil {
+				return rw.endOfInput(err)
+			}
+		default:
+			return rw.unexpected(tok, "',' or ']'")
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestRewrite(t *testing.T) {
+	doc := `{
+		"name": "app",
+		"users": [
+			{"id": 1, "password": "a", "tags": ["x"]},
+			{"id": 2, "password": "b"}
+		],
+		"a/b": {"old": "\u00e9"}
+	}`
+
+	tests := []struct {
+		name     string
+		input    string
+		edits    []Edit
+		expected string
+	}{
+		{
+			name:     "no edits compacts",
+			input:    doc,
+			expected: `{"name":"app","users":[{"id":1,"password":"a","tags":["x"]},{"id":2,"password":"b"}],"a/b":{"old":"\u00e9"}}`,
+		},
+		{
+			name:     "set",
+			input:    doc,
+			edits:    []Edit{Set("/name", "web"), Set("/users/1", parser.JSONObject{"id": int64(3)})},
+			expected: `{"name":"web","users":[{"id":1,"password":"a","tags":["x"]},{"id":3}],"a/b":{"old":"\u00e9"}}`,
+		},
+		{
+			name:     "delete with wildcard",
+			input:    doc,
+			edits:    []Edit{Delete("/users/*/password")},
+			expected: `{"name":"app","users":[{"id":1,"tags":["x"]},{"id":2}],"a/b":{"old":"\u00e9"}}`,
+		},
+		{
+			name:     "delete first member and elements",
+			input:    doc,
+			edits:    []Edit{Delete("/name"), Delete("/users/0"), Delete("/a~1b")},
+			expected: `{"users":[{"id":2,"password":"b"}]}`,
+		},
+		{
+			name:     "indices refer to the input",
+			input:    `[0, 1, 2, 3]`,
+			edits:    []Edit{Delete("/0"), Set("/2", "two")},
+			expected: `[1,"two",3]`,
+		},
+		{
+			name:     "delete everything",
+			input:    `{"a": [1, 2], "b": {}}`,
+			edits:    []Edit{Delete("/*"), Delete("/a/*")},
+			expected: `{}`,
+		},
+		{
+			name:     "rename keeps and edits the value",
+			input:    doc,
+			edits:    []Edit{Rename("/a~1b/old", "new key"), Set("/a~1b/old", true)},
+			expected: `{"name":"app","users":[{"id":1,"password":"a","tags":["x"]},{"id":2,"password":"b"}],"a/b":{"new key":true}}`,
+		},
+		{
+			name:     "first matching set wins",
+			input:    `{"a": 1}`,
+			edits:    []Edit{Set("/a", 2), Set("/*", 3)},
+			expected: `{"a":2}`,
+		},
+		{
+			name:     "edits inside a replaced value have no effect",
+			input:    `{"a": {"b": 1}}`,
+			edits:    []Edit{Set("/a", nil), Delete("/a/b")},
+			expected: `{"a":null}`,
+		},
+		{name: "missing paths are ignored", input: `{"a": 1}`, edits: []Edit{Set("/b", 2), Delete("/a/0")}, expected: `{"a":1}`},
+		{name: "set root", input: `[1]`, edits: []Edit{Set("", "x")}, expected: `"x"`},
+		{name: "scalars copied verbatim", input: ` 1.50e+3 `, expected: `1.50e+3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := Rewrite(strings.NewReader(tt.input), &out, tt.edits...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRewrite_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		edits       []Edit
+		expectError string
+	}{
+		{name: "empty input", input: " ", expectError: "empty input"},
+		{name: "invalid pointer", input: `{}`, edits: []Edit{Delete("a")}, expectError: "must start with '/'"},
+		{name: "delete root", input: `{}`, edits: []Edit{Delete("")}, expectError: "root"},
+		{name: "unencodable value", input: `{}`, edits: []Edit{Set("/a", make(chan int))}, expectError: "/a"},
+		{name: "missing colon", input: `{"a" 1}`, expectError: "offset 5: expected ':', got 1"},
+		{name: "unquoted key", input: `{a: 1}`, expectError: "invalid value a"},
+		{name: "number key", input: `{1: 1}`, expectError: "expected a string key, got 1"},
+		{name: "trailing comma", input: `[1,]`, expectError: "expected a JSON value"},
+		{name: "missing comma", input: `[1 2]`, expectError: "expected ',' or ']'"},
+		{name: "invalid literal", input: `[tru]`, expectError: "invalid value tru"},
+		{name: "invalid escape", input: `["\x"]`, expectError: "invalid value"},
+		{name: "unterminated", input: `{"a": [1`, expectError: "unexpected end of input"},
+		{name: "content after document", input: `{} []`, expectError: "after the document"},
+		{name: "invalid input inside deleted value", input: `{"a": [1 2]}`, edits: []Edit{Delete("/a")}, expectError: "expected ',' or ']'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Rewrite(strings.NewReader(tt.input), &strings.Builder{}, tt.edits...)
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
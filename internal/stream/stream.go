@@ -0,0 +1,239 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// ElementReader reads the elements of a top-level JSON array one at a time,
+// so arrays larger than memory can be processed element by element.
+type ElementReader interface {
+	// Next returns the next element, or io.EOF after the last one.
+	Next() (parser.JSONValue, error)
+	// Elements returns an iterator over the remaining elements, for use
+	// with range. It stops after the last element, or after yielding the
+	// first error with a nil value.
+	Elements() iter.Seq2[parser.JSONValue, error]
+}
+
+// elementReader is the concrete implementation of ElementReader. It scans the
+// raw text of each element, tracking nesting and strings to find where the
+// element ends, and parses only that text.
+type elementReader struct {
+	r       *bufio.Reader
+	index   int  // index of the next element
+	started bool // the opening '[' has been read
+	done    bool // the closing ']' has been read
+	raw     []byte
+}
+
+// NewElementReader returns an ElementReader for the array read from r.
+func NewElementReader(r io.Reader) ElementReader {
+	return &elementReader{r: bufio.NewReader(r)}
+}
+
+// Next implements ElementReader.
+func (e *elementReader) Next() (parser.JSONValue, error) {
+	if !e.started {
+		e.started = true
+		if err := e.open(); err != nil {
+			return nil, err
+		}
+	}
+	if e.done {
+		return nil, io.EOF
+	}
+
+	if err := e.scanElement(); err != nil {
+		return nil, err
+	}
+	if e.done && e.index == 0 && len(e.raw) == 0 {
+		// "[]"
+		return nil, e.close()
+	}
+
+	input := string(e.raw)
+	value, err := parser.NewWithInput(lexer.New(input), input).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("array element %d: %w", e.index, err)
+	}
+	e.index++
+	if e.done {
+		if err := e.close(); err != io.EOF {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// Elements implements ElementReader.
+func (e *elementReader) Elements() iter.Seq2[parser.JSONValue, error] {
+	return values(e.Next)
+}
+
+// values returns an iterator over the values returned by next up to io.EOF
+// or the first error.
+func values[V any](next func() (V, error)) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for {
+			v, err := next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				var zero V
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// open consumes the opening bracket of the array.
+func (e *elementReader) open() error {
+	return openContainer(e.r, '[', "array")
+}
+
+// close checks that only whitespace follows the closing bracket. It returns
+// io.EOF when that is the case.
+func (e *elementReader) close() error {
+	return closeContainer(e.r, "array")
+}
+
+// openContainer consumes the opening bracket of a top-level array or object,
+// named by kind.
+func openContainer(r *bufio.Reader, open byte, kind string) error {
+	c, err := skipWhitespace(r)
+	if err == io.EOF {
+		return fmt.Errorf("expected a JSON %s, got empty input", kind)
+	}
+	if err != nil {
+		return err
+	}
+	if c != open {
+		return fmt.Errorf("expected a JSON %s, got %q", kind, c)
+	}
+	return nil
+}
+
+// closeContainer checks that only whitespace follows the closing bracket of
+// a top-level array or object, named by kind. It returns io.EOF when that is
+// the case.
+func closeContainer(r *bufio.Reader, kind string) error {
+	c, err := skipWhitespace(r)
+	if err == nil {
+		return fmt.Errorf("unexpected %q after the %s", c, kind)
+	}
+	return err
+}
+
+// scanElement reads the raw text of the next element into e.raw, up to the
+// ',' or ']' that ends it. It sets e.done when the array ends.
+func (e *elementReader) scanElement() error {
+	var err error
+	e.raw, e.done, err = scanValue(e.r, e.raw[:0], ']')
+	if err != nil {
+		return fmt.Errorf("array element %d: %w", e.index, err)
+	}
+	return e.checkNotEmpty()
+}
+
+// scanValue appends the raw text of the value read from r to raw, up to the
+// ',' or closing bracket that ends it, and reports whether it was the
+// closing bracket.
+func scanValue(r *bufio.Reader, raw []byte, closing byte) ([]byte, bool, error) {
+	depth := 0
+	inString, escaped := false, false
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			return raw, false, errors.New("unexpected end of input")
+		}
+		if err != nil {
+			return raw, false, err
+		}
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			raw = append(raw, c)
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				if c != closing {
+					return raw, false, fmt.Errorf("unexpected '%c'", c)
+				}
+				return raw, true, nil
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return raw, false, nil
+			}
+		}
+		raw = append(raw, c)
+	}
+}
+
+// checkNotEmpty rejects a missing element, as in "[1,,2]" or "[1,]". The
+// empty array "[]" is the only place an element may be absent.
+func (e *elementReader) checkNotEmpty() error {
+	if !isBlank(e.raw) {
+		return nil
+	}
+	e.raw = e.raw[:0]
+	if e.done && e.index == 0 {
+		return nil
+	}
+	return fmt.Errorf("array element %d: expected JSON value", e.index)
+}
+
+// isBlank reports whether raw is empty or only JSON whitespace.
+func isBlank(raw []byte) bool {
+	for _, c := range raw {
+		if !isWhitespace(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipWhitespace returns the next byte read from r that isn't JSON whitespace.
+func skipWhitespace(r *bufio.Reader) (byte, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !isWhitespace(c) {
+			return c, nil
+		}
+	}
+}
+
+// isWhitespace reports whether c is JSON whitespace.
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,104 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestElementReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []parser.JSONValue
+		expectError string
+	}{
+		{name: "empty array", input: " [ ] ", expected: nil},
+		{name: "scalars", input: `[1, "two", true, null, 2.5]`, expected: []parser.JSONValue{int64(1), "two", true, nil, 2.5}},
+		{
+			name:     "nested values",
+			input:    "[\n  {\"a\": [1, 2], \"b\": {\"c\": \"]\"}},\n  [[]]\n]\n",
+			expected: []parser.JSONValue{parser.JSONObject{"a": []any{int64(1), int64(2)}, "b": parser.JSONObject{"c": "]"}}, []any{[]any(nil)}},
+		},
+		{name: "escaped quote in string", input: `["a\",b", "c"]`, expected: []parser.JSONValue{`a",b`, "c"}},
+		{name: "not an array", input: `{"a": 1}`, expectError: "expected a JSON array"},
+		{name: "empty input", input: "  ", expectError: "empty input"},
+		{name: "trailing comma", input: `[1,]`, expectError: "array element 1: expected JSON value"},
+		{name: "missing element", input: `[1,,2]`, expectError: "array element 1: expected JSON value"},
+		{name: "invalid element", input: `[1, {"a": }]`, expectError: "array element 1"},
+		{name: "mismatched bracket", input: `[1}`, expectError: "unexpected '}'"},
+		{name: "unterminated", input: `[1, 2`, expectError: "unexpected end of input"},
+		{name: "content after array", input: `[1] [2]`, expectError: "after the array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewElementReader(strings.NewReader(tt.input))
+			var values []parser.JSONValue
+			var err error
+			for {
+				var value parser.JSONValue
+				value, err = r.Next()
+				if err != nil {
+					break
+				}
+				values = append(values, value)
+			}
+
+			if tt.expectError != "" {
+				if err == io.EOF || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, values)
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("expected io.EOF after the last element, got %v", err)
+			}
+		})
+	}
+}
+
+func TestElementReader_Elements(t *testing.T) {
+	var values []parser.JSONValue
+	for value, err := range NewElementReader(strings.NewReader(`[1, "two", [3]]`)).Elements() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		values = append(values, value)
+	}
+	expected := []parser.JSONValue{int64(1), "two", []any{int64(3)}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %#v, got %#v", expected, values)
+	}
+
+	var errs []error
+	for value, err := range NewElementReader(strings.NewReader(`[1, }`)).Elements() {
+		if err != nil {
+			errs = append(errs, err)
+			if value != nil {
+				t.Errorf("expected a nil value with the error, got %v", value)
+			}
+		}
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected a single error, got %v", errs)
+	}
+
+	r := NewElementReader(strings.NewReader(`[1, 2, 3]`))
+	for range r.Elements() {
+		break
+	}
+	if value, err := r.Next(); err != nil || value != int64(2) {
+		t.Errorf("expected to resume at 2 after a break, got %v, %v", value, err)
+	}
+}
@@ -0,0 +1,153 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// tokenizer splits a JSON document read from a stream into tokens, tracking
+// the offset of each for error messages and byte ranges.
+type tokenizer struct {
+	r      *bufio.Reader
+	offset int              // bytes read so far
+	raw    []byte           // text of the last string or scalar token
+	value  parser.JSONValue // value of the last string or scalar token
+}
+
+// first reads the first token of the document.
+func (t *tokenizer) first() (byte, error) {
+	tok, err := t.next()
+	if errors.Is(err, io.EOF) {
+		return 0, errors.New("expected a JSON value, got empty input")
+	}
+	return tok, err
+}
+
+// last checks that nothing but whitespace follows the document.
+func (t *tokenizer) last() error {
+	_, err := t.next()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("offset %d: unexpected content after the document", t.offset-1)
+	}
+	return err
+}
+
+// start returns the offset of the first byte of the token tok just read.
+func (t *tokenizer) start(tok byte) int {
+	if tok == '"' || tok == 'v' {
+		return t.offset - len(t.raw)
+	}
+	return t.offset - 1
+}
+
+// next reads the next token and returns its first byte: a structural
+// character, '"' for strings or 'v' for numbers, booleans and null. The text
+// of strings and scalars is left in t.raw, and validated.
+func (t *tokenizer) next() (byte, error) {
+	c, err := t.readByte()
+	for err == nil && isWhitespace(c) {
+		c, err = t.readByte()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch c {
+	case '{', '}', '[', ']', ':', ',':
+		return c, nil
+	case '"':
+		if err := t.readString(); err != nil {
+			return 0, err
+		}
+		return '"', nil
+	default:
+		if err := t.readScalar(c); err != nil {
+			return 0, err
+		}
+		return 'v', nil
+	}
+}
+
+// readString reads the string whose opening quote was just read into t.raw.
+func (t *tokenizer) readString() error {
+	t.raw = append(t.raw[:0], '"')
+	escaped := false
+	for {
+		c, err := t.readByte()
+		if err != nil {
+			return t.endOfInput(err)
+		}
+		t.raw = append(t.raw, c)
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return t.validate()
+		}
+	}
+}
+
+// readScalar reads the number or literal starting with c into t.raw.
+func (t *tokenizer) readScalar(c byte) error {
+	t.raw = append(t.raw[:0], c)
+	for {
+		c, err := t.readByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if isWhitespace(c) || c == ',' || c == ':' || c == ']' || c == '}' || c == '[' || c == '{' || c == '"' {
+			t.r.UnreadByte()
+			t.offset--
+			break
+		}
+		t.raw = append(t.raw, c)
+	}
+	return t.validate()
+}
+
+// validate parses t.raw, a string or scalar, into t.value.
+func (t *tokenizer) validate() error {
+	var err error
+	if t.value, err = parser.New(lexer.New(string(t.raw))).Parse(); err != nil {
+		return fmt.Errorf("offset %d: invalid value %s: %w", t.offset-len(t.raw), t.raw, err)
+	}
+	return nil
+}
+
+// readByte reads the next byte of input, counting the offset.
+func (t *tokenizer) readByte() (byte, error) {
+	c, err := t.r.ReadByte()
+	if err == nil {
+		t.offset++
+	}
+	return c, err
+}
+
+// unexpected reports that tok was read where expected was.
+func (t *tokenizer) unexpected(tok byte, expected string) error {
+	if tok == '"' || tok == 'v' {
+		return fmt.Errorf("offset %d: expected %s, got %s", t.start(tok), expected, t.raw)
+	}
+	return fmt.Errorf("offset %d: expected %s, got %q", t.start(tok), expected, tok)
+}
+
+// endOfInput turns io.EOF in the middle of the document into an error.
+func (t *tokenizer) endOfInput(err error) error {
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("offset %d: unexpected end of input", t.offset)
+	}
+	return err
+}
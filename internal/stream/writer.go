@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// ArrayWriter writes a JSON array one element at a time, so arrays can be
+// produced without holding all their elements in memory.
+type ArrayWriter interface {
+	// Write appends an element to the array.
+	Write(value parser.JSONValue) error
+	// Close terminates the array and flushes it to the underlying writer.
+	// It does not close the underlying writer.
+	Close() error
+}
+
+// arrayWriter is the concrete implementation of ArrayWriter. It writes each
+// element compactly on its own line.
+type arrayWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+// NewArrayWriter returns an ArrayWriter that writes to w.
+func NewArrayWriter(w io.Writer) ArrayWriter {
+	return &arrayWriter{w: bufio.NewWriter(w)}
+}
+
+// Write implements ArrayWriter.
+func (a *arrayWriter) Write(value parser.JSONValue) error {
+	data, err := encoder.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if a.count == 0 {
+		a.w.WriteString("[\n  ")
+	} else {
+		a.w.WriteString(",\n  ")
+	}
+	a.count++
+	_, err = a.w.Write(data)
+	return err
+}
+
+// Close implements ArrayWriter.
+func (a *arrayWriter) Close() error {
+	if a.count == 0 {
+		a.w.WriteString("[]\n")
+	} else {
+		a.w.WriteString("\n]\n")
+	}
+	return a.w.Flush()
+}
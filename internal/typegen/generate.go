@@ -0,0 +1,224 @@
+package typegen
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words Go names write in upper case, as golint does.
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "TCP": true,
+	"TLS": true, "UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// config holds the settings applied by Options.
+type config struct {
+	packageName string
+}
+
+// Option configures Generate.
+type Option func(*config)
+
+// WithPackage sets the package clause of the generated file (default main).
+func WithPackage(name string) Option {
+	return func(c *config) {
+		c.packageName = name
+	}
+}
+
+// generator collects the type declarations of a generated file.
+type generator struct {
+	decls []string        // type declarations, in the order they were named
+	names map[string]bool // type names taken
+}
+
+// Generate returns gofmt-formatted Go source declaring the type name for
+// values of shape s and a struct type for every object shape within it.
+// Nested struct types are named after their member (singular for array
+// elements, e.g. Users []User). Numbers that were all integers become int64
+// and other numbers float64; members that were null or missing in some
+// examples become pointers, and missing ones are tagged omitempty. Values of
+// mixed kinds become any.
+func Generate(s *Shape, name string, opts ...Option) ([]byte, error) {
+	cfg := &config{packageName: "main"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g := &generator{names: map[string]bool{}}
+	if s.Kinds&^Null == Object {
+		g.structType(s, name)
+	} else {
+		i := g.reserve(name)
+		g.decls[i] = fmt.Sprintf("type %s %s\n", name, g.goType(s, name))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage %s\n", cfg.packageName)
+	for _, decl := range g.decls {
+		b.WriteString("\n")
+		b.WriteString(decl)
+	}
+	return format.Source([]byte(b.String()))
+}
+
+// reserve takes a unique type name based on name and returns the index of
+// its declaration.
+func (g *generator) reserve(name string) int {
+	unique := name
+	for n := 2; g.names[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+	g.names[unique] = true
+	g.decls = append(g.decls, unique)
+	return len(g.decls) - 1
+}
+
+// structType declares a struct type for the object shape s, named after
+// name, and returns the name used.
+func (g *generator) structType(s *Shape, name string) string {
+	i := g.reserve(name)
+	name = g.decls[i]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, f := range s.Fields {
+		if !validTagName(f.Name) {
+			fmt.Fprintf(&b, "\t// member %q can't be named in a json tag\n", f.Name)
+			continue
+		}
+		fieldName := goName(f.Name)
+		for n := 2; fieldNames[fieldName]; n++ {
+			fieldName = goName(f.Name) + strconv.Itoa(n)
+		}
+		fieldNames[fieldName] = true
+
+		typ := g.goType(f.Shape, goName(f.Name))
+		optional := f.Optional(s)
+		if (optional || f.Shape.Kinds&Null != 0) && typ != "any" && !strings.HasPrefix(typ, "[]") {
+			typ = "*" + typ
+		}
+		tag := f.Name
+		if optional {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s\n", fieldName, typ, structTag(tag))
+	}
+	b.WriteString("}\n")
+
+	g.decls[i] = b.String()
+	return name
+}
+
+// goType returns the Go type for values of shape s, declaring struct types
+// named after name as needed.
+func (g *generator) goType(s *Shape, name string) string {
+	switch s.Kinds &^ Null {
+	case Bool:
+		return "bool"
+	case Int:
+		return "int64"
+	case Float, Int | Float:
+		return "float64"
+	case String:
+		return "string"
+	case Object:
+		return g.structType(s, name)
+	case Array:
+		if s.Elem == nil {
+			return "[]any"
+		}
+		return "[]" + g.goType(s.Elem, singular(name))
+	default:
+		return "any"
+	}
+}
+
+// structTag returns the struct tag with the given json tag value.
+func structTag(value string) string {
+	return "`json:" + strconv.Quote(value) + "`"
+}
+
+// validTagName reports whether encoding/json accepts name as the name in a
+// json tag: it must be non-empty, and the only punctuation allowed is
+// !#$%&()*+-./:;<=>?@[]^_{|}~ and space.
+func validTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r) && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// goName returns an exported Go identifier for the member name, e.g. UserID
+// for "user_id" or "userId".
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	ident := b.String()
+	switch {
+	case ident == "":
+		return "Field"
+	case !unicode.IsUpper([]rune(ident)[0]):
+		return "X" + ident // starts with a digit or a caseless letter
+	default:
+		return ident
+	}
+}
+
+// words splits name into words at characters that can't appear in an
+// identifier and where a lower-case letter is followed by an upper-case one.
+func words(name string) []string {
+	var words []string
+	var current []rune
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) && unicode.IsLower(current[len(current)-1]) {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// singular returns the type name for the elements of an array named name:
+// Users gives User, Categories gives Category, and names not ending in s get
+// an Item suffix.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name + "Item"
+	}
+}
@@ -0,0 +1,106 @@
+// Package typegen infers the structure shared by example JSON documents and
+// generates Go type definitions, with json tags, that can decode them.
+package typegen
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// Kind is a set of JSON value kinds.
+type Kind uint8
+
+const (
+	Null Kind = 1 << iota
+	Bool
+	Int // a number without a fraction or exponent that fits in an int64
+	Float
+	String
+	Array
+	Object
+)
+
+// Shape is the inferred structure of the values seen at one place in the
+// example documents.
+type Shape struct {
+	Kinds   Kind     // kinds of the values seen
+	Fields  []*Field // members of the objects seen, sorted by name
+	Elem    *Shape   // elements of the arrays seen, nil if all were empty
+	Objects int      // number of objects seen
+}
+
+// Field is an object member seen in the examples.
+type Field struct {
+	Name  string // JSON member name
+	Shape *Shape
+	Count int // number of objects the member was seen in
+}
+
+// Optional reports whether the member was missing from some of the objects
+// of shape s.
+func (f *Field) Optional(s *Shape) bool {
+	return f.Count < s.Objects
+}
+
+// Infer returns the shape of the given example values.
+func Infer(values ...parser.JSONValue) *Shape {
+	s := &Shape{}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add merges v into the shape.
+func (s *Shape) Add(v parser.JSONValue) {
+	if obj, ok := parser.AsObject(v); ok {
+		s.Kinds |= Object
+		s.Objects++
+		for name, member := range obj {
+			s.field(name).Shape.Add(member)
+		}
+		return
+	}
+
+	switch v := v.(type) {
+	case nil:
+		s.Kinds |= Null
+	case bool:
+		s.Kinds |= Bool
+	case int64:
+		s.Kinds |= Int
+	case float64:
+		s.Kinds |= Float
+	case parser.Number:
+		if _, err := v.Int64(); err == nil {
+			s.Kinds |= Int
+		} else {
+			s.Kinds |= Float
+		}
+	case string:
+		s.Kinds |= String
+	case []any:
+		s.Kinds |= Array
+		for _, element := range v {
+			if s.Elem == nil {
+				s.Elem = &Shape{}
+			}
+			s.Elem.Add(element)
+		}
+	}
+}
+
+// field returns the field with the given name, adding it if it's new, and
+// counts one more object holding it.
+func (s *Shape) field(name string) *Field {
+	i, found := slices.BinarySearchFunc(s.Fields, name, func(f *Field, name string) int {
+		return strings.Compare(f.Name, name)
+	})
+	if !found {
+		s.Fields = slices.Insert(s.Fields, i, &Field{Name: name, Shape: &Shape{}})
+	}
+	s.Fields[i].Count++
+	return s.Fields[i]
+}
@@ -0,0 +1,121 @@
+package typegen
+
+import (
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.JSONValue {
+	t.Helper()
+	value, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", input, err)
+	}
+	return value
+}
+
+func TestInfer(t *testing.T) {
+	s := Infer(
+		mustParse(t, `{"id": 1, "tags": ["a"], "score": 1}`),
+		mustParse(t, `{"id": 2, "tags": [], "score": 2.5, "note": null}`),
+	)
+
+	if s.Kinds != Object || s.Objects != 2 {
+		t.Fatalf("expected 2 objects, got kinds %b and %d objects", s.Kinds, s.Objects)
+	}
+	expected := map[string]struct {
+		kinds Kind
+		count int
+	}{
+		"id":    {Int, 2},
+		"note":  {Null, 1},
+		"score": {Int | Float, 2},
+		"tags":  {Array, 2},
+	}
+	if len(s.Fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %d", len(expected), len(s.Fields))
+	}
+	for i, f := range s.Fields {
+		if i > 0 && s.Fields[i-1].Name >= f.Name {
+			t.Errorf("fields not sorted: %q before %q", s.Fields[i-1].Name, f.Name)
+		}
+		if e := expected[f.Name]; f.Shape.Kinds != e.kinds || f.Count != e.count {
+			t.Errorf("%s: expected kinds %b in %d objects, got %b in %d", f.Name, e.kinds, e.count, f.Shape.Kinds, f.Count)
+		}
+	}
+	if tags := s.Fields[3].Shape; tags.Elem == nil || tags.Elem.Kinds != String {
+		t.Errorf("expected string elements for tags, got %+v", tags.Elem)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples []string
+		opts     []Option
+		expected string
+	}{
+		{
+			name: "object",
+			examples: []string{
+				`{"user_id": 1, "name": "a", "homeURL": "x", "address": {"city": "b"}, "users": [{"id": 1}], "categories": [[1.5]]}`,
+				`{"user_id": 2, "name": null, "homeURL": "y", "users": [{"id": 2, "admin": true}], "categories": []}`,
+			},
+			expected: "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage main\n\n" +
+				"type Root struct {\n" +
+				"\tAddress    *Address    `json:\"address,omitempty\"`\n" +
+				"\tCategories [][]float64 `json:\"categories\"`\n" +
+				"\tHomeURL    string      `json:\"homeURL\"`\n" +
+				"\tName       *string     `json:\"name\"`\n" +
+				"\tUserID     int64       `json:\"user_id\"`\n" +
+				"\tUsers      []User      `json:\"users\"`\n" +
+				"}\n\n" +
+				"type Address struct {\n\tCity string `json:\"city\"`\n}\n\n" +
+				"type User struct {\n" +
+				"\tAdmin *bool `json:\"admin,omitempty\"`\n" +
+				"\tID    int64 `json:\"id\"`\n" +
+				"}\n",
+		},
+		{
+			name:     "array of objects",
+			examples: []string{`[{"a": 1}, {"a": "x"}]`},
+			opts:     []Option{WithPackage("types")},
+			expected: "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage types\n\n" +
+				"type Root []RootItem\n\n" +
+				"type RootItem struct {\n\tA any `json:\"a\"`\n}\n",
+		},
+		{
+			name:     "unusual names",
+			examples: []string{`{"": 1, "2fa": true, "a-b": 1, "aB": 2, "q\"t": 3, "@type": {"Root": []}}`},
+			expected: "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage main\n\n" +
+				"type Root struct {\n" +
+				"\t// member \"\" can't be named in a json tag\n" +
+				"\tX2fa bool  `json:\"2fa\"`\n" +
+				"\tType Type  `json:\"@type\"`\n" +
+				"\tAB   int64 `json:\"a-b\"`\n" +
+				"\tAB2  int64 `json:\"aB\"`\n" +
+				"\t// member \"q\\\"t\" can't be named in a json tag\n" +
+				"}\n\n" +
+				"type Type struct {\n\tRoot []any `json:\"Root\"`\n}\n",
+		},
+		{name: "scalar", examples: []string{`1`, `2`}, expected: "// Code generated by json-parser gen-types; DO NOT EDIT.\n\npackage main\n\ntype Root int64\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values []parser.JSONValue
+			for _, example := range tt.examples {
+				values = append(values, mustParse(t, example))
+			}
+			source, err := Generate(Infer(values...), "Root", tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(source) != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, source)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/schema"
+)
+
+// FileResult is the outcome of validating one file with ValidateAll.
+type FileResult struct {
+	Path        string
+	Err         error              // the file couldn't be read or parsed, or its schema loaded
+	Violations  []schema.Violation // schema errors; the file is valid if empty and Err is nil
+	Annotations []schema.Violation // informational schema findings
+	Size        int64
+	Duration    time.Duration
+}
+
+// Valid reports whether the file is valid JSON conforming to its schema, if any.
+func (r FileResult) Valid() bool {
+	return r.Err == nil && len(r.Violations) == 0
+}
+
+// batchConfig holds the settings applied by BatchOptions.
+type batchConfig struct {
+	workers       int
+	schema        *schema.Schema
+	resolver      schema.Resolver
+	schemaOptions []schema.Option
+	parserOptions []parser.Option
+}
+
+// BatchOption configures ValidateAll.
+type BatchOption func(*batchConfig)
+
+// WithWorkers validates at most n files at a time instead of GOMAXPROCS.
+func WithWorkers(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.workers = n
+	}
+}
+
+// WithSchema validates every file against s.
+func WithSchema(s *schema.Schema) BatchOption {
+	return func(c *batchConfig) {
+		c.schema = s
+	}
+}
+
+// WithSchemaDiscovery validates files that name their schema in a top-level
+// "$schema" member against it, using r to load it and opts to compile it.
+// WithSchema takes precedence.
+func WithSchemaDiscovery(r schema.Resolver, opts ...schema.Option) BatchOption {
+	return func(c *batchConfig) {
+		c.resolver, c.schemaOptions = r, opts
+	}
+}
+
+// WithParserOptions parses the files with opts, e.g. to reject duplicate keys.
+func WithParserOptions(opts ...parser.Option) BatchOption {
+	return func(c *batchConfig) {
+		c.parserOptions = opts
+	}
+}
+
+// ValidateAll validates the files at paths concurrently with a bounded pool
+// of workers and returns their results in the order of paths, so build tools
+// can validate a whole repository without running the CLI. If ctx is done
+// before every file was validated, the remaining results carry ctx.Err() and
+// it is returned as well.
+func ValidateAll(ctx context.Context, paths []string, opts ...BatchOption) ([]FileResult, error) {
+	cfg := batchConfig{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	workers := max(1, min(cfg.workers, len(paths)))
+
+	results := make([]FileResult, len(paths))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = cfg.validateFile(paths[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(paths) && ctx.Err() == nil; next++ {
+		// select picks randomly when ctx is done and a worker is ready, so
+		// ctx is also checked above.
+		select {
+		case indices <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	for i := next; i < len(paths); i++ {
+		results[i] = FileResult{Path: paths[i], Err: ctx.Err()}
+	}
+	if next < len(paths) {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// validateFile reads, parses and checks the file at path.
+func (c *batchConfig) validateFile(path string) (result FileResult) {
+	start := time.Now()
+	result.Path = path
+	defer func() { result.Duration = time.Since(start) }()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Size = int64(len(content))
+
+	input := string(content)
+	value, err := parser.NewWithInput(lexer.New(input), input, c.parserOptions...).Parse()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	s := c.schema
+	if s == nil && c.resolver != nil {
+		if s, err = c.discoverSchema(value, path); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	if s != nil {
+		outcome := s.Validate(value)
+		result.Violations, result.Annotations = outcome.Errors, outcome.Annotations
+	}
+	return result
+}
+
+// discoverSchema returns the compiled schema named by the "$schema" member of
+// value, read from path, or nil if it doesn't name one.
+func (c *batchConfig) discoverSchema(value parser.JSONValue, path string) (*schema.Schema, error) {
+	uri, ok := schema.DeclaredURI(value)
+	if !ok {
+		return nil, nil
+	}
+	doc, err := c.resolver.Resolve(uri, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load schema %q: %w", uri, err)
+	}
+	s, err := schema.Compile(doc, c.schemaOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load schema %q: %w", uri, err)
+	}
+	return s, nil
+}
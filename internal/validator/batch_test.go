@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/schema"
+)
+
+func TestValidateAll(t *testing.T) {
+	tempDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		return path
+	}
+
+	writeFile("schema.json", `{"required": ["id"]}`)
+	valid := writeFile("valid.json", `{"id": 1}`)
+	invalid := writeFile("invalid.json", `{"id": }`)
+	declared := writeFile("declared.json", `{"$schema": "schema.json", "name": "x"}`)
+	duplicate := writeFile("duplicate.json", `{"id": 1, "id": 2}`)
+	missing := filepath.Join(tempDir, "missing.json")
+
+	doc, err := parser.New(lexer.New(`{"properties": {"id": {"type": "string"}}}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	stringIDs, err := schema.Compile(doc)
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		paths      []string
+		opts       []BatchOption
+		valid      []bool
+		violations []int
+	}{
+		{
+			name:       "syntax only",
+			paths:      []string{valid, invalid, missing, declared},
+			valid:      []bool{true, false, false, true},
+			violations: []int{0, 0, 0, 0},
+		},
+		{
+			name:       "schema",
+			paths:      []string{valid, declared},
+			opts:       []BatchOption{WithSchema(stringIDs), WithWorkers(1)},
+			valid:      []bool{false, true},
+			violations: []int{1, 0},
+		},
+		{
+			name:       "schema discovery",
+			paths:      []string{valid, declared},
+			opts:       []BatchOption{WithSchemaDiscovery(schema.NewResolver())},
+			valid:      []bool{true, false},
+			violations: []int{0, 1},
+		},
+		{
+			name:       "parser options",
+			paths:      []string{duplicate},
+			opts:       []BatchOption{WithParserOptions(parser.WithDuplicateKeys(parser.DuplicateKeysError, 0))},
+			valid:      []bool{false},
+			violations: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ValidateAll(context.Background(), tt.paths, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.paths) {
+				t.Fatalf("expected %d results, got %d", len(tt.paths), len(results))
+			}
+			for i, r := range results {
+				if r.Path != tt.paths[i] {
+					t.Errorf("result %d: expected path %s, got %s", i, tt.paths[i], r.Path)
+				}
+				if r.Valid() != tt.valid[i] {
+					t.Errorf("%s: expected valid %v, got %v (err: %v, violations: %v)", r.Path, tt.valid[i], r.Valid(), r.Err, r.Violations)
+				}
+				if len(r.Violations) != tt.violations[i] {
+					t.Errorf("%s: expected %d violations, got %v", r.Path, tt.violations[i], r.Violations)
+				}
+			}
+		})
+	}
+
+	results, _ := ValidateAll(context.Background(), []string{valid, missing})
+	if !errors.Is(results[1].Err, fs.ErrNotExist) {
+		t.Errorf("expected a not-exist error for the missing file, got %v", results[1].Err)
+	}
+	if results[0].Size != int64(len(`{"id": 1}`)) || results[0].Duration <= 0 {
+		t.Errorf("expected size and duration to be recorded, got %d and %v", results[0].Size, results[0].Duration)
+	}
+}
+
+func TestValidateAll_Concurrency(t *testing.T) {
+	tempDir := t.TempDir()
+	var paths []string
+	for i := range 50 {
+		path := filepath.Join(tempDir, fmt.Sprintf("%d.json", i))
+		content := fmt.Sprintf(`{"n": %d}`, i)
+		if i%7 == 0 {
+			content = `{"n": ]`
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	results, err := ValidateAll(context.Background(), paths, WithWorkers(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, r := range results {
+		if r.Path != paths[i] || r.Valid() != (i%7 != 0) {
+			t.Errorf("result %d: unexpected %s valid=%v", i, r.Path, r.Valid())
+		}
+	}
+}
+
+func TestValidateAll_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	paths := []string{"a.json", "b.json"}
+	results, err := ValidateAll(ctx, paths)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("expected %d results, got %d", len(paths), len(results))
+	}
+	for _, r := range results {
+		if r.Path == "" || r.Valid() {
+			t.Errorf("expected an invalid result with a path, got %+v", r)
+		}
+	}
+}
+
+func TestValidateAll_Empty(t *testing.T) {
+	results, err := ValidateAll(context.Background(), nil)
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no results, got %v, %v", results, err)
+	}
+}
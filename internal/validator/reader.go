@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"errors"
+	"io"
+)
+
+// ValidatingReader wraps an io.Reader and validates the JSON flowing through
+// it without altering or buffering the bytes. This lets proxies verify a
+// payload while forwarding it: a syntax error is returned from Read as soon as
+// it is detected, and a truncated document is reported in place of io.EOF.
+type ValidatingReader struct {
+	r         io.Reader
+	validator *Validator
+}
+
+// NewValidatingReader returns a ValidatingReader reading from r.
+func NewValidatingReader(r io.Reader) *ValidatingReader {
+	return &ValidatingReader{
+		r:         r,
+		validator: New(),
+	}
+}
+
+// Read reads from the underlying reader and validates the bytes read. The
+// bytes are always returned, even when they contain the error, so callers can
+// decide whether to forward a partial payload.
+func (vr *ValidatingReader) Read(p []byte) (int, error) {
+	n, err := vr.r.Read(p)
+	if n > 0 {
+		if _, validationErr := vr.validator.Write(p[:n]); validationErr != nil {
+			return n, validationErr
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		if validationErr := vr.validator.Close(); validationErr != nil {
+			return n, validationErr
+		}
+	}
+	return n, err
+}
+
+// Err returns the validation error found so far, if any. After Read has
+// returned io.EOF a nil result means the stream was a single valid JSON value.
+func (vr *ValidatingReader) Err() error {
+	return vr.validator.Err()
+}
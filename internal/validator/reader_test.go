@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestValidatingReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{name: "valid document", input: `{"items": [1, 2, 3], "name": "test"}`},
+		{name: "syntax error mid-stream", input: `{"items": [1, 2 3], "name": "test"}`, expectError: true},
+		{name: "truncated document", input: `{"items": [1, 2`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// OneByteReader forces validation to span many Read calls.
+			vr := NewValidatingReader(iotest.OneByteReader(strings.NewReader(tt.input)))
+
+			var out bytes.Buffer
+			_, err := io.Copy(&out, vr)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				if vr.Err() != err {
+					t.Errorf("expected Err() to return %v, got %v", err, vr.Err())
+				}
+				if !strings.HasPrefix(tt.input, out.String()) {
+					t.Errorf("forwarded bytes %q are not a prefix of the input", out.String())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if out.String() != tt.input {
+				t.Errorf("bytes were altered: expected %q, got %q", tt.input, out.String())
+			}
+		})
+	}
+}
+
+func TestValidatingReader_PropagatesReadErrors(t *testing.T) {
+	readErr := io.ErrUnexpectedEOF
+	vr := NewValidatingReader(iotest.ErrReader(readErr))
+
+	if _, err := vr.Read(make([]byte, 8)); err != readErr {
+		t.Errorf("expected %v, got %v", readErr, err)
+	}
+}
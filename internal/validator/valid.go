@@ -0,0 +1,232 @@
+package validator
+
+import (
+	"bytes"
+	"io"
+)
+
+// Valid reports whether input is a single JSON value, surrounded by optional
+// whitespace. It accepts exactly what a Validator accepts, but is faster for
+// callers that only need a yes or no: it tracks no positions, produces no
+// errors and skips whitespace and the contents of strings in bulk.
+func Valid(input []byte) bool {
+	s := scanner{input: input}
+	return s.scan()
+}
+
+// ValidReader is Valid for a document read from r, which is validated as it
+// is read instead of being buffered whole; reading stops at the first syntax
+// error. The error is that of a failed read, if any.
+func ValidReader(r io.Reader) (bool, error) {
+	v := New()
+	if _, err := io.Copy(v, r); err != nil && v.Err() == nil {
+		return false, err
+	}
+	return v.Close() == nil, nil
+}
+
+// scanner checks the syntax of a whole document for Valid.
+type scanner struct {
+	input []byte
+	pos   int
+}
+
+// scan reports whether the input is a single JSON value. Nesting is tracked
+// on a stack rather than by recursion, so deep documents can't exhaust the
+// goroutine's stack.
+func (s *scanner) scan() bool {
+	var buf [64]byte
+	stack := buf[:0] // open containers, '{' or '['
+	for {
+		// A value starts at pos, after any whitespace.
+		s.skipSpace()
+		if s.pos == len(s.input) {
+			return false
+		}
+		switch c := s.input[s.pos]; c {
+		case '{', '[':
+			s.pos++
+			s.skipSpace()
+			if s.pos < len(s.input) && s.input[s.pos] == c+2 { // '}' or ']'
+				s.pos++
+				break
+			}
+			stack = append(stack, c)
+			if c == '{' && !s.key() {
+				return false
+			}
+			continue
+		case '"':
+			if !s.string() {
+				return false
+			}
+		case 't':
+			if !s.literal("true") {
+				return false
+			}
+		case 'f':
+			if !s.literal("false") {
+				return false
+			}
+		case 'n':
+			if !s.literal("null") {
+				return false
+			}
+		default:
+			if !s.number() {
+				return false
+			}
+		}
+
+		// The value ended; close the containers it ends.
+		for {
+			s.skipSpace()
+			if len(stack) == 0 {
+				return s.pos == len(s.input)
+			}
+			if s.pos == len(s.input) {
+				return false
+			}
+			top := stack[len(stack)-1]
+			c := s.input[s.pos]
+			if c == top+2 {
+				stack = stack[:len(stack)-1]
+				s.pos++
+				continue
+			}
+			if c != ',' {
+				return false
+			}
+			s.pos++
+			if top == '{' && !s.key() {
+				return false
+			}
+			break
+		}
+	}
+}
+
+// key scans an object key and the colon following it, after any whitespace.
+func (s *scanner) key() bool {
+	s.skipSpace()
+	if s.pos == len(s.input) || s.input[s.pos] != '"' || !s.string() {
+		return false
+	}
+	s.skipSpace()
+	if s.pos == len(s.input) || s.input[s.pos] != ':' {
+		return false
+	}
+	s.pos++
+	return true
+}
+
+// plainString marks the bytes that may appear in a string without ending it,
+// starting an escape or being a control character.
+var plainString = func() (plain [256]bool) {
+	for c := 0x20; c < 256; c++ {
+		plain[c] = c != '"' && c != '\\'
+	}
+	return plain
+}()
+
+// string scans the string starting at pos.
+func (s *scanner) string() bool {
+	for i := s.pos + 1; i < len(s.input); {
+		c := s.input[i]
+		switch {
+		case plainString[c]:
+			i++
+		case c == '"':
+			s.pos = i + 1
+			return true
+		case c == '\\':
+			if i+1 == len(s.input) {
+				return false
+			}
+			switch s.input[i+1] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				i += 2
+			case 'u':
+				if i+6 > len(s.input) {
+					return false
+				}
+				for _, h := range s.input[i+2 : i+6] {
+					if !isHexDigit(h) {
+						return false
+					}
+				}
+				i += 6
+			default:
+				return false
+			}
+		default:
+			return false // a control character
+		}
+	}
+	return false
+}
+
+// literal scans the keyword starting at pos.
+func (s *scanner) literal(keyword string) bool {
+	if !bytes.HasPrefix(s.input[s.pos:], []byte(keyword)) {
+		return false
+	}
+	s.pos += len(keyword)
+	return true
+}
+
+// number scans the number starting at pos:
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func (s *scanner) number() bool {
+	if s.peek() == '-' {
+		s.pos++
+	}
+	switch c := s.peek(); {
+	case c == '0':
+		s.pos++
+	case c >= '1' && c <= '9':
+		s.digits()
+	default:
+		return false
+	}
+	if s.peek() == '.' {
+		s.pos++
+		if !s.digits() {
+			return false
+		}
+	}
+	if c := s.peek(); c == 'e' || c == 'E' {
+		s.pos++
+		if c := s.peek(); c == '+' || c == '-' {
+			s.pos++
+		}
+		if !s.digits() {
+			return false
+		}
+	}
+	return true
+}
+
+// digits scans a run of digits and reports whether there was any.
+func (s *scanner) digits() bool {
+	start := s.pos
+	for s.pos < len(s.input) && isDigit(s.input[s.pos]) {
+		s.pos++
+	}
+	return s.pos > start
+}
+
+// peek returns the byte at pos, or 0 at the end of the input.
+func (s *scanner) peek() byte {
+	if s.pos == len(s.input) {
+		return 0
+	}
+	return s.input[s.pos]
+}
+
+// skipSpace moves pos past whitespace.
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.input) && isSpace(s.input[s.pos]) {
+		s.pos++
+	}
+}
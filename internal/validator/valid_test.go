@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// validatorAccepts reports whether a Validator accepts input.
+func validatorAccepts(input []byte) bool {
+	v := New()
+	v.Write(input)
+	return v.Close() == nil
+}
+
+func TestValid(t *testing.T) {
+	inputs := []string{
+		"{}", " [ ] \n", "[{}, [], [[]], {\"a\": {}}]",
+		`{"a": [1, {"b": null}], "c": {"d": [true, false]}}`,
+		`"text"`, `-12.5e+3`, `0`, `-0`, `1E-2`, `"\"\\\/\b\f\n\r\té"`, `"é😀"`,
+		"", "  ", `{"a": 1`, `"abc`, `"abc\`, `"\u12"`, `"\u12G4"`, `"\x"`, "\"a\tb\"", "\"a\x00\"",
+		`{"a": 1,}`, `[1,]`, `[,]`, `{,}`, `{"a" 1}`, `{"a":}`, `{1: 2}`, `[1, 2}`, `{"a": 1]`, `{"a": 1 "b": 2}`,
+		`{} {}`, `01`, `[1.]`, `1e`, `[1e+]`, `-`, `[-]`, `+1`, `.5`, `True`, `[nul]`, `truex`, `12true`, `[1true]`,
+		`[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[["deep"]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]`,
+		strings.Repeat("[", 100000) + strings.Repeat("]", 100000),
+		strings.Repeat("[", 100000) + strings.Repeat("]", 99999),
+	}
+
+	for _, input := range inputs {
+		name := input
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		t.Run(name, func(t *testing.T) {
+			expected := validatorAccepts([]byte(input))
+			if got := Valid([]byte(input)); got != expected {
+				t.Errorf("expected Valid to report %v, got %v", expected, got)
+			}
+			got, err := ValidReader(iotest.OneByteReader(strings.NewReader(input)))
+			if err != nil || got != expected {
+				t.Errorf("expected ValidReader to report %v, got %v, %v", expected, got, err)
+			}
+		})
+	}
+}
+
+func TestValid_TestData(t *testing.T) {
+	testDir := filepath.Join("..", "..", "test", "testdata")
+	files, err := os.ReadDir(testDir)
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(file.Name(), func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(testDir, file.Name()))
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", file.Name(), err)
+			}
+			if expected := validatorAccepts(content); Valid(content) != expected {
+				t.Errorf("expected Valid to report %v", expected)
+			}
+		})
+	}
+}
+
+func TestValidReader_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	valid, err := ValidReader(iotest.ErrReader(readErr))
+	if valid || !errors.Is(err, readErr) {
+		t.Errorf("expected false and the read error, got %v, %v", valid, err)
+	}
+
+	// Reading stops at a syntax error, before the read error.
+	valid, err = ValidReader(iotest.TimeoutReader(strings.NewReader("[1 2]")))
+	if valid || err != nil {
+		t.Errorf("expected false without an error, got %v, %v", valid, err)
+	}
+}
+
+func BenchmarkValid(b *testing.B) {
+	input := []byte(`{"users": [` + strings.Repeat(`{"id": 1, "name": "Alice", "tags": ["a", "b"], "active": true, "score": 9.5}, `, 100) + `null]}`)
+
+	b.Run("Validator", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			if !validatorAccepts(input) {
+				b.Fatal("expected the input to be valid")
+			}
+		}
+	})
+
+	b.Run("Valid", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			if !Valid(input) {
+				b.Fatal("expected the input to be valid")
+			}
+		}
+	})
+}
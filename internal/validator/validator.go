@@ -0,0 +1,399 @@
+package validator
+
+import (
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+// scanState is the position of the validator within the JSON grammar.
+type scanState int
+
+const (
+	stateBeginValue      scanState = iota // expecting any value
+	stateBeginValueOrEnd                  // after '[': a value or ']'
+	stateBeginKeyOrEnd                    // after '{': a key or '}'
+	stateBeginKey                         // after ',' in an object: a key
+	stateColon                            // after an object key
+	stateEndValue                         // after a value nested in a container
+	stateEndTop                           // after the top-level value
+	stateInString                         // inside a string
+	stateInStringEscape                   // after a backslash in a string
+	stateInStringUnicode                  // inside a \uXXXX escape
+	stateNeg                              // after a leading '-'
+	stateZero                             // after a leading '0'
+	stateInt                              // inside integer digits
+	stateDot                              // after the decimal point
+	stateFrac                             // inside fraction digits
+	stateExp                              // after 'e' or 'E'
+	stateExpSign                          // after the exponent sign
+	stateExpDigits                        // inside exponent digits
+	stateLiteral                          // inside true, false, or null
+)
+
+// Validator checks JSON syntax incrementally as bytes are written to it, so
+// documents can be validated while they stream past without buffering them.
+// Call Close once all input has been written to detect truncated documents.
+type Validator struct {
+	state    scanState
+	stack    []byte // open containers, '{' or '['
+	inKey    bool   // the current string is an object key
+	literal  string // literal being matched in stateLiteral
+	matched  int    // bytes of literal (or hex digits of \u escape) seen so far
+	position lexer.Position
+	err      error
+}
+
+// New creates a Validator positioned at the start of a document.
+func New() *Validator {
+	return &Validator{
+		position: lexer.Position{Line: 1, Column: 1},
+	}
+}
+
+// Write feeds p to the validator. It returns the first syntax error found, at
+// which point the validator stops accepting input. Write always reports all
+// of p as consumed so the Validator can be used as an io.Writer in a tee.
+func (v *Validator) Write(p []byte) (int, error) {
+	if v.err != nil {
+		return len(p), v.err
+	}
+
+	for _, c := range p {
+		if err := v.step(c); err != nil {
+			v.err = err
+			return len(p), err
+		}
+		v.advance(c)
+	}
+	return len(p), nil
+}
+
+// Close signals the end of input and reports whether the bytes written so far
+// form exactly one complete JSON value.
+func (v *Validator) Close() error {
+	if v.err != nil {
+		return v.err
+	}
+
+	switch v.state {
+	case stateEndTop:
+		return nil
+	case stateZero, stateInt, stateFrac, stateExpDigits:
+		if len(v.stack) == 0 {
+			return nil
+		}
+	case stateInString, stateInStringEscape, stateInStringUnicode:
+		v.err = v.newError(parser.LexicalError, parser.MsgUnterminatedString)
+		return v.err
+	}
+
+	v.err = v.newError(parser.SyntaxError, parser.MsgUnexpectedEOF)
+	return v.err
+}
+
+// Err returns the first error encountered, if any.
+func (v *Validator) Err() error {
+	return v.err
+}
+
+// advance moves the tracked position past c.
+func (v *Validator) advance(c byte) {
+	if c == '\n' {
+		v.position.Line++
+		v.position.Column = 1
+	} else {
+		v.position.Column++
+	}
+	v.position.Offset++
+}
+
+// step processes one byte of input.
+func (v *Validator) step(c byte) error {
+	switch v.state {
+	case stateBeginValue:
+		return v.beginValue(c)
+	case stateBeginValueOrEnd:
+		if c == ']' {
+			v.closeContainer()
+			return nil
+		}
+		return v.beginValue(c)
+	case stateBeginKeyOrEnd:
+		if c == '}' {
+			v.closeContainer()
+			return nil
+		}
+		return v.beginKey(c)
+	case stateBeginKey:
+		if c == '}' {
+			return v.newError(parser.SyntaxError, parser.MsgTrailingComma)
+		}
+		return v.beginKey(c)
+	case stateColon:
+		if isSpace(c) {
+			return nil
+		}
+		if c != ':' {
+			return v.newError(parser.SyntaxError, parser.MsgExpectedColon)
+		}
+		v.state = stateBeginValue
+		return nil
+	case stateEndValue:
+		return v.endValue(c)
+	case stateEndTop:
+		if !isSpace(c) {
+			return v.newError(parser.SyntaxError, parser.MsgExtraContent)
+		}
+		return nil
+	case stateInString:
+		return v.inString(c)
+	case stateInStringEscape:
+		return v.inStringEscape(c)
+	case stateInStringUnicode:
+		if !isHexDigit(c) {
+			return v.newError(parser.LexicalError, parser.MsgInvalidUnicodeEscape)
+		}
+		v.matched++
+		if v.matched == 4 {
+			v.state = stateInString
+		}
+		return nil
+	case stateLiteral:
+		return v.inLiteral(c)
+	default:
+		return v.inNumber(c)
+	}
+}
+
+// beginValue handles the first byte of a value.
+func (v *Validator) beginValue(c byte) error {
+	switch {
+	case isSpace(c):
+		return nil
+	case c == '{':
+		v.stack = append(v.stack, c)
+		v.state = stateBeginKeyOrEnd
+	case c == '[':
+		v.stack = append(v.stack, c)
+		v.state = stateBeginValueOrEnd
+	case c == '"':
+		v.inKey = false
+		v.state = stateInString
+	case c == '-':
+		v.state = stateNeg
+	case c == '0':
+		v.state = stateZero
+	case c >= '1' && c <= '9':
+		v.state = stateInt
+	case c == 't':
+		v.beginLiteral("true")
+	case c == 'f':
+		v.beginLiteral("false")
+	case c == 'n':
+		v.beginLiteral("null")
+	case c == ']' && v.state == stateBeginValue && len(v.stack) > 0 && v.stack[len(v.stack)-1] == '[':
+		return v.newError(parser.SyntaxError, parser.MsgTrailingComma)
+	default:
+		return v.newError(parser.SyntaxError, parser.MsgExpectedValue)
+	}
+	return nil
+}
+
+// beginKey handles the first byte of an object key.
+func (v *Validator) beginKey(c byte) error {
+	if isSpace(c) {
+		return nil
+	}
+	if c != '"' {
+		return v.newError(parser.SyntaxError, parser.MsgExpectedStringKey)
+	}
+	v.inKey = true
+	v.state = stateInString
+	return nil
+}
+
+// beginLiteral starts matching one of the keywords true, false, or null.
+func (v *Validator) beginLiteral(literal string) {
+	v.literal = literal
+	v.matched = 1
+	v.state = stateLiteral
+}
+
+// endValue handles the bytes following a value nested in a container.
+func (v *Validator) endValue(c byte) error {
+	if isSpace(c) {
+		return nil
+	}
+
+	if v.stack[len(v.stack)-1] == '{' {
+		switch c {
+		case ',':
+			v.state = stateBeginKey
+			return nil
+		case '}':
+			v.closeContainer()
+			return nil
+		}
+		return v.newError(parser.SyntaxError, parser.MsgExpectedCommaOrBrace)
+	}
+
+	switch c {
+	case ',':
+		v.state = stateBeginValue
+		return nil
+	case ']':
+		v.closeContainer()
+		return nil
+	}
+	return v.newError(parser.SyntaxError, parser.MsgExpectedCommaOrBracket)
+}
+
+// closeContainer pops the innermost container. Callers only invoke it with
+// the bracket matching the top of the stack.
+func (v *Validator) closeContainer() {
+	v.stack = v.stack[:len(v.stack)-1]
+	v.valueDone()
+}
+
+// valueDone transitions to the state following a complete value.
+func (v *Validator) valueDone() {
+	if len(v.stack) == 0 {
+		v.state = stateEndTop
+	} else {
+		v.state = stateEndValue
+	}
+}
+
+// inString handles a byte inside a string.
+func (v *Validator) inString(c byte) error {
+	switch {
+	case c == '"':
+		if v.inKey {
+			v.state = stateColon
+		} else {
+			v.valueDone()
+		}
+	case c == '\\':
+		v.state = stateInStringEscape
+	case c < 0x20:
+		return v.newError(parser.LexicalError, parser.MsgControlCharacter)
+	}
+	return nil
+}
+
+// inStringEscape handles the byte after a backslash.
+func (v *Validator) inStringEscape(c byte) error {
+	switch c {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		v.state = stateInString
+	case 'u':
+		v.matched = 0
+		v.state = stateInStringUnicode
+	default:
+		return v.newError(parser.LexicalError, parser.MsgInvalidEscape, c)
+	}
+	return nil
+}
+
+// inLiteral matches the remaining bytes of a keyword.
+func (v *Validator) inLiteral(c byte) error {
+	if c != v.literal[v.matched] {
+		return v.newError(parser.LexicalError, parser.MsgInvalidKeyword)
+	}
+	v.matched++
+	if v.matched == len(v.literal) {
+		v.valueDone()
+	}
+	return nil
+}
+
+// inNumber handles a byte while scanning a number. A byte that can't extend
+// the number ends it, and is then processed as whatever follows the value.
+func (v *Validator) inNumber(c byte) error {
+	switch v.state {
+	case stateNeg:
+		switch {
+		case c == '0':
+			v.state = stateZero
+		case isDigit(c):
+			v.state = stateInt
+		default:
+			return v.newError(parser.LexicalError, parser.MsgInvalidNumber)
+		}
+		return nil
+	case stateZero, stateInt:
+		switch {
+		case isDigit(c) && v.state == stateZero:
+			return v.newError(parser.LexicalError, parser.MsgLeadingZeros)
+		case isDigit(c):
+			return nil
+		case c == '.':
+			v.state = stateDot
+			return nil
+		case c == 'e' || c == 'E':
+			v.state = stateExp
+			return nil
+		}
+	case stateDot:
+		if !isDigit(c) {
+			return v.newError(parser.LexicalError, parser.MsgMissingFractionDigits)
+		}
+		v.state = stateFrac
+		return nil
+	case stateFrac:
+		switch {
+		case isDigit(c):
+			return nil
+		case c == 'e' || c == 'E':
+			v.state = stateExp
+			return nil
+		}
+	case stateExp:
+		if c == '+' || c == '-' {
+			v.state = stateExpSign
+			return nil
+		}
+		fallthrough
+	case stateExpSign:
+		if !isDigit(c) {
+			return v.newError(parser.LexicalError, parser.MsgMissingExponentDigits)
+		}
+		v.state = stateExpDigits
+		return nil
+	case stateExpDigits:
+		if isDigit(c) {
+			return nil
+		}
+	}
+
+	v.valueDone()
+	return v.step(c)
+}
+
+// newError creates a ParseError at the current position with the message
+// for key.
+func (v *Validator) newError(errorType parser.ErrorType, key parser.MessageKey, args ...any) *parser.ParseError {
+	message, _ := parser.English.Format(key, args...)
+	return &parser.ParseError{
+		Type:     errorType,
+		Message:  message,
+		Position: v.position,
+		Key:      key,
+		Args:     args,
+	}
+}
+
+// isSpace reports whether c is JSON whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isHexDigit reports whether c is a hexadecimal digit.
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
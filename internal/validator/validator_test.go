@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VuNe/json-parser/internal/parser"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+		errorMsg    string
+	}{
+		{name: "empty object", input: "{}"},
+		{name: "empty array with whitespace", input: " [ ] \n"},
+		{name: "nested structures", input: `{"a": [1, {"b": null}], "c": {"d": [true, false]}}`},
+		{name: "top-level string", input: `"text"`},
+		{name: "top-level number", input: `-12.5e+3`},
+		{name: "top-level zero", input: `0`},
+		{name: "escapes", input: `"\"\\\/\b\f\n\r\té"`},
+		{name: "numbers in array", input: `[0, -0, 1.5, 2e10, 3E-2, 10]`},
+		{name: "empty input", input: "", expectError: true, errorMsg: "unexpected end of input"},
+		{name: "only whitespace", input: "  ", expectError: true, errorMsg: "unexpected end of input"},
+		{name: "unterminated object", input: `{"a": 1`, expectError: true, errorMsg: "unexpected end of input"},
+		{name: "unterminated string", input: `"abc`, expectError: true, errorMsg: "unterminated string"},
+		{name: "trailing comma in object", input: `{"a": 1,}`, expectError: true, errorMsg: "trailing comma not allowed"},
+		{name: "trailing comma in array", input: `[1,]`, expectError: true, errorMsg: "trailing comma not allowed"},
+		{name: "missing colon", input: `{"a" 1}`, expectError: true, errorMsg: "expected ':'"},
+		{name: "non-string key", input: `{1: 2}`, expectError: true, errorMsg: "expected string key"},
+		{name: "mismatched brackets", input: `[1, 2}`, expectError: true, errorMsg: "expected ',' or ']'"},
+		{name: "missing comma", input: `{"a": 1 "b": 2}`, expectError: true, errorMsg: "expected ',' or '}'"},
+		{name: "extra content", input: `{} {}`, expectError: true, errorMsg: "unexpected content after JSON value"},
+		{name: "leading zero", input: `01`, expectError: true, errorMsg: "leading zeros"},
+		{name: "trailing dot", input: `[1.]`, expectError: true, errorMsg: "missing digits after decimal point"},
+		{name: "incomplete exponent", input: `1e`, expectError: true, errorMsg: "unexpected end of input"},
+		{name: "bad exponent", input: `[1e+]`, expectError: true, errorMsg: "missing digits in exponent"},
+		{name: "lone minus", input: `-`, expectError: true, errorMsg: "unexpected end of input"},
+		{name: "invalid keyword", input: `True`, expectError: true, errorMsg: "expected JSON value"},
+		{name: "truncated keyword", input: `[nul]`, expectError: true, errorMsg: "invalid keyword"},
+		{name: "invalid escape", input: `"\x"`, expectError: true, errorMsg: "invalid escape sequence"},
+		{name: "invalid unicode escape", input: `"\u12G4"`, expectError: true, errorMsg: "invalid Unicode escape sequence"},
+		{name: "raw control character", input: "\"a\tb\"", expectError: true, errorMsg: "unescaped control character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			_, err := v.Write([]byte(tt.input))
+			if err == nil {
+				err = v.Close()
+			}
+
+			if !tt.expectError {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %q", tt.errorMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidator_ByteByByte(t *testing.T) {
+	input := `{"key": [1, 2.5e-3, "vé", true, null], "other": {}}`
+
+	v := New()
+	for i := 0; i < len(input); i++ {
+		if _, err := v.Write([]byte{input[i]}); err != nil {
+			t.Fatalf("unexpected error at byte %d: %v", i, err)
+		}
+	}
+	if err := v.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidator_ErrorPosition(t *testing.T) {
+	v := New()
+	_, err := v.Write([]byte("{\n  \"a\": 1,\n  \"b\" 2\n}"))
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	parseErr, ok := err.(*parser.ParseError)
+	if !ok {
+		t.Fatalf("expected *parser.ParseError, got %T", err)
+	}
+	if parseErr.Position.Line != 3 || parseErr.Position.Column != 7 {
+		t.Errorf("expected error at line 3, column 7, got %s", parseErr.Position)
+	}
+	if parseErr.Key != parser.MsgExpectedColon {
+		t.Errorf("expected key %s, got %s", parser.MsgExpectedColon, parseErr.Key)
+	}
+
+	// The validator keeps reporting the first error.
+	if _, again := v.Write([]byte("}")); again != err {
+		t.Errorf("expected sticky error, got %v", again)
+	}
+}
+
+func TestValidator_AgreesWithParserOnTestData(t *testing.T) {
+	testDir := filepath.Join("..", "..", "test", "testdata")
+	files, err := os.ReadDir(testDir)
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(file.Name(), func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(testDir, file.Name()))
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", file.Name(), err)
+			}
+
+			v := New()
+			_, err = v.Write(content)
+			if err == nil {
+				err = v.Close()
+			}
+
+			// step1_invalid_non_empty.json became valid once Step 2 added key-value pairs.
+			shouldFail := strings.Contains(file.Name(), "_invalid_") && file.Name() != "step1_invalid_non_empty.json"
+			if shouldFail && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !shouldFail && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
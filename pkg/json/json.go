@@ -0,0 +1,106 @@
+// Package json is the public API of json-parser, for use as a library: it
+// parses, validates and decodes JSON documents and encodes values. The
+// implementation lives in the internal packages, which this package
+// re-exports as a small, stable surface.
+package json
+
+import (
+	"bytes"
+
+	"github.com/VuNe/json-parser/internal/decoder"
+	"github.com/VuNe/json-parser/internal/encoder"
+	"github.com/VuNe/json-parser/internal/lexer"
+	"github.com/VuNe/json-parser/internal/parser"
+	"github.com/VuNe/json-parser/internal/query"
+	"github.com/VuNe/json-parser/internal/stream"
+)
+
+// Value is a parsed JSON value: nil, bool, string, int64 (integers that
+// fit), float64 (other numbers), []any for arrays or Object for objects.
+type Value = parser.JSONValue
+
+// Object is a parsed JSON object.
+type Object = parser.JSONObject
+
+// Number is the literal text of a JSON number. Marshal writes it verbatim,
+// so numbers can be passed through without being reformatted.
+type Number = parser.Number
+
+// ParseError describes invalid input, with its position, the expected and
+// found tokens, a snippet of the input and a suggestion.
+type ParseError = parser.ParseError
+
+// Parse parses the JSON document s. Invalid input is reported as a
+// *ParseError.
+func Parse(s string) (Value, error) {
+	return parser.NewWithInput(lexer.New(s), s).Parse()
+}
+
+// Valid reports whether s is a single valid JSON document, that is whether
+// Parse accepts it: a leading byte order mark is skipped and numbers beyond
+// the float64 range are invalid. It checks the document without building the
+// value, so it is cheaper than Parse.
+func Valid(s string) bool {
+	_, err := parser.ParseLazy(s)
+	return err == nil
+}
+
+// Hash returns the SHA-256 digest of the canonical (RFC 8785) encoding of v,
+// which doesn't depend on key order, whitespace or how numbers are written.
+func Hash(v Value) ([32]byte, error) {
+	return encoder.Hash(v)
+}
+
+// Get returns the value at path in the JSON document s, e.g. "a.b[2].c", and
+// whether it exists, without parsing the rest of the document: use it to read
+// one or a few fields of a large document. Path syntax is dot-separated member
+// names and bracketed indices, with names containing '.', '[' or ']' written
+// as quoted strings in brackets (`a["b.c"]`) and negative indices counting
+// from the end. Only the value returned is validated.
+func Get(s, path string) (Value, bool, error) {
+	result, found, err := query.Scan(s, path)
+	return result.Value, found, err
+}
+
+// Set returns the JSON document s with the value at path, as for Get,
+// replaced by the compact encoding of value, or added if only the object or
+// array holding it exists (index the length of an array to append). The
+// rest of the document is kept as written, formatting included.
+func Set(s, path string, value any) (string, error) {
+	return query.SetText(s, path, value)
+}
+
+// Delete returns the JSON document s without the member or element at path,
+// as for Get. The document is returned unchanged if the path doesn't exist.
+func Delete(s, path string) (string, error) {
+	return query.DeleteText(s, path)
+}
+
+// Unmarshal parses the JSON document data and stores it in the value pointed
+// to by v, following encoding/json's conventions for structs, maps, slices,
+// pointers and json tags.
+func Unmarshal(data []byte, v any) error {
+	return decoder.Unmarshal(data, v)
+}
+
+// Compact returns data without insignificant whitespace, with strings and
+// numbers kept exactly as written. Invalid input is an error.
+func Compact(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := stream.Compact(bytes.NewReader(data), &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Marshal returns the compact JSON encoding of v, which may be a parsed
+// Value or a Go value such as a struct, with object keys sorted.
+func Marshal(v any) ([]byte, error) {
+	return encoder.Marshal(v)
+}
+
+// MarshalIndent is like Marshal but starts each line with prefix and
+// indents nested values with one copy of indent per level.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return encoder.MarshalIndent(v, prefix, indent)
+}
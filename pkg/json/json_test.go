@@ -0,0 +1,194 @@
+package json
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Value
+		wantErr  bool
+	}{
+		{input: `{"a": [1, 2.5, "x", true, null]}`, expected: Object{"a": []any{int64(1), 2.5, "x", true, nil}}},
+		{input: ` 42 `, expected: int64(42)},
+		{input: `{"a": }`, wantErr: true},
+		{input: `[1] [2]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			value, err := Parse(tt.input)
+			if tt.wantErr {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("expected *ParseError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{input: `{"a": [1, {"b": null}]}`, expected: true},
+		{input: `"x"`, expected: true},
+		{input: ``, expected: false},
+		{input: `{"a": 1,}`, expected: false},
+		{input: `[1, 2`, expected: false},
+		{input: `{} {}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Valid(tt.input); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValid_AgreesWithParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "byte order mark", input: "\ufeff{}"},
+		{name: "out of range", input: `[1e1000]`},
+		{name: "negative out of range", input: `{"a": -1e400}`},
+		{name: "integer overflow", input: `[123456789012345678901234567890]`},
+		{name: "duplicate keys", input: `{"a": 1, "a": 2}`},
+		{name: "invalid UTF-8", input: "[\"\xff\"]"},
+		{name: "invalid UTF-8 key", input: "{\"\xfe\": 1}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if got := Valid(tt.input); got != (err == nil) {
+				t.Errorf("Valid returned %v, but Parse returned error %v", got, err)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := `{"a": {"b": [0, 1, {"c": "x"}]}, "d": null}`
+	tests := []struct {
+		path     string
+		expected Value
+		found    bool
+	}{
+		{path: "a.b[2].c", expected: "x", found: true},
+		{path: "a.b[-3]", expected: int64(0), found: true},
+		{path: "d", expected: nil, found: true},
+		{path: "a.b[3]", found: false},
+		{path: "e", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found, err := Get(doc, tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tt.found || !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("expected %#v (found %v), got %#v (found %v)", tt.expected, tt.found, value, found)
+			}
+		})
+	}
+}
+
+func TestSetDelete(t *testing.T) {
+	doc := `{"name": "a", "tags": ["x"]}`
+	doc, err := Set(doc, "name", "b")
+	if err == nil {
+		doc, err = Set(doc, "tags[1]", "y")
+	}
+	if err == nil {
+		doc, err = Delete(doc, "tags[0]")
+	}
+	if expected := `{"name": "b", "tags": ["y"]}`; err != nil || doc != expected {
+		t.Errorf("expected %q, got %q (%v)", expected, doc, err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	a, err := Parse(`{"a": 1, "b": [true]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Parse("{\"b\":[ true ],\n\"a\":1.0}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hashA, errA := Hash(a)
+	hashB, errB := Hash(b)
+	if errA != nil || errB != nil || hashA != hashB {
+		t.Errorf("expected equal digests, got %x and %x (%v, %v)", hashA, hashB, errA, errB)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var config struct {
+		Name  string   `json:"name"`
+		Ports []int    `json:"ports"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+	if err := Unmarshal([]byte(`{"name": "api", "ports": [80, 443]}`), &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Name != "api" || !reflect.DeepEqual(config.Ports, []int{80, 443}) {
+		t.Errorf("unexpected result %+v", config)
+	}
+
+	if err := Unmarshal([]byte(`{"name": `), &config); err == nil {
+		t.Error("expected an error for invalid input")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	output, err := Marshal(Object{"b": Number("1.50"), "a": []any{true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"a":[true],"b":1.50}`; string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+
+	output, err = MarshalIndent(struct {
+		ID int `json:"id"`
+	}{ID: 7}, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "{\n  \"id\": 7\n}"; string(output) != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	output, err := Compact([]byte("{\n  \"a\": [1.0, \"\\u00e9\"]\n}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"a":[1.0,"\u00e9"]}`; string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+
+	if _, err := Compact([]byte(`{"a" 1}`)); err == nil {
+		t.Error("expected an error for invalid input")
+	}
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+
+	"github.com/VuNe/json-parser/internal/fetch"
 )
 
 // downloadTestSuite downloads JSON test cases from various sources
@@ -115,11 +116,11 @@ func generateLongString(length int) string {
 }
 
 func downloadFile(url, filename string) error {
-	resp, err := http.Get(url)
+	body, err := fetch.New().Open(url)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
 	file, err := os.Create(filename)
 	if err != nil {
@@ -127,6 +128,6 @@ func downloadFile(url, filename string) error {
 	}
 	defer file.Close()
 
-	_, err = io.Copy(file, resp.Body)
+	_, err = io.Copy(file, body)
 	return err
 }
@@ -110,8 +110,9 @@ func TestCLIIntegration(t *testing.T) {
 			t.Error("Command should have failed for non-existent file")
 		}
 
-		if cmd.ProcessState.ExitCode() != 1 {
-			t.Errorf("Expected exit code 1, got %d", cmd.ProcessState.ExitCode())
+		// File access problems use a dedicated exit code, distinct from invalid JSON
+		if cmd.ProcessState.ExitCode() != 2 {
+			t.Errorf("Expected exit code 2, got %d", cmd.ProcessState.ExitCode())
 		}
 
 		if !strings.Contains(stderr.String(), "does not exist") {